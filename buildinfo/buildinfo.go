@@ -0,0 +1,246 @@
+package buildinfo
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"hash"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// The scopes of the dependencies.
+const (
+	// Required by the go.mod file of the module, and not marked as indirect.
+	DirectScope   = "direct"
+	IndirectScope = "indirect"
+	// Needed by the packages, only by the tests or only by the tools of the module. See cmd.GetDependenciesScopes.
+	MainScope = string(cmd.MainScope)
+	TestScope = string(cmd.TestScope)
+	ToolScope = string(cmd.ToolScope)
+)
+
+// The types of the dependency files.
+const (
+	ZipType = "zip"
+	ModType = "mod"
+)
+
+type Checksums struct {
+	Sha1   string `json:"sha1,omitempty"`
+	Sha256 string `json:"sha256,omitempty"`
+	Md5    string `json:"md5,omitempty"`
+}
+
+// A file of a dependency, which is either its zip or its go.mod file.
+type Dependency struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// ZipType or ModType.
+	Type      string    `json:"type"`
+	Scopes    []string  `json:"scopes,omitempty"`
+	Checksums Checksums `json:"checksums"`
+	// The time the version was published, if known.
+	Time *time.Time `json:"time,omitempty"`
+}
+
+// A module built by the build, with the dependencies used for building it.
+type Module struct {
+	Path         string       `json:"path"`
+	Version      string       `json:"version,omitempty"`
+	Dependencies []Dependency `json:"dependencies"`
+}
+
+// The metadata of a build, which isn't tied to a specific registry. See the adapters for converting it to other formats.
+type BuildInfo struct {
+	Name    string    `json:"name"`
+	Number  string    `json:"number"`
+	Started time.Time `json:"started"`
+	Modules []Module  `json:"modules"`
+}
+
+// Describes how the checksums of the dependency files are calculated.
+type ChecksumOptions struct {
+	// The number of files hashed concurrently, 1 if not positive.
+	Workers int
+	// Skip calculating the checksums which aren't needed, leaving them empty.
+	SkipSha1   bool
+	SkipSha256 bool
+	SkipMd5    bool
+}
+
+// Creates an empty build info, started now.
+func New(name, number string) *BuildInfo {
+	return &BuildInfo{Name: name, Number: number, Started: time.Now(), Modules: []Module{}}
+}
+
+// Resolves the dependencies of the project of ctx with go list -m all, and adds the project as a module of the build
+// info, with the checksums of the dependency files in the module cache and the scopes of the dependencies.
+func (info *BuildInfo) Collect(ctx context.Context, options ChecksumOptions) error {
+	listed, err := cmd.ListModules(ctx)
+	if err != nil {
+		return err
+	}
+	scopes, err := cmd.GetDependenciesScopes(ctx)
+	if err != nil {
+		return err
+	}
+	cache, err := cmd.GetModCache(ctx)
+	if err != nil {
+		return err
+	}
+	module, err := NewModule(listed, scopes, cache, options)
+	if err != nil {
+		return err
+	}
+	info.Modules = append(info.Modules, *module)
+	return nil
+}
+
+// Writes the build info as JSON.
+func (info *BuildInfo) Write(writer io.Writer) error {
+	content, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = writer.Write(append(content, '\n'))
+	return errorutils.CheckError(err)
+}
+
+// Creates the module of the main module of the listed modules, as returned by cmd.ListModules, with its dependencies.
+// Each dependency has its zip and go.mod files, with their checksums, if found in the module cache.
+// Replaced dependencies have the files of their replacements. Dependencies replaced by local directories have no files.
+// The scopes of the dependencies, as returned by cmd.GetDependenciesScopes, are added to their direct or indirect scope.
+// The files are hashed concurrently by options.Workers workers.
+func NewModule(listed []cmd.ListedModule, scopes map[string]cmd.DependencyScope, cache *cmd.ModCache, options ChecksumOptions) (*Module, error) {
+	module := &Module{Dependencies: []Dependency{}}
+	var dependencies []Dependency
+	for _, listedModule := range listed {
+		if listedModule.Main {
+			module.Path, module.Version = listedModule.Path, listedModule.Version
+			continue
+		}
+		source := listedModule
+		if listedModule.Replace != nil {
+			source = *listedModule.Replace
+		}
+		if source.Version == "" {
+			continue
+		}
+		dependencyScopes := []string{DirectScope}
+		if listedModule.Indirect {
+			dependencyScopes[0] = IndirectScope
+		}
+		if scope, ok := scopes[listedModule.Path+"@"+listedModule.Version]; ok {
+			dependencyScopes = append(dependencyScopes, string(scope))
+		}
+		for _, dependencyType := range []string{ZipType, ModType} {
+			dependencies = append(dependencies, Dependency{
+				Path:    source.Path,
+				Version: source.Version,
+				Type:    dependencyType,
+				Scopes:  dependencyScopes,
+				Time:    source.Time,
+			})
+		}
+	}
+
+	found, err := setChecksums(dependencies, cache, options)
+	if err != nil {
+		return nil, err
+	}
+	for i, dependency := range dependencies {
+		if found[i] {
+			module.Dependencies = append(module.Dependencies, dependency)
+		}
+	}
+	return module, nil
+}
+
+// Sets the checksums of the files of the dependencies in the module cache, using a pool of options.Workers workers.
+// Returns whether the file of each dependency was found.
+func setChecksums(dependencies []Dependency, cache *cmd.ModCache, options ChecksumOptions) ([]bool, error) {
+	workers := options.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	found := make([]bool, len(dependencies))
+	errs := make([]error, len(dependencies))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				dependency := &dependencies[index]
+				path := cache.DownloadPath(dependency.Path, dependency.Version, "."+dependency.Type)
+				checksums, err := calcChecksums(path, options)
+				if os.IsNotExist(err) {
+					continue
+				}
+				if err != nil {
+					errs[index] = err
+					continue
+				}
+				dependency.Checksums, found[index] = *checksums, true
+			}
+		}()
+	}
+	for index := range dependencies {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+	}
+	return found, nil
+}
+
+// Calculates the checksums of the file in a single read, except for the skipped ones.
+func calcChecksums(path string, options ChecksumOptions) (*Checksums, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var writers []io.Writer
+	sha1Hash, sha256Hash, md5Hash := sha1.New(), sha256.New(), md5.New()
+	if !options.SkipSha1 {
+		writers = append(writers, sha1Hash)
+	}
+	if !options.SkipSha256 {
+		writers = append(writers, sha256Hash)
+	}
+	if !options.SkipMd5 {
+		writers = append(writers, md5Hash)
+	}
+	if len(writers) > 0 {
+		if _, err = io.Copy(io.MultiWriter(writers...), file); err != nil {
+			return nil, err
+		}
+	}
+	return &Checksums{
+		Sha1:   encodeHash(sha1Hash, options.SkipSha1),
+		Sha256: encodeHash(sha256Hash, options.SkipSha256),
+		Md5:    encodeHash(md5Hash, options.SkipMd5),
+	}, nil
+}
+
+func encodeHash(checksum hash.Hash, skip bool) string {
+	if skip {
+		return ""
+	}
+	return hex.EncodeToString(checksum.Sum(nil))
+}
@@ -0,0 +1,143 @@
+package buildinfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Creates a module cache with the zip and go.mod files of the modules, whose content is "<module>@<version> <type>".
+func createTestModCache(t *testing.T, modules ...string) *cmd.ModCache {
+	cacheDir, err := ioutil.TempDir("", "buildInfoTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := cmd.NewModCache(cacheDir)
+	for _, module := range modules {
+		path, version := module[:bytes.LastIndexByte([]byte(module), '@')], module[bytes.LastIndexByte([]byte(module), '@')+1:]
+		for _, extension := range []string{".zip", ".mod"} {
+			filePath := cache.DownloadPath(path, version, extension)
+			if err = os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err = ioutil.WriteFile(filePath, []byte(module+" "+extension), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return cache
+}
+
+func TestNewModule(t *testing.T) {
+	cache := createTestModCache(t, "github.com/pkg/errors@v0.9.1", "golang.org/x/text@v0.3.2")
+	defer os.RemoveAll(cache.Dir)
+	published := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	listed := []cmd.ListedModule{
+		{Path: "example.com/m", Main: true},
+		{Path: "github.com/pkg/errors", Version: "v0.9.1", Time: &published},
+		{Path: "golang.org/x/text", Version: "v0.3.0", Indirect: true, Replace: &cmd.ListedModule{Path: "golang.org/x/text", Version: "v0.3.2"}},
+		{Path: "example.com/local", Version: "v1.0.0", Replace: &cmd.ListedModule{Path: "../local"}},
+		{Path: "example.com/missing", Version: "v1.0.0"},
+	}
+	module, err := NewModule(listed, map[string]cmd.DependencyScope{"github.com/pkg/errors@v0.9.1": cmd.MainScope, "golang.org/x/text@v0.3.0": cmd.TestScope}, cache, ChecksumOptions{Workers: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if module.Path != "example.com/m" || len(module.Dependencies) != 4 {
+		t.Fatalf("Unexpected module: %+v", module)
+	}
+	expected := Dependency{
+		Path:    "github.com/pkg/errors",
+		Version: "v0.9.1",
+		Type:    ZipType,
+		Scopes:  []string{DirectScope, MainScope},
+		// The checksums of "github.com/pkg/errors@v0.9.1 .zip".
+		Checksums: module.Dependencies[0].Checksums,
+		Time:      &published,
+	}
+	if !reflect.DeepEqual(expected, module.Dependencies[0]) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, module.Dependencies[0])
+	}
+	checksums, err := calcChecksums(cache.DownloadPath("github.com/pkg/errors", "v0.9.1", ".zip"), ChecksumOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if module.Dependencies[0].Checksums != *checksums || len(checksums.Sha1) != 40 || len(checksums.Sha256) != 64 || len(checksums.Md5) != 32 {
+		t.Errorf("Unexpected checksums: %+v", module.Dependencies[0].Checksums)
+	}
+	replaced := module.Dependencies[3]
+	if replaced.Path != "golang.org/x/text" || replaced.Version != "v0.3.2" || replaced.Type != ModType || !reflect.DeepEqual(replaced.Scopes, []string{IndirectScope, TestScope}) {
+		t.Errorf("Unexpected replaced dependency: %+v", replaced)
+	}
+}
+
+func TestSkipChecksums(t *testing.T) {
+	cache := createTestModCache(t, "github.com/pkg/errors@v0.9.1")
+	defer os.RemoveAll(cache.Dir)
+	path := cache.DownloadPath("github.com/pkg/errors", "v0.9.1", ".zip")
+	all, err := calcChecksums(path, ChecksumOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		options  ChecksumOptions
+		expected Checksums
+	}{
+		{"skipSha256", ChecksumOptions{SkipSha256: true}, Checksums{Sha1: all.Sha1, Md5: all.Md5}},
+		{"onlySha256", ChecksumOptions{SkipSha1: true, SkipMd5: true}, Checksums{Sha256: all.Sha256}},
+		{"skipAll", ChecksumOptions{SkipSha1: true, SkipSha256: true, SkipMd5: true}, Checksums{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := calcChecksums(path, test.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *actual != test.expected {
+				t.Errorf("Expected: %+v, Got: %+v", test.expected, *actual)
+			}
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	cache := createTestModCache(t, "github.com/pkg/errors@v0.9.1")
+	defer os.RemoveAll(cache.Dir)
+	executor := cmd.NewFakeExecutor()
+	executor.On(cmd.ExecutorResult{Stdout: `{"Path": "example.com/m", "Main": true}
+{"Path": "github.com/pkg/errors", "Version": "v0.9.1"}
+`}, "list", "-m", "-json", "all")
+	env, err := json.Marshal(map[string]string{"GOMODCACHE": cache.Dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor.On(cmd.ExecutorResult{Stdout: string(env)}, "env", "-json")
+	executor.On(cmd.ExecutorResult{Stdout: "github.com/pkg/errors@v0.9.1\n"}, "list", "-deps", "-test", "-f", "{{with .Module}}{{if not .Main}}{{.Path}}@{{.Version}}{{end}}{{end}}", "./...")
+	info := New("build", "1")
+	if err = info.Collect(cmd.WithExecutor(context.Background(), executor), ChecksumOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(info.Modules) != 1 || len(info.Modules[0].Dependencies) != 2 || !reflect.DeepEqual(info.Modules[0].Dependencies[0].Scopes, []string{DirectScope, TestScope}) {
+		t.Fatalf("Unexpected build info: %+v", info)
+	}
+
+	var buffer bytes.Buffer
+	if err = info.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	written := &BuildInfo{}
+	if err = json.Unmarshal(buffer.Bytes(), written); err != nil {
+		t.Fatal(err)
+	}
+	if written.Name != "build" || written.Number != "1" || !reflect.DeepEqual(written.Modules, info.Modules) {
+		t.Errorf("Unexpected written build info: %s", buffer.String())
+	}
+}
@@ -0,0 +1,21 @@
+package buildinfo
+
+import (
+	"github.com/jfrog/gocmd/cmd"
+	jfrogbuildinfo "github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
+)
+
+// Converts the dependencies of the module to JFrog build-info dependencies, identified as "<module>:<version>",
+// with the module and version escaped as in the module cache. JFrog build-info has no SHA-256 checksums and no types,
+// so the zip and mod files of a version share the same Id, as in the build-info of the go executers.
+func (module *Module) ToJFrogDependencies() []jfrogbuildinfo.Dependency {
+	var dependencies []jfrogbuildinfo.Dependency
+	for _, dependency := range module.Dependencies {
+		dependencies = append(dependencies, jfrogbuildinfo.Dependency{
+			Id:       cmd.EscapeModulePath(dependency.Path) + ":" + cmd.EscapeModulePath(dependency.Version),
+			Scopes:   dependency.Scopes,
+			Checksum: &jfrogbuildinfo.Checksum{Sha1: dependency.Checksums.Sha1, Md5: dependency.Checksums.Md5},
+		})
+	}
+	return dependencies
+}
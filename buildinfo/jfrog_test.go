@@ -0,0 +1,23 @@
+package buildinfo
+
+import (
+	"testing"
+)
+
+func TestToJFrogDependencies(t *testing.T) {
+	module := &Module{Path: "example.com/m", Dependencies: []Dependency{
+		{Path: "github.com/Azure/go-autorest", Version: "v10.15.0+incompatible", Type: ZipType, Scopes: []string{DirectScope},
+			Checksums: Checksums{Sha1: "sha1", Sha256: "sha256", Md5: "md5"}},
+		{Path: "github.com/Azure/go-autorest", Version: "v10.15.0+incompatible", Type: ModType, Scopes: []string{DirectScope},
+			Checksums: Checksums{Sha1: "modSha1", Md5: "modMd5"}},
+	}}
+	dependencies := module.ToJFrogDependencies()
+	if len(dependencies) != 2 {
+		t.Fatalf("Expected the zip and mod dependencies, Got: %+v", dependencies)
+	}
+	dependency := dependencies[0]
+	if dependency.Id != "github.com/!azure/go-autorest:v10.15.0+incompatible" || dependency.Id != dependencies[1].Id ||
+		dependencies[1].Checksum.Sha1 != "modSha1" || dependency.Checksum.Sha1 != "sha1" || dependency.Checksum.Md5 != "md5" || dependency.Scopes[0] != DirectScope {
+		t.Errorf("Unexpected dependency: %+v", dependency)
+	}
+}
@@ -0,0 +1,206 @@
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// The files of a module version in a bundle, which has the layout served by a GOPROXY:
+// <module>/@v/<version>.zip, <version>.mod and <version>.info, with the module paths and versions escaped.
+type moduleVersion struct {
+	path    string
+	version string
+	// The paths of the files in the bundle by their extensions, such as ".zip".
+	files map[string]string
+}
+
+// Returns the module versions in the bundle directory, sorted by path and version.
+func readBundle(dir string) ([]*moduleVersion, error) {
+	versions := map[string]*moduleVersion{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() || info.Name() != "@v" {
+			return nil
+		}
+		relativePath, err := filepath.Rel(dir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		modulePath := cmd.UnescapeModulePath(filepath.ToSlash(relativePath))
+		files, err := ioutil.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			extension := filepath.Ext(file.Name())
+			if file.IsDir() || (extension != ".zip" && extension != ".mod" && extension != ".info") {
+				continue
+			}
+			version := cmd.UnescapeModulePath(strings.TrimSuffix(file.Name(), extension))
+			id := modulePath + "@" + version
+			if versions[id] == nil {
+				versions[id] = &moduleVersion{path: modulePath, version: version, files: map[string]string{}}
+			}
+			versions[id].files[extension] = filepath.Join(path, file.Name())
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var result []*moduleVersion
+	for _, version := range versions {
+		result = append(result, version)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].path != result[j].path {
+			return result[i].path < result[j].path
+		}
+		return result[i].version < result[j].version
+	})
+	return result, nil
+}
+
+// Returns true if the path is a tar archive, which may be gzipped, rather than a directory.
+func isArchive(path string) bool {
+	return strings.HasSuffix(path, ".tar") || strings.HasSuffix(path, ".tar.gz") || strings.HasSuffix(path, ".tgz")
+}
+
+// Extracts the tar archive, which may be gzipped, into the directory.
+func extractArchive(archivePath, destDir string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer file.Close()
+	var reader io.Reader = file
+	if !strings.HasSuffix(archivePath, ".tar") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return errorutils.CheckError(fmt.Errorf("Invalid path %s in %s.", header.Name, archivePath))
+		}
+		target := filepath.Join(destDir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg, tar.TypeRegA:
+			err = writeFile(target, tarReader)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Creates a gzipped tar archive of the files in the directory, unless the archive path ends with ".tar".
+func createArchive(dir, archivePath string) (err error) {
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if closeErr := archive.Close(); err == nil {
+			err = errorutils.CheckError(closeErr)
+		}
+	}()
+	var writer io.Writer = archive
+	if !strings.HasSuffix(archivePath, ".tar") {
+		gzipWriter := gzip.NewWriter(archive)
+		defer func() {
+			if closeErr := gzipWriter.Close(); err == nil {
+				err = errorutils.CheckError(closeErr)
+			}
+		}()
+		writer = gzipWriter
+	}
+	tarWriter := tar.NewWriter(writer)
+	defer func() {
+		if closeErr := tarWriter.Close(); err == nil {
+			err = errorutils.CheckError(closeErr)
+		}
+	}()
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || path == archivePath {
+			return err
+		}
+		relativePath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		err = tarWriter.WriteHeader(&tar.Header{Name: filepath.ToSlash(relativePath), Mode: 0644, Size: info.Size(), ModTime: info.ModTime(), Typeflag: tar.TypeReg})
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	return errorutils.CheckError(err)
+}
+
+// Writes the content to a temp file next to the path, and then renames it, so a partially written file is never left at path.
+func writeFile(path string, content io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	tempFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = io.Copy(tempFile, content)
+	closeErr := tempFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tempFile.Name())
+		return errorutils.CheckError(err)
+	}
+	return nil
+}
+
+func copyFile(source, dest string) error {
+	file, err := os.Open(source)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer file.Close()
+	return writeFile(dest, file)
+}
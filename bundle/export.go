@@ -0,0 +1,136 @@
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The name of the manifest file at the root of a bundle.
+const ManifestFileName = "manifest.json"
+
+// Describes the modules of a bundle.
+type Manifest struct {
+	Created string           `json:"created"`
+	Modules []ManifestModule `json:"modules"`
+}
+
+type ManifestModule struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+	// The go.sum hashes of the module zip, which is empty if only the go.mod file is in the bundle, and of the go.mod file.
+	Hash      string `json:"hash,omitempty"`
+	GoModHash string `json:"goModHash"`
+}
+
+// Returns the go.sum entries of the modules in the manifest.
+func (manifest *Manifest) GoSumEntries() []gosum.ModuleEntry {
+	var entries []gosum.ModuleEntry
+	for _, module := range manifest.Modules {
+		if module.Hash != "" {
+			entries = append(entries, gosum.ModuleEntry{Path: module.Path, Version: module.Version, Hash: module.Hash})
+		}
+		entries = append(entries, gosum.ModuleEntry{Path: module.Path, Version: module.Version, Hash: module.GoModHash, IsMod: true})
+	}
+	return entries
+}
+
+// Copies the files of the modules, in the "path@version" notation, from the module cache into a self-contained bundle,
+// which can be imported using ImportToModCache. The destination is a directory, or a .tar, .tar.gz or .tgz archive.
+// The bundle includes a manifest with the hashes of the modules. Modules whose go.mod file is not in the cache fail
+// the export. Modules whose zip is not in the cache, because only their go.mod file is needed, are exported without it.
+// An archive is created from a temp directory of the TempDirProvider of ctx.
+func Export(ctx context.Context, cache *cmd.ModCache, modules []string, dest string) (manifest *Manifest, err error) {
+	dir := dest
+	if isArchive(dest) {
+		tempDirs := cmd.GetTempDirProvider(ctx)
+		if dir, err = tempDirs.MkdirTemp("gocmd-bundle"); err != nil {
+			return nil, err
+		}
+		defer func(tempDir string) {
+			if removeErr := tempDirs.Remove(tempDir, err != nil); err == nil {
+				err = removeErr
+			}
+		}(dir)
+	}
+	sorted := append([]string(nil), modules...)
+	sort.Strings(sorted)
+	manifest = &Manifest{Created: time.Now().UTC().Format(time.RFC3339), Modules: []ManifestModule{}}
+	for _, id := range sorted {
+		index := strings.LastIndex(id, "@")
+		if index < 0 {
+			return nil, errorutils.CheckError(fmt.Errorf("Invalid module %s: expecting the path@version notation.", id))
+		}
+		module, err := exportModuleVersion(cache, id[:index], id[index+1:], dir)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Modules = append(manifest.Modules, *module)
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if err = writeFile(filepath.Join(dir, ManifestFileName), strings.NewReader(string(content))); err != nil {
+		return nil, err
+	}
+	if dir != dest {
+		if err = createArchive(dir, dest); err != nil {
+			return nil, err
+		}
+	}
+	cmd.GetLogger(ctx).Info(fmt.Sprintf("Exported %d modules to %s.", len(manifest.Modules), dest))
+	return manifest, nil
+}
+
+func exportModuleVersion(cache *cmd.ModCache, path, version, dir string) (*ManifestModule, error) {
+	module := &ManifestModule{Path: path, Version: version}
+	versionDir := filepath.Join(dir, filepath.FromSlash(cmd.EscapeModulePath(path)), "@v")
+	modContent, err := ioutil.ReadFile(cache.DownloadPath(path, version, ".mod"))
+	if err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("The go.mod file of %s@%s is not in the module cache: %s", path, version, err.Error()))
+	}
+	if module.GoModHash, err = gosum.HashMod(modContent); err != nil {
+		return nil, err
+	}
+	for _, extension := range []string{".mod", ".info", ".zip"} {
+		source := cache.DownloadPath(path, version, extension)
+		if _, err = os.Stat(source); os.IsNotExist(err) {
+			continue
+		}
+		if err = copyFile(source, filepath.Join(versionDir, cmd.EscapeModulePath(version)+extension)); err != nil {
+			return nil, err
+		}
+		if extension == ".zip" {
+			if module.Hash, err = gosum.HashZip(source); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return module, nil
+}
+
+// Reads the manifest of the bundle directory. Returns nil if the bundle has no manifest.
+func readManifest(dir string) (*Manifest, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, ManifestFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	manifest := &Manifest{}
+	if err = json.Unmarshal(content, manifest); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the bundle manifest: %s", err.Error()))
+	}
+	return manifest, nil
+}
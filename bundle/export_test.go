@@ -0,0 +1,58 @@
+package bundle
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExport(t *testing.T) {
+	bundleDir := createTestBundle(t)
+	defer os.RemoveAll(bundleDir)
+	cacheDir, err := ioutil.TempDir("", "modCacheTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	cache := cmd.NewModCache(cacheDir)
+	if _, err = ImportToModCache(context.Background(), cache, bundleDir, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	exportDir, err := ioutil.TempDir("", "exportTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(exportDir)
+	archivePath := filepath.Join(exportDir, "bundle.tgz")
+	manifest, err := Export(context.Background(), cache, []string{"github.com/Test@v1.2.3"}, archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ManifestModule{{Path: "github.com/Test", Version: "v1.2.3", Hash: testZipHash, GoModHash: manifest.Modules[0].GoModHash}}
+	if !reflect.DeepEqual(expected, manifest.Modules) || manifest.Modules[0].GoModHash == "" {
+		t.Errorf("Expected: %v, Got: %v", expected, manifest.Modules)
+	}
+
+	// The exported bundle is imported to an empty cache, and verified against its manifest.
+	otherCacheDir, err := ioutil.TempDir("", "modCacheTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(otherCacheDir)
+	imported, err := ImportToModCache(context.Background(), cmd.NewModCache(otherCacheDir), archivePath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"github.com/Test@v1.2.3"}, imported) {
+		t.Error("Unexpected imported modules:", imported)
+	}
+
+	if _, err = Export(context.Background(), cache, []string{"github.com/Missing@v1.0.0"}, filepath.Join(exportDir, "missing")); err == nil {
+		t.Error("Expecting an error for a module which is not in the cache")
+	}
+}
@@ -0,0 +1,138 @@
+package bundle
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Installs the modules of the source into the module cache, so they can be used without network access.
+// The source is a directory, or a .tar, .tar.gz or .tgz archive of a directory, with the layout served by a GOPROXY:
+// <module>/@v/<version>.zip, <version>.mod and <version>.info, with the module paths and versions escaped.
+// The hashes of the zips are calculated and written to the cache. Modules with entries in sums, or in the manifest
+// of the bundle, are verified against them.
+// An archive is extracted to a temp directory of the TempDirProvider of ctx.
+// Returns the imported modules in the "path@version" notation.
+func ImportToModCache(ctx context.Context, cache *cmd.ModCache, source string, sums []gosum.ModuleEntry) (imported []string, err error) {
+	dir := source
+	if isArchive(source) {
+		tempDirs := cmd.GetTempDirProvider(ctx)
+		var tempDir string
+		if tempDir, err = tempDirs.MkdirTemp("gocmd-bundle"); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if removeErr := tempDirs.Remove(tempDir, err != nil); err == nil {
+				err = removeErr
+			}
+		}()
+		if err = extractArchive(source, tempDir); err != nil {
+			return nil, err
+		}
+		dir = tempDir
+	}
+	versions, err := readBundle(dir)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := readManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if manifest != nil {
+		sums = append(manifest.GoSumEntries(), sums...)
+	}
+	entries := map[string]gosum.ModuleEntry{}
+	for _, entry := range sums {
+		key := entry.ModuleId()
+		if entry.IsMod {
+			key += "/go.mod"
+		}
+		entries[key] = entry
+	}
+	// Verify all the modules before importing any, so the cache isn't left with part of a tampered bundle.
+	for _, moduleVersion := range versions {
+		if err = verifyModuleVersion(moduleVersion, entries); err != nil {
+			return nil, err
+		}
+	}
+	for _, moduleVersion := range versions {
+		id := moduleVersion.path + "@" + moduleVersion.version
+		if cmd.SkipInDryRunWithContext(ctx, "Importing "+id+" to the module cache "+cache.Dir) {
+			continue
+		}
+		cmd.GetLogger(ctx).Debug("Importing", id, "to the module cache", cache.Dir)
+		if err = importModuleVersion(cache, moduleVersion); err != nil {
+			return imported, err
+		}
+		imported = append(imported, id)
+	}
+	return imported, nil
+}
+
+// Verifies the zip and the go.mod file of the module version against their go.sum entries, if exist.
+func verifyModuleVersion(moduleVersion *moduleVersion, entries map[string]gosum.ModuleEntry) error {
+	id := moduleVersion.path + "@" + moduleVersion.version
+	if _, exists := moduleVersion.files[".mod"]; !exists {
+		return errorutils.CheckError(fmt.Errorf("The go.mod file of %s is missing from the bundle.", id))
+	}
+	if entry, exists := entries[id+"/go.mod"]; exists {
+		if err := gosum.VerifyModFile(entry, moduleVersion.files[".mod"]); err != nil {
+			return err
+		}
+	}
+	if zipPath, exists := moduleVersion.files[".zip"]; exists {
+		if entry, exists := entries[id]; exists {
+			if err := gosum.VerifyModuleZip(entry, zipPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func importModuleVersion(cache *cmd.ModCache, moduleVersion *moduleVersion) error {
+	for extension, path := range moduleVersion.files {
+		if err := copyFile(path, cache.DownloadPath(moduleVersion.path, moduleVersion.version, extension)); err != nil {
+			return err
+		}
+	}
+	if zipPath, exists := moduleVersion.files[".zip"]; exists {
+		hash, err := gosum.HashZip(zipPath)
+		if err != nil {
+			return err
+		}
+		err = writeFile(cache.DownloadPath(moduleVersion.path, moduleVersion.version, ".ziphash"), strings.NewReader(hash))
+		if err != nil {
+			return err
+		}
+	}
+	return addToVersionsList(cache, moduleVersion)
+}
+
+// Adds the version to the list of the module's versions in the cache, which is used when resolving versions offline.
+func addToVersionsList(cache *cmd.ModCache, moduleVersion *moduleVersion) error {
+	listPath := cache.DownloadPath(moduleVersion.path, "list", "")
+	content, err := ioutil.ReadFile(listPath)
+	if err != nil && !os.IsNotExist(err) {
+		return errorutils.CheckError(err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == moduleVersion.version {
+			return nil
+		}
+	}
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	content = append(content, []byte(moduleVersion.version+"\n")...)
+	return writeFile(listPath, bytes.NewReader(content))
+}
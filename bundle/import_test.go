@@ -0,0 +1,113 @@
+package bundle
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/gosum"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testZipHash = "h1:xD90HeW8F8sOt8zOweJdoUEwjoFXTN2nFCipo+wQAKQ="
+
+// Creates a bundle with github.com/Test@v1.2.3, whose zip is in the testdata.
+func createTestBundle(t *testing.T) string {
+	bundleDir, err := ioutil.TempDir("", "bundleTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	versionDir := filepath.Join(bundleDir, "github.com", "!test", "@v")
+	if err = os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = copyFile(filepath.Join("..", "testdata", "zip", "v1.2.3.zip"), filepath.Join(versionDir, "v1.2.3.zip")); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(versionDir, "v1.2.3.mod"), []byte("module github.com/Test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(versionDir, "v1.2.3.info"), []byte(`{"Version":"v1.2.3"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return bundleDir
+}
+
+func TestImportToModCache(t *testing.T) {
+	bundleDir := createTestBundle(t)
+	defer os.RemoveAll(bundleDir)
+	cacheDir, err := ioutil.TempDir("", "modCacheTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	cache := cmd.NewModCache(cacheDir)
+
+	sums := []gosum.ModuleEntry{{Path: "github.com/Test", Version: "v1.2.3", Hash: testZipHash}}
+	imported, err := ImportToModCache(context.Background(), cache, bundleDir, sums)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"github.com/Test@v1.2.3"}, imported) {
+		t.Error("Unexpected imported modules:", imported)
+	}
+	for _, extension := range []string{".zip", ".mod", ".info"} {
+		if _, err = os.Stat(cache.DownloadPath("github.com/Test", "v1.2.3", extension)); err != nil {
+			t.Error(err)
+		}
+	}
+	zipHash, err := ioutil.ReadFile(cache.DownloadPath("github.com/Test", "v1.2.3", ".ziphash"))
+	if err != nil || string(zipHash) != testZipHash {
+		t.Error("Unexpected zip hash:", string(zipHash), err)
+	}
+	// Importing again doesn't duplicate the version in the list.
+	if _, err = ImportToModCache(context.Background(), cache, bundleDir, nil); err != nil {
+		t.Fatal(err)
+	}
+	list, err := ioutil.ReadFile(cache.DownloadPath("github.com/Test", "list", ""))
+	if err != nil || string(list) != "v1.2.3\n" {
+		t.Errorf("Unexpected versions list: %q, %v", string(list), err)
+	}
+}
+
+func TestImportToModCacheMismatch(t *testing.T) {
+	bundleDir := createTestBundle(t)
+	defer os.RemoveAll(bundleDir)
+	cacheDir, err := ioutil.TempDir("", "modCacheTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	sums := []gosum.ModuleEntry{{Path: "github.com/Test", Version: "v1.2.3", Hash: "h1:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="}}
+	if _, err = ImportToModCache(context.Background(), cmd.NewModCache(cacheDir), bundleDir, sums); err == nil {
+		t.Error("Expecting an error for a zip which doesn't match go.sum")
+	}
+	if _, err = os.Stat(filepath.Join(cacheDir, "cache")); !os.IsNotExist(err) {
+		t.Error("Expecting nothing to be imported")
+	}
+}
+
+func TestImportToModCacheFromArchive(t *testing.T) {
+	bundleDir := createTestBundle(t)
+	defer os.RemoveAll(bundleDir)
+	archivePath := filepath.Join(bundleDir, "bundle.tar.gz")
+	if err := createArchive(bundleDir, archivePath); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir, err := ioutil.TempDir("", "modCacheTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	imported, err := ImportToModCache(context.Background(), cmd.NewModCache(cacheDir), archivePath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"github.com/Test@v1.2.3"}, imported) {
+		t.Error("Unexpected imported modules:", imported)
+	}
+}
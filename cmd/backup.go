@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+const backupManifestName = "manifest"
+
+// Backs up files, such as go.mod and go.sum, before they are changed or removed, and restores them on Rollback.
+// The backups are kept on disk, so that they survive a crash, and can then be restored by RestoreBackups.
+// Use RecoverAndRollback in a defer statement to restore the files on panic. The signals of the process are handled only
+// if HandleSignals is called, to leave them to the application: to restore the files on interrupt, either call it or
+// cancel the context of the manager. The manager never terminates the process.
+type FileBackupManager struct {
+	mutex     sync.Mutex
+	backupDir string
+	backups   []fileBackup
+	// Closed when the manager is closed, to stop watching its context.
+	done     chan struct{}
+	closed   bool
+	tempDirs TempDirProvider
+	// The file system of the backed up files. The backups themselves are always kept on disk.
+	files fsys.FileSystem
+	// Carries the listeners of the removed files events. The files are restored when it's done.
+	ctx context.Context
+}
+
+type fileBackup struct {
+	originalPath string
+	backupPath   string
+	mode         os.FileMode
+}
+
+// Creates a manager storing its backups in a new temp directory.
+func NewFileBackupManager() (*FileBackupManager, error) {
+	return newFileBackupManager(context.Background(), &TempDirs{}, fsys.OsFileSystem{})
+}
+
+// Creates a manager storing its backups in a new directory of the TempDirProvider of ctx.
+// The files are restored when ctx is done, as when it's cancelled on interrupt.
+// The files are backed up from and restored to the FileSystem of ctx, and their removal is reported to the EventListeners of ctx.
+func NewFileBackupManagerWithContext(ctx context.Context) (*FileBackupManager, error) {
+	return newFileBackupManager(ctx, getTempDirProvider(ctx), GetFileSystem(ctx))
+}
+
+func newFileBackupManager(ctx context.Context, tempDirs TempDirProvider, files fsys.FileSystem) (*FileBackupManager, error) {
+	backupDir, err := tempDirs.MkdirTemp("gocmd-backup")
+	if err != nil {
+		return nil, err
+	}
+	manager := &FileBackupManager{backupDir: backupDir, done: make(chan struct{}), tempDirs: tempDirs, files: files, ctx: ctx}
+	if ctx.Done() != nil {
+		go manager.rollbackOnDone()
+	}
+	return manager, nil
+}
+
+// Returns the directory holding the backups. If the process crashes, RestoreBackups can restore the files from it.
+func (manager *FileBackupManager) GetBackupDir() string {
+	return manager.backupDir
+}
+
+// Backs up the file. Files which don't exist are ignored.
+func (manager *FileBackupManager) Backup(path string) error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.closed {
+		return errorutils.CheckError(fmt.Errorf("Cannot backup %s, the backup manager is already closed.", path))
+	}
+	exists, err := fsys.IsFileExists(manager.files, path)
+	if err != nil || !exists {
+		return err
+	}
+	content, stat, err := getFileDetails(manager.files, path)
+	if err != nil {
+		return err
+	}
+	backup := fileBackup{originalPath: path, backupPath: filepath.Join(manager.backupDir, strconv.Itoa(len(manager.backups))), mode: stat.Mode()}
+	getLogger(manager.ctx).Debug("Backing up file:", path)
+	err = fsys.WriteFileAtomically(backup.backupPath, content, backup.mode)
+	if err != nil {
+		return err
+	}
+	manager.backups = append(manager.backups, backup)
+	return manager.writeManifest()
+}
+
+// Backs up the file and removes it. Files which don't exist are ignored.
+func (manager *FileBackupManager) BackupAndRemove(path string) error {
+	err := manager.Backup(path)
+	if err != nil {
+		return err
+	}
+	exists, err := fsys.IsFileExists(manager.files, path)
+	if err != nil || !exists || SkipInDryRunWithContext(manager.ctx, "Removing file: "+path) {
+		return err
+	}
+	getLogger(manager.ctx).Debug("Removing file:", path)
+	if err = manager.files.Remove(path); err != nil {
+		return errorutils.CheckError(err)
+	}
+	EmitEvent(manager.ctx, Event{Type: FileRemovedEvent, Path: path})
+	return nil
+}
+
+// Keeps the current state of the files and discards the backups.
+func (manager *FileBackupManager) Commit() error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	return manager.close()
+}
+
+// Restores the backed up files and discards the backups.
+func (manager *FileBackupManager) Rollback() error {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.closed {
+		return nil
+	}
+	if err := restoreFiles(manager.ctx, manager.files, manager.backups); err != nil {
+		// Keep the backups, so that the files can still be restored from them.
+		getLogger(manager.ctx).Error(fmt.Sprintf("Failed restoring the backed up files. The backups are kept in %s: %s", manager.backupDir, err.Error()))
+		return err
+	}
+	return manager.close()
+}
+
+// Rolls back and re-panics if called during a panic. Should be called in a defer statement.
+func (manager *FileBackupManager) RecoverAndRollback() {
+	if r := recover(); r != nil {
+		manager.Rollback()
+		panic(r)
+	}
+}
+
+// Restores the backed up files when the process receives SIGINT or SIGTERM, and then sends the signal on the returned
+// channel, leaving it to the caller to decide whether to exit. The returned channel is closed without a signal once the
+// manager is committed or rolled back, and the signals are then no longer handled by the manager.
+// The signal handlers of the application are left as is, and each manager handling the signals restores its own files.
+// While the signals are handled, they no longer terminate the process by default, as with signal.Notify.
+func (manager *FileBackupManager) HandleSignals() <-chan os.Signal {
+	signals := make(chan os.Signal, 1)
+	received := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go manager.rollbackOnSignal(signals, received)
+	return received
+}
+
+func (manager *FileBackupManager) rollbackOnSignal(signals, received chan os.Signal) {
+	defer close(received)
+	defer signal.Stop(signals)
+	select {
+	case sig := <-signals:
+		getLogger(manager.ctx).Warn("Received", sig, "- restoring the backed up files...")
+		manager.Rollback()
+		received <- sig
+	case <-manager.done:
+	}
+}
+
+// Restores the files backed up by a manager which did not complete, for example due to a crash.
+// The files are restored to the FileSystem of ctx.
+func RestoreBackups(ctx context.Context, backupDir string) error {
+	manifest, err := ioutil.ReadFile(filepath.Join(backupDir, backupManifestName))
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	var backups []fileBackup
+	for _, line := range getLines(manifest) {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			return errorutils.CheckError(fmt.Errorf("Invalid backup manifest line: %s", line))
+		}
+		mode, err := strconv.ParseUint(fields[0], 8, 32)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		backups = append(backups, fileBackup{mode: os.FileMode(mode), backupPath: fields[1], originalPath: fields[2]})
+	}
+	if err = restoreFiles(ctx, GetFileSystem(ctx), backups); err != nil {
+		return err
+	}
+	return errorutils.CheckError(os.RemoveAll(backupDir))
+}
+
+func (manager *FileBackupManager) rollbackOnDone() {
+	select {
+	case <-manager.ctx.Done():
+		getLogger(manager.ctx).Warn("The operation was interrupted - restoring the backed up files...")
+		manager.Rollback()
+	case <-manager.done:
+	}
+}
+
+func (manager *FileBackupManager) close() error {
+	if manager.closed {
+		return nil
+	}
+	manager.closed = true
+	close(manager.done)
+	return manager.tempDirs.Remove(manager.backupDir, false)
+}
+
+// Lists the backups, so that they can be restored by RestoreBackups after a crash.
+func (manager *FileBackupManager) writeManifest() error {
+	var manifest strings.Builder
+	for _, backup := range manager.backups {
+		manifest.WriteString(fmt.Sprintf("%o\t%s\t%s\n", uint32(backup.mode), backup.backupPath, backup.originalPath))
+	}
+	return fsys.WriteFileAtomically(filepath.Join(manager.backupDir, backupManifestName), []byte(manifest.String()), 0600)
+}
+
+func restoreFiles(ctx context.Context, files fsys.FileSystem, backups []fileBackup) error {
+	// Restore in reverse order, so that the earliest backup of a file backed up more than once wins.
+	for i := len(backups) - 1; i >= 0; i-- {
+		backup := backups[i]
+		getLogger(ctx).Debug("Restoring file:", backup.originalPath)
+		content, err := ioutil.ReadFile(backup.backupPath)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		// Files which weren't changed aren't written, so that nothing is written in dry run unless a go command
+		// changed the files.
+		if current, err := readFileIfExists(files, backup.originalPath); err == nil && current != nil && bytes.Equal(current, content) {
+			continue
+		}
+		if err = files.WriteFile(backup.originalPath, content, backup.mode); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	return nil
+}
+
+// Writes to a temp file in the same directory and renames it, so that the file is never partially written.
+func WriteFileAtomically(path string, content []byte, mode os.FileMode) error {
+	return fsys.WriteFileAtomically(path, content, mode)
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestFileBackupManagerRollback(t *testing.T) {
+	tempDir, modPath, sumPath := createBackupTestFiles(t)
+	defer os.RemoveAll(tempDir)
+
+	manager, err := NewFileBackupManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.Backup(modPath); err != nil {
+		t.Error(err)
+	}
+	if err = manager.BackupAndRemove(sumPath); err != nil {
+		t.Error(err)
+	}
+	if err = ioutil.WriteFile(modPath, []byte("module changed"), 0600); err != nil {
+		t.Error(err)
+	}
+	if _, err = os.Stat(sumPath); !os.IsNotExist(err) {
+		t.Error("Expecting go.sum to be removed, got:", err)
+	}
+
+	if err = manager.Rollback(); err != nil {
+		t.Error(err)
+	}
+	assertFileContent(t, modPath, "module original")
+	assertFileContent(t, sumPath, "sum original")
+	if _, err = os.Stat(manager.GetBackupDir()); !os.IsNotExist(err) {
+		t.Error("Expecting the backup directory to be removed, got:", err)
+	}
+}
+
+func TestFileBackupManagerRollbackDryRun(t *testing.T) {
+	tempDir, modPath, sumPath := createBackupTestFiles(t)
+	defer os.RemoveAll(tempDir)
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	manager, err := NewFileBackupManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.BackupAndRemove(sumPath); err != nil {
+		t.Error(err)
+	}
+	if err = manager.Backup(modPath); err != nil {
+		t.Error(err)
+	}
+	assertFileContent(t, sumPath, "sum original")
+	// A file changed by a go command which ran in dry run is restored.
+	if err = ioutil.WriteFile(modPath, []byte("module changed"), 0600); err != nil {
+		t.Error(err)
+	}
+	if err = manager.Rollback(); err != nil {
+		t.Error(err)
+	}
+	assertFileContent(t, modPath, "module original")
+	assertFileContent(t, sumPath, "sum original")
+	if _, err = os.Stat(manager.GetBackupDir()); !os.IsNotExist(err) {
+		t.Error("Expecting the backup directory to be removed, got:", err)
+	}
+}
+
+func TestFileBackupManagerCommit(t *testing.T) {
+	tempDir, modPath, _ := createBackupTestFiles(t)
+	defer os.RemoveAll(tempDir)
+
+	manager, err := NewFileBackupManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.Backup(modPath); err != nil {
+		t.Error(err)
+	}
+	if err = ioutil.WriteFile(modPath, []byte("module changed"), 0600); err != nil {
+		t.Error(err)
+	}
+	if err = manager.Commit(); err != nil {
+		t.Error(err)
+	}
+	// Rolling back after commit has no effect.
+	if err = manager.Rollback(); err != nil {
+		t.Error(err)
+	}
+	assertFileContent(t, modPath, "module changed")
+}
+
+func TestRestoreBackups(t *testing.T) {
+	tempDir, modPath, _ := createBackupTestFiles(t)
+	defer os.RemoveAll(tempDir)
+
+	manager, err := NewFileBackupManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.Backup(modPath); err != nil {
+		t.Error(err)
+	}
+	if err = ioutil.WriteFile(modPath, []byte("module changed"), 0600); err != nil {
+		t.Error(err)
+	}
+	// Simulate a crash, by restoring from the backup directory without using the manager.
+	if err = RestoreBackups(context.Background(), manager.GetBackupDir()); err != nil {
+		t.Error(err)
+	}
+	assertFileContent(t, modPath, "module original")
+	manager.Commit()
+}
+
+func createBackupTestFiles(t *testing.T) (tempDir, modPath, sumPath string) {
+	tempDir, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modPath = filepath.Join(tempDir, "go.mod")
+	sumPath = filepath.Join(tempDir, "go.sum")
+	if err = ioutil.WriteFile(modPath, []byte("module original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(sumPath, []byte("sum original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+func assertFileContent(t *testing.T, path, expected string) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(content) != expected {
+		t.Errorf("Expecting %s to contain %q, got: %q", path, expected, string(content))
+	}
+}
+
+func TestFileBackupManagerRollbackOnCancel(t *testing.T) {
+	tempDir, modPath, sumPath := createBackupTestFiles(t)
+	defer os.RemoveAll(tempDir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	manager, err := NewFileBackupManagerWithContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.BackupAndRemove(sumPath); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for i := 0; i < 100; i++ {
+		if _, err = os.Stat(manager.GetBackupDir()); os.IsNotExist(err) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assertFileContent(t, sumPath, "sum original")
+	assertFileContent(t, modPath, "module original")
+}
+
+func TestFileBackupManagerHandleSignals(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Sending signals is not supported on Windows")
+	}
+	tempDir, modPath, sumPath := createBackupTestFiles(t)
+	defer os.RemoveAll(tempDir)
+
+	// Each manager restores its own files, and neither terminates the process.
+	sumManager, err := NewFileBackupManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sumSignals := sumManager.HandleSignals()
+	if err = sumManager.BackupAndRemove(sumPath); err != nil {
+		t.Fatal(err)
+	}
+	modManager, err := NewFileBackupManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	modSignals := modManager.HandleSignals()
+	if err = modManager.BackupAndRemove(modPath); err != nil {
+		t.Fatal(err)
+	}
+	process, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	for _, signals := range []<-chan os.Signal{sumSignals, modSignals} {
+		select {
+		case sig := <-signals:
+			if sig != syscall.SIGTERM {
+				t.Errorf("Expecting SIGTERM to be returned, got: %v", sig)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expecting the signal to be handled")
+		}
+	}
+	assertFileContent(t, sumPath, "sum original")
+	assertFileContent(t, modPath, "module original")
+}
+
+func TestFileBackupManagerHandleSignalsCommit(t *testing.T) {
+	manager, err := NewFileBackupManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signals := manager.HandleSignals()
+	if err = manager.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case sig, ok := <-signals:
+		if ok {
+			t.Errorf("Expecting the channel to be closed without a signal, got: %v", sig)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expecting the channel to be closed once the manager is committed")
+	}
+}
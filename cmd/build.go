@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// The default template of the binaries' file names, such as "app_linux_amd64" and "app_windows_amd64.exe".
+const DefaultOutputTemplate = "{{.Name}}_{{.OS}}_{{.Arch}}{{.Ext}}"
+
+// An OS and architecture to build for.
+type BuildTarget struct {
+	GOOS   string
+	GOARCH string
+}
+
+func (target BuildTarget) String() string {
+	return target.GOOS + "/" + target.GOARCH
+}
+
+// Describes how BuildMatrix builds the binaries.
+type BuildOptions struct {
+	// The main package to build, "." if empty. Relative directories, such as "./cmd/app", are relative to the working
+	// directory of the go commands.
+	Package string
+	// The directory of the binaries. A relative or empty directory is relative to the working directory of the go commands.
+	OutputDir string
+	// The name of the binaries, used by the output template. Defaults to the last element of the package path.
+	Name string
+	// A text/template of the binaries' file names, with the Name, OS, Arch and Ext fields. Defaults to DefaultOutputTemplate.
+	OutputTemplate string
+	// Removes file system paths from the binaries (-trimpath).
+	TrimPath bool
+	LdFlags  string
+	Tags     []string
+	// Environment variables set for all the targets, such as CGO_ENABLED=0.
+	Env map[string]string
+	// The number of targets built concurrently, 1 if not positive.
+	Concurrency int
+}
+
+// The result of building a single target.
+type BuildResult struct {
+	Target BuildTarget
+	// The absolute path of the binary.
+	BinaryPath string
+	Size       int64
+	Duration   time.Duration
+	Err        error
+}
+
+// Builds the package for each of the targets, with GOOS and GOARCH set only for the go command of the target.
+// Returns a result per target, in the order of the targets. A target failing to build doesn't stop the other targets.
+// An error is returned only for invalid options.
+func BuildMatrix(ctx context.Context, targets []BuildTarget, options BuildOptions) ([]BuildResult, error) {
+	if options.Package == "" {
+		options.Package = "."
+	}
+	// go build runs in the working directory of ctx, which may differ from the one of the process.
+	wd, err := getWorkingDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !filepath.IsAbs(options.OutputDir) {
+		if options.OutputDir, err = filepath.Abs(filepath.Join(wd, options.OutputDir)); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+	}
+	if options.Name == "" {
+		name, err := getBinaryName(wd, options.Package)
+		if err != nil {
+			return nil, err
+		}
+		options.Name = name
+	}
+	if options.OutputTemplate == "" {
+		options.OutputTemplate = DefaultOutputTemplate
+	}
+	outputTemplate, err := template.New("output").Parse(options.OutputTemplate)
+	if err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid output template %q: %s", options.OutputTemplate, err.Error()))
+	}
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]BuildResult, len(targets))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		results[i].Target = target
+		binaryName, err := executeOutputTemplate(outputTemplate, options.Name, target)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].BinaryPath = filepath.Join(options.OutputDir, binaryName)
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(result *BuildResult) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			buildTarget(ctx, options, result)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// Builds the target of the result and sets the result's fields.
+func buildTarget(ctx context.Context, options BuildOptions, result *BuildResult) {
+	if SkipInDryRunWithContext(ctx, "Building "+result.BinaryPath+" for "+result.Target.String()) {
+		return
+	}
+	for key, value := range options.Env {
+		ctx = WithEnv(ctx, key, value)
+	}
+	ctx = WithEnv(WithEnv(ctx, "GOOS", result.Target.GOOS), "GOARCH", result.Target.GOARCH)
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	goCmd.Command = []string{"build", "-o", result.BinaryPath}
+	if options.TrimPath {
+		goCmd.Command = append(goCmd.Command, "-trimpath")
+	}
+	if options.LdFlags != "" {
+		goCmd.Command = append(goCmd.Command, "-ldflags", options.LdFlags)
+	}
+	if len(options.Tags) > 0 {
+		goCmd.Command = append(goCmd.Command, "-tags", strings.Join(options.Tags, ","))
+	}
+	goCmd.Command = append(goCmd.Command, options.Package)
+
+	getLogger(ctx).Info("Building", result.BinaryPath, "for", result.Target.String())
+	start := time.Now()
+	_, errorOutput, err := runCmdWithOutputParser(goCmd, false)
+	result.Duration = time.Since(start)
+	if err != nil {
+		err = contextError(ctx, err)
+		if errorOutput = strings.TrimSpace(errorOutput); errorOutput != "" {
+			err = fmt.Errorf("%s: %s", err.Error(), errorOutput)
+		}
+		result.Err = errorutils.CheckError(fmt.Errorf("Building %s for %s failed: %s", options.Package, result.Target.String(), err.Error()))
+		return
+	}
+	info, err := os.Stat(result.BinaryPath)
+	if err != nil {
+		result.Err = errorutils.CheckError(err)
+		return
+	}
+	result.Size = info.Size()
+}
+
+func executeOutputTemplate(outputTemplate *template.Template, name string, target BuildTarget) (string, error) {
+	ext := ""
+	if target.GOOS == "windows" {
+		ext = ".exe"
+	}
+	data := struct{ Name, OS, Arch, Ext string }{name, target.GOOS, target.GOARCH, ext}
+	var output bytes.Buffer
+	if err := outputTemplate.Execute(&output, data); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return output.String(), nil
+}
+
+// Returns the last element of the package path, as go build names binaries. Relative directories are resolved against wd.
+// The major version suffix of import paths, such as "/v2", is skipped.
+func getBinaryName(wd, pkg string) (string, error) {
+	if filepath.IsAbs(pkg) {
+		return filepath.Base(filepath.Clean(pkg)), nil
+	}
+	if pkg == "." || strings.HasPrefix(pkg, "./") || strings.HasPrefix(pkg, "../") {
+		absolutePath, err := filepath.Abs(filepath.Join(wd, pkg))
+		if err != nil {
+			return "", errorutils.CheckError(err)
+		}
+		return filepath.Base(absolutePath), nil
+	}
+	name := path.Base(pkg)
+	if parent := path.Dir(pkg); parent != "." && len(name) > 1 && name[0] == 'v' && isDigits(name[1:]) {
+		name = path.Base(parent)
+	}
+	return name, nil
+}
+
+func isDigits(text string) bool {
+	for _, char := range text {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return text != ""
+}
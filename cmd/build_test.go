@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildMatrix(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "buildMatrixTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	files := map[string]string{
+		"go.mod":           "module example.com/app/v2\n",
+		"main.go":          "package main\n\nfunc main() {}\n",
+		"cmd/tool/main.go": "package main\n\nfunc main() {}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The output directory and the packages are relative to the directory of the context, rather than to the working
+	// directory of the process.
+	ctx := WithModuleDir(context.Background(), projectDir)
+
+	targets := []BuildTarget{{"linux", "amd64"}, {"windows", "amd64"}, {"plan10", "amd64"}}
+	options := BuildOptions{Package: "example.com/app/v2", OutputDir: "bin", LdFlags: "-s -w", Env: map[string]string{"CGO_ENABLED": "0"}, Concurrency: 2}
+	results, err := BuildMatrix(ctx, targets, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binDir := filepath.Join(projectDir, "bin")
+	expectedPaths := []string{filepath.Join(binDir, "app_linux_amd64"), filepath.Join(binDir, "app_windows_amd64.exe"), filepath.Join(binDir, "app_plan10_amd64")}
+	for i, result := range results {
+		if result.Target != targets[i] || result.BinaryPath != expectedPaths[i] {
+			t.Errorf("Expected: %s %s, Got: %s %s", targets[i], expectedPaths[i], result.Target, result.BinaryPath)
+		}
+		if i < 2 && (result.Err != nil || result.Size == 0) {
+			t.Errorf("Expected %s to be built, Got: %v", result.Target, result.Err)
+		}
+	}
+	if results[2].Err == nil {
+		t.Error("Expecting an error for an unsupported GOOS")
+	}
+
+	results, err = BuildMatrix(ctx, targets[:1], BuildOptions{Package: "./cmd/tool", OutputDir: "bin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expectedPath := filepath.Join(binDir, "tool_linux_amd64"); results[0].BinaryPath != expectedPath || results[0].Err != nil {
+		t.Errorf("Expected %s to be built, Got: %s %v", expectedPath, results[0].BinaryPath, results[0].Err)
+	}
+}
+
+func TestBuildMatrixInvalidTemplate(t *testing.T) {
+	if _, err := BuildMatrix(context.Background(), []BuildTarget{{"linux", "amd64"}}, BuildOptions{Name: "app", OutputTemplate: "{{.Name"}); err == nil {
+		t.Error("Expecting an error for an invalid output template")
+	}
+}
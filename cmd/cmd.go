@@ -1,30 +1,39 @@
 package cmd
 
 import (
+	"context"
 	"errors"
-	gofrogcmd "github.com/jfrog/gofrog/io"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/gocmd/graph"
+	"github.com/jfrog/gocmd/log"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
-	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
-	"github.com/jfrog/jfrog-client-go/utils/log"
 	"io"
-	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
-var protocolRegExp *gofrogcmd.CmdOutputPattern
-var unrecognizedImportRegExp *gofrogcmd.CmdOutputPattern
-var notFoundRegExp *gofrogcmd.CmdOutputPattern
-var unknownRevisionRegExp *gofrogcmd.CmdOutputPattern
-var notFoundZipRegExp *gofrogcmd.CmdOutputPattern
-
-func NewCmd() (*Cmd, error) {
+// Creates a go command bound to ctx. Cancelling ctx kills the running go process.
+func NewCmd(ctx context.Context) (*Cmd, error) {
+	options := GetOptions(ctx)
+	executable := options.Executable
+	if executable == nil {
+		executable = getGoExecutable()
+	}
+	if executable == nil && options.Executor != nil {
+		// The executor may run without a go binary installed.
+		executable = &GoExecutable{Path: "go"}
+	}
+	if executable != nil {
+		return &Cmd{Go: executable.Path, Context: ctx, Env: options.Env, Dir: options.Dir}, nil
+	}
 	execPath, err := exec.LookPath("go")
 	if err != nil {
 		return nil, errorutils.CheckError(err)
 	}
-	return &Cmd{Go: execPath}, nil
+	return &Cmd{Go: execPath, Context: ctx, Env: options.Env, Dir: options.Dir}, nil
 }
 
 func (config *Cmd) GetCmd() *exec.Cmd {
@@ -32,9 +41,24 @@ func (config *Cmd) GetCmd() *exec.Cmd {
 	cmd = append(cmd, config.Go)
 	cmd = append(cmd, config.Command...)
 	cmd = append(cmd, config.CommandFlags...)
-	return exec.Command(cmd[0], cmd[1:]...)
+	var execCmd *exec.Cmd
+	if config.Context != nil {
+		execCmd = exec.CommandContext(config.Context, cmd[0], cmd[1:]...)
+	} else {
+		execCmd = exec.Command(cmd[0], cmd[1:]...)
+	}
+	if len(config.Env) > 0 {
+		execCmd.Env = os.Environ()
+		for key, value := range config.Env {
+			execCmd.Env = append(execCmd.Env, key+"="+value)
+		}
+	}
+	execCmd.Dir = config.Dir
+	return execCmd
 }
 
+// The environment variables of the command are set on the exec.Cmd returned by GetCmd,
+// rather than returned here, to avoid changing the environment of the whole process.
 func (config *Cmd) GetEnv() map[string]string {
 	return map[string]string{}
 }
@@ -48,147 +72,241 @@ func (config *Cmd) GetErrWriter() io.WriteCloser {
 }
 
 type Cmd struct {
-	Go           string
+	Context context.Context
+	Go      string
+	Env     map[string]string
+	// The working directory of the command. Defaults to the working directory of the process.
+	Dir          string
 	Command      []string
 	CommandFlags []string
 	StrWriter    io.WriteCloser
 	ErrWriter    io.WriteCloser
 }
 
-func GetGoVersion() (string, error) {
-	goCmd, err := NewCmd()
+func GetGoVersion(ctx context.Context) (string, error) {
+	goCmd, err := NewCmd(ctx)
 	if err != nil {
 		return "", err
 	}
 	goCmd.Command = []string{"version"}
-	output, err := gofrogcmd.RunCmdOutput(goCmd)
-	return output, errorutils.CheckError(err)
+	output, _, err := runCmdWithOutputParser(goCmd, false)
+	return output, errorutils.CheckError(contextError(ctx, err))
 }
 
-func RunGo(goArg []string) error {
-	goCmd, err := NewCmd()
-	if err != nil {
-		return err
-	}
-	goCmd.Command = goArg
-	err = prepareRegExp()
-	if err != nil {
-		return err
-	}
-	_, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, true, protocolRegExp, notFoundRegExp, unrecognizedImportRegExp, unknownRevisionRegExp, notFoundZipRegExp)
-	return errorutils.CheckError(err)
+func RunGo(ctx context.Context, goArg []string) error {
+	_, err := RunGoWithResult(ctx, goArg)
+	return err
 }
 
 // Using go mod download {dependency} command to download the dependency
-func DownloadDependency(dependencyName string) error {
-	goCmd, err := NewCmd()
+func DownloadDependency(ctx context.Context, dependencyName string) error {
+	if SkipInDryRunWithContext(ctx, "Running 'go mod download -json "+dependencyName+"'") {
+		return nil
+	}
+	goCmd, err := NewCmd(ctx)
 	if err != nil {
 		return err
 	}
-	log.Debug("Running go mod download -json", dependencyName)
+	getLogger(ctx).Debug("Running go mod download -json", dependencyName)
 	goCmd.Command = []string{"mod", "download", "-json", dependencyName}
-	return errorutils.CheckError(gofrogcmd.RunCmd(goCmd))
+	err = runWithRetries(ctx, "go mod download "+dependencyName, func() error {
+		output, _, err := runCmdWithOutputParser(goCmd, true)
+		getLogger(ctx).Debug(output)
+		return err
+	})
+	return errorutils.CheckError(contextError(ctx, err))
 }
 
 // Runs go mod graph command and returns slice of the dependencies
-func GetDependenciesGraph() (map[string]bool, error) {
-	pwd, err := os.Getwd()
+func GetDependenciesGraph(ctx context.Context) (map[string]bool, error) {
+	output, err := runGoModGraph(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return outputToMap(output), nil
+}
 
-	projectDir, err := GetProjectRoot()
-	if err != nil {
-		return nil, err
+// Returns the dependencies of the project, using the resolution mode of the options carried by ctx.
+func GetDependencies(ctx context.Context) (dependencies map[string]bool, err error) {
+	ctx, span := startSpan(ctx, "gocmd.GetDependencies")
+	defer func() {
+		span.SetAttribute(ModulesCountAttribute, len(dependencies))
+		span.End(err)
+	}()
+	switch GetOptions(ctx).Resolution {
+	case ListModulesResolution:
+		return GetDependenciesList(ctx)
+	case VendorResolution:
+		return getConsistentVendorDependencies(ctx)
 	}
+	return GetDependenciesGraph(ctx)
+}
 
-	// Read and store the details of the go.mod and go.sum files,
-	// because they may change by the "go mod graph" command.
-	modFileContent, modFileStat, err := GetFileDetails(filepath.Join(projectDir, "go.mod"))
+// Runs go mod graph command and returns the dependencies with the requirements between them.
+func GetDependencyGraph(ctx context.Context) (dependencyGraph *graph.DependencyGraph, err error) {
+	ctx, span := startSpan(ctx, "gocmd.GetDependencyGraph")
+	defer func() {
+		if dependencyGraph != nil {
+			span.SetAttribute(ModulesCountAttribute, len(dependencyGraph.Nodes()))
+		}
+		span.End(err)
+	}()
+	output, err := runGoModGraph(ctx)
 	if err != nil {
 		return nil, err
 	}
-	sumFileContent, sumFileStat, err := GetSumContentAndRemove(projectDir)
-	if len(sumFileContent) > 0 && sumFileStat != nil {
-		defer RestoreSumFile(projectDir, sumFileContent, sumFileStat)
-	}
+	return graph.ParseModGraph(output), nil
+}
 
-	log.Info("Running 'go mod graph' in", pwd)
-	goCmd, err := NewCmd()
+// Runs go mod graph command and returns its output.
+// The go.mod and go.sum files are left unchanged.
+func runGoModGraph(ctx context.Context) (string, error) {
+	return runWithUnchangedModFiles(ctx, true, "mod", "graph")
+}
+
+// Runs the go command and returns its output.
+// The go.mod and go.sum files are left unchanged. If removeSum, go.sum is removed while the command runs, and restored
+// once done. The go list commands need go.sum in place, since under the default -mod=readonly they fail on missing
+// go.sum entries rather than adding them.
+func runWithUnchangedModFiles(ctx context.Context, removeSum bool, args ...string) (output string, err error) {
+	description := "go " + strings.Join(args, " ")
+	start := time.Now()
+	EmitEvent(ctx, Event{Type: ResolutionStartedEvent, Command: description})
+	defer func() {
+		EmitEvent(ctx, Event{Type: ResolutionCompletedEvent, Command: description, Duration: time.Since(start), Err: err})
+	}()
+	pwd, err := getWorkingDir(ctx)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	goCmd.Command = []string{"mod", "graph"}
+	logger := getLogger(ctx).WithFields(log.Fields{"command": description, "dir": pwd})
 
-	err = prepareGlobalRegExp()
+	projectDir, err := findProjectRoot(GetFileSystem(ctx), pwd)
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	output, _, err := gofrogcmd.RunCmdWithOutputParser(goCmd, true, protocolRegExp, notFoundRegExp, unrecognizedImportRegExp, unknownRevisionRegExp)
-	if len(output) != 0 {
-		log.Debug(output)
+
+	resolutionStore := GetOptions(ctx).ResolutionStore
+	var cacheKey string
+	if resolutionStore != nil {
+		if cacheKey, err = getResolutionKey(ctx, projectDir, args); err != nil {
+			return "", err
+		}
+		// An unavailable store doesn't fail the resolution, which runs as if there were no store.
+		output, found, err := readCachedResolution(resolutionStore, cacheKey)
+		if err != nil {
+			logger.Warn("Failed reading the stored result of '"+description+"':", err.Error())
+		}
+		if found {
+			logger.Info("go.mod and go.sum are unchanged since the last '" + description + "'. Using its stored result.")
+			return output, nil
+		}
 	}
 
+	// Backup the go.mod and go.sum files, because they may change by the command.
+	// They are restored when done, to make sure they stay the same as before running the command.
+	backups, err := NewFileBackupManagerWithContext(ctx)
 	if err != nil {
-		// If the command fails, the mod stays the same, therefore, don't need to be restored.
-		return nil, errorutils.CheckError(err)
+		return "", err
 	}
-
-	// Restore the the go.mod and go.sum files, to make sure they stay the same as before
-	// running the "go mod graph" command.
-	err = ioutil.WriteFile(filepath.Join(projectDir, "go.mod"), modFileContent, modFileStat.Mode())
+	defer backups.Rollback()
+	defer backups.RecoverAndRollback()
+	err = backups.Backup(filepath.Join(projectDir, "go.mod"))
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if removeSum {
+		err = backups.BackupAndRemove(filepath.Join(projectDir, "go.sum"))
+	} else {
+		err = backups.Backup(filepath.Join(projectDir, "go.sum"))
+	}
+	if err != nil {
+		return "", err
 	}
 
-	return outputToMap(output), errorutils.CheckError(err)
-}
-
-// Using go mod download command to download all the dependencies before publishing to Artifactory
-func RunGoModTidy() error {
-	pwd, err := os.Getwd()
+	logger.Info("Running '"+description+"' in", pwd)
+	goCmd, err := NewCmd(ctx)
 	if err != nil {
-		return err
+		return "", err
 	}
+	goCmd.Command = args
 
-	log.Info("Running 'go mod tidy' in", pwd)
-	goCmd, err := NewCmd()
+	patterns, err := getPatterns(ctx, NotFoundZipPattern)
 	if err != nil {
-		return err
+		return "", err
+	}
+	err = runWithRetries(ctx, description, func() error {
+		var errorOutput string
+		output, errorOutput, err = runCmdWithOutputParser(goCmd, true, patterns...)
+		return checksumError(err, errorOutput)
+	})
+	if len(output) != 0 {
+		logger.Debug(output)
 	}
 
-	goCmd.Command = []string{"mod", "tidy"}
-	_, err = gofrogcmd.RunCmdOutput(goCmd)
-	return err
+	if err != nil {
+		return "", errorutils.CheckError(contextError(ctx, err))
+	}
+	if resolutionStore != nil {
+		if err = writeCachedResolution(resolutionStore, cacheKey, description, output); err != nil {
+			logger.Warn("Failed storing the result of '"+description+"':", err.Error())
+		}
+	}
+	return output, nil
 }
 
-func RunGoModInit(moduleName string) error {
-	pwd, err := os.Getwd()
+func RunGoModInit(ctx context.Context, moduleName string) error {
+	pwd, err := getWorkingDir(ctx)
 	if err != nil {
 		return err
 	}
 
-	log.Info("Running 'go mod init' in", pwd)
-	goCmd, err := NewCmd()
+	if SkipInDryRunWithContext(ctx, "Running 'go mod init "+moduleName+"' in "+pwd) {
+		return nil
+	}
+	getLogger(ctx).Info("Running 'go mod init' in", pwd)
+	goCmd, err := NewCmd(ctx)
 	if err != nil {
 		return err
 	}
 
 	goCmd.Command = []string{"mod", "init", moduleName}
-	_, _, err = gofrogcmd.RunCmdWithOutputParser(goCmd, true)
-	return err
+	_, _, err = runCmdWithOutputParser(goCmd, true)
+	return contextError(ctx, err)
 }
 
-// Returns the root dir where the go.mod located.
+// Returns the root dir where the go.mod located, searching from the working directory of the process up.
 func GetProjectRoot() (string, error) {
-	// Create a map to store all paths visited, to avoid running in circles.
-	visitedPaths := make(map[string]bool)
-	// Get the current directory.
 	wd, err := os.Getwd()
 	if err != nil {
 		return wd, errorutils.CheckError(err)
 	}
-	defer os.Chdir(wd)
+	return findProjectRoot(fsys.OsFileSystem{}, wd)
+}
+
+// Returns the root dir where the go.mod located, searching from the working directory of the go commands run with ctx up.
+func getProjectRoot(ctx context.Context) (string, error) {
+	wd, err := getWorkingDir(ctx)
+	if err != nil {
+		return wd, err
+	}
+	return findProjectRoot(GetFileSystem(ctx), wd)
+}
+
+// Returns the working directory of the go commands run with ctx.
+func getWorkingDir(ctx context.Context) (string, error) {
+	if dir := GetOptions(ctx).Dir; dir != "" {
+		return dir, nil
+	}
+	wd, err := os.Getwd()
+	return wd, errorutils.CheckError(err)
+}
+
+// Returns the first dir, starting from wd and going up, which includes a go.mod file.
+// Unlike changing the working directory, walking up the path is safe to run concurrently.
+func findProjectRoot(files fsys.FileSystem, wd string) (string, error) {
+	// Create a map to store all paths visited, to avoid running in circles.
+	visitedPaths := make(map[string]bool)
 
 	// Get the OS root.
 	osRoot := os.Getenv("SYSTEMDRIVE")
@@ -204,21 +322,20 @@ func GetProjectRoot() (string, error) {
 	// and so on.
 	for {
 		// If the go.mod is found the current directory, return the path.
-		exists, err := fileutils.IsFileExists(filepath.Join(wd, "go.mod"), false)
+		exists, err := fsys.IsFileExists(files, filepath.Join(wd, "go.mod"))
 		if err != nil || exists {
 			return wd, err
 		}
 
 		// If this the OS root, we can stop.
-		if wd == osRoot {
+		if fsys.SamePath(wd, osRoot) {
 			break
 		}
 
 		// Save this path.
 		visitedPaths[wd] = true
-		// CD to the parent directory.
+		// Move to the parent directory.
 		wd = filepath.Dir(wd)
-		os.Chdir(wd)
 
 		// If we already visited this directory, it means that there's a loop and we can stop.
 		if visitedPaths[wd] {
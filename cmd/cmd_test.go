@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"github.com/jfrog/gocmd/log"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -52,6 +57,14 @@ rsc.io/sampler@v1.3.0 golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c
 	}
 }
 
+func TestOutputToMapWindowsLineEndings(t *testing.T) {
+	content := "github.com/you/hello rsc.io/quote@v1.5.2\r\nrsc.io/quote@v1.5.2 rsc.io/sampler@v1.3.0\r\n"
+	expected := map[string]bool{"rsc.io/quote@v1.5.2": true, "rsc.io/sampler@v1.3.0": true}
+	if actual := outputToMap(content); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expecting: \n%v \nGot: \n%v", expected, actual)
+	}
+}
+
 func TestGetProjectDir(t *testing.T) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -120,3 +133,64 @@ func TestGetProjectDir(t *testing.T) {
 		t.Error("Expecting a different value than", root)
 	}
 }
+
+func TestGetSumContentAndRemoveDryRun(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "dryrun")
+	if err != nil {
+		t.Error(err)
+	}
+	defer os.RemoveAll(tempDir)
+	sumPath := filepath.Join(tempDir, "go.sum")
+	err = ioutil.WriteFile(sumPath, []byte("rsc.io/quote v1.5.2 h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=\n"), 0600)
+	if err != nil {
+		t.Error(err)
+	}
+
+	SetDryRun(true)
+	defer SetDryRun(false)
+	content, _, err := GetSumContentAndRemove(tempDir)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(content) == 0 {
+		t.Error("Expecting the go.sum content to be returned")
+	}
+	if _, err := os.Stat(sumPath); err != nil {
+		t.Error("Expecting go.sum not to be removed in dry run mode, got:", err)
+	}
+}
+
+func TestGetSumContentAndRemoveWithDryRunContext(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "dryrun")
+	if err != nil {
+		t.Error(err)
+	}
+	defer os.RemoveAll(tempDir)
+	sumPath := filepath.Join(tempDir, "go.sum")
+	err = ioutil.WriteFile(sumPath, []byte("rsc.io/quote v1.5.2 h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=\n"), 0600)
+	if err != nil {
+		t.Error(err)
+	}
+
+	var buffer bytes.Buffer
+	ctx := WithDryRun(WithOptions(context.Background(), &Options{Logger: log.NewTextLogger(&buffer, log.DebugLevel)}), true)
+	if _, _, err = GetSumContentAndRemoveWithContext(ctx, tempDir); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(sumPath); err != nil {
+		t.Error("Expecting go.sum not to be removed in the dry run mode of the context, got:", err)
+	}
+	if !strings.Contains(buffer.String(), "[Dry run] Removing file: "+sumPath) {
+		t.Error("Expecting the skipped removal to be logged to the logger of the context, got:", buffer.String())
+	}
+	if IsDryRun() {
+		t.Error("Expecting the dry run mode of the context not to enable the dry run mode of the process")
+	}
+
+	if _, _, err = GetSumContentAndRemoveWithContext(context.Background(), tempDir); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(sumPath); !os.IsNotExist(err) {
+		t.Error("Expecting go.sum to be removed without the dry run mode, got:", err)
+	}
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// The project files included in a diagnostics archive.
+var diagnosticsProjectFiles = []string{"go.mod", "go.sum", "go.work", "go.work.sum"}
+
+// The files of a diagnostics archive, in the order they are written.
+var diagnosticsFiles = append(append([]string{"version.txt", "env.json"}, diagnosticsProjectFiles...), "commands.jsonl", "errors.txt")
+
+// Options of WriteDiagnostics.
+type DiagnosticsOptions struct {
+	// The directory of the project whose go.mod and go.sum files are included. Defaults to the project root of ctx.
+	ProjectDir string
+	// The recent go commands, such as those recorded by a RecordingExecutor and read by ReadInvocationRecords.
+	Records []InvocationRecord
+}
+
+// Writes a gzipped tar archive to archivePath, with the information needed for investigating a failure:
+// the output of go version and go env, the go.mod and go.sum files of the project, and the recent go commands.
+// All the content is masked with MaskSecrets, and the environment variables whose names suggest secrets are masked.
+// Information which can't be collected is skipped, and the reason is written to errors.txt in the archive.
+func WriteDiagnostics(ctx context.Context, archivePath string, options DiagnosticsOptions) (err error) {
+	files := map[string][]byte{}
+	var collectErrors []string
+	addError := func(what string, err error) {
+		collectErrors = append(collectErrors, what+": "+MaskSecrets(err.Error()))
+	}
+
+	if version, err := GetGoVersion(ctx); err != nil {
+		addError("go version", err)
+	} else {
+		files["version.txt"] = []byte(MaskSecrets(version))
+	}
+	if env, err := GetGoEnv(ctx); err != nil {
+		addError("go env", err)
+	} else if files["env.json"], err = json.MarshalIndent(maskEnv(env.Values), "", "  "); err != nil {
+		addError("go env", err)
+	}
+
+	projectDir := options.ProjectDir
+	if projectDir == "" {
+		if projectDir, err = getProjectRoot(ctx); err != nil {
+			addError("project root", err)
+		}
+	}
+	if projectDir != "" {
+		for _, name := range diagnosticsProjectFiles {
+			content, err := ioutil.ReadFile(filepath.Join(projectDir, name))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				addError(name, err)
+				continue
+			}
+			files[name] = []byte(maskLines(string(content)))
+		}
+	}
+
+	var commands bytes.Buffer
+	encoder := json.NewEncoder(&commands)
+	for _, record := range options.Records {
+		record.Cmd = maskCredentials(record.Cmd)
+		record.Env = maskEnv(record.Env)
+		record.Stdout = maskLines(record.Stdout)
+		record.Stderr = maskLines(record.Stderr)
+		record.Err = MaskSecrets(record.Err)
+		if err := encoder.Encode(record); err != nil {
+			addError("commands", err)
+		}
+	}
+	if commands.Len() > 0 {
+		files["commands.jsonl"] = commands.Bytes()
+	}
+	if len(collectErrors) > 0 {
+		files["errors.txt"] = []byte(strings.Join(collectErrors, "\n") + "\n")
+	}
+	getLogger(ctx).Info("Writing diagnostics to", archivePath)
+	return writeDiagnosticsArchive(archivePath, files)
+}
+
+// Masks each line of the content separately, so that the rules don't match across lines.
+func maskLines(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = MaskSecrets(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeDiagnosticsArchive(archivePath string, files map[string][]byte) (err error) {
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		if closeErr := archive.Close(); err == nil {
+			err = errorutils.CheckError(closeErr)
+		}
+	}()
+	gzipWriter := gzip.NewWriter(archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	modTime := time.Now()
+	for _, name := range diagnosticsFiles {
+		content, exists := files[name]
+		if !exists {
+			continue
+		}
+		header := &tar.Header{Name: "diagnostics/" + name, Mode: 0644, Size: int64(len(content)), ModTime: modTime}
+		if err = tarWriter.WriteHeader(header); err != nil {
+			return errorutils.CheckError(err)
+		}
+		if _, err = tarWriter.Write(content); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	if err = tarWriter.Close(); err != nil {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(gzipWriter.Close())
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"sync"
+	"time"
+)
+
+// The result of downloading a single module.
+type DownloadStatus struct {
+	// The module in the "path@version" notation.
+	Module   string
+	Err      error
+	Duration time.Duration
+}
+
+// Downloads the modules, in the "path@version" notation, using the given number of concurrent workers.
+// The downloads are started at the rate allowed by the RateLimiter of ctx, if any.
+// If onStatus isn't nil, it is called as soon as each module is downloaded. Calls to onStatus are not concurrent.
+// Returns the statuses in the order of the modules, and an error if any of the downloads failed.
+func DownloadDependencies(ctx context.Context, modules []string, workers int, onStatus func(status DownloadStatus)) ([]DownloadStatus, error) {
+	return downloadDependencies(ctx, modules, workers, DownloadDependency, onStatus)
+}
+
+func downloadDependencies(ctx context.Context, modules []string, workers int, download func(ctx context.Context, module string) error, onStatus func(status DownloadStatus)) ([]DownloadStatus, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	statuses := make([]DownloadStatus, len(modules))
+	indexes := make(chan int)
+	var statusMutex sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				start := time.Now()
+				err := ctx.Err()
+				if err == nil {
+					err = GetOptions(ctx).RateLimiter.Wait(ctx)
+				}
+				if err == nil {
+					EmitEvent(ctx, Event{Type: ModuleDownloadStartedEvent, Module: modules[index]})
+					err = download(ctx, modules[index])
+					EmitEvent(ctx, Event{Type: ModuleDownloadedEvent, Module: modules[index], Duration: time.Since(start), Err: err})
+				}
+				status := DownloadStatus{Module: modules[index], Err: err, Duration: time.Since(start)}
+				statusMutex.Lock()
+				statuses[index] = status
+				if onStatus != nil {
+					onStatus(status)
+				}
+				statusMutex.Unlock()
+			}
+		}()
+	}
+	for index := range modules {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	failures := 0
+	for _, status := range statuses {
+		if status.Err != nil {
+			getLogger(ctx).Debug(fmt.Sprintf("Failed downloading %s: %s", status.Module, status.Err.Error()))
+			failures++
+		}
+	}
+	if failures > 0 {
+		return statuses, errorutils.CheckError(fmt.Errorf("Failed downloading %d out of %d modules.", failures, len(modules)))
+	}
+	return statuses, nil
+}
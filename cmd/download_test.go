@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadDependencies(t *testing.T) {
+	modules := []string{"rsc.io/quote@v1.5.2", "rsc.io/sampler@v1.3.0", "rsc.io/missing@v1.0.0", "golang.org/x/text@v0.3.0"}
+	var downloads int32
+	download := func(ctx context.Context, module string) error {
+		atomic.AddInt32(&downloads, 1)
+		if module == "rsc.io/missing@v1.0.0" {
+			return errors.New("404 Not Found:" + module)
+		}
+		return nil
+	}
+	reported := 0
+	statuses, err := downloadDependencies(context.Background(), modules, 3, download, func(status DownloadStatus) {
+		reported++
+	})
+	if err == nil {
+		t.Error("Expecting an error for the missing module")
+	}
+	if downloads != 4 || reported != 4 {
+		t.Error("Expecting 4 downloads and 4 reported statuses, got:", downloads, reported)
+	}
+	for i, status := range statuses {
+		if status.Module != modules[i] {
+			t.Error("Expecting status of", modules[i], "got:", status.Module)
+		}
+		if (status.Err != nil) != (status.Module == "rsc.io/missing@v1.0.0") {
+			t.Error("Unexpected error for", status.Module, ":", status.Err)
+		}
+	}
+}
+
+func TestDownloadDependenciesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	statuses, err := downloadDependencies(ctx, []string{"rsc.io/quote@v1.5.2"}, 2, func(ctx context.Context, module string) error {
+		t.Error("Expecting no downloads after cancellation")
+		return nil
+	}, nil)
+	if err == nil || statuses[0].Err != context.Canceled {
+		t.Error("Expecting the download to be cancelled, got:", err)
+	}
+}
+
+func TestDownloadDependenciesRateLimited(t *testing.T) {
+	ctx := WithRateLimiter(context.Background(), NewRateLimiter(20, 1))
+	start := time.Now()
+	_, err := downloadDependencies(ctx, []string{"rsc.io/quote@v1.5.2", "rsc.io/sampler@v1.3.0", "golang.org/x/text@v0.3.0"}, 3, func(ctx context.Context, module string) error {
+		return nil
+	}, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	// The first download starts at once, and the others wait for 1/20 second each.
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Error("Expecting the downloads to be rate limited, took:", elapsed)
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+var dryRun int32
+
+// Enables or disables the dry run mode of the process.
+// In dry run mode, file changes and go commands which change the project or the module cache are logged instead of performed.
+// Read only commands, such as go mod graph, still run, since their output is needed to plan the rest of the flow.
+// To enable the dry run mode of a single flow, use WithDryRun.
+func SetDryRun(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&dryRun, value)
+}
+
+func IsDryRun() bool {
+	return atomic.LoadInt32(&dryRun) == 1
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose operations run in dry run mode if enabled.
+// The operations run in dry run mode as well if it was enabled by SetDryRun.
+func WithDryRun(ctx context.Context, enabled bool) context.Context {
+	options := *GetOptions(ctx)
+	options.DryRun = enabled
+	return WithOptions(ctx, &options)
+}
+
+// Returns true if the operations run with ctx are in dry run mode, either by the options of ctx or by SetDryRun.
+func IsDryRunWithContext(ctx context.Context) bool {
+	return GetOptions(ctx).DryRun || IsDryRun()
+}
+
+// Returns true, after logging the action, if running in dry run mode.
+func SkipInDryRun(action string) bool {
+	return SkipInDryRunWithContext(context.Background(), action)
+}
+
+// Returns true, after logging the action to the logger of ctx, if the operations run with ctx are in dry run mode.
+func SkipInDryRunWithContext(ctx context.Context, action string) bool {
+	if IsDryRunWithContext(ctx) {
+		getLogger(ctx).Info("[Dry run]", action)
+		return true
+	}
+	return false
+}
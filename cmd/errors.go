@@ -0,0 +1,390 @@
+package cmd
+
+import (
+	"fmt"
+	gofrogio "github.com/jfrog/gofrog/io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var checksumMismatchRegExp = regexp.MustCompile(`^verifying (\S+): checksum mismatch`)
+
+// Implemented by the errors parsed from the output of the go commands.
+type GoError interface {
+	error
+	// Returns the output line the error was parsed from.
+	GetLine() string
+}
+
+// A module could not be found. Usually returned by a proxy with a 404 status.
+type ModuleNotFoundError struct {
+	// The module in the "path@version" notation.
+	Module     string
+	StatusCode int
+	// The status as it appears in the output, such as "404 Not Found".
+	Status string
+	Line   string
+}
+
+func (err *ModuleNotFoundError) Error() string {
+	return err.Status + ":" + err.Module
+}
+
+func (err *ModuleNotFoundError) GetLine() string {
+	return err.Line
+}
+
+// The import path of a module is not recognized.
+type UnrecognizedImportError struct {
+	Module string
+	Line   string
+}
+
+func (err *UnrecognizedImportError) Error() string {
+	return "unrecognized import path:" + err.Module
+}
+
+func (err *UnrecognizedImportError) GetLine() string {
+	return err.Line
+}
+
+// The requested revision of a module does not exist in the VCS.
+type UnknownRevisionError struct {
+	// The module in the "path@version" notation.
+	Module   string
+	Revision string
+	Line     string
+}
+
+func (err *UnknownRevisionError) Error() string {
+	return "unknown revision:" + err.Module
+}
+
+func (err *UnknownRevisionError) GetLine() string {
+	return err.Line
+}
+
+// Fetching a module from its git repository failed.
+type GitFetchError struct {
+	Repo       string
+	ExitStatus int
+	Line       string
+}
+
+func (err *GitFetchError) Error() string {
+	return fmt.Sprintf("git fetch exit status %d:%s", err.ExitStatus, err.Repo)
+}
+
+func (err *GitFetchError) GetLine() string {
+	return err.Line
+}
+
+// Fetching a module from its VCS is disallowed by GOVCS.
+type VcsDisallowedError struct {
+	// The module in the "path@version" notation, if reported.
+	Module string
+	// The disallowed version control system, such as "git".
+	Vcs string
+	// True if the module is private by GOPRIVATE.
+	Private bool
+	// The root of the module's repository.
+	Repo string
+	Line string
+}
+
+func (err *VcsDisallowedError) Error() string {
+	visibility := "public"
+	if err.Private {
+		visibility = "private"
+	}
+	return fmt.Sprintf("GOVCS disallows using %s for %s %s", err.Vcs, visibility, err.Repo)
+}
+
+func (err *VcsDisallowedError) GetLine() string {
+	return err.Line
+}
+
+// No version of a module matches the requested version query, such as a version which was never published.
+type NoMatchingVersionsError struct {
+	Module string
+	// The version query, such as "v1.2.3", "latest" or ">v1.2".
+	Query string
+	Line  string
+}
+
+func (err *NoMatchingVersionsError) Error() string {
+	return fmt.Sprintf("no matching versions for query %q:%s", err.Query, err.Module)
+}
+
+func (err *NoMatchingVersionsError) GetLine() string {
+	return err.Line
+}
+
+// A module was found for a package path, but the module doesn't contain the package.
+type PackageNotInModuleError struct {
+	Module string
+	// The version of the module which was found.
+	Version string
+	Package string
+	Line    string
+}
+
+func (err *PackageNotInModuleError) Error() string {
+	return fmt.Sprintf("module %s@%s does not contain package:%s", err.Module, err.Version, err.Package)
+}
+
+func (err *PackageNotInModuleError) GetLine() string {
+	return err.Line
+}
+
+// The go.sum file has no hash for a module, or for the module providing a package.
+type MissingGoSumEntryError struct {
+	// The module in the "path@version" notation, if reported.
+	Module string
+	// The imported package, if the entry is missing for the module providing it.
+	Package string
+	// True if the missing entry is of the module's go.mod file.
+	IsMod bool
+	Line  string
+}
+
+func (err *MissingGoSumEntryError) Error() string {
+	if err.Package != "" {
+		return "missing go.sum entry for package:" + err.Package
+	}
+	if err.IsMod {
+		return "missing go.sum entry:" + err.Module + "/go.mod"
+	}
+	return "missing go.sum entry:" + err.Module
+}
+
+func (err *MissingGoSumEntryError) GetLine() string {
+	return err.Line
+}
+
+// None of the modules required by go.mod provides an imported package.
+type NoRequiredModuleError struct {
+	Package string
+	Line    string
+}
+
+func (err *NoRequiredModuleError) Error() string {
+	return "no required module provides package:" + err.Package
+}
+
+func (err *NoRequiredModuleError) GetLine() string {
+	return err.Line
+}
+
+// The version of a module is invalid, such as a v2 version of a module path without a /v2 suffix.
+// Unknown revisions are returned as an UnknownRevisionError.
+type InvalidVersionError struct {
+	// The module in the "path@version" notation.
+	Module string
+	// Why the version is invalid, as reported by the go command.
+	Reason string
+	Line   string
+}
+
+func (err *InvalidVersionError) Error() string {
+	return "invalid version:" + err.Module + ": " + err.Reason
+}
+
+func (err *InvalidVersionError) GetLine() string {
+	return err.Line
+}
+
+// The hash of a downloaded module doesn't match the expected hash, from go.sum or from the checksum database.
+type ChecksumMismatchError struct {
+	// The module in the "path@version" notation.
+	Module string
+	// True if the mismatch is of the module's go.mod file.
+	IsMod bool
+	// The hash of the downloaded module.
+	Actual string
+	// The expected hash and its source, such as "go.sum" or "sum.golang.org".
+	Expected       string
+	ExpectedSource string
+	Line           string
+}
+
+func (err *ChecksumMismatchError) Error() string {
+	module := err.Module
+	if err.IsMod {
+		module += "/go.mod"
+	}
+	return fmt.Sprintf("checksum mismatch:%s: downloaded %s, %s %s", module, err.Actual, err.ExpectedSource, err.Expected)
+}
+
+func (err *ChecksumMismatchError) GetLine() string {
+	return err.Line
+}
+
+// Several errors parsed from the output of a single go command, such as a 404 of each of several modules,
+// so all of them can be fixed at once.
+type MultiError struct {
+	Errors []error
+}
+
+// Returns the messages of the errors, one per line.
+func (err *MultiError) Error() string {
+	messages := make([]string, len(err.Errors))
+	for i, e := range err.Errors {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Returns the errors, which allows errors.Is and errors.As to match any of them.
+func (err *MultiError) Unwrap() []error {
+	return err.Errors
+}
+
+// The exit code the go command exits with when it's used incorrectly, such as with an unknown flag or command.
+const UsageExitCode = 2
+
+// A go command exited with a non-zero exit code.
+// If its output matched patterns returning errors, such as a ModuleNotFoundError, Err is the error of the patterns,
+// so errors.As matches both the ExitError and the typed error.
+type ExitError struct {
+	// The go command without its arguments, such as "go build".
+	Command  string
+	ExitCode int
+	// The output the command printed to stderr.
+	Stderr string
+	// The error returned by the patterns, or the underlying error, such as an *exec.ExitError.
+	Err error
+}
+
+func (err *ExitError) Error() string {
+	return err.Err.Error()
+}
+
+func (err *ExitError) Unwrap() error {
+	return err.Err
+}
+
+// Returns true if the go command failed because it was used incorrectly, rather than because of the project,
+// such as a compilation failure.
+func (err *ExitError) IsUsageError() bool {
+	return err.ExitCode == UsageExitCode
+}
+
+// Parses a checksum mismatch from the error output of a go command. The mismatch spans several lines:
+//
+//	verifying github.com/jfrog/gocmd@v0.1.0: checksum mismatch
+//		downloaded: h1:...
+//		go.sum:     h1:...
+//
+// Returns nil if the output has no checksum mismatch.
+func parseChecksumMismatch(output string) *ChecksumMismatchError {
+	var mismatch *ChecksumMismatchError
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if mismatch == nil {
+			if match := checksumMismatchRegExp.FindStringSubmatch(trimmed); match != nil {
+				mismatch = &ChecksumMismatchError{Module: match[1], Line: trimmed}
+				if strings.HasSuffix(mismatch.Module, "/go.mod") {
+					mismatch.Module = strings.TrimSuffix(mismatch.Module, "/go.mod")
+					mismatch.IsMod = true
+				}
+			}
+			continue
+		}
+		sourceAndHash := strings.SplitN(trimmed, ":", 2)
+		if len(sourceAndHash) != 2 || !strings.HasPrefix(strings.TrimSpace(sourceAndHash[1]), "h1:") {
+			break
+		}
+		if sourceAndHash[0] == "downloaded" {
+			mismatch.Actual = strings.TrimSpace(sourceAndHash[1])
+		} else {
+			mismatch.ExpectedSource = sourceAndHash[0]
+			mismatch.Expected = strings.TrimSpace(sourceAndHash[1])
+		}
+	}
+	return mismatch
+}
+
+// Returns the checksum mismatch parsed from the error output of a failed go command, or err otherwise.
+func checksumError(err error, errorOutput string) error {
+	if err == nil {
+		return nil
+	}
+	if mismatch := parseChecksumMismatch(errorOutput); mismatch != nil {
+		return mismatch
+	}
+	return err
+}
+
+// Handles the not found patterns. Expects the module in the first group and the status in the second.
+func ModuleNotFound(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	status := strings.TrimSpace(pattern.MatchedResults[2])
+	statusCode, _ := strconv.Atoi(strings.Fields(status)[0])
+	return "", &ModuleNotFoundError{Module: strings.TrimSpace(pattern.MatchedResults[1]), StatusCode: statusCode, Status: status, Line: pattern.Line}
+}
+
+// Handles the unrecognized import path pattern. Expects the module in the first group.
+func UnrecognizedImport(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	return "", &UnrecognizedImportError{Module: strings.TrimSpace(pattern.MatchedResults[1]), Line: pattern.Line}
+}
+
+// Handles the unknown revision pattern. Expects the module in the first group.
+// The revision is the text following "unknown revision" in the line.
+func UnknownRevision(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	module := strings.TrimSpace(pattern.MatchedResults[1])
+	revision := ""
+	if index := strings.Index(pattern.Line, "unknown revision"); index >= 0 {
+		revision = strings.TrimSpace(pattern.Line[index+len("unknown revision"):])
+	}
+	if revision == "" {
+		if nameAndVersion := strings.SplitN(module, "@", 2); len(nameAndVersion) == 2 {
+			revision = nameAndVersion[1]
+		}
+	}
+	return "", &UnknownRevisionError{Module: module, Revision: revision, Line: pattern.Line}
+}
+
+// Handles the git fetch pattern. Expects the repository in the first group and the exit status in the second.
+func GitFetchFailed(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	exitStatus, _ := strconv.Atoi(pattern.MatchedResults[2])
+	return "", &GitFetchError{Repo: pattern.MatchedResults[1], ExitStatus: exitStatus, Line: pattern.Line}
+}
+
+// Handles the GOVCS pattern. Expects the module in the first group, which may be empty, the version control system
+// in the second, the visibility in the third and the repository root in the fourth.
+func VcsDisallowed(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	return "", &VcsDisallowedError{Module: pattern.MatchedResults[1], Vcs: pattern.MatchedResults[2], Private: pattern.MatchedResults[3] == "private", Repo: pattern.MatchedResults[4], Line: pattern.Line}
+}
+
+// Handles the no matching versions pattern. Expects the module in the first group and the query in the second.
+func NoMatchingVersions(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	return "", &NoMatchingVersionsError{Module: pattern.MatchedResults[1], Query: pattern.MatchedResults[2], Line: pattern.Line}
+}
+
+// Handles the package not in module pattern. Expects the module in the first group, the version in the second
+// and the package in the third.
+func PackageNotInModule(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	return "", &PackageNotInModuleError{Module: pattern.MatchedResults[1], Version: pattern.MatchedResults[2], Package: pattern.MatchedResults[3], Line: pattern.Line}
+}
+
+// Handles the missing go.sum entry pattern. Expects the module in the first group, the package in the second
+// and the go.mod file indication in the third. Any of the groups may be empty.
+func MissingGoSumEntry(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	return "", &MissingGoSumEntryError{Module: pattern.MatchedResults[1], Package: pattern.MatchedResults[2], IsMod: pattern.MatchedResults[3] != "", Line: pattern.Line}
+}
+
+// Handles the no required module pattern. Expects the package in the first group.
+func NoRequiredModule(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	return "", &NoRequiredModuleError{Package: pattern.MatchedResults[1], Line: pattern.Line}
+}
+
+// Handles the invalid version pattern. Expects the module in the first group and the reason in the second.
+// Unknown revisions are handled as by UnknownRevision.
+func InvalidVersion(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	reason := strings.TrimSpace(pattern.MatchedResults[2])
+	if strings.HasPrefix(reason, "unknown revision") {
+		return UnknownRevision(pattern)
+	}
+	return "", &InvalidVersionError{Module: pattern.MatchedResults[1], Reason: reason, Line: pattern.Line}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestTypedErrors(t *testing.T) {
+	registry, err := NewDefaultPatternRegistry()
+	if err != nil {
+		t.Error(err)
+	}
+	tests := []struct {
+		name     string
+		line     string
+		expected error
+	}{
+		{"notFound", "go: github.com/pkg/errors@v0.8.1: 404 Not Found",
+			&ModuleNotFoundError{Module: "github.com/pkg/errors@v0.8.1", StatusCode: 404, Status: "404 Not Found", Line: "go: github.com/pkg/errors@v0.8.1: 404 Not Found"}},
+		{"unknownRevision", "go: github.com/pkg/errors@v0.8.9: unknown revision v0.8.9",
+			&UnknownRevisionError{Module: "github.com/pkg/errors@v0.8.9", Revision: "v0.8.9", Line: "go: github.com/pkg/errors@v0.8.9: unknown revision v0.8.9"}},
+		{"unrecognizedImport", "go: golang.org/x/lint@v0.1.0: unrecognized import path \"golang.org/x/lint\"",
+			&UnrecognizedImportError{Module: "golang.org/x/lint@v0.1.0", Line: "go: golang.org/x/lint@v0.1.0: unrecognized import path \"golang.org/x/lint\""}},
+		{"gitFetch", "go: github.com/pkg/errors@v0.8.1: git fetch -f https://github.com/pkg/errors refs/heads/*:refs/heads/* in /tmp/vcs: exit status 128",
+			&GitFetchError{Repo: "https://github.com/pkg/errors", ExitStatus: 128, Line: "go: github.com/pkg/errors@v0.8.1: git fetch -f https://github.com/pkg/errors refs/heads/*:refs/heads/* in /tmp/vcs: exit status 128"}},
+		{"vcsDisallowed", "go: github.com/mycorp/lib@v1.0.0: unrecognized import path \"github.com/mycorp/lib\": GOVCS disallows using git for public github.com/mycorp/lib; see 'go help vcs'",
+			&VcsDisallowedError{Module: "github.com/mycorp/lib@v1.0.0", Vcs: "git", Repo: "github.com/mycorp/lib", Line: "go: github.com/mycorp/lib@v1.0.0: unrecognized import path \"github.com/mycorp/lib\": GOVCS disallows using git for public github.com/mycorp/lib; see 'go help vcs'"}},
+		{"noMatchingVersions", "go: module github.com/pkg/errors: no matching versions for query \"v9\"",
+			&NoMatchingVersionsError{Module: "github.com/pkg/errors", Query: "v9", Line: "go: module github.com/pkg/errors: no matching versions for query \"v9\""}},
+		{"noMatchingVersionsGoGet", "go get github.com/pkg/errors@v9: no matching versions for query \"v9\"",
+			&NoMatchingVersionsError{Module: "github.com/pkg/errors", Query: "v9", Line: "go get github.com/pkg/errors@v9: no matching versions for query \"v9\""}},
+		{"unknownRevisionInvalidVersion", "go: github.com/pkg/errors@v0.8.9: invalid version: unknown revision v0.8.9",
+			&UnknownRevisionError{Module: "github.com/pkg/errors@v0.8.9", Revision: "v0.8.9", Line: "go: github.com/pkg/errors@v0.8.9: invalid version: unknown revision v0.8.9"}},
+		{"invalidVersion", "go: github.com/pkg/errors@v2.0.0: invalid version: should be v0 or v1, not v2",
+			&InvalidVersionError{Module: "github.com/pkg/errors@v2.0.0", Reason: "should be v0 or v1, not v2", Line: "go: github.com/pkg/errors@v2.0.0: invalid version: should be v0 or v1, not v2"}},
+		{"packageNotInModule", "go: module github.com/pkg/errors@latest found (v0.9.1), but does not contain package github.com/pkg/errors/wrap",
+			&PackageNotInModuleError{Module: "github.com/pkg/errors", Version: "v0.9.1", Package: "github.com/pkg/errors/wrap", Line: "go: module github.com/pkg/errors@latest found (v0.9.1), but does not contain package github.com/pkg/errors/wrap"}},
+		{"packageNotInReplacedModule", "go: module github.com/pkg/errors@upgrade found (v0.9.1, replaced by ../errors), but does not contain package github.com/pkg/errors/wrap",
+			&PackageNotInModuleError{Module: "github.com/pkg/errors", Version: "v0.9.1", Package: "github.com/pkg/errors/wrap", Line: "go: module github.com/pkg/errors@upgrade found (v0.9.1, replaced by ../errors), but does not contain package github.com/pkg/errors/wrap"}},
+		{"missingGoSumEntryPackage", "main.go:4:2: missing go.sum entry for module providing package github.com/pkg/errors (imported by example.com/m); to add:",
+			&MissingGoSumEntryError{Package: "github.com/pkg/errors", Line: "main.go:4:2: missing go.sum entry for module providing package github.com/pkg/errors (imported by example.com/m); to add:"}},
+		{"missingGoSumEntryModule", "go: github.com/pkg/errors@v0.9.1: missing go.sum entry; to add it:",
+			&MissingGoSumEntryError{Module: "github.com/pkg/errors@v0.9.1", Line: "go: github.com/pkg/errors@v0.9.1: missing go.sum entry; to add it:"}},
+		{"missingGoSumEntryGoMod", "go: github.com/pkg/errors@v0.9.1: missing go.sum entry for go.mod file; to add it:",
+			&MissingGoSumEntryError{Module: "github.com/pkg/errors@v0.9.1", IsMod: true, Line: "go: github.com/pkg/errors@v0.9.1: missing go.sum entry for go.mod file; to add it:"}},
+		{"noRequiredModule", "main.go:3:8: no required module provides package github.com/pkg/errors; to add it:",
+			&NoRequiredModuleError{Package: "github.com/pkg/errors", Line: "main.go:3:8: no required module provides package github.com/pkg/errors; to add it:"}},
+		{"cannotFindModule", "main.go:3:8: cannot find module providing package github.com/pkg/errors: working directory is not part of a module",
+			&NoRequiredModuleError{Package: "github.com/pkg/errors", Line: "main.go:3:8: cannot find module providing package github.com/pkg/errors: working directory is not part of a module"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual error
+			for _, pattern := range registry.Patterns() {
+				pattern.MatchedResults = pattern.RegExp.FindStringSubmatch(test.line)
+				if len(pattern.MatchedResults) == 0 {
+					continue
+				}
+				pattern.Line = test.line
+				_, actual = pattern.ExecFunc(pattern)
+				break
+			}
+			if !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("Test name: %s: Expected: %#v, Got: %#v", test.name, test.expected, actual)
+			}
+			if _, ok := actual.(GoError); !ok {
+				t.Errorf("Test name: %s: Expected a GoError, Got: %T", test.name, actual)
+			}
+		})
+	}
+}
+
+func TestParseChecksumMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected *ChecksumMismatchError
+	}{
+		{"goSum", "go: downloading rsc.io/quote v1.5.2\nverifying rsc.io/quote@v1.5.2: checksum mismatch\n\tdownloaded: h1:AAAA=\n\tgo.sum:     h1:BBBB=\n\nSECURITY ERROR\n",
+			&ChecksumMismatchError{Module: "rsc.io/quote@v1.5.2", Actual: "h1:AAAA=", Expected: "h1:BBBB=", ExpectedSource: "go.sum", Line: "verifying rsc.io/quote@v1.5.2: checksum mismatch"}},
+		{"sumDb", "verifying rsc.io/quote@v1.5.2/go.mod: checksum mismatch\n\tdownloaded: h1:AAAA=\n\tsum.golang.org: h1:CCCC=\n",
+			&ChecksumMismatchError{Module: "rsc.io/quote@v1.5.2", IsMod: true, Actual: "h1:AAAA=", Expected: "h1:CCCC=", ExpectedSource: "sum.golang.org", Line: "verifying rsc.io/quote@v1.5.2/go.mod: checksum mismatch"}},
+		{"noMismatch", "go: rsc.io/quote@v1.5.2: 404 Not Found\n", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := parseChecksumMismatch(test.output)
+			if !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("Test name: %s: Expected: %#v, Got: %#v", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExitError(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stderr: "./main.go:3:1: syntax error\n", ExitCode: 1}, "build", "./...")
+	tests := []struct {
+		name               string
+		ctx                context.Context
+		args               []string
+		expectedExitCode   int
+		expectedUsageError bool
+	}{
+		{"usage", context.Background(), []string{"no-such-command"}, 2, true},
+		{"compilation", WithExecutor(context.Background(), executor), []string{"build", "./..."}, 1, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := RunGo(test.ctx, test.args)
+			exitErr, ok := err.(*ExitError)
+			if !ok {
+				t.Fatalf("Expected an ExitError, Got: %v", err)
+			}
+			if exitErr.ExitCode != test.expectedExitCode || exitErr.IsUsageError() != test.expectedUsageError || exitErr.Unwrap() == nil || exitErr.Stderr == "" {
+				t.Errorf("Unexpected error: %+v", exitErr)
+			}
+		})
+	}
+}
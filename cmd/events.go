@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"time"
+)
+
+// The phases reported to the EventListeners. Each phase which takes time has a started and a completed event.
+type EventType string
+
+const (
+	// Resolving the dependencies of the project, with a go command such as go mod graph or go list -m all.
+	ResolutionStartedEvent   EventType = "resolutionStarted"
+	ResolutionCompletedEvent EventType = "resolutionCompleted"
+	// Downloading a module by DownloadDependencies.
+	ModuleDownloadStartedEvent EventType = "moduleDownloadStarted"
+	ModuleDownloadedEvent      EventType = "moduleDownloaded"
+	// Removing a file of the project, such as go.sum before resolving the dependencies. The file is restored when done.
+	FileRemovedEvent EventType = "fileRemoved"
+	// Publishing a module to a registry.
+	PublishStartedEvent   EventType = "publishStarted"
+	PublishCompletedEvent EventType = "publishCompleted"
+)
+
+// A phase of an operation, reported to the EventListeners. Only the fields relevant to the type of the event are set.
+type Event struct {
+	Type EventType
+	// The time the event was emitted.
+	Time time.Time
+	// The go command of the resolution events, such as "go mod graph".
+	Command string
+	// The module of the download and publish events, such as "github.com/jfrog/gocmd@v0.1.0".
+	Module string
+	// The file of the file events.
+	Path string
+	// The time the phase took, for the completed events.
+	Duration time.Duration
+	// The error the phase failed with, for the completed events. Nil if it succeeded.
+	Err error
+}
+
+// Receives the events of the operations run with a context returned by WithEventListener, for example for audit logging
+// or for showing progress. The listener is called synchronously, and may be called concurrently by concurrent operations.
+type EventListener interface {
+	OnEvent(ctx context.Context, event Event)
+}
+
+// Adapts a function to an EventListener.
+type EventListenerFunc func(ctx context.Context, event Event)
+
+func (listener EventListenerFunc) OnEvent(ctx context.Context, event Event) {
+	listener(ctx, event)
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose events are also sent to the listener.
+// The listeners are called in the order they were added.
+func WithEventListener(ctx context.Context, listener EventListener) context.Context {
+	options := *GetOptions(ctx)
+	options.EventListeners = append(append([]EventListener(nil), options.EventListeners...), listener)
+	return WithOptions(ctx, &options)
+}
+
+// Sends the event to the listeners of the options carried by ctx. The time of the event is set if it's zero.
+func EmitEvent(ctx context.Context, event Event) {
+	listeners := GetOptions(ctx).EventListeners
+	if len(listeners) == 0 {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	for _, listener := range listeners {
+		listener.OnEvent(ctx, event)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"github.com/jfrog/gocmd/fsys"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type recordingEventListener struct {
+	mutex  sync.Mutex
+	events []Event
+}
+
+func (listener *recordingEventListener) OnEvent(ctx context.Context, event Event) {
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+	listener.events = append(listener.events, event)
+}
+
+// Returns the types of the received events, with the module, command or path of each.
+func (listener *recordingEventListener) summary() []string {
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+	var summary []string
+	for _, event := range listener.events {
+		summary = append(summary, string(event.Type)+" "+event.Module+event.Command+event.Path)
+	}
+	return summary
+}
+
+func TestEmitEvent(t *testing.T) {
+	// No listeners.
+	EmitEvent(context.Background(), Event{Type: ResolutionStartedEvent})
+
+	var order []string
+	ctx := WithEventListener(context.Background(), EventListenerFunc(func(ctx context.Context, event Event) {
+		order = append(order, "first")
+		if event.Time.IsZero() {
+			t.Error("Expecting the time of the event to be set")
+		}
+	}))
+	other := WithEventListener(ctx, EventListenerFunc(func(ctx context.Context, event Event) {
+		order = append(order, "second")
+	}))
+	EmitEvent(other, Event{Type: ResolutionStartedEvent})
+	if !reflect.DeepEqual([]string{"first", "second"}, order) {
+		t.Error("Expecting the listeners to be called in the order they were added, got:", order)
+	}
+	order = nil
+	EmitEvent(ctx, Event{Type: ResolutionStartedEvent})
+	if !reflect.DeepEqual([]string{"first"}, order) {
+		t.Error("Expecting the parent context to keep its own listeners, got:", order)
+	}
+}
+
+func TestResolutionEvents(t *testing.T) {
+	projectDir := filepath.FromSlash("/project")
+	modPath, sumPath := filepath.Join(projectDir, "go.mod"), filepath.Join(projectDir, "go.sum")
+	fileSystem := fsys.NewMemFileSystem(map[string]string{modPath: "module github.com/jfrog/project", sumPath: "sum"})
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: "github.com/jfrog/project rsc.io/quote@v1.5.2\n"}, "mod", "graph")
+	listener := &recordingEventListener{}
+	ctx := WithOptions(context.Background(), &Options{Dir: projectDir, Executor: executor, FileSystem: fileSystem, EventListeners: []EventListener{listener}})
+
+	if _, err := runGoModGraph(ctx); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"resolutionStarted go mod graph", "fileRemoved " + sumPath, "resolutionCompleted go mod graph"}
+	if !reflect.DeepEqual(expected, listener.summary()) {
+		t.Errorf("Expecting: %v, Got: %v", expected, listener.summary())
+	}
+	if fileSystem.Files()[sumPath] != "sum" {
+		t.Error("Expecting go.sum to be restored")
+	}
+}
+
+func TestModuleDownloadEvents(t *testing.T) {
+	listener := &recordingEventListener{}
+	ctx := WithEventListener(context.Background(), listener)
+	download := func(ctx context.Context, module string) error {
+		if module == "rsc.io/sampler@v1.3.0" {
+			return errors.New("not found")
+		}
+		return nil
+	}
+	modules := []string{"rsc.io/quote@v1.5.2", "rsc.io/sampler@v1.3.0"}
+	if _, err := downloadDependencies(ctx, modules, 2, download, nil); err == nil {
+		t.Error("Expecting the failed download to fail the downloads")
+	}
+	summary := listener.summary()
+	sort.Strings(summary)
+	expected := []string{
+		"moduleDownloadStarted rsc.io/quote@v1.5.2", "moduleDownloadStarted rsc.io/sampler@v1.3.0",
+		"moduleDownloaded rsc.io/quote@v1.5.2", "moduleDownloaded rsc.io/sampler@v1.3.0",
+	}
+	if !reflect.DeepEqual(expected, summary) {
+		t.Errorf("Expecting: %v, Got: %v", expected, summary)
+	}
+	for _, event := range listener.events {
+		if event.Type == ModuleDownloadedEvent && (event.Err != nil) != (event.Module == "rsc.io/sampler@v1.3.0") {
+			t.Errorf("Unexpected error of %s: %v", event.Module, event.Err)
+		}
+	}
+}
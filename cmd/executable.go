@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var goExecutable *GoExecutable
+var goExecutableMutex sync.Mutex
+
+// Go versions, such as "1.21", "1.21.3" and "1.22rc1".
+var goVersionRegExp = regexp.MustCompile(`^1(\.(0|[1-9][0-9]*)){1,2}((rc|beta)[1-9][0-9]*)?$`)
+
+// Represents the go binary used to run the go commands.
+type GoExecutable struct {
+	Path string
+	// The version reported by go version, such as "1.12.5". Development builds have the "devel" version.
+	Version string
+}
+
+// The go binary is older than the minimum version required by the caller.
+type UnsupportedGoVersionError struct {
+	Path            string
+	Version         string
+	RequiredVersion string
+}
+
+func (err *UnsupportedGoVersionError) Error() string {
+	return fmt.Sprintf("Go %s or above is required, but %s is of version %s.", err.RequiredVersion, err.Path, err.Version)
+}
+
+// Locates the go binary and detects its version.
+// An explicit path takes precedence. Otherwise, the go binary of GOROOT is used if GOROOT is set, and the one in the PATH if not.
+func FindGoExecutable(ctx context.Context, explicitPath string) (*GoExecutable, error) {
+	path, err := findGoPath(explicitPath)
+	if err != nil {
+		return nil, err
+	}
+	goCmd := &Cmd{Context: ctx, Go: path, Env: GetOptions(ctx).Env, Command: []string{"version"}}
+	output, _, err := runCmdWithOutputParser(goCmd, false)
+	if err != nil {
+		return nil, errorutils.CheckError(contextError(ctx, err))
+	}
+	version, err := parseGoVersion(output)
+	if err != nil {
+		return nil, err
+	}
+	return &GoExecutable{Path: path, Version: version}, nil
+}
+
+// Sets the go binary used by the go commands of this package. By default, the go binary in the PATH is used.
+//
+// Deprecated: the go binary applies to all the go commands of the process. Use the Executable of a Runner instead.
+func SetGoExecutable(executable *GoExecutable) {
+	goExecutableMutex.Lock()
+	defer goExecutableMutex.Unlock()
+	goExecutable = executable
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands run with the go binary.
+func WithExecutable(ctx context.Context, executable *GoExecutable) context.Context {
+	options := *GetOptions(ctx)
+	options.Executable = executable
+	return WithOptions(ctx, &options)
+}
+
+func getGoExecutable() *GoExecutable {
+	goExecutableMutex.Lock()
+	defer goExecutableMutex.Unlock()
+	return goExecutable
+}
+
+// Returns an UnsupportedGoVersionError if the go binary is older than minVersion, such as "1.12".
+// Development builds are considered as the newest version.
+func (executable *GoExecutable) RequireVersion(minVersion string) error {
+	if executable.Version == "devel" || CompareGoVersions(executable.Version, minVersion) >= 0 {
+		return nil
+	}
+	return errorutils.CheckError(&UnsupportedGoVersionError{Path: executable.Path, Version: executable.Version, RequiredVersion: minVersion})
+}
+
+// Returns true if the version is a go version, such as "1.21", "1.21.3" or "1.22rc1", as in the go directive of go.mod.
+func IsGoVersion(version string) bool {
+	return goVersionRegExp.MatchString(version)
+}
+
+// Compares two go versions such as "1.12", "1.12.5" or "1.13beta1".
+// Returns a negative number if version1 < version2, zero if equal and a positive number if version1 > version2.
+// A pre-release is older than the release of the same version.
+func CompareGoVersions(version1, version2 string) int {
+	numbers1, preRelease1 := splitGoVersion(version1)
+	numbers2, preRelease2 := splitGoVersion(version2)
+	for i := 0; i < len(numbers1) || i < len(numbers2); i++ {
+		var number1, number2 int
+		if i < len(numbers1) {
+			number1 = numbers1[i]
+		}
+		if i < len(numbers2) {
+			number2 = numbers2[i]
+		}
+		if number1 != number2 {
+			return number1 - number2
+		}
+	}
+	switch {
+	case preRelease1 == preRelease2:
+		return 0
+	case preRelease1 == "":
+		return 1
+	case preRelease2 == "":
+		return -1
+	}
+	return comparePreReleases(preRelease1, preRelease2)
+}
+
+// Compares pre-release suffixes such as "beta1" and "rc10": first by their labels, so that betas precede release
+// candidates, and then by their numbers, so that "rc10" follows "rc2".
+func comparePreReleases(preRelease1, preRelease2 string) int {
+	label1 := strings.TrimRight(preRelease1, "0123456789")
+	label2 := strings.TrimRight(preRelease2, "0123456789")
+	if label1 != label2 {
+		return strings.Compare(label1, label2)
+	}
+	number1, _ := strconv.Atoi(preRelease1[len(label1):])
+	number2, _ := strconv.Atoi(preRelease2[len(label2):])
+	return number1 - number2
+}
+
+// Splits a version such as "1.13beta1" to its numbers [1 13] and its pre-release suffix "beta1".
+func splitGoVersion(version string) (numbers []int, preRelease string) {
+	version = strings.TrimPrefix(version, "go")
+	for _, part := range strings.Split(version, ".") {
+		digits := len(part) - len(strings.TrimLeft(part, "0123456789"))
+		number, _ := strconv.Atoi(part[:digits])
+		numbers = append(numbers, number)
+		if digits < len(part) {
+			preRelease = part[digits:]
+			break
+		}
+	}
+	return
+}
+
+// Parses the output of go version, such as "go version go1.12.5 linux/amd64".
+func parseGoVersion(output string) (string, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 || fields[0] != "go" || fields[1] != "version" {
+		return "", errorutils.CheckError(fmt.Errorf("Unexpected go version output: %s", output))
+	}
+	if fields[2] == "devel" {
+		return "devel", nil
+	}
+	return strings.TrimPrefix(fields[2], "go"), nil
+}
+
+func findGoPath(explicitPath string) (string, error) {
+	if explicitPath != "" {
+		return explicitPath, nil
+	}
+	if goRoot := os.Getenv("GOROOT"); goRoot != "" {
+		binaryName := "go"
+		if runtime.GOOS == "windows" {
+			binaryName += ".exe"
+		}
+		path := filepath.Join(goRoot, "bin", binaryName)
+		exists, err := fileutils.IsFileExists(path, false)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return path, nil
+		}
+	}
+	path, err := exec.LookPath("go")
+	return path, errorutils.CheckError(err)
+}
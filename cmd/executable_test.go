@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestCompareGoVersions(t *testing.T) {
+	tests := []struct {
+		version1 string
+		version2 string
+		expected int
+	}{
+		{"1.12", "1.12", 0},
+		{"1.12.0", "1.12", 0},
+		{"1.12.5", "1.12", 1},
+		{"1.11.9", "1.12", -1},
+		{"1.13beta1", "1.13", -1},
+		{"1.13", "1.13rc1", 1},
+		{"1.13beta1", "1.13rc1", -1},
+		{"1.22beta10", "1.22beta9", 1},
+		{"1.21rc10", "1.21rc2", 1},
+		{"1.21rc2", "1.21rc2", 0},
+		{"1.2", "1.10", -1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version1+"_"+test.version2, func(t *testing.T) {
+			actual := CompareGoVersions(test.version1, test.version2)
+			if sign(actual) != test.expected {
+				t.Errorf("Comparing %s to %s: Expected: %d, Got: %d", test.version1, test.version2, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRequireVersion(t *testing.T) {
+	version, err := parseGoVersion("go version go1.11.4 linux/amd64")
+	if err != nil {
+		t.Error(err)
+	}
+	executable := &GoExecutable{Path: "/usr/local/go/bin/go", Version: version}
+	if err := executable.RequireVersion("1.11"); err != nil {
+		t.Error(err)
+	}
+	if _, ok := executable.RequireVersion("1.12").(*UnsupportedGoVersionError); !ok {
+		t.Error("Expecting an UnsupportedGoVersionError for go", version)
+	}
+}
+
+func sign(number int) int {
+	switch {
+	case number > 0:
+		return 1
+	case number < 0:
+		return -1
+	}
+	return 0
+}
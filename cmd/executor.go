@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Runs the commands of this package. Set an executor on the options of a context to run the go commands
+// with it instead of the go binary, for example with a FakeExecutor to test flows without a Go toolchain installed.
+type Executor interface {
+	// Runs the command, whose first element is the executable, with the environment variables added to those of the process,
+	// in dir, or in the working directory of the process if dir is empty.
+	// Returns the output of the command and its exit code. The error is only returned if the command couldn't run.
+	Run(ctx context.Context, cmd []string, env map[string]string, dir string) (stdout, stderr string, exitCode int, err error)
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands are run by the executor.
+func WithExecutor(ctx context.Context, executor Executor) context.Context {
+	options := *GetOptions(ctx)
+	options.Executor = executor
+	return WithOptions(ctx, &options)
+}
+
+// Runs the commands as processes.
+type ProcessExecutor struct{}
+
+func (executor *ProcessExecutor) Run(ctx context.Context, cmd []string, env map[string]string, dir string) (string, string, int, error) {
+	execCmd := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
+	if len(env) > 0 {
+		execCmd.Env = os.Environ()
+		for key, value := range env {
+			execCmd.Env = append(execCmd.Env, key+"="+value)
+		}
+	}
+	execCmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	execCmd.Stdout = &stdout
+	execCmd.Stderr = &stderr
+	err := execCmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return stdout.String(), stderr.String(), exitErr.ExitCode(), nil
+	}
+	return stdout.String(), stderr.String(), 0, err
+}
+
+// A command run by a FakeExecutor.
+type ExecutorCall struct {
+	Cmd []string
+	Env map[string]string
+	Dir string
+}
+
+// The result returned by a FakeExecutor for a command.
+type ExecutorResult struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Err      error
+}
+
+// Records the commands it is asked to run, without running them, and returns the results they were set to return.
+// Safe for concurrent use.
+type FakeExecutor struct {
+	mutex   sync.Mutex
+	results map[string]ExecutorResult
+	calls   []ExecutorCall
+	// Returned for commands without a result.
+	DefaultResult ExecutorResult
+}
+
+func NewFakeExecutor() *FakeExecutor {
+	return &FakeExecutor{results: map[string]ExecutorResult{}}
+}
+
+// Sets the result returned for the command with the arguments, such as "mod", "graph". The executable isn't matched.
+func (executor *FakeExecutor) On(result ExecutorResult, args ...string) {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+	executor.results[strings.Join(args, " ")] = result
+}
+
+// Returns the commands run so far, in the order they were run.
+func (executor *FakeExecutor) Calls() []ExecutorCall {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+	return append([]ExecutorCall(nil), executor.calls...)
+}
+
+func (executor *FakeExecutor) Run(ctx context.Context, cmd []string, env map[string]string, dir string) (string, string, int, error) {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+	envCopy := map[string]string{}
+	for key, value := range env {
+		envCopy[key] = value
+	}
+	executor.calls = append(executor.calls, ExecutorCall{Cmd: append([]string(nil), cmd...), Env: envCopy, Dir: dir})
+	result, ok := executor.results[strings.Join(cmd[1:], " ")]
+	if !ok {
+		result = executor.DefaultResult
+	}
+	return result.Stdout, result.Stderr, result.ExitCode, result.Err
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFakeExecutor(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stderr: "go: github.com/pkg/errors@v0.8.1: 404 Not Found\n", ExitCode: 1}, "mod", "download", "github.com/pkg/errors@v0.8.1")
+	executor.DefaultResult = ExecutorResult{Stdout: "go version go1.12.5 linux/amd64\n"}
+	runner, err := NewRunner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner.Executor = executor
+	runner.Env = map[string]string{"GOPROXY": "off"}
+	runner.Dir = "/project"
+	ctx := runner.WithContext(context.Background())
+
+	version, err := GetGoVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(version) != "go version go1.12.5 linux/amd64" {
+		t.Errorf("Unexpected go version: %s", version)
+	}
+	err = RunGo(ctx, []string{"mod", "download", "github.com/pkg/errors@v0.8.1"})
+	var notFoundErr *ModuleNotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("Expected a ModuleNotFoundError, Got: %v", err)
+	}
+
+	expected := []ExecutorCall{
+		{Cmd: []string{"go", "version"}, Env: map[string]string{"GOPROXY": "off"}, Dir: "/project"},
+		{Cmd: []string{"go", "mod", "download", "github.com/pkg/errors@v0.8.1"}, Env: map[string]string{"GOPROXY": "off"}, Dir: "/project"},
+	}
+	if !reflect.DeepEqual(executor.Calls(), expected) {
+		t.Errorf("Expected calls: %v, Got: %v", expected, executor.Calls())
+	}
+}
+
+func TestProcessExecutor(t *testing.T) {
+	ctx := WithExecutor(context.Background(), &ProcessExecutor{})
+	version, err := GetGoVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(version, "go version go") {
+		t.Errorf("Unexpected go version: %s", version)
+	}
+	if err = RunGo(ctx, []string{"no-such-command"}); err == nil || err.Error() != "exit status 2" {
+		t.Errorf("Expected exit status 2, Got: %v", err)
+	}
+}
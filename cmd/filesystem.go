@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/fsys"
+)
+
+// Returns a copy of ctx carrying the options of ctx, whose operations read and write the go.mod and go.sum files
+// through the file system, for example to refuse changing a read-only source tree with fsys.ReadOnly.
+// The go commands themselves still access the files on disk.
+func WithFileSystem(ctx context.Context, fileSystem fsys.FileSystem) context.Context {
+	options := *GetOptions(ctx)
+	options.FileSystem = fileSystem
+	return WithOptions(ctx, &options)
+}
+
+// Returns the file system of ctx, or the files on disk if none was set.
+func GetFileSystem(ctx context.Context) fsys.FileSystem {
+	if fileSystem := GetOptions(ctx).FileSystem; fileSystem != nil {
+		return fileSystem
+	}
+	return fsys.OsFileSystem{}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/fsys"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileBackupManagerWithFileSystem(t *testing.T) {
+	modPath, sumPath := filepath.Join("project", "go.mod"), filepath.Join("project", "go.sum")
+	fileSystem := fsys.NewMemFileSystem(map[string]string{modPath: "module original", sumPath: "sum original"})
+	manager, err := NewFileBackupManagerWithContext(WithFileSystem(context.Background(), fileSystem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = manager.Backup(modPath); err != nil {
+		t.Error(err)
+	}
+	if err = manager.BackupAndRemove(sumPath); err != nil {
+		t.Error(err)
+	}
+	if err = fileSystem.WriteFile(modPath, []byte("module changed"), 0644); err != nil {
+		t.Error(err)
+	}
+	if exists, _ := fsys.IsFileExists(fileSystem, sumPath); exists {
+		t.Error("Expecting go.sum to be removed from the file system")
+	}
+
+	if err = manager.Rollback(); err != nil {
+		t.Error(err)
+	}
+	files := fileSystem.Files()
+	if files[modPath] != "module original" || files[sumPath] != "sum original" {
+		t.Error("Expecting the files to be restored, got:", files)
+	}
+}
+
+func TestFileBackupManagerWithReadOnlyFileSystem(t *testing.T) {
+	sumPath := filepath.Join("project", "go.sum")
+	fileSystem := fsys.ReadOnly(fsys.NewMemFileSystem(map[string]string{sumPath: "sum original"}))
+	manager, err := NewFileBackupManagerWithContext(WithFileSystem(context.Background(), fileSystem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Rollback()
+	if err = manager.BackupAndRemove(sumPath); err == nil || err.(*os.PathError).Err != fsys.ErrReadOnly {
+		t.Error("Expecting removing go.sum to fail with ErrReadOnly, got:", err)
+	}
+}
+
+func TestGetProjectRootWithFileSystem(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "project")
+	fileSystem := fsys.NewMemFileSystem(map[string]string{filepath.Join(root, "go.mod"): "module example.com/project"})
+	options := &Options{Dir: filepath.Join(root, "internal", "pkg"), FileSystem: fileSystem}
+	projectRoot, err := getProjectRoot(WithOptions(context.Background(), options))
+	if err != nil || projectRoot != root {
+		t.Error("Expecting", root, "got", projectRoot, err)
+	}
+}
+
+func TestSumFileWithFileSystem(t *testing.T) {
+	sumPath := filepath.Join("project", "go.sum")
+	fileSystem := fsys.NewMemFileSystem(map[string]string{sumPath: "sum original"})
+	ctx := WithFileSystem(context.Background(), fileSystem)
+	content, stat, err := GetSumContentAndRemoveWithContext(ctx, "project")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "sum original" {
+		t.Errorf("Unexpected go.sum content: %q", content)
+	}
+	if exists, _ := fsys.IsFileExists(fileSystem, sumPath); exists {
+		t.Error("Expecting go.sum to be removed from the file system")
+	}
+	if err = RestoreSumFileWithContext(ctx, "project", content, stat); err != nil {
+		t.Fatal(err)
+	}
+	if restored := fileSystem.Files()[sumPath]; restored != "sum original" {
+		t.Errorf("Expecting go.sum to be restored to the file system, got: %q", restored)
+	}
+}
@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const generatePrefix = "//go:generate "
+
+// Flags of go generate, and the generators to install before running it.
+type GenerateOptions struct {
+	// The packages to run go generate in, "./..." if empty.
+	Packages []string
+	// Runs only the directives matching the regular expression (-run).
+	Run string
+	// The go install targets of generators, by the name of their binary, such as
+	// "stringer": "golang.org/x/tools/cmd/stringer@v0.1.0". Generators referenced by the directives are installed to a
+	// temp directory, which is added to the PATH of go generate.
+	Tools map[string]string
+}
+
+// A //go:generate directive.
+type GenerateDirective struct {
+	File string
+	Line int
+	// The command and its arguments, as they appear after "//go:generate".
+	Command string
+	// The binary the directive runs, such as "stringer", or the package of "go run pkg@version" directives.
+	// Empty for other go commands, such as "go tool yacc".
+	Tool string
+}
+
+// The changes go generate made, to the files in the directories of the packages. Paths are absolute.
+type GenerateResult struct {
+	Directives []GenerateDirective
+	// The go install targets of the generators which were installed.
+	Installed []string
+	Added     []string
+	Modified  []string
+	Removed   []string
+}
+
+// Runs go generate in the packages and returns the files it changed.
+// Before running, installs the generators of the directives found in options.Tools. Returns an error if a generator
+// is neither in options.Tools nor in the PATH.
+func RunGenerate(ctx context.Context, options GenerateOptions) (*GenerateResult, error) {
+	packages := options.Packages
+	if len(packages) == 0 {
+		packages = []string{"./..."}
+	}
+	dirs, err := getPackagesDirs(ctx, packages)
+	if err != nil {
+		return nil, err
+	}
+	result := &GenerateResult{}
+	if result.Directives, err = FindGenerateDirectives(dirs); err != nil {
+		return nil, err
+	}
+	if SkipInDryRunWithContext(ctx, "Running 'go generate "+strings.Join(packages, " ")+"'") {
+		return result, nil
+	}
+
+	tempDirs := getTempDirProvider(ctx)
+	binDir, err := tempDirs.MkdirTemp("gocmd-generate")
+	if err != nil {
+		return nil, err
+	}
+	defer tempDirs.Remove(binDir, false)
+	if result.Installed, err = installGenerators(ctx, result.Directives, options.Tools, binDir); err != nil {
+		return nil, err
+	}
+	if len(result.Installed) > 0 {
+		ctx = WithEnv(ctx, "PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	}
+
+	before, err := snapshotDirs(dirs)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"generate"}
+	if options.Run != "" {
+		args = append(args, "-run", options.Run)
+	}
+	if err = RunGo(ctx, append(args, packages...)); err != nil {
+		return nil, err
+	}
+	after, err := snapshotDirs(dirs)
+	if err != nil {
+		return nil, err
+	}
+	result.Added, result.Modified, result.Removed = diffSnapshots(before, after)
+	return result, nil
+}
+
+// Returns the //go:generate directives of the go files in the directories, in the order they appear.
+func FindGenerateDirectives(dirs []string) ([]GenerateDirective, error) {
+	var directives []GenerateDirective
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		for _, file := range files {
+			content, err := ioutil.ReadFile(file)
+			if err != nil {
+				return nil, errorutils.CheckError(err)
+			}
+			directives = append(directives, parseGenerateDirectives(file, content)...)
+		}
+	}
+	return directives, nil
+}
+
+func parseGenerateDirectives(file string, content []byte) []GenerateDirective {
+	var directives []GenerateDirective
+	// Aliases defined by "//go:generate -command alias command args...".
+	aliases := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if !strings.HasPrefix(line, generatePrefix) {
+			continue
+		}
+		command := strings.TrimSpace(strings.TrimPrefix(line, generatePrefix))
+		fields := splitGenerateCommand(command)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "-command" {
+			if len(fields) >= 3 {
+				aliases[fields[1]] = getGenerateTool(fields[2:])
+			}
+			continue
+		}
+		tool, isAlias := aliases[fields[0]]
+		if !isAlias {
+			tool = getGenerateTool(fields)
+		}
+		directives = append(directives, GenerateDirective{File: file, Line: lineNumber, Command: command, Tool: tool})
+	}
+	return directives
+}
+
+// Returns the binary the command runs, or the package of "go run" commands.
+func getGenerateTool(fields []string) string {
+	if fields[0] != "go" {
+		return fields[0]
+	}
+	if len(fields) < 2 || fields[1] != "run" {
+		return ""
+	}
+	for _, field := range fields[2:] {
+		if !strings.HasPrefix(field, "-") {
+			return field
+		}
+	}
+	return ""
+}
+
+// Splits the command to its words. Double quoted strings are single words, as go generate parses them.
+func splitGenerateCommand(command string) []string {
+	var fields []string
+	for command = strings.TrimSpace(command); command != ""; command = strings.TrimSpace(command) {
+		if command[0] == '"' {
+			end := 1
+			for ; end < len(command) && command[end] != '"'; end++ {
+				if command[end] == '\\' {
+					end++
+				}
+			}
+			if end < len(command) {
+				if unquoted, err := strconv.Unquote(command[:end+1]); err == nil {
+					fields = append(fields, unquoted)
+					command = command[end+1:]
+					continue
+				}
+			}
+		}
+		end := strings.IndexAny(command, " \t")
+		if end < 0 {
+			end = len(command)
+		}
+		fields = append(fields, command[:end])
+		command = command[end:]
+	}
+	return fields
+}
+
+// Installs the generators of the directives found in tools to binDir. Returns the installed targets.
+func installGenerators(ctx context.Context, directives []GenerateDirective, tools map[string]string, binDir string) ([]string, error) {
+	var installed []string
+	var missing []string
+	handled := map[string]bool{}
+	for _, directive := range directives {
+		tool := directive.Tool
+		// Packages of "go run" directives are built by go generate, and paths of binaries are not looked up.
+		if tool == "" || handled[tool] || strings.ContainsAny(tool, `/\`) {
+			continue
+		}
+		handled[tool] = true
+		target, exists := tools[tool]
+		if !exists {
+			if _, err := exec.LookPath(tool); err != nil {
+				missing = append(missing, tool)
+			}
+			continue
+		}
+		getLogger(ctx).Info("Installing the generator", target)
+		if err := RunGo(WithEnv(ctx, "GOBIN", binDir), []string{"install", target}); err != nil {
+			return installed, err
+		}
+		installed = append(installed, target)
+	}
+	if len(missing) > 0 {
+		return installed, errorutils.CheckError(fmt.Errorf("The generators %s are not in the PATH. Add their go install targets to the generate options.", strings.Join(missing, ", ")))
+	}
+	return installed, nil
+}
+
+// Returns the directories of the packages, using go list.
+func getPackagesDirs(ctx context.Context, packages []string) ([]string, error) {
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = append([]string{"list", "-f", "{{.Dir}}"}, packages...)
+	output, _, err := runCmdWithOutputParser(goCmd, true)
+	if err != nil {
+		return nil, errorutils.CheckError(contextError(ctx, err))
+	}
+	return getLines([]byte(output)), nil
+}
+
+// Returns the SHA-1 of each of the files in the directories, by their absolute path.
+func snapshotDirs(dirs []string) (map[string][sha1.Size]byte, error) {
+	snapshot := map[string][sha1.Size]byte{}
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		for _, file := range files {
+			if !file.Mode().IsRegular() {
+				continue
+			}
+			path := filepath.Join(dir, file.Name())
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, errorutils.CheckError(err)
+			}
+			snapshot[path] = sha1.Sum(content)
+		}
+	}
+	return snapshot, nil
+}
+
+func diffSnapshots(before, after map[string][sha1.Size]byte) (added, modified, removed []string) {
+	for path, hash := range after {
+		beforeHash, exists := before[path]
+		switch {
+		case !exists:
+			added = append(added, path)
+		case beforeHash != hash:
+			modified = append(modified, path)
+		}
+	}
+	for path := range before {
+		if _, exists := after[path]; !exists {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(modified)
+	sort.Strings(removed)
+	return
+}
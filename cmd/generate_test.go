@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseGenerateDirectives(t *testing.T) {
+	content := `package a
+
+//go:generate stringer -type=Color
+//go:generate go run golang.org/x/tools/cmd/stringer@v0.1.0 -type=Size
+//go:generate -command yacc go tool yacc
+//go:generate yacc -o "gen file.go" parser.y
+//go:generate go run -mod=mod ./gen
+// go:generate ignored
+`
+	expected := []GenerateDirective{
+		{"a.go", 3, "stringer -type=Color", "stringer"},
+		{"a.go", 4, "go run golang.org/x/tools/cmd/stringer@v0.1.0 -type=Size", "golang.org/x/tools/cmd/stringer@v0.1.0"},
+		{"a.go", 6, `yacc -o "gen file.go" parser.y`, ""},
+		{"a.go", 7, "go run -mod=mod ./gen", "./gen"},
+	}
+	if actual := parseGenerateDirectives("a.go", []byte(content)); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+	expectedFields := []string{"yacc", "-o", "gen file.go", `a"b`, "parser.y"}
+	if actual := splitGenerateCommand(`yacc -o "gen file.go"  "a\"b" parser.y`); !reflect.DeepEqual(expectedFields, actual) {
+		t.Errorf("Expected: %v, Got: %v", expectedFields, actual)
+	}
+}
+
+func TestRunGenerate(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "generateTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	files := map[string]string{
+		"go.mod":  "module example.com/a\n",
+		"a.go":    "package a\n\n//go:generate sh -c \"echo package a > gen.go && rm old.txt && echo changed > b.txt\"\n",
+		"old.txt": "old\n",
+		"b.txt":   "b\n",
+	}
+	for name, content := range files {
+		if err = ioutil.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err = os.Chdir(projectDir); err != nil {
+		t.Fatal(err)
+	}
+	// The temp directory may be a symbolic link, such as on macOS, while go list reports the real path.
+	projectDir, err = os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RunGenerate(context.Background(), GenerateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &GenerateResult{
+		Directives: []GenerateDirective{{filepath.Join(projectDir, "a.go"), 3, `sh -c "echo package a > gen.go && rm old.txt && echo changed > b.txt"`, "sh"}},
+		Added:      []string{filepath.Join(projectDir, "gen.go")},
+		Modified:   []string{filepath.Join(projectDir, "b.txt")},
+		Removed:    []string{filepath.Join(projectDir, "old.txt")},
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, result)
+	}
+
+	if err = ioutil.WriteFile("c.go", []byte("package a\n\n//go:generate gocmd-missing-generator\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = RunGenerate(context.Background(), GenerateOptions{}); err == nil {
+		t.Error("Expecting an error for a generator which is not in the PATH")
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/graph"
+	"github.com/jfrog/gocmd/semver"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Flags of go get.
+type GoGetOptions struct {
+	// Also updates the dependencies of the packages to newer minor or patch releases (-u).
+	UpdateDependencies bool
+	// Updates the dependencies of the packages to newer patch releases only (-u=patch).
+	PatchUpdatesOnly bool
+	// Also gets the dependencies of the packages' tests (-t).
+	Tests bool
+	// Additional flags, such as "-insecure".
+	Flags []string
+}
+
+// The changes go get made to the requirements of go.mod. Requirements are in the "path@version" notation.
+type GetResult struct {
+	Added      []string
+	Removed    []string
+	Upgraded   []graph.VersionChange
+	Downgraded []graph.VersionChange
+}
+
+// Returns true if go get didn't change the requirements.
+func (result *GetResult) IsEmpty() bool {
+	return len(result.Added) == 0 && len(result.Removed) == 0 && len(result.Upgraded) == 0 && len(result.Downgraded) == 0
+}
+
+// Runs go get for the packages, such as "github.com/pkg/errors@v0.8.1", and returns the changes it made to the
+// requirements of go.mod. Failures to resolve the packages are returned as ExitErrors wrapping the typed errors of
+// this package, such as ModuleNotFoundError, UnknownRevisionError and NoMatchingVersionsError.
+func GoGet(ctx context.Context, packages []string, options GoGetOptions) (*GetResult, error) {
+	projectDir, err := getProjectRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	modPath := filepath.Join(projectDir, "go.mod")
+	modBefore, err := readFileIfExists(GetFileSystem(ctx), modPath)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"get"}
+	switch {
+	case options.PatchUpdatesOnly:
+		args = append(args, "-u=patch")
+	case options.UpdateDependencies:
+		args = append(args, "-u")
+	}
+	if options.Tests {
+		args = append(args, "-t")
+	}
+	args = append(append(args, options.Flags...), packages...)
+	if err = RunGo(ctx, args); err != nil {
+		return nil, err
+	}
+
+	modAfter, err := readFileIfExists(GetFileSystem(ctx), modPath)
+	if err != nil {
+		return nil, err
+	}
+	requirementsBefore, err := getRequirements(modBefore)
+	if err != nil {
+		return nil, err
+	}
+	requirementsAfter, err := getRequirements(modAfter)
+	if err != nil {
+		return nil, err
+	}
+	return diffRequirements(requirementsBefore, requirementsAfter), nil
+}
+
+// Compares requirements in the "path@version" notation.
+func diffRequirements(before, after []string) *GetResult {
+	versionsBefore := requirementsToMap(before)
+	versionsAfter := requirementsToMap(after)
+	result := &GetResult{}
+	for path, version := range versionsAfter {
+		versionBefore, exists := versionsBefore[path]
+		switch {
+		case !exists:
+			result.Added = append(result.Added, path+"@"+version)
+		case semver.Compare(version, versionBefore) > 0:
+			result.Upgraded = append(result.Upgraded, graph.VersionChange{Path: path, Before: versionBefore, After: version})
+		case semver.Compare(version, versionBefore) < 0:
+			result.Downgraded = append(result.Downgraded, graph.VersionChange{Path: path, Before: versionBefore, After: version})
+		}
+	}
+	for path, version := range versionsBefore {
+		if _, exists := versionsAfter[path]; !exists {
+			result.Removed = append(result.Removed, path+"@"+version)
+		}
+	}
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sortVersionChanges(result.Upgraded)
+	sortVersionChanges(result.Downgraded)
+	return result
+}
+
+func requirementsToMap(requirements []string) map[string]string {
+	versions := map[string]string{}
+	for _, requirement := range requirements {
+		if pathAndVersion := strings.SplitN(requirement, "@", 2); len(pathAndVersion) == 2 {
+			versions[pathAndVersion[0]] = pathAndVersion[1]
+		}
+	}
+	return versions
+}
+
+func sortVersionChanges(changes []graph.VersionChange) {
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+}
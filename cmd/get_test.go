@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/jfrog/gocmd/graph"
+	"reflect"
+	"testing"
+)
+
+func TestDiffRequirements(t *testing.T) {
+	before := []string{"rsc.io/quote@v1.5.2", "github.com/pkg/errors@v0.8.1", "golang.org/x/text@v0.3.0", "rsc.io/sampler@v1.3.0"}
+	after := []string{"rsc.io/quote@v1.5.3", "github.com/pkg/errors@v0.8.0", "golang.org/x/text@v0.3.0", "golang.org/x/lint@v0.1.0"}
+	expected := &GetResult{
+		Added:      []string{"golang.org/x/lint@v0.1.0"},
+		Removed:    []string{"rsc.io/sampler@v1.3.0"},
+		Upgraded:   []graph.VersionChange{{Path: "rsc.io/quote", Before: "v1.5.2", After: "v1.5.3"}},
+		Downgraded: []graph.VersionChange{{Path: "github.com/pkg/errors", Before: "v0.8.1", After: "v0.8.0"}},
+	}
+	if actual := diffRequirements(before, after); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+	if !diffRequirements(before, before).IsEmpty() {
+		t.Error("Expecting no changes between identical requirements")
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// The environment variables the askpass script reads the credentials from. They are set only for the go commands.
+const (
+	gitUsernameEnv = "GOCMD_GIT_USERNAME"
+	gitPasswordEnv = "GOCMD_GIT_PASSWORD"
+)
+
+// Answers the username prompt of git with the username, and any other prompt with the password.
+const unixAskPassScript = `#!/bin/sh
+case "$1" in
+Username*) printf '%s\n' "$` + gitUsernameEnv + `" ;;
+*) printf '%s\n' "$` + gitPasswordEnv + `" ;;
+esac
+`
+
+// The prompt is read with %~1, which strips its quotes, before delayed expansion is enabled, so "!" in it is kept.
+// The credentials are expanded with delayed expansion, whose values aren't parsed again, so they may contain special
+// characters such as "&", "%" and "!". "echo(" prints empty values and values such as "off" as they are.
+const windowsAskPassScript = `@echo off
+set "GOCMD_GIT_PROMPT=%~1"
+setlocal EnableDelayedExpansion
+if "!GOCMD_GIT_PROMPT:~0,8!"=="Username" (
+	echo(!` + gitUsernameEnv + `!
+) else (
+	echo(!` + gitPasswordEnv + `!
+)
+`
+
+// Credentials for fetching modules directly from their git repositories, as done for the "direct" entry of GOPROXY.
+type GitCredentials struct {
+	// Answered to the prompts of git for HTTPS repositories, through a GIT_ASKPASS script.
+	Username string
+	Password string
+	// The private key for SSH repositories, passed to ssh through GIT_SSH_COMMAND.
+	SshKeyPath string
+	// The known hosts file the SSH hosts are strictly checked against. If empty, the known hosts of the user are used.
+	SshKnownHostsPath string
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands fetch the git repositories with the credentials,
+// without changing the git configuration of the user or the environment of the process.
+// The askpass script is written to a temp directory of the TempDirProvider of ctx. The returned function removes it,
+// and should be called when done.
+func WithGitCredentials(ctx context.Context, credentials GitCredentials) (context.Context, func(), error) {
+	hasHttps := credentials.Username != "" || credentials.Password != ""
+	if !hasHttps && credentials.SshKeyPath == "" {
+		return nil, nil, errorutils.CheckError(errors.New("The git credentials have neither a username and password nor an SSH key."))
+	}
+	cleanup := func() {}
+	if credentials.SshKeyPath != "" {
+		sshCommand, err := gitSshCommand(credentials)
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx = WithEnv(ctx, "GIT_SSH_COMMAND", sshCommand)
+	}
+	if hasHttps {
+		tempDirs := getTempDirProvider(ctx)
+		dir, err := tempDirs.MkdirTemp("gocmd-git")
+		if err != nil {
+			return nil, nil, err
+		}
+		cleanup = func() {
+			if err := tempDirs.Remove(dir, false); err != nil {
+				getLogger(ctx).Debug("Failed removing the askpass script directory", dir, err.Error())
+			}
+		}
+		scriptPath, err := writeAskPassScript(dir, runtime.GOOS)
+		if err != nil {
+			cleanup()
+			return nil, nil, err
+		}
+		ctx = WithEnv(ctx, "GIT_ASKPASS", scriptPath)
+		ctx = WithEnv(ctx, gitUsernameEnv, credentials.Username)
+		ctx = WithEnv(ctx, gitPasswordEnv, credentials.Password)
+	}
+	return ctx, cleanup, nil
+}
+
+// Writes the askpass script for the OS to the directory, and returns its path.
+func writeAskPassScript(dir, goos string) (string, error) {
+	scriptPath, script := filepath.Join(dir, "askpass.sh"), unixAskPassScript
+	if goos == "windows" {
+		scriptPath, script = filepath.Join(dir, "askpass.cmd"), windowsAskPassScript
+	}
+	return scriptPath, errorutils.CheckError(ioutil.WriteFile(scriptPath, []byte(script), 0700))
+}
+
+// Returns the ssh command authenticating with the key of the credentials only, without prompting.
+// git runs the command with a shell, so the paths are quoted.
+func gitSshCommand(credentials GitCredentials) (string, error) {
+	args := []string{"ssh"}
+	for _, option := range []struct {
+		path   string
+		format string
+	}{
+		{credentials.SshKeyPath, "-i %s -o IdentitiesOnly=yes"},
+		{credentials.SshKnownHostsPath, "-o UserKnownHostsFile=%s -o StrictHostKeyChecking=yes"},
+	} {
+		if option.path == "" {
+			continue
+		}
+		if _, err := os.Stat(option.path); err != nil {
+			return "", errorutils.CheckError(err)
+		}
+		if strings.ContainsAny(option.path, `'"`) {
+			return "", errorutils.CheckError(fmt.Errorf("The SSH path %s contains quotes, which GIT_SSH_COMMAND doesn't support.", option.path))
+		}
+		args = append(args, fmt.Sprintf(option.format, "'"+filepath.ToSlash(option.path)+"'"))
+	}
+	return strings.Join(append(args, "-o BatchMode=yes"), " "), nil
+}
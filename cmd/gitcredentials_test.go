@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// Runs the askpass script of the OS, which is askpass.cmd on Windows.
+func TestWithGitCredentialsAskPass(t *testing.T) {
+	password := `pa!ss & "word" %PATH% off`
+	ctx, cleanup, err := WithGitCredentials(context.Background(), GitCredentials{Username: "user", Password: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := GetOptions(ctx).Env
+	scriptPath := env["GIT_ASKPASS"]
+	expectedScript := "askpass.sh"
+	if runtime.GOOS == "windows" {
+		expectedScript = "askpass.cmd"
+	}
+	if filepath.Base(scriptPath) != expectedScript {
+		t.Errorf("Expecting the %s askpass script, got: %s", expectedScript, scriptPath)
+	}
+	for prompt, expected := range map[string]string{"Username for 'https://github.com': ": "user", "Password for 'https://user@github.com': ": password} {
+		script := exec.Command(scriptPath, prompt)
+		script.Env = append(os.Environ(), gitUsernameEnv+"="+env[gitUsernameEnv], gitPasswordEnv+"="+env[gitPasswordEnv])
+		output, err := script.Output()
+		if err != nil {
+			t.Error(err)
+		}
+		if actual := strings.TrimSpace(string(output)); actual != expected {
+			t.Errorf("Test name: %s: Expected: %s, Got: %s", prompt, expected, actual)
+		}
+	}
+	if _, ok := env["GIT_SSH_COMMAND"]; ok {
+		t.Error("Expecting GIT_SSH_COMMAND not to be set without an SSH key")
+	}
+
+	cleanup()
+	if _, err = os.Stat(scriptPath); !os.IsNotExist(err) {
+		t.Error("Expecting the askpass script to be removed, got:", err)
+	}
+}
+
+func TestWithGitCredentialsSsh(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "gitCredentialsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	keyPath, knownHostsPath := filepath.Join(tempDir, "id_ed25519"), filepath.Join(tempDir, "known_hosts")
+	for _, path := range []string{keyPath, knownHostsPath} {
+		if err = ioutil.WriteFile(path, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		credentials GitCredentials
+		expected    string
+	}{
+		{"key", GitCredentials{SshKeyPath: keyPath},
+			"ssh -i '" + filepath.ToSlash(keyPath) + "' -o IdentitiesOnly=yes -o BatchMode=yes"},
+		{"keyAndKnownHosts", GitCredentials{SshKeyPath: keyPath, SshKnownHostsPath: knownHostsPath},
+			"ssh -i '" + filepath.ToSlash(keyPath) + "' -o IdentitiesOnly=yes -o UserKnownHostsFile='" + filepath.ToSlash(knownHostsPath) + "' -o StrictHostKeyChecking=yes -o BatchMode=yes"},
+	}
+	for _, test := range tests {
+		ctx, cleanup, err := WithGitCredentials(context.Background(), test.credentials)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		cleanup()
+		env := GetOptions(ctx).Env
+		if env["GIT_SSH_COMMAND"] != test.expected {
+			t.Errorf("Test name: %s: Expected: %s, Got: %s", test.name, test.expected, env["GIT_SSH_COMMAND"])
+		}
+		if _, ok := env["GIT_ASKPASS"]; ok {
+			t.Errorf("Test name: %s: Expecting GIT_ASKPASS not to be set without a username and password", test.name)
+		}
+	}
+}
+
+func TestWithGitCredentialsErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials GitCredentials
+	}{
+		{"empty", GitCredentials{}},
+		{"missingKey", GitCredentials{SshKeyPath: filepath.Join("missing", "id_rsa")}},
+	}
+	for _, test := range tests {
+		if _, _, err := WithGitCredentials(context.Background(), test.credentials); err == nil {
+			t.Errorf("Test name: %s: Expecting an error", test.name)
+		}
+	}
+}
+
+func TestGitPasswordNotRecorded(t *testing.T) {
+	if masked := maskEnv(map[string]string{gitPasswordEnv: "secret"}); masked[gitPasswordEnv] == "secret" {
+		t.Error("Expecting the git password to be masked in the invocation records")
+	}
+}
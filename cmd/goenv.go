@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var goEnvKeyRegExp = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// The environment of the go command, as reported by go env -json.
+// Variables unknown to the go version are empty.
+type GoEnv struct {
+	CGO_ENABLED string
+	GO111MODULE string
+	GOARCH      string
+	GOBIN       string
+	GOCACHE     string
+	GOENV       string
+	GOFLAGS     string
+	GOINSECURE  string
+	GOMOD       string
+	GOMODCACHE  string
+	GONOPROXY   string
+	GONOSUMDB   string
+	GOOS        string
+	GOPATH      string
+	GOPRIVATE   string
+	GOPROXY     string
+	GOROOT      string
+	GOSUMDB     string
+	GOTOOLCHAIN string
+	GOVERSION   string
+	GOWORK      string
+	// All the variables reported by go env, including the ones without a field.
+	Values map[string]string `json:"-"`
+}
+
+// Returns the value of the variable, or an empty string if go env doesn't report it.
+func (env *GoEnv) Get(key string) string {
+	return env.Values[key]
+}
+
+// Runs go env -json and returns the environment of the go command.
+func GetGoEnv(ctx context.Context) (*GoEnv, error) {
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = []string{"env", "-json"}
+	output, _, err := runCmdWithOutputParser(goCmd, false)
+	if err != nil {
+		return nil, errorutils.CheckError(contextError(ctx, err))
+	}
+	return parseGoEnv([]byte(output))
+}
+
+// Writes the variables to the go environment configuration file, using go env -w, so that they apply to all the
+// following go commands of the user.
+func SetGoEnv(ctx context.Context, values map[string]string) error {
+	var keys []string
+	for key, value := range values {
+		if err := validateGoEnvKey(key); err != nil {
+			return err
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return errorutils.CheckError(fmt.Errorf("Invalid value of the go environment variable %s: values cannot contain new lines.", key))
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Strings(keys)
+	args := []string{"env", "-w"}
+	for _, key := range keys {
+		args = append(args, key+"="+values[key])
+	}
+	getLogger(ctx).Debug("Setting the go environment variables", strings.Join(keys, ", "))
+	return RunGo(ctx, args)
+}
+
+// Removes the variables from the go environment configuration file, using go env -u.
+func UnsetGoEnv(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		if err := validateGoEnvKey(key); err != nil {
+			return err
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	getLogger(ctx).Debug("Unsetting the go environment variables", strings.Join(keys, ", "))
+	return RunGo(ctx, append([]string{"env", "-u"}, keys...))
+}
+
+func parseGoEnv(output []byte) (*GoEnv, error) {
+	env := &GoEnv{}
+	if err := json.Unmarshal(output, &env.Values); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the output of go env: %s", err.Error()))
+	}
+	if err := json.Unmarshal(output, env); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the output of go env: %s", err.Error()))
+	}
+	return env, nil
+}
+
+// GOENV is the path of the configuration file go env -w writes to, so it can only be set in the process environment.
+func validateGoEnvKey(key string) error {
+	if !goEnvKeyRegExp.MatchString(key) {
+		return errorutils.CheckError(fmt.Errorf("Invalid go environment variable name: %q.", key))
+	}
+	if key == "GOENV" {
+		return errorutils.CheckError(errors.New("GOENV cannot be set by go env -w. Set it in the environment instead."))
+	}
+	return nil
+}
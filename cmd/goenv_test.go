@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseGoEnv(t *testing.T) {
+	env, err := parseGoEnv([]byte(`{"GOPATH": "/home/user/go", "GOPROXY": "https://proxy.golang.org,direct", "GOFUTURE": "1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.GOPATH != "/home/user/go" || env.GOPROXY != "https://proxy.golang.org,direct" || env.Get("GOFUTURE") != "1" || env.GOMODCACHE != "" {
+		t.Errorf("Unexpected go env: %+v", env)
+	}
+	if _, err = parseGoEnv([]byte("GOPATH=/home/user/go")); err == nil {
+		t.Error("Expecting an error for an output which is not JSON")
+	}
+}
+
+func TestSetGoEnv(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "goEnvTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	ctx := WithEnv(context.Background(), "GOENV", filepath.Join(tempDir, "env"))
+
+	if err = SetGoEnv(ctx, map[string]string{"GOPRIVATE": "github.mycorp.com"}); err != nil {
+		t.Fatal(err)
+	}
+	env, err := GetGoEnv(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.GOPRIVATE != "github.mycorp.com" {
+		t.Errorf("Expected GOPRIVATE: github.mycorp.com, Got: %s", env.GOPRIVATE)
+	}
+	if err = UnsetGoEnv(ctx, "GOPRIVATE"); err != nil {
+		t.Fatal(err)
+	}
+	if env, err = GetGoEnv(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if env.GOPRIVATE != "" {
+		t.Errorf("Expected an empty GOPRIVATE, Got: %s", env.GOPRIVATE)
+	}
+
+	for _, values := range []map[string]string{{"goproxy": "off"}, {"GOENV": "/tmp/env"}, {"GOPROXY": "off\nGOSUMDB=off"}} {
+		if err = SetGoEnv(ctx, values); err == nil {
+			t.Errorf("Expecting an error for: %v", values)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/gocmd/store"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	gomodfile "golang.org/x/mod/modfile"
+	"hash"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The output of a resolution command, persisted to be reused while go.mod and go.sum are unchanged.
+type resolutionCacheEntry struct {
+	Command string    `json:"command"`
+	Output  string    `json:"output"`
+	Time    time.Time `json:"time"`
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose dependency resolution commands, such as go mod graph
+// and go list -m all, persist their results to cacheDir. A resolution of a project whose go.mod and go.sum files,
+// and the environment variables of the options, are unchanged since a previous resolution returns the persisted
+// result instead of running the command again.
+func WithIncrementalResolution(ctx context.Context, cacheDir string) context.Context {
+	return WithResolutionStore(ctx, store.NewFileStore(cacheDir))
+}
+
+// Like WithIncrementalResolution, but persists the results to the store, which may be shared by several machines.
+func WithResolutionStore(ctx context.Context, resolutionStore store.Store) context.Context {
+	options := *GetOptions(ctx)
+	options.ResolutionStore = resolutionStore
+	return WithOptions(ctx, &options)
+}
+
+// The environment variables of the process which affect the resolution. They are hashed unless set in the options.
+var resolutionProcessEnv = []string{"GOFLAGS", "GOPROXY", "GONOPROXY", "GOPRIVATE", "GONOSUMDB", "GOSUMDB", "GOINSECURE", "GOWORK", "GOTOOLCHAIN"}
+
+// Returns the key of the resolution command's result, hashing the command, the go binary, the environment variables
+// of the options carried by ctx and of the process, the go.mod and go.sum files of the project, its go.work and
+// go.work.sum files, and the go.mod files of the modules replaced by local directories.
+func getResolutionKey(ctx context.Context, projectDir string, args []string) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(strings.Join(args, " ") + "\n"))
+	if err := hashGoExecutable(ctx, hash); err != nil {
+		return "", err
+	}
+	env := GetOptions(ctx).Env
+	var keys []string
+	for key := range env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		hash.Write([]byte(key + "=" + env[key] + "\n"))
+	}
+	for _, key := range resolutionProcessEnv {
+		if _, ok := env[key]; !ok {
+			hash.Write([]byte("process " + key + "=" + os.Getenv(key) + "\n"))
+		}
+	}
+	fileSystem := GetFileSystem(ctx)
+	modContent, err := hashFile(fileSystem, hash, filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	if _, err = hashFile(fileSystem, hash, filepath.Join(projectDir, "go.sum")); err != nil {
+		return "", err
+	}
+	replaces := getLocalReplaces(projectDir, modContent, false)
+	workPath, err := findGoWork(ctx, projectDir)
+	if err != nil {
+		return "", err
+	}
+	if workPath != "" {
+		workContent, err := hashFile(fileSystem, hash, workPath)
+		if err != nil {
+			return "", err
+		}
+		if _, err = hashFile(fileSystem, hash, workPath+".sum"); err != nil {
+			return "", err
+		}
+		replaces = append(replaces, getLocalReplaces(filepath.Dir(workPath), workContent, true)...)
+	}
+	for _, replaceDir := range replaces {
+		if _, err = hashFile(fileSystem, hash, filepath.Join(replaceDir, "go.mod")); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Hashes the path of the go binary, and its version if known, or its size and modification time otherwise.
+func hashGoExecutable(ctx context.Context, digest hash.Hash) error {
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return err
+	}
+	digest.Write([]byte("go " + goCmd.Go + "\n"))
+	executable := GetOptions(ctx).Executable
+	if executable == nil {
+		executable = getGoExecutable()
+	}
+	if executable != nil && executable.Version != "" {
+		digest.Write([]byte("version " + executable.Version + "\n"))
+	} else if info, err := os.Stat(goCmd.Go); err == nil {
+		digest.Write([]byte(fmt.Sprintf("binary %d %d\n", info.Size(), info.ModTime().UnixNano())))
+	}
+	return nil
+}
+
+// Hashes the path and content of the file, and returns the content. A missing file is hashed as empty.
+func hashFile(fileSystem fsys.FileSystem, digest hash.Hash, path string) ([]byte, error) {
+	content, err := readFileIfExists(fileSystem, path)
+	if err != nil {
+		return nil, err
+	}
+	digest.Write([]byte(filepath.ToSlash(path) + "\n"))
+	digest.Write(content)
+	return content, nil
+}
+
+// Returns the local directories replacing modules in the go.mod or go.work content of the file in dir.
+// A content which can't be parsed has no replacements, as the go command reports its errors.
+func getLocalReplaces(dir string, content []byte, work bool) []string {
+	var replaces []*gomodfile.Replace
+	if work {
+		if workFile, err := gomodfile.ParseWork("go.work", content, nil); err == nil {
+			replaces = workFile.Replace
+		}
+	} else if modFile, err := gomodfile.Parse("go.mod", content, nil); err == nil {
+		replaces = modFile.Replace
+	}
+	var dirs []string
+	for _, replace := range replaces {
+		if replace.New.Version != "" {
+			continue
+		}
+		replaceDir := filepath.FromSlash(replace.New.Path)
+		if !filepath.IsAbs(replaceDir) {
+			replaceDir = filepath.Join(dir, replaceDir)
+		}
+		dirs = append(dirs, replaceDir)
+	}
+	return dirs
+}
+
+// Returns the go.work file of the project, which is the GOWORK of the options or of the process if set, or the first
+// go.work file in projectDir and its parents. Returns an empty string if GOWORK is off or there's no go.work file.
+func findGoWork(ctx context.Context, projectDir string) (string, error) {
+	gowork, ok := GetOptions(ctx).Env["GOWORK"]
+	if !ok {
+		gowork = os.Getenv("GOWORK")
+	}
+	if gowork == "off" {
+		return "", nil
+	}
+	if gowork != "" {
+		return gowork, nil
+	}
+	for dir := projectDir; ; dir = filepath.Dir(dir) {
+		workPath := filepath.Join(dir, "go.work")
+		exists, err := fsys.IsFileExists(GetFileSystem(ctx), workPath)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return workPath, nil
+		}
+		if filepath.Dir(dir) == dir {
+			return "", nil
+		}
+	}
+}
+
+// Returns the persisted output of the resolution with the key, and false if there's none.
+func readCachedResolution(resolutionStore store.Store, key string) (string, bool, error) {
+	content, found, err := resolutionStore.Get(key)
+	if err != nil || !found {
+		return "", false, err
+	}
+	var entry resolutionCacheEntry
+	if err = json.Unmarshal(content, &entry); err != nil {
+		// A corrupted entry is treated as missing, and is overwritten by the next resolution.
+		return "", false, nil
+	}
+	return entry.Output, true, nil
+}
+
+// Persists the output of the resolution with the key.
+func writeCachedResolution(resolutionStore store.Store, key, command, output string) error {
+	content, err := json.Marshal(resolutionCacheEntry{Command: command, Output: output, Time: time.Now()})
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	return resolutionStore.Put(key, content)
+}
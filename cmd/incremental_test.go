@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIncrementalResolution(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "incrementalTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	projectDir := filepath.Join(tempDir, "project")
+	if err = os.Mkdir(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	modPath := filepath.Join(projectDir, "go.mod")
+	if err = ioutil.WriteFile(modPath, []byte("module example.com/test\n\nrequire github.com/pkg/errors v0.8.1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: "example.com/test github.com/pkg/errors@v0.8.1\n"}, "mod", "graph")
+	ctx := WithOptions(context.Background(), &Options{Executor: executor, Dir: projectDir})
+	ctx = WithIncrementalResolution(ctx, filepath.Join(tempDir, "cache"))
+
+	for _, expectedCalls := range []int{1, 1} {
+		dependencies, err := GetDependencies(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !dependencies["github.com/pkg/errors@v0.8.1"] || len(dependencies) != 1 {
+			t.Errorf("Unexpected dependencies: %v", dependencies)
+		}
+		if len(executor.Calls()) != expectedCalls {
+			t.Errorf("Expected %d calls, Got: %v", expectedCalls, executor.Calls())
+		}
+	}
+
+	// Changing go.mod resolves the dependencies again.
+	if err = ioutil.WriteFile(modPath, []byte("module example.com/test\n\nrequire github.com/pkg/errors v0.8.1 // indirect\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = GetDependencies(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(executor.Calls()) != 2 {
+		t.Errorf("Expected 2 calls, Got: %v", executor.Calls())
+	}
+
+	// Changing the go.mod of a local replacement, go.work or the process environment resolves the dependencies again.
+	replaceDir := filepath.Join(tempDir, "errors")
+	if err = os.Mkdir(replaceDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	replaceModPath := filepath.Join(replaceDir, "go.mod")
+	if err = ioutil.WriteFile(replaceModPath, []byte("module github.com/pkg/errors\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(modPath, []byte("module example.com/test\n\nrequire github.com/pkg/errors v0.8.1\n\nreplace github.com/pkg/errors => ../errors\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("GOFLAGS", os.Getenv("GOFLAGS"))
+	changes := []func() error{
+		func() error { return nil },
+		func() error {
+			return ioutil.WriteFile(replaceModPath, []byte("module github.com/pkg/errors\n\ngo 1.12\n"), 0644)
+		},
+		func() error {
+			return ioutil.WriteFile(filepath.Join(projectDir, "go.work"), []byte("go 1.21\n\nuse .\n"), 0644)
+		},
+		func() error { return os.Setenv("GOFLAGS", "-mod=mod -gocmd-test") },
+	}
+	for i, change := range changes {
+		if err = change(); err != nil {
+			t.Fatal(err)
+		}
+		for range []int{1, 2} {
+			if _, err = GetDependencies(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if len(executor.Calls()) != 3+i {
+			t.Errorf("Expected %d calls, Got: %v", 3+i, executor.Calls())
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"time"
+)
+
+// A JUnit XML report, as rendered by CI systems such as Jenkins and GitLab. Each package is a test suite.
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+	SystemOut string          `xml:"system-out,omitempty"`
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Error     *JUnitFailure `xml:"error,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type JUnitFailure struct {
+	Message  string `xml:"message,attr"`
+	Contents string `xml:",chardata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// Creates a JUnit report of the test results. A package which failed without a failed test, such as a package which
+// failed to build, is reported as a test case with an error.
+func NewJUnitReport(report *TestReport) *JUnitTestSuites {
+	junit := &JUnitTestSuites{}
+	var total time.Duration
+	for _, pkg := range report.Packages {
+		suite := JUnitTestSuite{Name: pkg.Package, Time: junitTime(pkg.Elapsed), SystemOut: pkg.Output}
+		for _, test := range pkg.Tests {
+			testCase := JUnitTestCase{Name: test.Name, ClassName: pkg.Package, Time: junitTime(test.Elapsed)}
+			switch test.Status {
+			case TestFailed:
+				testCase.Failure = &JUnitFailure{Message: "Failed", Contents: test.Output}
+				suite.Failures++
+			case TestSkipped:
+				testCase.Skipped = &JUnitSkipped{Message: test.Output}
+				suite.Skipped++
+			default:
+				testCase.SystemOut = test.Output
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		if pkg.Status == TestFailed && suite.Failures == 0 {
+			suite.TestCases = append(suite.TestCases, JUnitTestCase{
+				Name:      pkg.Package,
+				ClassName: pkg.Package,
+				Time:      junitTime(pkg.Elapsed),
+				Error:     &JUnitFailure{Message: "The package failed", Contents: pkg.Output},
+			})
+			suite.Errors++
+		}
+		suite.Tests = len(suite.TestCases)
+		junit.Tests += suite.Tests
+		junit.Failures += suite.Failures
+		junit.Errors += suite.Errors
+		junit.Skipped += suite.Skipped
+		total += pkg.Elapsed
+		junit.Suites = append(junit.Suites, suite)
+	}
+	junit.Time = junitTime(total)
+	return junit
+}
+
+func (junit *JUnitTestSuites) Write(writer io.Writer) error {
+	content, err := xml.MarshalIndent(junit, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = writer.Write(append([]byte(xml.Header), append(content, '\n')...))
+	return errorutils.CheckError(err)
+}
+
+func junitTime(duration time.Duration) string {
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestNewJUnitReport(t *testing.T) {
+	junit := NewJUnitReport(parseTestEvents(testEventsOutput))
+	if junit.Tests != 4 || junit.Failures != 1 || junit.Errors != 1 || junit.Skipped != 1 || junit.Time != "1.500" {
+		t.Errorf("Expected 4 tests, 1 failure, 1 error, 1 skipped and 1.500 seconds, Got: %d, %d, %d, %d, %s", junit.Tests, junit.Failures, junit.Errors, junit.Skipped, junit.Time)
+	}
+	if len(junit.Suites) != 3 || junit.Suites[1].TestCases[0].Error == nil {
+		t.Fatalf("Expected 3 suites, the second with a build error, Got: %+v", junit.Suites)
+	}
+
+	var buffer bytes.Buffer
+	if err := junit.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<testsuites tests="4" failures="1" errors="1" skipped="1" time="1.500">`,
+		`<testcase name="TestFail" classname="example.com/a" time="0.250">`,
+		`<failure message="Failed">    a_test.go:10: failed&#xA;</failure>`,
+		`<error message="The package failed">b.go:3:1: syntax error&#xA;</error>`,
+	} {
+		if !bytes.Contains(buffer.Bytes(), []byte(expected)) {
+			t.Errorf("Expected the report to contain: %s, Got:\n%s", expected, buffer.String())
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"strings"
+	"time"
+)
+
+// The command used for resolving the dependencies of the project.
+type ResolutionMode int
+
+const (
+	// Uses go mod graph, which also lists versions that are not selected by the minimal version selection.
+	ModGraphResolution ResolutionMode = iota
+	// Uses go list -m all, which lists only the selected versions, i.e. the modules actually used in the build.
+	ListModulesResolution
+	// Reads the vendored modules from vendor/modules.txt, without running a go command, so it works without network
+	// access. Fails if the vendor directory is inconsistent with the go.mod requirements.
+	VendorResolution
+)
+
+// The details go list -m -json reports for each module.
+type ListedModule struct {
+	Path     string
+	Version  string
+	Time     *time.Time
+	Main     bool
+	Indirect bool
+	// The replacement of the module, if replaced in go.mod.
+	Replace *ListedModule
+	// The latest version of the module, if newer than Version and listed with -u.
+	Update *ListedModule
+	// The directory of the module files, if downloaded.
+	Dir       string
+	GoMod     string
+	GoVersion string
+	Error     *ListedModuleError
+}
+
+type ListedModuleError struct {
+	Err string
+}
+
+// Runs go list -m -json all and returns the main module and the selected versions of all its dependencies.
+// The go.mod and go.sum files are left unchanged.
+func ListModules(ctx context.Context) ([]ListedModule, error) {
+	output, err := runWithUnchangedModFiles(ctx, false, "list", "-m", "-json", "all")
+	if err != nil {
+		return nil, err
+	}
+	return parseListedModules(strings.NewReader(output))
+}
+
+// Runs go list -m -json all and returns the dependencies of the main module in the "path@version" notation.
+func GetDependenciesList(ctx context.Context) (map[string]bool, error) {
+	modules, err := ListModules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return listedModulesToMap(modules), nil
+}
+
+// Parses the stream of JSON objects printed by go list -m -json.
+func parseListedModules(reader io.Reader) ([]ListedModule, error) {
+	var modules []ListedModule
+	decoder := json.NewDecoder(reader)
+	for {
+		var module ListedModule
+		err := decoder.Decode(&module)
+		if err == io.EOF {
+			return modules, nil
+		}
+		if err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the output of go list: %s", err.Error()))
+		}
+		modules = append(modules, module)
+	}
+}
+
+func listedModulesToMap(modules []ListedModule) map[string]bool {
+	mapOfDeps := map[string]bool{}
+	for _, module := range modules {
+		if module.Main || module.Version == "" {
+			continue
+		}
+		mapOfDeps[module.Path+"@"+module.Version] = true
+	}
+	return mapOfDeps
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	gomodule "golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+	gozip "golang.org/x/mod/zip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const listModulesOutput = `{
+	"Path": "github.com/you/hello",
+	"Main": true,
+	"Dir": "/home/you/hello",
+	"GoMod": "/home/you/hello/go.mod",
+	"GoVersion": "1.12"
+}
+{
+	"Path": "golang.org/x/text",
+	"Version": "v0.3.1",
+	"Time": "2019-01-31T19:12:56Z",
+	"Indirect": true,
+	"GoMod": "/home/you/go/pkg/mod/cache/download/golang.org/x/text/@v/v0.3.1.mod"
+}
+{
+	"Path": "rsc.io/quote",
+	"Version": "v1.5.2",
+	"Replace": {
+		"Path": "../quote",
+		"Dir": "/home/you/quote"
+	}
+}
+{
+	"Path": "rsc.io/missing",
+	"Version": "v1.0.0",
+	"Error": {
+		"Err": "module rsc.io/missing: not found"
+	}
+}
+`
+
+func TestParseListedModules(t *testing.T) {
+	modules, err := parseListedModules(strings.NewReader(listModulesOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != 4 {
+		t.Fatal("Expecting 4 modules, got:", len(modules))
+	}
+	if !modules[0].Main || modules[0].GoVersion != "1.12" {
+		t.Error("Expecting the first module to be the main module, got:", modules[0])
+	}
+	if !modules[1].Indirect || modules[1].Time == nil || modules[1].Time.Year() != 2019 {
+		t.Error("Expecting an indirect module with its time, got:", modules[1])
+	}
+	if modules[2].Replace == nil || modules[2].Replace.Dir != "/home/you/quote" {
+		t.Error("Expecting a replaced module, got:", modules[2])
+	}
+	if modules[3].Error == nil || modules[3].Error.Err != "module rsc.io/missing: not found" {
+		t.Error("Expecting a module with an error, got:", modules[3])
+	}
+
+	expected := map[string]bool{"golang.org/x/text@v0.3.1": true, "rsc.io/quote@v1.5.2": true, "rsc.io/missing@v1.0.0": true}
+	if actual := listedModulesToMap(modules); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
+
+func TestParseListedModulesInvalid(t *testing.T) {
+	_, err := parseListedModules(strings.NewReader(`{"Path": "rsc.io/quote"`))
+	if err == nil {
+		t.Error("Expecting an error for a truncated output")
+	}
+}
+
+func TestListModulesReadonly(t *testing.T) {
+	projectDir, ctx := createFileProxyProject(t)
+	defer os.RemoveAll(filepath.Dir(projectDir))
+	sumPath := filepath.Join(projectDir, "go.sum")
+	sumContent, err := ioutil.ReadFile(sumPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = RunInSandbox(ctx, func(ctx context.Context) error {
+		modules, err := ListModules(ctx)
+		if err != nil {
+			return err
+		}
+		if len(modules) != 2 || modules[1].Path != "example.com/dep" || modules[1].Version != "v1.0.0" {
+			t.Errorf("Expected the main module and example.com/dep@v1.0.0, Got: %+v", modules)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertFileContent(t, sumPath, string(sumContent))
+}
+
+// Creates a project requiring example.com/dep v1.0.0 with a complete go.sum, and a GOPROXY directory serving the
+// dependency and the info of its v1.1.0 update, both in a new temp directory. Returns the project directory, whose parent is the temp directory, and a
+// context running the go commands in it offline, with GOFLAGS cleared, so that they run with the default
+// -mod=readonly. The go commands should run in a sandbox, so that the dependency isn't added to the module cache of
+// the machine.
+func createFileProxyProject(t *testing.T) (string, context.Context) {
+	tempDir, err := ioutil.TempDir("", "fileProxyProject")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dep := gomodule.Version{Path: "example.com/dep", Version: "v1.0.0"}
+	depMod := "module example.com/dep\n"
+	files := map[string]string{
+		"dep/go.mod":                           depMod,
+		"dep/dep.go":                           "package dep\n",
+		"proxy/example.com/dep/@v/list":        "v1.0.0\nv1.1.0\n",
+		"proxy/example.com/dep/@v/v1.0.0.info": `{"Version": "v1.0.0"}`,
+		"proxy/example.com/dep/@v/v1.0.0.mod":  depMod,
+		"proxy/example.com/dep/@v/v1.1.0.info": `{"Version": "v1.1.0"}`,
+		"proxy/example.com/dep/@v/v1.1.0.mod":  depMod,
+		"project/go.mod":                       "module example.com/test\n\ngo 1.16\n\nrequire example.com/dep v1.0.0\n",
+		"project/main.go":                      "package main\n\nimport _ \"example.com/dep\"\n\nfunc main() {}\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(tempDir, filepath.FromSlash(path))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	zipPath := filepath.Join(tempDir, "proxy", "example.com", "dep", "@v", "v1.0.0.zip")
+	zipFile, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = gozip.CreateFromDir(zipFile, dep, filepath.Join(tempDir, "dep"))
+	if closeErr := zipFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	zipHash, err := dirhash.HashZip(zipPath, dirhash.Hash1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modHash, err := dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(strings.NewReader(depMod)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sumContent := "example.com/dep v1.0.0 " + zipHash + "\nexample.com/dep v1.0.0/go.mod " + modHash + "\n"
+	projectDir := filepath.Join(tempDir, "project")
+	if err = ioutil.WriteFile(filepath.Join(projectDir, "go.sum"), []byte(sumContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	proxyUrl := "file://" + filepath.ToSlash(filepath.Join(tempDir, "proxy"))
+	if !strings.HasPrefix(proxyUrl, "file:///") {
+		proxyUrl = "file:///" + strings.TrimPrefix(proxyUrl, "file://")
+	}
+	env := map[string]string{"GOPROXY": proxyUrl, "GOSUMDB": "off", "GOFLAGS": "", "GOTOOLCHAIN": "local", "GOWORK": "off"}
+	return projectDir, WithOptions(context.Background(), &Options{Dir: projectDir, Env: env})
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/semver"
+	"os"
+	"strings"
+	"time"
+)
+
+// Names of the metrics reported to the MetricsCollector.
+const (
+	// The duration of each go command, labeled by CommandLabel and ResultLabel.
+	CommandDurationMetric = "gocmd_command_duration"
+	// The number of modules the go commands downloaded, as reported by their "go: downloading" lines. Labeled by CommandLabel.
+	ModulesDownloadedMetric = "gocmd_modules_downloaded"
+	// The number of modules requested by DownloadModules, which were already in the module cache or were missing from it.
+	ModuleCacheHitsMetric   = "gocmd_module_cache_hits"
+	ModuleCacheMissesMetric = "gocmd_module_cache_misses"
+	// The number of times go commands were retried, labeled by CommandLabel.
+	RetriesMetric = "gocmd_command_retries"
+	// The number of failed go commands, labeled by CommandLabel and ErrorTypeLabel.
+	ErrorsMetric = "gocmd_command_errors"
+)
+
+// Labels of the metrics reported to the MetricsCollector.
+const (
+	// The go command without its arguments, such as "go mod graph".
+	CommandLabel = "command"
+	// "success" or "failure".
+	ResultLabel = "result"
+	// The type of the error, such as "ModuleNotFoundError", "ExitError", "DeadlineExceeded" or "Error" for untyped errors.
+	ErrorTypeLabel = "error_type"
+)
+
+// Receives timing and counter events from the go commands, to be exported by the application, for example as Prometheus metrics.
+// The methods may be called concurrently.
+type MetricsCollector interface {
+	ObserveDuration(name string, duration time.Duration, labels map[string]string)
+	AddCount(name string, count int, labels map[string]string)
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands report their metrics to the collector.
+func WithMetricsCollector(ctx context.Context, collector MetricsCollector) context.Context {
+	options := *GetOptions(ctx)
+	options.Metrics = collector
+	return WithOptions(ctx, &options)
+}
+
+// Reports the metrics of a go command which is done.
+func reportCmdMetrics(goCmd *Cmd, duration time.Duration, stderr string, err error) {
+	collector := GetOptions(goCmd.Context).Metrics
+	if collector == nil {
+		return
+	}
+	name := getCmdName(goCmd)
+	result := "success"
+	if err != nil {
+		result = "failure"
+		collector.AddCount(ErrorsMetric, 1, map[string]string{CommandLabel: name, ErrorTypeLabel: getErrorType(goCmd.Context, err)})
+	}
+	collector.ObserveDuration(CommandDurationMetric, duration, map[string]string{CommandLabel: name, ResultLabel: result})
+	downloaded := 0
+	for _, line := range strings.Split(stderr, "\n") {
+		if event := ParseProgressLine(line); event != nil && event.Phase == DownloadingPhase {
+			downloaded++
+		}
+	}
+	if downloaded > 0 {
+		collector.AddCount(ModulesDownloadedMetric, downloaded, map[string]string{CommandLabel: name})
+	}
+}
+
+// Reports the modules requested by DownloadModules which were found in the module cache, or missing from it.
+// Modules requested by a query rather than by an exact version, such as "path@latest", aren't reported.
+func reportModuleCacheMetrics(ctx context.Context, modules []string) {
+	collector := GetOptions(ctx).Metrics
+	if collector == nil || len(modules) == 0 {
+		return
+	}
+	cache, err := GetModCache(ctx)
+	if err != nil {
+		return
+	}
+	hits, misses := 0, 0
+	for _, module := range modules {
+		path, version := splitModuleId(module)
+		if !semver.IsValid(version) {
+			continue
+		}
+		if _, err = os.Stat(cache.DownloadPath(path, version, ".zip")); err == nil {
+			hits++
+		} else {
+			misses++
+		}
+	}
+	collector.AddCount(ModuleCacheHitsMetric, hits, nil)
+	collector.AddCount(ModuleCacheMissesMetric, misses, nil)
+}
+
+// Returns the name of the error's type without its package, such as "ModuleNotFoundError".
+func getErrorType(ctx context.Context, err error) string {
+	if ctx != nil {
+		switch ctx.Err() {
+		case context.DeadlineExceeded:
+			return "DeadlineExceeded"
+		case context.Canceled:
+			return "Canceled"
+		}
+	}
+	// An ExitError is reported by the typed errors it wraps.
+	if exitErr, ok := err.(*ExitError); ok {
+		switch exitErr.Err.(type) {
+		case GoError, *MultiError:
+			err = exitErr.Err
+		}
+	}
+	switch err.(type) {
+	case GoError, *ExitError, *TimeoutError, *MultiError:
+	default:
+		return "Error"
+	}
+	errType := strings.TrimPrefix(fmt.Sprintf("%T", err), "*")
+	return errType[strings.LastIndex(errType, ".")+1:]
+}
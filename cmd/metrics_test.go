@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordedMetric struct {
+	name   string
+	count  int
+	labels map[string]string
+}
+
+type recordingCollector struct {
+	mutex     sync.Mutex
+	counts    []recordedMetric
+	durations []recordedMetric
+}
+
+func (collector *recordingCollector) ObserveDuration(name string, duration time.Duration, labels map[string]string) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	collector.durations = append(collector.durations, recordedMetric{name: name, labels: labels})
+}
+
+func (collector *recordingCollector) AddCount(name string, count int, labels map[string]string) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	collector.counts = append(collector.counts, recordedMetric{name, count, labels})
+}
+
+func TestMetricsCollector(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stderr: "go: downloading github.com/pkg/errors v0.8.1\ngo: downloading golang.org/x/text v0.3.0\n"}, "build", "./...")
+	executor.On(ExecutorResult{Stderr: "go: github.com/pkg/errors@v0.9.0: 404 Not Found\n", ExitCode: 1}, "get", "github.com/pkg/errors@v0.9.0")
+	collector := &recordingCollector{}
+	ctx := WithMetricsCollector(WithExecutor(context.Background(), executor), collector)
+
+	if err := RunGo(ctx, []string{"build", "./..."}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RunGo(ctx, []string{"get", "github.com/pkg/errors@v0.9.0"}); err == nil {
+		t.Fatal("Expecting an error for the failing command")
+	}
+
+	expectedCounts := []recordedMetric{
+		{ModulesDownloadedMetric, 2, map[string]string{CommandLabel: "go build"}},
+		{ErrorsMetric, 1, map[string]string{CommandLabel: "go get", ErrorTypeLabel: "ModuleNotFoundError"}},
+	}
+	if !reflect.DeepEqual(collector.counts, expectedCounts) {
+		t.Errorf("Expected counts: %v, Got: %v", expectedCounts, collector.counts)
+	}
+	expectedDurations := []recordedMetric{
+		{name: CommandDurationMetric, labels: map[string]string{CommandLabel: "go build", ResultLabel: "success"}},
+		{name: CommandDurationMetric, labels: map[string]string{CommandLabel: "go get", ResultLabel: "failure"}},
+	}
+	if !reflect.DeepEqual(collector.durations, expectedDurations) {
+		t.Errorf("Expected durations: %v, Got: %v", expectedDurations, collector.durations)
+	}
+}
+
+func TestGetErrorType(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		err      error
+		expected string
+	}{
+		{"typed", context.Background(), &UnknownRevisionError{}, "UnknownRevisionError"},
+		{"untyped", context.Background(), errors.New("exit status 1"), "Error"},
+		{"canceled", cancelledCtx, errors.New("signal: killed"), "Canceled"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := getErrorType(test.ctx, test.err); actual != test.expected {
+				t.Errorf("Expected: %s, Got: %s", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The module cache, where the go command keeps the downloaded modules.
+// The extracted modules are kept in <dir>/<module>@<version>, and the downloaded files in <dir>/cache/download.
+type ModCache struct {
+	Dir string
+	// The context of the module cache returned by GetModCache, whose logger logs the removals. Nil for NewModCache.
+	ctx context.Context
+}
+
+// The disk usage of a module version in the module cache.
+type ModuleUsage struct {
+	Module  string
+	Version string
+	// The total size, in bytes, of the extracted module and of its downloaded files.
+	Size int64
+}
+
+// Returns the module cache in the given directory.
+func NewModCache(dir string) *ModCache {
+	return &ModCache{Dir: dir}
+}
+
+// Returns the module cache used by the go command, which is GOMODCACHE, or pkg/mod in the first GOPATH entry
+// for go versions without GOMODCACHE.
+func GetModCache(ctx context.Context) (*ModCache, error) {
+	env, err := GetGoEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if env.GOMODCACHE != "" {
+		return &ModCache{Dir: env.GOMODCACHE, ctx: ctx}, nil
+	}
+	if goPaths := filepath.SplitList(env.GOPATH); len(goPaths) > 0 {
+		return &ModCache{Dir: filepath.Join(goPaths[0], "pkg", "mod"), ctx: ctx}, nil
+	}
+	return nil, errorutils.CheckError(errors.New("Could not find the module cache: GOMODCACHE and GOPATH are empty."))
+}
+
+func (cache *ModCache) context() context.Context {
+	if cache.ctx == nil {
+		return context.Background()
+	}
+	return cache.ctx
+}
+
+// Returns the total size, in bytes, of the module cache.
+func (cache *ModCache) Size() (int64, error) {
+	return dirSize(fsys.LongPath(cache.Dir))
+}
+
+// Returns the path of a downloaded file of the module version, such as its ".zip", ".mod" or ".info" file.
+// On Windows, paths too long for the Windows APIs have the \\?\ prefix.
+func (cache *ModCache) DownloadPath(module, version, extension string) string {
+	return fsys.LongPath(filepath.Join(cache.Dir, "cache", "download", filepath.FromSlash(EscapeModulePath(module)), "@v", EscapeModulePath(version)+extension))
+}
+
+// Returns the disk usage of each module version in the cache, sorted by module and version.
+func (cache *ModCache) ModulesUsage() ([]ModuleUsage, error) {
+	files, err := cache.moduleFiles()
+	if err != nil {
+		return nil, err
+	}
+	var usages []ModuleUsage
+	for id, paths := range files {
+		usage := ModuleUsage{}
+		usage.Module, usage.Version = splitModuleId(id)
+		for _, path := range paths {
+			size, err := dirSize(path)
+			if err != nil {
+				return nil, err
+			}
+			usage.Size += size
+		}
+		usages = append(usages, usage)
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].Module != usages[j].Module {
+			return usages[i].Module < usages[j].Module
+		}
+		return usages[i].Version < usages[j].Version
+	})
+	return usages, nil
+}
+
+// Removes the module versions which are not referenced by the go.sum entries, such as the entries of the projects
+// built on the machine. Returns the removed modules in the "path@version" notation, sorted.
+func (cache *ModCache) Prune(referenced []gosum.ModuleEntry) ([]string, error) {
+	keep := map[string]bool{}
+	for _, entry := range referenced {
+		keep[entry.ModuleId()] = true
+	}
+	files, err := cache.moduleFiles()
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for id, paths := range files {
+		if keep[id] || SkipInDryRunWithContext(cache.context(), "Removing "+id+" from the module cache") {
+			continue
+		}
+		getLogger(cache.context()).Debug("Removing", id, "from the module cache")
+		for _, path := range paths {
+			if err := removeReadOnly(path); err != nil {
+				return nil, err
+			}
+		}
+		removed = append(removed, id)
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// Removes the extracted module version and its downloaded files from the cache, so the go command downloads it again.
+func (cache *ModCache) RemoveModule(module, version string) error {
+	if SkipInDryRunWithContext(cache.context(), "Removing "+module+"@"+version+" from the module cache") {
+		return nil
+	}
+	getLogger(cache.context()).Debug("Removing", module+"@"+version, "from the module cache")
+	paths := []string{fsys.LongPath(filepath.Join(cache.Dir, filepath.FromSlash(EscapeModulePath(module))+"@"+EscapeModulePath(version)))}
+	for _, extension := range []string{".zip", ".ziphash", ".mod", ".info", ".lock"} {
+		paths = append(paths, cache.DownloadPath(module, version, extension))
+	}
+	for _, path := range paths {
+		if err := removeReadOnly(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Runs go clean -modcache, which removes the entire module cache.
+func CleanModCache(ctx context.Context) error {
+	if SkipInDryRunWithContext(ctx, "Running 'go clean -modcache'") {
+		return nil
+	}
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return err
+	}
+	goCmd.Command = []string{"clean", "-modcache"}
+	getLogger(ctx).Info("Running 'go clean -modcache'")
+	_, _, err = runCmdWithOutputParser(goCmd, true)
+	return errorutils.CheckError(contextError(ctx, err))
+}
+
+// Returns the paths of the extracted module directories and of the downloaded files, by the module versions
+// in the "path@version" notation.
+func (cache *ModCache) moduleFiles() (map[string][]string, error) {
+	files := map[string][]string{}
+	// If the cache directory is too long for the Windows APIs, walk it with the long path prefix.
+	cacheDir := fsys.LongPath(cache.Dir)
+	downloadDir := filepath.Join(cacheDir, "cache", "download")
+	err := filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == cacheDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !info.IsDir() || path == cacheDir {
+			return nil
+		}
+		if path == filepath.Join(cacheDir, "cache") {
+			return filepath.SkipDir
+		}
+		if strings.Contains(info.Name(), "@") {
+			relativePath, err := filepath.Rel(cacheDir, path)
+			if err != nil {
+				return err
+			}
+			id := UnescapeModulePath(filepath.ToSlash(relativePath))
+			files[id] = append(files[id], path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	err = filepath.Walk(downloadDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == downloadDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !info.IsDir() || info.Name() != "@v" {
+			return nil
+		}
+		relativePath, err := filepath.Rel(downloadDir, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		module := UnescapeModulePath(filepath.ToSlash(relativePath))
+		versionFiles, err := ioutil.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, versionFile := range versionFiles {
+			extension := filepath.Ext(versionFile.Name())
+			if versionFile.IsDir() || extension == "" || versionFile.Name() == "list" || versionFile.Name() == "list.lock" {
+				continue
+			}
+			// Such as v1.5.2.zip, v1.5.2.ziphash, v1.5.2.mod and v1.5.2.lock.
+			version := UnescapeModulePath(strings.TrimSuffix(versionFile.Name(), extension))
+			id := module + "@" + version
+			files[id] = append(files[id], filepath.Join(path, versionFile.Name()))
+		}
+		return filepath.SkipDir
+	})
+	return files, errorutils.CheckError(err)
+}
+
+func splitModuleId(id string) (string, string) {
+	index := strings.LastIndex(id, "@")
+	if index < 0 {
+		return id, ""
+	}
+	return id[:index], id[index+1:]
+}
+
+// Returns the total size of the files in path, which may be a file or a directory.
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	return size, errorutils.CheckError(err)
+}
+
+// Removes the path, after making it writable. The go command makes the extracted modules read-only.
+func removeReadOnly(path string) error {
+	err := filepath.Walk(path, func(walkedPath string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() {
+			err = os.Chmod(walkedPath, info.Mode()|0200)
+		}
+		return err
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return errorutils.CheckError(err)
+	}
+	return errorutils.CheckError(os.RemoveAll(path))
+}
+
+// Reverses EscapeModulePath.
+func UnescapeModulePath(path string) string {
+	var unescaped strings.Builder
+	bang := false
+	for _, letter := range path {
+		if bang {
+			unescaped.WriteRune(unicode.ToUpper(letter))
+			bang = false
+		} else if letter == '!' {
+			bang = true
+		} else {
+			unescaped.WriteRune(letter)
+		}
+	}
+	return unescaped.String()
+}
+
+// Escapes the upper case letters in module paths to "!" followed by the lower case letter, as done in the module cache.
+func EscapeModulePath(path string) string {
+	var escaped strings.Builder
+	for _, letter := range path {
+		if unicode.IsUpper(letter) {
+			escaped.WriteString("!" + string(unicode.ToLower(letter)))
+		} else {
+			escaped.WriteRune(letter)
+		}
+	}
+	return escaped.String()
+}
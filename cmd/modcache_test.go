@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"github.com/jfrog/gocmd/gosum"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestModCache(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "modCacheTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeReadOnly(cacheDir)
+	files := map[string]string{
+		"rsc.io/quote@v1.5.2/quote.go":                                               "package quote",
+		"github.com/!azure/go-autorest@v10.15.0+incompatible/a.go":                   "package autorest",
+		"cache/download/rsc.io/quote/@v/v1.5.2.zip":                                  "zip",
+		"cache/download/rsc.io/quote/@v/v1.5.2.mod":                                  "module rsc.io/quote",
+		"cache/download/rsc.io/quote/@v/list":                                        "v1.5.2\n",
+		"cache/download/golang.org/x/text/@v/v0.3.1.mod":                             "module golang.org/x/text",
+		"cache/download/github.com/!azure/go-autorest/@v/v10.15.0+incompatible.info": "{}",
+	}
+	for name, content := range files {
+		path := filepath.Join(cacheDir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The go command makes the extracted modules read-only.
+	if err = os.Chmod(filepath.Join(cacheDir, "rsc.io", "quote@v1.5.2"), 0555); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := NewModCache(cacheDir)
+	size, err := cache.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 84 {
+		t.Error("Expecting the cache size to be 84, got:", size)
+	}
+	usages, err := cache.ModulesUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedUsages := []ModuleUsage{
+		{"github.com/Azure/go-autorest", "v10.15.0+incompatible", 18},
+		{"golang.org/x/text", "v0.3.1", 24},
+		{"rsc.io/quote", "v1.5.2", 35},
+	}
+	if !reflect.DeepEqual(expectedUsages, usages) {
+		t.Errorf("Expected: %v, Got: %v", expectedUsages, usages)
+	}
+
+	removed, err := cache.Prune([]gosum.ModuleEntry{{Path: "rsc.io/quote", Version: "v1.5.2", Hash: "h1:"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedRemoved := []string{"github.com/Azure/go-autorest@v10.15.0+incompatible", "golang.org/x/text@v0.3.1"}
+	if !reflect.DeepEqual(expectedRemoved, removed) {
+		t.Errorf("Expected: %v, Got: %v", expectedRemoved, removed)
+	}
+	if _, err = os.Stat(filepath.Join(cacheDir, "github.com", "!azure", "go-autorest@v10.15.0+incompatible")); !os.IsNotExist(err) {
+		t.Error("Expecting the extracted module to be removed")
+	}
+	if _, err = os.Stat(filepath.Join(cacheDir, "rsc.io", "quote@v1.5.2", "quote.go")); err != nil {
+		t.Error("Expecting the referenced module to be kept:", err)
+	}
+}
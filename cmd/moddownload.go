@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"strings"
+)
+
+// The details go mod download -json reports for each module.
+type ModuleDownload struct {
+	Path    string
+	Version string
+	// The error downloading the module, if any.
+	Error string
+	// The paths to the downloaded files in the module cache.
+	Info  string
+	GoMod string
+	Zip   string
+	// The path to the extracted module in the module cache.
+	Dir string
+	// The go.sum hashes of the module and of its go.mod file.
+	Sum      string
+	GoModSum string
+}
+
+// Runs go mod download -json for the modules, in the "path@version" notation, and returns the reported details.
+// Modules which failed to download are returned with their Error field set.
+func DownloadModules(ctx context.Context, modules ...string) ([]ModuleDownload, error) {
+	args := append([]string{"mod", "download", "-json"}, modules...)
+	if SkipInDryRunWithContext(ctx, "Running 'go "+strings.Join(args, " ")+"'") {
+		return nil, nil
+	}
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = args
+	reportModuleCacheMetrics(ctx, modules)
+	getLogger(ctx).Debug("Running go", strings.Join(args, " "))
+	var output string
+	err = runWithRetries(ctx, "go mod download", func() error {
+		output, _, err = runCmdWithOutputParser(goCmd, true)
+		return err
+	})
+	if ctx.Err() != nil {
+		return nil, errorutils.CheckError(ctx.Err())
+	}
+	downloads, parseErr := parseModuleDownloads(strings.NewReader(output))
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	// go mod download exits with an error if any of the modules failed. These modules are reported by their Error field.
+	if err != nil && len(downloads) == 0 {
+		return nil, errorutils.CheckError(err)
+	}
+	return downloads, nil
+}
+
+// Parses the stream of JSON objects printed by go mod download -json.
+func parseModuleDownloads(reader io.Reader) ([]ModuleDownload, error) {
+	var downloads []ModuleDownload
+	decoder := json.NewDecoder(reader)
+	for {
+		var download ModuleDownload
+		err := decoder.Decode(&download)
+		if err == io.EOF {
+			return downloads, nil
+		}
+		if err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the output of go mod download: %s", err.Error()))
+		}
+		downloads = append(downloads, download)
+	}
+}
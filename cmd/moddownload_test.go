@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseModuleDownloads(t *testing.T) {
+	output := `{
+	"Path": "rsc.io/quote",
+	"Version": "v1.5.2",
+	"Info": "/go/pkg/mod/cache/download/rsc.io/quote/@v/v1.5.2.info",
+	"GoMod": "/go/pkg/mod/cache/download/rsc.io/quote/@v/v1.5.2.mod",
+	"Zip": "/go/pkg/mod/cache/download/rsc.io/quote/@v/v1.5.2.zip",
+	"Dir": "/go/pkg/mod/rsc.io/quote@v1.5.2",
+	"Sum": "h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=",
+	"GoModSum": "h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0="
+}
+{
+	"Path": "rsc.io/missing",
+	"Version": "v1.0.0",
+	"Error": "rsc.io/missing@v1.0.0: reading https://proxy.golang.org/rsc.io/missing/@v/v1.0.0.info: 404 Not Found"
+}
+`
+	expected := []ModuleDownload{
+		{Path: "rsc.io/quote", Version: "v1.5.2",
+			Info:     "/go/pkg/mod/cache/download/rsc.io/quote/@v/v1.5.2.info",
+			GoMod:    "/go/pkg/mod/cache/download/rsc.io/quote/@v/v1.5.2.mod",
+			Zip:      "/go/pkg/mod/cache/download/rsc.io/quote/@v/v1.5.2.zip",
+			Dir:      "/go/pkg/mod/rsc.io/quote@v1.5.2",
+			Sum:      "h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=",
+			GoModSum: "h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0="},
+		{Path: "rsc.io/missing", Version: "v1.0.0", Error: "rsc.io/missing@v1.0.0: reading https://proxy.golang.org/rsc.io/missing/@v/v1.0.0.info: 404 Not Found"},
+	}
+	actual, err := parseModuleDownloads(strings.NewReader(output))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expecting: \n%v \nGot: \n%v", expected, actual)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Several modules were found under a dir which has no go.mod of its own, so the module to run in is ambiguous.
+type MultipleModulesError struct {
+	Root string
+	// The dirs of the modules, sorted.
+	Dirs []string
+}
+
+func (err *MultipleModulesError) Error() string {
+	return fmt.Sprintf("Found %d modules under %s: %s. Set the dir of the module to run in with WithModuleDir.", len(err.Dirs), err.Root, strings.Join(err.Dirs, ", "))
+}
+
+// Returns the dirs under root, including root itself, which have a go.mod file, sorted.
+// Vendor and testdata dirs, and dirs starting with "." or "_", are skipped, as the go command ignores them.
+// The dirs of modules nested in other modules are returned as well.
+func FindModuleDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() == "go.mod" {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	sort.Strings(dirs)
+	return dirs, nil
+}
+
+// Returns the dir of the module of a repository, which may not be at its root, as when its go.mod is in a src dir.
+// Returns root if it has a go.mod file. Otherwise, returns the single module under root,
+// or a MultipleModulesError if there are several, such as sibling modules.
+func FindModuleDir(root string) (string, error) {
+	dirs, err := FindModuleDirs(root)
+	if err != nil {
+		return "", err
+	}
+	if len(dirs) > 0 && dirs[0] == root {
+		return root, nil
+	}
+	switch len(dirs) {
+	case 0:
+		return "", errorutils.CheckError(errors.New("Could not find go.mod under " + root + "."))
+	case 1:
+		return dirs[0], nil
+	}
+	return "", errorutils.CheckError(&MultipleModulesError{Root: root, Dirs: dirs})
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose operations run in the module of dir.
+// The go commands run in dir, and the go.mod and go.sum files are looked up from dir up, rather than from the
+// working directory of the process. Use FindModuleDir or FindModuleDirs to find the dir.
+func WithModuleDir(ctx context.Context, dir string) context.Context {
+	options := *GetOptions(ctx)
+	options.Dir = dir
+	return WithOptions(ctx, &options)
+}
+
+// Returns the root dir where the go.mod located, searching from the dir set by WithModuleDir up,
+// or from the working directory of the process if none.
+func GetProjectRootWithContext(ctx context.Context) (string, error) {
+	return getProjectRoot(ctx)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func createModuleDirs(t *testing.T, modDirs ...string) string {
+	root, err := ioutil.TempDir("", "moduleDirsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range modDirs {
+		path := filepath.Join(root, filepath.FromSlash(dir), "go.mod")
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte("module github.com/test/"+filepath.Base(filepath.Dir(path))+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFindModuleDirs(t *testing.T) {
+	root := createModuleDirs(t, "src", "tools", "src/nested", "vendor/dep", "src/testdata/mod", ".hidden", "_examples/ex")
+	defer os.RemoveAll(root)
+
+	dirs, err := FindModuleDirs(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{filepath.Join(root, "src"), filepath.Join(root, "src", "nested"), filepath.Join(root, "tools")}
+	if !reflect.DeepEqual(expected, dirs) {
+		t.Errorf("Expecting: %v, Got: %v", expected, dirs)
+	}
+}
+
+func TestFindModuleDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		modDirs  []string
+		expected string
+		multiple bool
+	}{
+		{"root", []string{"", "src"}, "", false},
+		{"subdir", []string{"src"}, "src", false},
+		{"siblings", []string{"api", "web"}, "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := createModuleDirs(t, test.modDirs...)
+			defer os.RemoveAll(root)
+			dir, err := FindModuleDir(root)
+			if test.multiple {
+				multipleErr, ok := err.(*MultipleModulesError)
+				if !ok {
+					t.Fatalf("Expecting a MultipleModulesError, got: %v", err)
+				}
+				if len(multipleErr.Dirs) != len(test.modDirs) {
+					t.Errorf("Unexpected module dirs: %v", multipleErr.Dirs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if expected := filepath.Join(root, test.expected); dir != expected {
+				t.Errorf("Expecting: %s, Got: %s", expected, dir)
+			}
+		})
+	}
+
+	root := createModuleDirs(t)
+	defer os.RemoveAll(root)
+	if _, err := FindModuleDir(root); err == nil {
+		t.Error("Expecting an error for a dir without modules")
+	}
+}
+
+func TestWithModuleDir(t *testing.T) {
+	root := createModuleDirs(t, "src")
+	defer os.RemoveAll(root)
+	moduleDir := filepath.Join(root, "src")
+	if err := os.MkdirAll(filepath.Join(moduleDir, "pkg"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	projectRoot, err := GetProjectRootWithContext(WithModuleDir(context.Background(), filepath.Join(moduleDir, "pkg")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if projectRoot != moduleDir {
+		t.Errorf("Expecting: %s, Got: %s", moduleDir, projectRoot)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Modules required by go.sum are missing from the module cache, so they can't be resolved offline.
+type MissingModulesError struct {
+	// The missing modules in the "path@version" notation. Modules whose go.mod file is missing have a "/go.mod" suffix.
+	Modules []string
+}
+
+func (err *MissingModulesError) Error() string {
+	return fmt.Sprintf("%d modules are missing from the module cache and must be added to it before running offline: %s", len(err.Modules), strings.Join(err.Modules, ", "))
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands run without network access,
+// by setting GOPROXY=off and -mod=mod in GOFLAGS. The other flags of GOFLAGS, from the options of ctx or else from the
+// environment of the process, are kept.
+// Before returning, verifies that all the modules in the go.sum file of the project are in the module cache,
+// and returns a MissingModulesError listing the modules which are not.
+func WithOfflineMode(ctx context.Context, projectDir string) (context.Context, error) {
+	cache, err := GetModCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := gosum.ParseGoSumFileFrom(GetFileSystem(ctx), filepath.Join(projectDir, "go.sum"))
+	if err != nil {
+		return nil, err
+	}
+	if err = cache.VerifyEntries(entries); err != nil {
+		return nil, err
+	}
+	getLogger(ctx).Debug("All the", len(entries), "go.sum entries were found in the module cache", cache.Dir)
+	goFlags, ok := GetOptions(ctx).Env["GOFLAGS"]
+	if !ok {
+		goFlags = os.Getenv("GOFLAGS")
+	}
+	ctx = WithEnv(ctx, "GOFLAGS", setModFlag(goFlags, "mod"))
+	return WithEnv(ctx, "GOPROXY", "off"), nil
+}
+
+// Returns the GOFLAGS value with the -mod flag set to mode, replacing the -mod flag it may already have.
+func setModFlag(goFlags, mode string) string {
+	flags := []string{}
+	for _, flag := range strings.Fields(goFlags) {
+		if !strings.HasPrefix(flag, "-mod=") && !strings.HasPrefix(flag, "--mod=") {
+			flags = append(flags, flag)
+		}
+	}
+	return strings.Join(append(flags, "-mod="+mode), " ")
+}
+
+// Verifies that the modules and go.mod files of the go.sum entries were downloaded to the module cache.
+// Returns a MissingModulesError listing the missing ones.
+func (cache *ModCache) VerifyEntries(entries []gosum.ModuleEntry) error {
+	var missing []string
+	for _, entry := range entries {
+		path := cache.DownloadPath(entry.Path, entry.Version, ".zip")
+		id := entry.ModuleId()
+		if entry.IsMod {
+			path = cache.DownloadPath(entry.Path, entry.Version, ".mod")
+			id += "/go.mod"
+		}
+		_, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			missing = append(missing, id)
+			continue
+		}
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return &MissingModulesError{Modules: missing}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/gosum"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVerifyEntries(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "offlineTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(cacheDir)
+	for _, name := range []string{"rsc.io/quote/@v/v1.5.2.zip", "rsc.io/quote/@v/v1.5.2.mod", "github.com/!azure/go-autorest/@v/v10.15.0+incompatible.mod"} {
+		path := filepath.Join(cacheDir, "cache", "download", filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache := NewModCache(cacheDir)
+	entries := []gosum.ModuleEntry{
+		{Path: "rsc.io/quote", Version: "v1.5.2"},
+		{Path: "rsc.io/quote", Version: "v1.5.2", IsMod: true},
+		{Path: "github.com/Azure/go-autorest", Version: "v10.15.0+incompatible", IsMod: true},
+	}
+	if err = cache.VerifyEntries(entries); err != nil {
+		t.Error(err)
+	}
+
+	entries = append(entries, gosum.ModuleEntry{Path: "github.com/Azure/go-autorest", Version: "v10.15.0+incompatible"}, gosum.ModuleEntry{Path: "golang.org/x/text", Version: "v0.3.1", IsMod: true})
+	missingErr, ok := cache.VerifyEntries(entries).(*MissingModulesError)
+	if !ok {
+		t.Fatal("Expecting a MissingModulesError")
+	}
+	expected := []string{"github.com/Azure/go-autorest@v10.15.0+incompatible", "golang.org/x/text@v0.3.1/go.mod"}
+	if !reflect.DeepEqual(expected, missingErr.Modules) {
+		t.Errorf("Expected: %v, Got: %v", expected, missingErr.Modules)
+	}
+}
+
+func TestSetModFlag(t *testing.T) {
+	tests := []struct {
+		goFlags  string
+		expected string
+	}{
+		{"", "-mod=mod"},
+		{"-tags=integration", "-tags=integration -mod=mod"},
+		{"-mod=vendor -tags=integration", "-tags=integration -mod=mod"},
+		{"--mod=readonly  -trimpath -mod=vendor", "-trimpath -mod=mod"},
+	}
+	for _, test := range tests {
+		if actual := setModFlag(test.goFlags, "mod"); actual != test.expected {
+			t.Errorf("GOFLAGS %q: Expected: %q, Got: %q", test.goFlags, test.expected, actual)
+		}
+	}
+}
+
+func TestWithOfflineModeKeepsGoFlags(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "offlineTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	if err = ioutil.WriteFile(filepath.Join(projectDir, "go.sum"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("GOFLAGS", os.Getenv("GOFLAGS"))
+	if err = os.Setenv("GOFLAGS", "-mod=vendor -tags=process"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := WithOfflineMode(context.Background(), projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goFlags := GetOptions(ctx).Env["GOFLAGS"]; goFlags != "-tags=process -mod=mod" {
+		t.Errorf("Expecting the GOFLAGS of the process with -mod=mod, got: %q", goFlags)
+	}
+	ctx, err = WithOfflineMode(WithEnv(context.Background(), "GOFLAGS", "-mod=readonly -trimpath"), projectDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goFlags := GetOptions(ctx).Env["GOFLAGS"]; goFlags != "-trimpath -mod=mod" {
+		t.Errorf("Expecting the GOFLAGS of the context with -mod=mod, got: %q", goFlags)
+	}
+}
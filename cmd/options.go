@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/gocmd/log"
+	"github.com/jfrog/gocmd/store"
+	"io"
+	"time"
+)
+
+type optionsKey struct{}
+
+// Options applied to the go commands run with a context returned by WithOptions.
+type Options struct {
+	// Environment variables, such as GOFLAGS, GONOSUMCHECK or GOINSECURE, set only for the go commands,
+	// without changing the environment of the process.
+	Env map[string]string
+	// The command used for resolving the dependencies of the project. Defaults to go mod graph.
+	Resolution ResolutionMode
+	// Receive the output lines of the go commands while they run.
+	OnStdoutLine LineCallback
+	OnStderrLine LineCallback
+	// Controls what the go commands write to Stderr. See WithOutput.
+	OutputMode OutputMode
+	// Where the go commands write their passed through stderr and the lines matched by the error patterns.
+	// Defaults to the stderr of the process.
+	Stderr io.Writer
+	// The working directory of the go commands. Defaults to the working directory of the process.
+	Dir string
+	// The go binary running the go commands. Defaults to the one set by SetGoExecutable, or the go binary in the PATH.
+	Executable *GoExecutable
+	// The patterns the output of the go commands is matched against. Defaults to the registry returned by GetPatternRegistry.
+	Patterns *PatternRegistry
+	// Defines how failing go commands are retried. Defaults to the policy set by SetRetryPolicy.
+	RetryPolicy *RetryPolicy
+	// Receives the log entries of the go commands. Defaults to the logger set by log.SetLogger.
+	Logger log.Logger
+	// Runs the go commands. By default, they run as processes whose output is processed while they run.
+	Executor Executor
+	// Traces the go commands. By default, they aren't traced.
+	Tracer Tracer
+	// Receives the metrics of the go commands. By default, they aren't collected.
+	Metrics MetricsCollector
+	// The time each go command may run before it is interrupted, and then killed after the grace period. See WithTimeout.
+	Timeout            time.Duration
+	TimeoutGracePeriod time.Duration
+	// Persists the results of the dependency resolution commands. See WithIncrementalResolution.
+	ResolutionStore store.Store
+	// Collects the warnings matched in the output of the go commands. See WithWarnings.
+	Warnings *Warnings
+	// Creates the temp directories of the operations. Defaults to temp directories of the OS. See WithTempDirProvider.
+	TempDirs TempDirProvider
+	// Reads and writes the go.mod and go.sum files. Defaults to the files on disk. See WithFileSystem.
+	FileSystem fsys.FileSystem
+	// Controls fetching modules directly from their VCS. See WithVcs.
+	DirectFallback DirectFallback
+	// Limits the rate of the module downloads and of the proxy requests. By default, they aren't limited. See WithRateLimiter.
+	RateLimiter *RateLimiter
+	// Collects the results of the go commands. See WithRunResults.
+	Results *RunResults
+	// Receive the events of the operations. See WithEventListener.
+	EventListeners []EventListener
+	// Logs the file changes and the go commands changing the project or the module cache instead of performing them.
+	// The global dry run mode set by SetDryRun applies as well. See WithDryRun.
+	DryRun bool
+}
+
+// Returns a copy of ctx carrying the options. All the go commands run with the returned context, or with contexts
+// derived from it, use the options. This allows running several flows concurrently, each with its own environment.
+func WithOptions(ctx context.Context, options *Options) context.Context {
+	return context.WithValue(ctx, optionsKey{}, options)
+}
+
+// Returns a copy of ctx carrying the options of ctx, with the environment variable added.
+func WithEnv(ctx context.Context, key, value string) context.Context {
+	env := map[string]string{}
+	for existingKey, existingValue := range GetOptions(ctx).Env {
+		env[existingKey] = existingValue
+	}
+	env[key] = value
+	options := *GetOptions(ctx)
+	options.Env = env
+	return WithOptions(ctx, &options)
+}
+
+// Returns a copy of ctx carrying the options of ctx, with the resolution mode set.
+func WithResolution(ctx context.Context, mode ResolutionMode) context.Context {
+	options := *GetOptions(ctx)
+	options.Resolution = mode
+	return WithOptions(ctx, &options)
+}
+
+// Returns the options carried by ctx, or empty options if none.
+func GetOptions(ctx context.Context) *Options {
+	if ctx != nil {
+		if options, ok := ctx.Value(optionsKey{}).(*Options); ok && options != nil {
+			return options
+		}
+	}
+	return &Options{}
+}
+
+// Returns an entry logging to the logger of the options carried by ctx, or to the logger set by log.SetLogger if none.
+func GetLogger(ctx context.Context) *log.Entry {
+	return getLogger(ctx)
+}
+
+func getLogger(ctx context.Context) *log.Entry {
+	return log.NewEntry(GetOptions(ctx).Logger)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"testing"
+)
+
+func TestOptionsEnv(t *testing.T) {
+	ctx := WithOptions(context.Background(), &Options{Env: map[string]string{"GOFLAGS": "-mod=vendor"}})
+	ctx = WithEnv(ctx, "GOINSECURE", "example.com")
+	goCmd := &Cmd{Context: ctx, Go: "go", Env: GetOptions(ctx).Env, Command: []string{"env"}}
+	execCmd := goCmd.GetCmd()
+
+	expected := map[string]bool{"GOFLAGS=-mod=vendor": false, "GOINSECURE=example.com": false}
+	for _, variable := range execCmd.Env {
+		if _, exists := expected[variable]; exists {
+			expected[variable] = true
+		}
+	}
+	for variable, found := range expected {
+		if !found {
+			t.Error("Expecting the command environment to include", variable)
+		}
+	}
+	if os.Getenv("GOINSECURE") == "example.com" {
+		t.Error("Expecting the process environment to stay unchanged")
+	}
+	if len(goCmd.GetEnv()) != 0 {
+		t.Error("Expecting GetEnv to be empty, got:", goCmd.GetEnv())
+	}
+}
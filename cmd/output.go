@@ -0,0 +1,357 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/log"
+	gofrogio "github.com/jfrog/gofrog/io"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The longest output line which is scanned. Longer lines fail the command.
+const maxOutputLineSize = 1 << 20
+
+// Called with each line printed by the go command, as soon as it is printed.
+type LineCallback func(line string)
+
+// Controls what the go commands write to the stderr writer of their options.
+type OutputMode int
+
+const (
+	// The stderr of the go commands whose output is meant for the user, such as go build, is passed through,
+	// and the lines matched by the error patterns are written.
+	DefaultOutput OutputMode = iota
+	// The stderr of all the go commands is passed through, and the lines matched by the error patterns are written.
+	VerboseOutput
+	// Nothing is written. The output is still returned and passed to the line callbacks.
+	QuietOutput
+)
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands write their output to stderr as set by the mode.
+// If stderr is nil, the output is written to the stderr of the process. Pass a buffer to keep it, or ioutil.Discard to drop it.
+func WithOutput(ctx context.Context, mode OutputMode, stderr io.Writer) context.Context {
+	options := *GetOptions(ctx)
+	options.OutputMode = mode
+	options.Stderr = stderr
+	return WithOptions(ctx, &options)
+}
+
+// Returns a copy of ctx carrying the options of ctx, with the callbacks receiving the output lines of the go commands.
+// Either callback may be nil. The callbacks of stdout and stderr may be called concurrently.
+func WithOutputCallbacks(ctx context.Context, onStdoutLine, onStderrLine LineCallback) context.Context {
+	options := *GetOptions(ctx)
+	options.OnStdoutLine = onStdoutLine
+	options.OnStderrLine = onStderrLine
+	return WithOptions(ctx, &options)
+}
+
+// Runs the command, passing each output line through the patterns and then to the line callbacks of the command's options.
+// The lines are processed while the command runs, rather than after it is done.
+// If prompt is true, the stderr lines are also printed to the stderr of the process.
+// Returns the stdout and stderr, and an ExitError if the command exited with a non-zero exit code.
+// The ExitError wraps the errors returned by the patterns, or the error of the command if none, so errors.As
+// matches both. Several errors returned by the patterns are returned as a MultiError.
+func runCmdWithOutputParser(goCmd *Cmd, prompt bool, patterns ...*gofrogio.CmdOutputPattern) (stdout string, stderr string, err error) {
+	result, err := runCmdWithResult(goCmd, prompt, patterns...)
+	return result.Stdout, result.Stderr, err
+}
+
+// Runs the command as runCmdWithOutputParser does, and returns the result of the run, which is never nil.
+// The result is also added to the RunResults of the command's options.
+func runCmdWithResult(goCmd *Cmd, prompt bool, patterns ...*gofrogio.CmdOutputPattern) (*RunResult, error) {
+	ctx, span := startCmdSpan(goCmd)
+	tracedCmd := *goCmd
+	tracedCmd.Context = ctx
+	parser := newOutputParser(goCmd, patterns)
+	start := time.Now()
+	result := &RunResult{Cmd: getCmdArgs(goCmd)}
+	var err error
+	if executor := GetOptions(goCmd.Context).Executor; executor != nil {
+		result.Stdout, result.Stderr, result.ExitCode, err = runCmdWithExecutor(executor, &tracedCmd, prompt, parser)
+	} else {
+		result.Stdout, result.Stderr, result.ExitCode, err = runCmdProcess(&tracedCmd, prompt, parser)
+	}
+	result.Duration = time.Since(start)
+	result.MatchedPatterns = parser.matchedPatterns()
+	result.Err = err
+	reportCmdMetrics(goCmd, result.Duration, result.Stderr, err)
+	span.SetAttribute(ExitCodeAttribute, result.ExitCode)
+	span.End(err)
+	if results := GetOptions(goCmd.Context).Results; results != nil {
+		results.add(result)
+	}
+	return result, err
+}
+
+// Runs the command as a process. Returns its exit code, or -1 if it couldn't run or was killed.
+func runCmdProcess(goCmd *Cmd, prompt bool, parser *outputParser) (string, string, int, error) {
+	execCmd := goCmd.GetCmd()
+	stdoutReader, err := execCmd.StdoutPipe()
+	if err != nil {
+		return "", "", -1, err
+	}
+	stderrReader, err := execCmd.StderrPipe()
+	if err != nil {
+		return "", "", -1, err
+	}
+	if err = execCmd.Start(); err != nil {
+		return "", "", -1, err
+	}
+
+	options := GetOptions(goCmd.Context)
+	watcher := watchTimeout(execCmd.Process, options.Timeout, options.TimeoutGracePeriod)
+	var stdoutBuilder, stderrBuilder strings.Builder
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		parser.scan(stdoutReader, &stdoutBuilder, options.OnStdoutLine, nil)
+	}()
+	go func() {
+		defer wg.Done()
+		parser.scan(stderrReader, &stderrBuilder, options.OnStderrLine, parser.promptWriter(prompt))
+	}()
+	wg.Wait()
+	waitErr := execCmd.Wait()
+	exitCode := execCmd.ProcessState.ExitCode()
+	if timedOut, killed := watcher.stop(); timedOut {
+		return stdoutBuilder.String(), stderrBuilder.String(), exitCode, &TimeoutError{Command: getCmdName(goCmd), Timeout: options.Timeout, Killed: killed, Stdout: stdoutBuilder.String(), Stderr: stderrBuilder.String()}
+	}
+	patternErr := parser.err()
+	if waitErr != nil {
+		if patternErr != nil {
+			waitErr = patternErr
+		}
+		return stdoutBuilder.String(), stderrBuilder.String(), exitCode, &ExitError{Command: getCmdName(goCmd), ExitCode: exitCode, Stderr: stderrBuilder.String(), Err: waitErr}
+	}
+	return stdoutBuilder.String(), stderrBuilder.String(), exitCode, patternErr
+}
+
+// Runs the command with the executor, and passes its output lines through the patterns and then to the line callbacks
+// once it is done.
+func runCmdWithExecutor(executor Executor, goCmd *Cmd, prompt bool, parser *outputParser) (string, string, int, error) {
+	parentCtx := goCmd.Context
+	if parentCtx == nil {
+		parentCtx = context.Background()
+	}
+	ctx := parentCtx
+	options := GetOptions(goCmd.Context)
+	// Executors aren't necessarily running processes which can be interrupted, so they are only cancelled.
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+	cmd := append([]string{goCmd.Go}, goCmd.Command...)
+	cmd = append(cmd, goCmd.CommandFlags...)
+	stdout, stderr, exitCode, err := executor.Run(ctx, cmd, goCmd.Env, goCmd.Dir)
+	if options.Timeout > 0 && ctx.Err() == context.DeadlineExceeded && parentCtx.Err() == nil {
+		return stdout, stderr, exitCode, &TimeoutError{Command: getCmdName(goCmd), Timeout: options.Timeout, Killed: true, Stdout: stdout, Stderr: stderr}
+	}
+	if err != nil {
+		return "", "", -1, err
+	}
+
+	var stdoutBuilder, stderrBuilder strings.Builder
+	parser.scan(strings.NewReader(stdout), &stdoutBuilder, options.OnStdoutLine, nil)
+	parser.scan(strings.NewReader(stderr), &stderrBuilder, options.OnStderrLine, parser.promptWriter(prompt))
+	patternErr := parser.err()
+	if exitCode != 0 {
+		err = patternErr
+		if err == nil {
+			err = fmt.Errorf("exit status %d", exitCode)
+		}
+		return stdoutBuilder.String(), stderrBuilder.String(), exitCode, &ExitError{Command: getCmdName(goCmd), ExitCode: exitCode, Stderr: stderrBuilder.String(), Err: err}
+	}
+	return stdoutBuilder.String(), stderrBuilder.String(), exitCode, patternErr
+}
+
+type outputParser struct {
+	// Guards the patterns, which keep the matched line, and the errors.
+	mutex    sync.Mutex
+	patterns []*gofrogio.CmdOutputPattern
+	// The names of the patterns, by their index.
+	names []string
+	// The names of the patterns which matched, in the order they first matched.
+	matched []string
+	errs    []error
+	// Receives the warnings returned by the patterns. May be nil.
+	warnings *Warnings
+	logger   *log.Entry
+	// Receives the lines matched by the patterns returning errors, and the passed through stderr. Nil in quiet mode.
+	stderr io.Writer
+	mode   OutputMode
+}
+
+// Creates a parser matching copies of the patterns. The patterns keep the line they matched,
+// so each command matches its own copies, allowing commands to share the same registry concurrently.
+// The warnings returned by the patterns are logged, and collected by the Warnings of the command's options.
+// The lines matched by the patterns returning errors are written to the stderr writer of the options, unless in quiet mode.
+// The patterns are named by the names they are registered under in the registry of the command's options,
+// or by their regexps if they aren't registered.
+func newOutputParser(goCmd *Cmd, patterns []*gofrogio.CmdOutputPattern) *outputParser {
+	options := GetOptions(goCmd.Context)
+	parser := &outputParser{warnings: options.Warnings, logger: getLogger(goCmd.Context), mode: options.OutputMode}
+	if options.OutputMode != QuietOutput {
+		stderr := options.Stderr
+		if stderr == nil {
+			stderr = os.Stderr
+		}
+		parser.stderr = &syncWriter{writer: stderr}
+	}
+	registry, _ := getPatternRegistry(goCmd.Context)
+	for _, pattern := range patterns {
+		patternCopy := *pattern
+		parser.patterns = append(parser.patterns, &patternCopy)
+		parser.names = append(parser.names, patternName(registry, pattern))
+	}
+	return parser
+}
+
+func patternName(registry *PatternRegistry, pattern *gofrogio.CmdOutputPattern) string {
+	if registry != nil {
+		if name, ok := registry.nameOf(pattern); ok {
+			return name
+		}
+	}
+	if pattern.RegExp == nil {
+		return ""
+	}
+	return pattern.RegExp.String()
+}
+
+func (parser *outputParser) scan(reader io.Reader, output *strings.Builder, callback LineCallback, promptWriter io.Writer) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxOutputLineSize)
+	for scanner.Scan() {
+		line := parser.match(scanner.Text(), promptWriter != nil)
+		output.WriteString(line + "\n")
+		if promptWriter != nil {
+			fmt.Fprintln(promptWriter, line)
+		}
+		if callback != nil {
+			callback(line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		parser.setErr(err)
+		// Drain the rest of the output, so the command doesn't block on a full pipe.
+		io.Copy(ioutil.Discard, reader)
+	}
+}
+
+// Passes the line through the matching patterns, and returns the line as modified by them.
+// The patterns following the first one returning an error are skipped, as the line already failed the command.
+// The line is written to stderr by the pattern returning the error, unless echoed, as when stderr is passed through.
+func (parser *outputParser) match(line string, echoed bool) string {
+	parser.mutex.Lock()
+	defer parser.mutex.Unlock()
+	for i, pattern := range parser.patterns {
+		matched := pattern.RegExp.FindStringSubmatch(line)
+		if len(matched) == 0 {
+			continue
+		}
+		if !contains(parser.matched, parser.names[i]) {
+			parser.matched = append(parser.matched, parser.names[i])
+		}
+		pattern.Line = line
+		pattern.MatchedResults = matched
+		modifiedLine, err := pattern.ExecFunc(pattern)
+		if warning, ok := err.(*Warning); ok {
+			parser.addWarning(warning)
+			continue
+		}
+		if err != nil {
+			if !echoed {
+				parser.printLine(line)
+			}
+			parser.addErr(err)
+			return line
+		}
+		line = modifiedLine
+	}
+	return line
+}
+
+// Returns the writer the stderr lines are passed through to, or nil if they aren't.
+// In the default mode, only the stderr of the commands which prompt is passed through.
+func (parser *outputParser) promptWriter(prompt bool) io.Writer {
+	if parser.stderr == nil || (parser.mode == DefaultOutput && !prompt) {
+		return nil
+	}
+	return parser.stderr
+}
+
+// Writes a line matched by a pattern returning an error, unless in quiet mode. Must be called with the mutex locked.
+func (parser *outputParser) printLine(line string) {
+	if parser.stderr == nil {
+		return
+	}
+	if _, err := fmt.Fprintln(parser.stderr, line); err != nil {
+		parser.addErr(errorutils.CheckError(err))
+	}
+}
+
+func (parser *outputParser) setErr(err error) {
+	parser.mutex.Lock()
+	defer parser.mutex.Unlock()
+	parser.addErr(err)
+}
+
+// Adds the error, unless an error with the same message was already added, as when several lines report the same failure.
+// Must be called with the mutex locked.
+func (parser *outputParser) addErr(err error) {
+	for _, existing := range parser.errs {
+		if existing.Error() == err.Error() {
+			return
+		}
+	}
+	parser.errs = append(parser.errs, err)
+}
+
+// Must be called with the mutex locked.
+func (parser *outputParser) addWarning(warning *Warning) {
+	parser.logger.WithFields(log.Fields{"pattern": warning.Pattern, "module": warning.Module}).Warn(warning.Line)
+	if parser.warnings != nil {
+		parser.warnings.add(*warning)
+	}
+}
+
+// Returns the names of the patterns which matched, in the order they first matched.
+func (parser *outputParser) matchedPatterns() []string {
+	parser.mutex.Lock()
+	defer parser.mutex.Unlock()
+	return append([]string(nil), parser.matched...)
+}
+
+// Returns nil if no errors were added, the error if one was added, or a MultiError of all the added errors.
+func (parser *outputParser) err() error {
+	parser.mutex.Lock()
+	defer parser.mutex.Unlock()
+	switch len(parser.errs) {
+	case 0:
+		return nil
+	case 1:
+		return parser.errs[0]
+	}
+	return &MultiError{Errors: append([]error(nil), parser.errs...)}
+}
+
+// Serializes the writes of the stdout and stderr scanners, which run concurrently.
+type syncWriter struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+func (writer *syncWriter) Write(p []byte) (int, error) {
+	writer.mutex.Lock()
+	defer writer.mutex.Unlock()
+	return writer.writer.Write(p)
+}
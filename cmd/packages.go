@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"sort"
+	"strings"
+)
+
+// The details go list -json reports for each package.
+type ListedPackage struct {
+	ImportPath string
+	Name       string
+	Dir        string
+	// True for the packages of the standard library.
+	Standard bool
+	// True for packages which aren't matched by the patterns, and are listed only because they are dependencies.
+	DepOnly bool
+	// The module of the package, nil for the packages of the standard library.
+	Module  *ListedModule
+	Imports []string
+	Error   *ListedPackageError
+}
+
+type ListedPackageError struct {
+	Pos string
+	Err string
+}
+
+// Runs go list -deps -json for the package patterns, "./..." if empty, and returns the packages matched by the patterns
+// and all the packages they import, directly or indirectly. The go.mod and go.sum files are left unchanged.
+func ListPackages(ctx context.Context, patterns ...string) ([]ListedPackage, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	output, err := runWithUnchangedModFiles(ctx, false, append([]string{"list", "-deps", "-json"}, patterns...)...)
+	if err != nil {
+		return nil, err
+	}
+	return parseListedPackages(strings.NewReader(output))
+}
+
+// Runs go list -deps -json ./... and returns the sorted import paths of the packages of each dependency which are used
+// by the main module, by the "path@version" notation of the dependency.
+// Replaced dependencies are returned by the path and version they are required by.
+func GetUsedPackages(ctx context.Context) (map[string][]string, error) {
+	packages, err := ListPackages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return packagesByModule(packages), nil
+}
+
+// Parses the stream of JSON objects printed by go list -json.
+func parseListedPackages(reader io.Reader) ([]ListedPackage, error) {
+	var packages []ListedPackage
+	decoder := json.NewDecoder(reader)
+	for {
+		var listedPackage ListedPackage
+		err := decoder.Decode(&listedPackage)
+		if err == io.EOF {
+			return packages, nil
+		}
+		if err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the output of go list: %s", err.Error()))
+		}
+		packages = append(packages, listedPackage)
+	}
+}
+
+func packagesByModule(packages []ListedPackage) map[string][]string {
+	modulesPackages := map[string][]string{}
+	for _, listedPackage := range packages {
+		if listedPackage.Module == nil || listedPackage.Module.Main {
+			continue
+		}
+		module := listedPackage.Module.Path + "@" + listedPackage.Module.Version
+		modulesPackages[module] = append(modulesPackages[module], listedPackage.ImportPath)
+	}
+	for _, modulePackages := range modulesPackages {
+		sort.Strings(modulePackages)
+	}
+	return modulesPackages
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const listPackagesOutput = `{
+	"Dir": "/usr/local/go/src/errors",
+	"ImportPath": "errors",
+	"Name": "errors",
+	"Standard": true,
+	"DepOnly": true
+}
+{
+	"Dir": "/home/you/go/pkg/mod/golang.org/x/text@v0.3.2/language",
+	"ImportPath": "golang.org/x/text/language",
+	"Name": "language",
+	"Module": {
+		"Path": "golang.org/x/text",
+		"Version": "v0.3.2"
+	},
+	"Imports": ["errors", "golang.org/x/text/internal/tag"],
+	"DepOnly": true
+}
+{
+	"Dir": "/home/you/go/pkg/mod/golang.org/x/text@v0.3.2/internal/tag",
+	"ImportPath": "golang.org/x/text/internal/tag",
+	"Name": "tag",
+	"Module": {
+		"Path": "golang.org/x/text",
+		"Version": "v0.3.2"
+	},
+	"DepOnly": true
+}
+{
+	"Dir": "/home/you/quote",
+	"ImportPath": "rsc.io/quote",
+	"Name": "quote",
+	"Module": {
+		"Path": "rsc.io/quote",
+		"Version": "v1.5.2",
+		"Replace": {
+			"Path": "../quote",
+			"Dir": "/home/you/quote"
+		}
+	},
+	"Imports": ["golang.org/x/text/language"],
+	"DepOnly": true
+}
+{
+	"Dir": "/home/you/hello",
+	"ImportPath": "github.com/you/hello",
+	"Name": "main",
+	"Module": {
+		"Path": "github.com/you/hello",
+		"Main": true
+	},
+	"Imports": ["rsc.io/quote"],
+	"Error": {
+		"Pos": "hello.go:3:2",
+		"Err": "imported and not used"
+	}
+}
+`
+
+func TestListPackages(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: listPackagesOutput}, "list", "-deps", "-json", "./...")
+	packages, err := ListPackages(WithExecutor(context.Background(), executor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packages) != 5 {
+		t.Fatal("Expecting 5 packages, got:", len(packages))
+	}
+	if !packages[0].Standard || packages[0].Module != nil {
+		t.Error("Expecting a standard library package, got:", packages[0])
+	}
+	if packages[3].Module.Replace == nil || !reflect.DeepEqual(packages[3].Imports, []string{"golang.org/x/text/language"}) {
+		t.Error("Expecting a package of a replaced module, got:", packages[3])
+	}
+	if packages[4].DepOnly || packages[4].Error == nil || packages[4].Error.Pos != "hello.go:3:2" {
+		t.Error("Expecting a main package with an error, got:", packages[4])
+	}
+
+	expected := map[string][]string{
+		"golang.org/x/text@v0.3.2": {"golang.org/x/text/internal/tag", "golang.org/x/text/language"},
+		"rsc.io/quote@v1.5.2":      {"rsc.io/quote"},
+	}
+	if actual := packagesByModule(packages); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
+
+func TestGetUsedPackagesReadonly(t *testing.T) {
+	projectDir, ctx := createFileProxyProject(t)
+	defer os.RemoveAll(filepath.Dir(projectDir))
+	err := RunInSandbox(ctx, func(ctx context.Context) error {
+		usedPackages, err := GetUsedPackages(ctx)
+		if err != nil {
+			return err
+		}
+		expected := map[string][]string{"example.com/dep@v1.0.0": {"example.com/dep"}}
+		if !reflect.DeepEqual(expected, usedPackages) {
+			t.Errorf("Expected: %v, Got: %v", expected, usedPackages)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,330 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/log"
+	gofrogio "github.com/jfrog/gofrog/io"
+	"github.com/jfrog/jfrog-client-go/utils"
+	"os"
+	"sync"
+)
+
+// Names of the built-in output patterns.
+const (
+	CredentialsPattern        = "credentials"
+	NotFoundPattern           = "notFound"
+	UnrecognizedImportPattern = "unrecognizedImport"
+	UnknownRevisionPattern    = "unknownRevision"
+	NotFoundZipPattern        = "notFoundZip"
+	GitFetchPattern           = "gitFetch"
+	VcsDisallowedPattern      = "vcsDisallowed"
+	NoMatchingVersionsPattern = "noMatchingVersions"
+	PackageNotInModulePattern = "packageNotInModule"
+	MissingGoSumEntryPattern  = "missingGoSumEntry"
+	NoRequiredModulePattern   = "noRequiredModule"
+	CannotFindModulePattern   = "cannotFindModule"
+	InvalidVersionPattern     = "invalidVersion"
+	DeprecatedPattern         = "deprecated"
+	ToolchainSwitchPattern    = "toolchainSwitch"
+	FindingModulePattern      = "findingModule"
+)
+
+var defaultRegistry *PatternRegistry
+var defaultRegistryMutex sync.Mutex
+
+// Holds the patterns which the output of the go commands is matched against.
+// Patterns are matched in the order they were registered.
+type PatternRegistry struct {
+	mutex    sync.Mutex
+	names    []string
+	patterns map[string]*gofrogio.CmdOutputPattern
+	// The go versions the patterns apply to, for the patterns registered with RegisterVersioned.
+	versions map[string]versionRange
+}
+
+// The go versions from min, inclusive, to max, exclusive. An empty bound is unbounded.
+type versionRange struct {
+	min string
+	max string
+}
+
+// Creates an empty registry.
+func NewPatternRegistry() *PatternRegistry {
+	return &PatternRegistry{patterns: map[string]*gofrogio.CmdOutputPattern{}, versions: map[string]versionRange{}}
+}
+
+// Creates a registry with the built-in patterns.
+func NewDefaultPatternRegistry() (*PatternRegistry, error) {
+	registry := NewPatternRegistry()
+	// The lines are masked before they are matched against the other patterns, which keep the lines in the errors.
+	log.Debug("Initializing", CredentialsPattern, "regexp")
+	if err := registry.RegisterRegExp(CredentialsPattern, utils.CredentialsInUrlRegexp, MaskCredentials); err != nil {
+		return nil, err
+	}
+	for _, rule := range defaultMaskingRules {
+		registry.RegisterMaskingRule(rule)
+	}
+	// The versions are those of the go releases which introduced or replaced the messages. Patterns without versions
+	// apply to all the versions.
+	builtIns := []struct {
+		name       string
+		regex      string
+		execFunc   func(pattern *gofrogio.CmdOutputPattern) (string, error)
+		minVersion string
+		maxVersion string
+	}{
+		{NotFoundPattern, `^go: ([^\/\r\n]+\/[^\r\n\s:]*).*(404( Not Found)?[\s]?)$`, ModuleNotFound, "", ""},
+		// Precedes the unrecognized import pattern, which also matches the lines of modules disallowed by GOVCS.
+		{VcsDisallowedPattern, `(?:([^\s:@]+@[^\s:]+): .*)?GOVCS disallows using (\S+) for (public|private) (\S+);`, VcsDisallowed, "", ""},
+		{UnrecognizedImportPattern, `[^go:]([^\/\r\n]+\/[^\r\n\s:]*).*(unrecognized import path)`, UnrecognizedImport, "", ""},
+		{UnknownRevisionPattern, `[^go:]([^\/\r\n]+\/[^\r\n\s:]*).*(unknown revision)`, UnknownRevision, "", ""},
+		{NotFoundZipPattern, `unknown import path ["]([^\/\r\n]+\/[^\r\n\s:]*)["].*(404( Not Found)?[\s]?)$`, ModuleNotFound, "", ""},
+		{GitFetchPattern, `git fetch (?:-\S+ )*(\S+) .*exit status (\d+)`, GitFetchFailed, "", ""},
+		{NoMatchingVersionsPattern, `([^\s:@"]+\/[^\s:@"]*)(?:@\S+)?: no matching versions for query "([^"]*)"`, NoMatchingVersions, "", ""},
+		{PackageNotInModulePattern, `module ([^\s@]+)(?:@\S+)? found \(([^,)\s]+)[^)]*\), but does not contain package (\S+)`, PackageNotInModule, "1.14", ""},
+		{MissingGoSumEntryPattern, `(?:([^\s:@]+@[^\s:]+): )?missing go\.sum entry(?: for module providing package ([^\s;]+))?( for go\.mod file)?`, MissingGoSumEntry, "1.16", ""},
+		{NoRequiredModulePattern, `no required module provides package ([^\s;]+)`, NoRequiredModule, "1.16", ""},
+		{CannotFindModulePattern, `cannot find module providing package ([^\s;:]+)`, NoRequiredModule, "", "1.16"},
+		{InvalidVersionPattern, `([^\s:@]+@[^\s:]+): invalid version: (.+)$`, InvalidVersion, "1.13", ""},
+		{DeprecatedPattern, `module (\S+) is deprecated`, WarningHandler(DeprecatedPattern), "", ""},
+		{ToolchainSwitchPattern, `^go: (?:downloading|switching to) (go\d\S*)`, WarningHandler(ToolchainSwitchPattern), "", ""},
+		{FindingModulePattern, `^go: finding module for package (\S+)`, WarningHandler(FindingModulePattern), "", ""},
+	}
+	for _, builtIn := range builtIns {
+		log.Debug("Initializing", builtIn.name, "regexp")
+		err := registry.RegisterVersionedRegExp(builtIn.name, builtIn.regex, builtIn.execFunc, builtIn.minVersion, builtIn.maxVersion)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Returns the registry used by the go commands of this package.
+// Patterns registered on it or removed from it apply to all the following go commands.
+//
+// Deprecated: the registry is shared by all the go commands of the process. Use the Patterns of a Runner instead.
+func GetPatternRegistry() (*PatternRegistry, error) {
+	defaultRegistryMutex.Lock()
+	defer defaultRegistryMutex.Unlock()
+	if defaultRegistry == nil {
+		registry, err := NewDefaultPatternRegistry()
+		if err != nil {
+			return nil, err
+		}
+		defaultRegistry = registry
+	}
+	return defaultRegistry, nil
+}
+
+// Returns the registry of the options carried by ctx, or the default registry if none.
+func getPatternRegistry(ctx context.Context) (*PatternRegistry, error) {
+	if registry := GetOptions(ctx).Patterns; registry != nil {
+		return registry, nil
+	}
+	return GetPatternRegistry()
+}
+
+// Registers the pattern under the given name, for all the go versions.
+// A pattern previously registered under the same name is replaced, keeping its position.
+func (registry *PatternRegistry) Register(name string, pattern *gofrogio.CmdOutputPattern) {
+	registry.RegisterVersioned(name, pattern, "", "")
+}
+
+// Registers the pattern under the given name, for the go versions from minVersion, inclusive, to maxVersion, exclusive,
+// such as "1.16" and "1.17". Either bound may be empty. Used for output formats which changed between go releases.
+// A pattern previously registered under the same name is replaced, keeping its position.
+func (registry *PatternRegistry) RegisterVersioned(name string, pattern *gofrogio.CmdOutputPattern, minVersion, maxVersion string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if _, exists := registry.patterns[name]; !exists {
+		registry.names = append(registry.names, name)
+	}
+	registry.patterns[name] = pattern
+	if minVersion == "" && maxVersion == "" {
+		delete(registry.versions, name)
+	} else {
+		registry.versions[name] = versionRange{min: minVersion, max: maxVersion}
+	}
+}
+
+// Compiles the regex and registers it under the given name with the function handling its matches.
+func (registry *PatternRegistry) RegisterRegExp(name, regex string, execFunc func(pattern *gofrogio.CmdOutputPattern) (string, error)) error {
+	pattern, err := initRegExp(regex, execFunc)
+	if err != nil {
+		return err
+	}
+	registry.Register(name, pattern)
+	return nil
+}
+
+// Compiles the regex and registers it under the given name with the function handling its matches,
+// for the go versions from minVersion to maxVersion. See RegisterVersioned.
+func (registry *PatternRegistry) RegisterVersionedRegExp(name, regex string, execFunc func(pattern *gofrogio.CmdOutputPattern) (string, error), minVersion, maxVersion string) error {
+	pattern, err := initRegExp(regex, execFunc)
+	if err != nil {
+		return err
+	}
+	registry.RegisterVersioned(name, pattern, minVersion, maxVersion)
+	return nil
+}
+
+// Removes the pattern registered under the given name, if exists.
+func (registry *PatternRegistry) Remove(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	if _, exists := registry.patterns[name]; !exists {
+		return
+	}
+	delete(registry.patterns, name)
+	delete(registry.versions, name)
+	for i, registeredName := range registry.names {
+		if registeredName == name {
+			registry.names = append(registry.names[:i], registry.names[i+1:]...)
+			break
+		}
+	}
+}
+
+// Returns the names of the registered patterns, in their registration order.
+func (registry *PatternRegistry) Names() []string {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	return append([]string(nil), registry.names...)
+}
+
+// Returns the name the pattern is registered under, or false if it isn't registered.
+func (registry *PatternRegistry) nameOf(pattern *gofrogio.CmdOutputPattern) (string, bool) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	for _, name := range registry.names {
+		if registry.patterns[name] == pattern {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Returns the registered patterns in their registration order, without the excluded ones.
+// The patterns of all the go versions are returned.
+func (registry *PatternRegistry) Patterns(excluded ...string) []*gofrogio.CmdOutputPattern {
+	return registry.PatternsForVersion("", excluded...)
+}
+
+// Returns the registered patterns which apply to the go version, such as "1.16.5", in their registration order,
+// without the excluded ones. If the version is empty, because it is unknown, the patterns of all the versions are returned.
+// Development builds are considered as the newest version.
+func (registry *PatternRegistry) PatternsForVersion(goVersion string, excluded ...string) []*gofrogio.CmdOutputPattern {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	var patterns []*gofrogio.CmdOutputPattern
+	for _, name := range registry.names {
+		if !contains(excluded, name) && registry.versions[name].contains(goVersion) {
+			patterns = append(patterns, registry.patterns[name])
+		}
+	}
+	return patterns
+}
+
+func (versions versionRange) contains(goVersion string) bool {
+	if goVersion == "" {
+		return true
+	}
+	if goVersion == "devel" {
+		return versions.max == ""
+	}
+	return (versions.min == "" || CompareGoVersions(goVersion, versions.min) >= 0) &&
+		(versions.max == "" || CompareGoVersions(goVersion, versions.max) < 0)
+}
+
+// Returns the patterns of the registry of the options carried by ctx which apply to the version of the go binary,
+// without the excluded ones. The version is that of the Executable of the options or of SetGoExecutable, if known.
+// Otherwise, it is detected by go version once per go binary and GOTOOLCHAIN. If it can't be detected, or the go commands are run by an
+// Executor, the patterns of all the versions are returned.
+func getPatterns(ctx context.Context, excluded ...string) ([]*gofrogio.CmdOutputPattern, error) {
+	registry, err := getPatternRegistry(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return registry.PatternsForVersion(getCachedGoVersion(ctx), excluded...), nil
+}
+
+// The detections of getCachedGoVersion, by the path of the go binary and its GOTOOLCHAIN.
+var detectedGoVersions = map[string]*goVersionDetection{}
+var detectedGoVersionsMutex sync.Mutex
+
+// The detection of the version of a go binary. The version is set before done is closed.
+type goVersionDetection struct {
+	done    chan struct{}
+	version string
+}
+
+func getCachedGoVersion(ctx context.Context) string {
+	options := GetOptions(ctx)
+	executable := options.Executable
+	if executable == nil {
+		executable = getGoExecutable()
+	}
+	if executable != nil && executable.Version != "" {
+		return executable.Version
+	}
+	if options.Executor != nil {
+		// The executor may run without a go binary installed.
+		return ""
+	}
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return ""
+	}
+	// The same go binary may switch to another toolchain, so its version depends on GOTOOLCHAIN.
+	goToolchain, ok := goCmd.Env["GOTOOLCHAIN"]
+	if !ok {
+		goToolchain = os.Getenv("GOTOOLCHAIN")
+	}
+	key := goCmd.Go + "\x00" + goToolchain
+	detectedGoVersionsMutex.Lock()
+	detection, detecting := detectedGoVersions[key]
+	if !detecting {
+		detection = &goVersionDetection{done: make(chan struct{})}
+		detectedGoVersions[key] = detection
+	}
+	detectedGoVersionsMutex.Unlock()
+	if detecting {
+		// go version is already running for the same binary, so wait for it rather than running it again.
+		select {
+		case <-detection.done:
+			return detection.version
+		case <-ctx.Done():
+			return ""
+		}
+	}
+
+	cached := true
+	output, err := GetGoVersion(ctx)
+	if err == nil {
+		detection.version, err = parseGoVersion(output)
+	} else {
+		// Failing to run go version, such as when ctx is cancelled, may succeed next time.
+		cached = false
+	}
+	if err != nil {
+		// An unexpected output is cached, so that go version runs once per go binary.
+		getLogger(ctx).Debug("Failed detecting the version of", goCmd.Go+":", err.Error())
+	}
+	if !cached {
+		detectedGoVersionsMutex.Lock()
+		delete(detectedGoVersions, key)
+		detectedGoVersionsMutex.Unlock()
+	}
+	close(detection.done)
+	return detection.version
+}
+
+func contains(slice []string, value string) bool {
+	for _, element := range slice {
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestPatternRegistry(t *testing.T) {
+	registry, err := NewDefaultPatternRegistry()
+	if err != nil {
+		t.Error(err)
+	}
+	expected := []string{CredentialsPattern, UrlUserInfoMaskingRule, AuthorizationMaskingRule, ArtApiKeyMaskingRule, NetrcMaskingRule, NotFoundPattern, VcsDisallowedPattern, UnrecognizedImportPattern, UnknownRevisionPattern, NotFoundZipPattern, GitFetchPattern, NoMatchingVersionsPattern, PackageNotInModulePattern, MissingGoSumEntryPattern, NoRequiredModulePattern, CannotFindModulePattern, InvalidVersionPattern, DeprecatedPattern, ToolchainSwitchPattern, FindingModulePattern}
+	if !reflect.DeepEqual(expected, registry.Names()) {
+		t.Errorf("Expecting: %v, Got: %v", expected, registry.Names())
+	}
+
+	err = registry.RegisterRegExp("forbidden", `(403 Forbidden)`, Error)
+	if err != nil {
+		t.Error(err)
+	}
+	registry.Remove(UnknownRevisionPattern)
+	registry.Remove("missing")
+	expected = []string{CredentialsPattern, UrlUserInfoMaskingRule, AuthorizationMaskingRule, ArtApiKeyMaskingRule, NetrcMaskingRule, NotFoundPattern, VcsDisallowedPattern, UnrecognizedImportPattern, NotFoundZipPattern, GitFetchPattern, NoMatchingVersionsPattern, PackageNotInModulePattern, MissingGoSumEntryPattern, NoRequiredModulePattern, CannotFindModulePattern, InvalidVersionPattern, DeprecatedPattern, ToolchainSwitchPattern, FindingModulePattern, "forbidden"}
+	if !reflect.DeepEqual(expected, registry.Names()) {
+		t.Errorf("Expecting: %v, Got: %v", expected, registry.Names())
+	}
+
+	if len(registry.Patterns(NotFoundZipPattern, "forbidden")) != 18 {
+		t.Error("Expecting 18 patterns, got:", len(registry.Patterns(NotFoundZipPattern, "forbidden")))
+	}
+
+	// Registering an existing name replaces the pattern and keeps its position.
+	err = registry.RegisterRegExp(NotFoundPattern, `(404)`, Error)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(expected, registry.Names()) {
+		t.Errorf("Expecting: %v, Got: %v", expected, registry.Names())
+	}
+	if registry.Patterns()[5].RegExp.String() != `(404)` {
+		t.Error("Expecting the not found pattern to be replaced, got:", registry.Patterns()[5].RegExp.String())
+	}
+}
+
+func TestPatternsForVersion(t *testing.T) {
+	registry := NewPatternRegistry()
+	if err := registry.RegisterRegExp("all", `all`, Error); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterVersionedRegExp("old", `old`, Error, "", "1.16"); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterVersionedRegExp("new", `new`, Error, "1.16", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.RegisterVersionedRegExp("between", `between`, Error, "1.13", "1.17"); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		version  string
+		expected []string
+	}{
+		{"", []string{"all", "old", "new", "between"}},
+		{"1.12.5", []string{"all", "old"}},
+		{"1.13", []string{"all", "old", "between"}},
+		{"1.16beta1", []string{"all", "old", "between"}},
+		{"1.16", []string{"all", "new", "between"}},
+		{"1.17.1", []string{"all", "new"}},
+		{"devel", []string{"all", "new"}},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			var actual []string
+			for _, pattern := range registry.PatternsForVersion(test.version) {
+				actual = append(actual, pattern.RegExp.String())
+			}
+			if !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("Expecting: %v, Got: %v", test.expected, actual)
+			}
+		})
+	}
+
+	// Registering a pattern again without versions makes it apply to all the versions.
+	registry.Register("old", registry.Patterns()[1])
+	if len(registry.PatternsForVersion("1.17", "between")) != 3 {
+		t.Error("Expecting 3 patterns, got:", len(registry.PatternsForVersion("1.17", "between")))
+	}
+
+	ctx := WithOptions(context.Background(), &Options{Patterns: registry, Executable: &GoExecutable{Path: "go", Version: "1.12"}})
+	patterns, err := getPatterns(ctx, "all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 1 || patterns[0].RegExp.String() != "old" {
+		t.Errorf("Expecting the old pattern, got: %v", patterns)
+	}
+}
+
+func TestGetPatternsDetectsGoVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test uses sh")
+	}
+	tempDir, err := ioutil.TempDir("", "patternsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	// Counts its runs, to verify the version is detected once.
+	goPath := filepath.Join(tempDir, "go")
+	script := "#!/bin/sh\necho run >> " + filepath.Join(tempDir, "runs") + "\necho go version go1.15.2 linux/amd64\n"
+	if err = ioutil.WriteFile(goPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	registry, err := NewDefaultPatternRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithOptions(context.Background(), &Options{Patterns: registry, Executable: &GoExecutable{Path: goPath}})
+	for i := 0; i < 2; i++ {
+		patterns, err := getPatterns(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(registry.PatternsForVersion("1.15.2"), patterns) {
+			t.Error("Expecting the patterns of go 1.15.2")
+		}
+	}
+	runs, err := ioutil.ReadFile(filepath.Join(tempDir, "runs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(runs) != "run\n" {
+		t.Errorf("Expecting go version to run once, got: %q", runs)
+	}
+}
+
+func TestGetCachedGoVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test uses sh")
+	}
+	tempDir, err := ioutil.TempDir("", "patternsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	goPath := filepath.Join(tempDir, "go")
+	script := "#!/bin/sh\nif [ \"$GOTOOLCHAIN\" = go1.21.0 ]; then echo go version go1.21.0 linux/amd64; else echo go version go1.15.2 linux/amd64; fi\n"
+	if err = ioutil.WriteFile(goPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithOptions(context.Background(), &Options{Executable: &GoExecutable{Path: goPath}, Env: map[string]string{"GOTOOLCHAIN": "local"}})
+
+	// The failure of a cancelled go version isn't cached.
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if version := getCachedGoVersion(cancelledCtx); version != "" {
+		t.Errorf("Expecting no version with a cancelled context, got: %s", version)
+	}
+	if version := getCachedGoVersion(ctx); version != "1.15.2" {
+		t.Errorf("Expecting: 1.15.2, Got: %s", version)
+	}
+	if version := getCachedGoVersion(WithEnv(ctx, "GOTOOLCHAIN", "go1.21.0")); version != "1.21.0" {
+		t.Errorf("Expecting the version of GOTOOLCHAIN: 1.21.0, Got: %s", version)
+	}
+}
+
+func TestBuiltInPatternsVersions(t *testing.T) {
+	registry, err := NewDefaultPatternRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		version  string
+		pattern  string
+		expected bool
+	}{
+		{"1.15.2", CannotFindModulePattern, true},
+		{"1.15.2", NoRequiredModulePattern, false},
+		{"1.15.2", MissingGoSumEntryPattern, false},
+		{"1.16", CannotFindModulePattern, false},
+		{"1.16", NoRequiredModulePattern, true},
+		{"1.16", MissingGoSumEntryPattern, true},
+		{"1.13", PackageNotInModulePattern, false},
+		{"1.14", PackageNotInModulePattern, true},
+		{"1.12.17", InvalidVersionPattern, false},
+		{"1.13", InvalidVersionPattern, true},
+	}
+	for _, test := range tests {
+		t.Run(test.version+"/"+test.pattern, func(t *testing.T) {
+			found := false
+			for _, pattern := range registry.PatternsForVersion(test.version) {
+				if name, _ := registry.nameOf(pattern); name == test.pattern {
+					found = true
+				}
+			}
+			if found != test.expected {
+				t.Errorf("Expecting the pattern to apply: %t, got: %t", test.expected, found)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Patterns of private modules, which are applied to the go commands as GOPRIVATE, GONOPROXY and GONOSUMDB.
+// Each pattern is a glob matching a prefix of the module paths, such as "github.com/mycorp" or "*.corp.example.com".
+type PrivateModules struct {
+	// Modules which are neither downloaded through the proxy nor checked against the checksum database.
+	Private []string
+	// Modules which are downloaded directly from their VCS, overriding Private.
+	NoProxy []string
+	// Modules which are not checked against the checksum database, overriding Private.
+	NoSumDb []string
+}
+
+// Returns the patterns matching all the modules hosted by the VCS hosts, such as "github.mycorp.com".
+// The hosts may also be given as URLs, such as "https://github.mycorp.com/".
+func PrivatePatternsFromHosts(hosts ...string) ([]string, error) {
+	var patterns []string
+	for _, host := range hosts {
+		host = strings.TrimSpace(host)
+		if strings.Contains(host, "://") {
+			parsedUrl, err := url.Parse(host)
+			if err != nil {
+				return nil, errorutils.CheckError(err)
+			}
+			host = parsedUrl.Host + parsedUrl.Path
+		}
+		host = strings.Trim(host, "/")
+		if host == "" {
+			continue
+		}
+		if err := validatePattern(host); err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, host)
+	}
+	return patterns, nil
+}
+
+// Validates all the patterns.
+func (privateModules *PrivateModules) Validate() error {
+	for _, patterns := range [][]string{privateModules.Private, privateModules.NoProxy, privateModules.NoSumDb} {
+		for _, pattern := range patterns {
+			if err := validatePattern(pattern); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Returns true if the module is downloaded directly from its VCS.
+func (privateModules *PrivateModules) IsNoProxy(modulePath string) bool {
+	if privateModules.NoProxy != nil {
+		return MatchPrefixPatterns(privateModules.NoProxy, modulePath)
+	}
+	return MatchPrefixPatterns(privateModules.Private, modulePath)
+}
+
+// Returns true if the module is not checked against the checksum database.
+func (privateModules *PrivateModules) IsNoSumDb(modulePath string) bool {
+	if privateModules.NoSumDb != nil {
+		return MatchPrefixPatterns(privateModules.NoSumDb, modulePath)
+	}
+	return MatchPrefixPatterns(privateModules.Private, modulePath)
+}
+
+// Returns a copy of ctx carrying the options of ctx, with the private modules patterns set in the environment.
+// Only the variables with patterns are set, so the go command defaults GONOPROXY and GONOSUMDB to GOPRIVATE.
+func WithPrivateModules(ctx context.Context, privateModules *PrivateModules) (context.Context, error) {
+	if err := privateModules.Validate(); err != nil {
+		return nil, err
+	}
+	for name, patterns := range map[string][]string{"GOPRIVATE": privateModules.Private, "GONOPROXY": privateModules.NoProxy, "GONOSUMDB": privateModules.NoSumDb} {
+		if len(patterns) > 0 {
+			ctx = WithEnv(ctx, name, strings.Join(patterns, ","))
+		}
+	}
+	return ctx, nil
+}
+
+func validatePattern(pattern string) error {
+	if pattern == "" || strings.ContainsAny(pattern, ", \t") {
+		return errorutils.CheckError(fmt.Errorf("Invalid private modules pattern %q.", pattern))
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return errorutils.CheckError(fmt.Errorf("Invalid private modules pattern %q: %s", pattern, err.Error()))
+	}
+	return nil
+}
+
+// Returns true if any of the patterns matches a prefix of the module path, the same way the go command does.
+// A pattern with n path elements is matched against the first n elements of the module path.
+func MatchPrefixPatterns(patterns []string, modulePath string) bool {
+	for _, pattern := range patterns {
+		elements := strings.Count(pattern, "/") + 1
+		prefix := modulePath
+		for i := 0; i < len(modulePath); i++ {
+			if modulePath[i] == '/' {
+				elements--
+				if elements == 0 {
+					prefix = modulePath[:i]
+					break
+				}
+			}
+		}
+		if elements > 1 {
+			continue
+		}
+		if matched, _ := path.Match(pattern, prefix); matched {
+			return true
+		}
+	}
+	return false
+}
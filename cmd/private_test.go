@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestPrivatePatternsFromHosts(t *testing.T) {
+	patterns, err := PrivatePatternsFromHosts("github.mycorp.com", "https://gitlab.mycorp.com/group/", " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"github.mycorp.com", "gitlab.mycorp.com/group"}
+	if !reflect.DeepEqual(expected, patterns) {
+		t.Errorf("Expected: %v, Got: %v", expected, patterns)
+	}
+	if _, err = PrivatePatternsFromHosts("github.mycorp.com/[a"); err == nil {
+		t.Error("Expecting an error for an invalid pattern")
+	}
+}
+
+func TestPrivateModulesMatching(t *testing.T) {
+	privateModules := &PrivateModules{Private: []string{"github.mycorp.com", "*.corp.example.com/team"}, NoSumDb: []string{"github.mycorp.com/public"}}
+	tests := []struct {
+		modulePath string
+		noProxy    bool
+		noSumDb    bool
+	}{
+		{"github.mycorp.com/repo", true, false},
+		{"github.mycorp.com/public/repo", true, true},
+		{"git.corp.example.com/team/repo", true, false},
+		{"git.corp.example.com/other/repo", false, false},
+		{"github.com/mycorp/repo", false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.modulePath, func(t *testing.T) {
+			if actual := privateModules.IsNoProxy(test.modulePath); actual != test.noProxy {
+				t.Errorf("Test name: %s: Expected no proxy: %t, Got: %t", test.modulePath, test.noProxy, actual)
+			}
+			if actual := privateModules.IsNoSumDb(test.modulePath); actual != test.noSumDb {
+				t.Errorf("Test name: %s: Expected no sumdb: %t, Got: %t", test.modulePath, test.noSumDb, actual)
+			}
+		})
+	}
+}
+
+func TestWithPrivateModules(t *testing.T) {
+	ctx, err := WithPrivateModules(context.Background(), &PrivateModules{Private: []string{"github.mycorp.com", "gitlab.mycorp.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"GOPRIVATE": "github.mycorp.com,gitlab.mycorp.com"}
+	if !reflect.DeepEqual(expected, GetOptions(ctx).Env) {
+		t.Errorf("Expected: %v, Got: %v", expected, GetOptions(ctx).Env)
+	}
+	if _, err = WithPrivateModules(context.Background(), &PrivateModules{NoProxy: []string{"a,b"}}); err == nil {
+		t.Error("Expecting an error for an invalid pattern")
+	}
+}
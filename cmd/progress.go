@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// The phase of a module in the resolution, as reported by the go command.
+type ProgressPhase string
+
+const (
+	FindingPhase     ProgressPhase = "finding"
+	DownloadingPhase ProgressPhase = "downloading"
+	ExtractingPhase  ProgressPhase = "extracting"
+	// The module providing an imported package was found.
+	FoundPhase ProgressPhase = "found"
+)
+
+var progressRegExp = regexp.MustCompile(`^go: (finding|downloading|extracting) (\S+) (v\S+)$`)
+var foundRegExp = regexp.MustCompile(`^go: found \S+ in (\S+) (v\S+)$`)
+
+// A module reached a phase of the resolution.
+type ProgressEvent struct {
+	Module  string
+	Version string
+	Phase   ProgressPhase
+	// The output line the event was parsed from.
+	Line string
+}
+
+// Receives the progress of the go commands, module by module.
+type ProgressListener interface {
+	OnProgress(event ProgressEvent)
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands report their progress to the listener.
+// The progress is parsed from the stderr lines of the go commands, such as "go: downloading rsc.io/quote v1.5.2".
+// A stderr callback already set on ctx keeps receiving all the lines.
+func WithProgressListener(ctx context.Context, listener ProgressListener) context.Context {
+	options := *GetOptions(ctx)
+	previous := options.OnStderrLine
+	options.OnStderrLine = func(line string) {
+		if event := ParseProgressLine(line); event != nil {
+			listener.OnProgress(*event)
+		}
+		if previous != nil {
+			previous(line)
+		}
+	}
+	return WithOptions(ctx, &options)
+}
+
+// Parses a progress line of the go command. Returns nil if the line doesn't report progress.
+func ParseProgressLine(line string) *ProgressEvent {
+	line = strings.TrimRight(line, "\r")
+	if match := progressRegExp.FindStringSubmatch(line); match != nil {
+		return &ProgressEvent{Module: match[2], Version: match[3], Phase: ProgressPhase(match[1]), Line: line}
+	}
+	if match := foundRegExp.FindStringSubmatch(line); match != nil {
+		return &ProgressEvent{Module: match[1], Version: match[2], Phase: FoundPhase, Line: line}
+	}
+	return nil
+}
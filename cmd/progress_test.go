@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+type recordingListener struct {
+	events []ProgressEvent
+}
+
+func (listener *recordingListener) OnProgress(event ProgressEvent) {
+	listener.events = append(listener.events, event)
+}
+
+func TestParseProgressLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected *ProgressEvent
+	}{
+		{"go: downloading rsc.io/quote v1.5.2", &ProgressEvent{"rsc.io/quote", "v1.5.2", DownloadingPhase, "go: downloading rsc.io/quote v1.5.2"}},
+		{"go: extracting rsc.io/quote v1.5.2", &ProgressEvent{"rsc.io/quote", "v1.5.2", ExtractingPhase, "go: extracting rsc.io/quote v1.5.2"}},
+		{"go: finding golang.org/x/text v0.0.0-20170915032832-14c0d48ead0c", &ProgressEvent{"golang.org/x/text", "v0.0.0-20170915032832-14c0d48ead0c", FindingPhase, "go: finding golang.org/x/text v0.0.0-20170915032832-14c0d48ead0c"}},
+		{"go: found rsc.io/quote/v3 in rsc.io/quote/v3 v3.1.0", &ProgressEvent{"rsc.io/quote/v3", "v3.1.0", FoundPhase, "go: found rsc.io/quote/v3 in rsc.io/quote/v3 v3.1.0"}},
+		{"go: finding module for package rsc.io/quote", nil},
+		{"go: github.com/pkg/errors@v0.8.1: 404 Not Found", nil},
+		{"go: downloading rsc.io/quote v1.5.2\r", &ProgressEvent{"rsc.io/quote", "v1.5.2", DownloadingPhase, "go: downloading rsc.io/quote v1.5.2"}},
+	}
+	for _, test := range tests {
+		t.Run(test.line, func(t *testing.T) {
+			if actual := ParseProgressLine(test.line); !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("Test name: %s: Expected: %v, Got: %v", test.line, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWithProgressListener(t *testing.T) {
+	var lines []string
+	ctx := WithOutputCallbacks(context.Background(), nil, func(line string) {
+		lines = append(lines, line)
+	})
+	listener := &recordingListener{}
+	ctx = WithProgressListener(ctx, listener)
+	onStderrLine := GetOptions(ctx).OnStderrLine
+	onStderrLine("go: downloading rsc.io/quote v1.5.2")
+	onStderrLine("unrelated")
+
+	if len(listener.events) != 1 || listener.events[0].Module != "rsc.io/quote" {
+		t.Error("Expecting a single event of rsc.io/quote, got:", listener.events)
+	}
+	if !reflect.DeepEqual([]string{"go: downloading rsc.io/quote v1.5.2", "unrelated"}, lines) {
+		t.Error("Expecting the previous callback to receive all the lines, got:", lines)
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limits the rate of the requests to the proxies, such as to avoid tripping the DoS protection of a registry.
+// Up to burst requests are allowed at once, and then requestsPerSecond. Safe for concurrent use.
+type RateLimiter struct {
+	mutex             sync.Mutex
+	requestsPerSecond float64
+	burst             float64
+	tokens            float64
+	last              time.Time
+}
+
+// Creates a limiter allowing requestsPerSecond on average, with bursts of up to burst requests.
+// A limiter with requestsPerSecond <= 0 doesn't limit the requests.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{requestsPerSecond: requestsPerSecond, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Blocks until a request is allowed, or returns the error of ctx if it's done first. A nil limiter allows all requests.
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	delay := limiter.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		limiter.cancel()
+		return ctx.Err()
+	}
+}
+
+// Takes a token from the bucket, and returns the time until the token is available.
+func (limiter *RateLimiter) reserve() time.Duration {
+	if limiter == nil || limiter.requestsPerSecond <= 0 {
+		return 0
+	}
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	now := time.Now()
+	limiter.tokens += now.Sub(limiter.last).Seconds() * limiter.requestsPerSecond
+	if limiter.tokens > limiter.burst {
+		limiter.tokens = limiter.burst
+	}
+	limiter.last = now
+	limiter.tokens--
+	if limiter.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-limiter.tokens / limiter.requestsPerSecond * float64(time.Second))
+}
+
+// Returns the token of a request which was cancelled while waiting.
+func (limiter *RateLimiter) cancel() {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.tokens++
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose module downloads and proxy requests are limited by the limiter.
+func WithRateLimiter(ctx context.Context, limiter *RateLimiter) context.Context {
+	options := *GetOptions(ctx)
+	options.RateLimiter = limiter
+	return WithOptions(ctx, &options)
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"os"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// Environment variables whose values are not recorded.
+var secretEnvKeyRegExp = regexp.MustCompile(`(?i)(TOKEN|PASSWORD|PASSWD|SECRET|CREDENTIALS|API_?KEY|AUTH)`)
+
+// A go command run by a RecordingExecutor. Secrets in the environment variables and in the output are masked.
+type InvocationRecord struct {
+	Cmd      []string          `json:"cmd"`
+	Env      map[string]string `json:"env,omitempty"`
+	Dir      string            `json:"dir,omitempty"`
+	Stdout   string            `json:"stdout,omitempty"`
+	Stderr   string            `json:"stderr,omitempty"`
+	ExitCode int               `json:"exitCode"`
+	// The error returned when the command couldn't run.
+	Err string `json:"error,omitempty"`
+}
+
+// Runs the commands with another executor, and appends a JSON line recording each of them to a file.
+// The file can be attached to a bug report, and replayed by a ReplayExecutor.
+type RecordingExecutor struct {
+	mutex    sync.Mutex
+	executor Executor
+	file     *os.File
+}
+
+// Creates an executor running the commands with executor, and recording them to the file at path.
+// The file is created if it doesn't exist, and appended to if it does. Call Close when done.
+func NewRecordingExecutor(executor Executor, path string) (*RecordingExecutor, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return &RecordingExecutor{executor: executor, file: file}, nil
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands are recorded to the file at path.
+// The commands are run by the executor of ctx, or as processes if it has none. Close the returned recorder when done.
+func WithRecording(ctx context.Context, path string) (context.Context, *RecordingExecutor, error) {
+	executor := GetOptions(ctx).Executor
+	if executor == nil {
+		executor = &ProcessExecutor{}
+	}
+	recorder, err := NewRecordingExecutor(executor, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return WithExecutor(ctx, recorder), recorder, nil
+}
+
+func (recorder *RecordingExecutor) Run(ctx context.Context, cmd []string, env map[string]string, dir string) (string, string, int, error) {
+	stdout, stderr, exitCode, err := recorder.executor.Run(ctx, cmd, env, dir)
+	record := InvocationRecord{Cmd: maskCredentials(cmd), Env: maskEnv(env), Dir: dir, Stdout: MaskSecrets(stdout), Stderr: MaskSecrets(stderr), ExitCode: exitCode}
+	if err != nil {
+		record.Err = err.Error()
+	}
+	if recordErr := recorder.write(record); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	return stdout, stderr, exitCode, err
+}
+
+func (recorder *RecordingExecutor) write(record InvocationRecord) error {
+	content, err := json.Marshal(record)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	_, err = recorder.file.Write(append(content, '\n'))
+	return errorutils.CheckError(err)
+}
+
+// Closes the recording file.
+func (recorder *RecordingExecutor) Close() error {
+	return errorutils.CheckError(recorder.file.Close())
+}
+
+// Returns the recorded output for each command, instead of running it.
+// Each record is replayed once, in the order recorded, so a command run several times returns the outputs of its runs in order.
+type ReplayExecutor struct {
+	mutex    sync.Mutex
+	records  []InvocationRecord
+	replayed []bool
+}
+
+// Creates an executor replaying the records of the file written by a RecordingExecutor.
+func NewReplayExecutor(path string) (*ReplayExecutor, error) {
+	records, err := ReadInvocationRecords(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayExecutor{records: records, replayed: make([]bool, len(records))}, nil
+}
+
+// Reads the records of the file written by a RecordingExecutor.
+func ReadInvocationRecords(path string) ([]InvocationRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer file.Close()
+	var records []InvocationRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*maxOutputLineSize)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record InvocationRecord
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		records = append(records, record)
+	}
+	return records, errorutils.CheckError(scanner.Err())
+}
+
+// Returns the output of the first record of the command which wasn't replayed yet. The executable isn't matched,
+// so records made on one machine can be replayed on another. Returns an error if there's no such record.
+func (replayer *ReplayExecutor) Run(ctx context.Context, cmd []string, env map[string]string, dir string) (string, string, int, error) {
+	replayer.mutex.Lock()
+	defer replayer.mutex.Unlock()
+	args := maskCredentials(cmd)[1:]
+	for i, record := range replayer.records {
+		if replayer.replayed[i] || len(record.Cmd) == 0 || !reflect.DeepEqual(record.Cmd[1:], args) {
+			continue
+		}
+		replayer.replayed[i] = true
+		if record.Err != "" {
+			return record.Stdout, record.Stderr, record.ExitCode, errors.New(record.Err)
+		}
+		return record.Stdout, record.Stderr, record.ExitCode, nil
+	}
+	return "", "", 0, errorutils.CheckError(fmt.Errorf("No recorded invocation of: %v", cmd))
+}
+
+func maskEnv(env map[string]string) map[string]string {
+	if len(env) == 0 {
+		return nil
+	}
+	masked := map[string]string{}
+	for key, value := range env {
+		if secretEnvKeyRegExp.MatchString(key) {
+			value = maskedValue
+		}
+		masked[key] = MaskSecrets(value)
+	}
+	return masked
+}
+
+func maskCredentials(values []string) []string {
+	masked := make([]string, len(values))
+	for i, value := range values {
+		masked[i] = MaskSecrets(value)
+	}
+	return masked
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"path/filepath"
+)
+
+// Describes how RegenerateGoSum regenerates go.sum.
+type RegenerateGoSumOptions struct {
+	// Private modules, which are not checked against the checksum database while regenerating.
+	PrivateModules *PrivateModules
+	// Uses the module cache of ctx instead of an empty sandbox, which is faster, but trusts the cached modules.
+	ReuseModCache bool
+}
+
+// The differences between the committed go.sum and a go.sum regenerated from scratch.
+type GoSumReport struct {
+	// Entries of the committed go.sum which the regenerated go.sum doesn't have.
+	Extraneous []gosum.ModuleEntry
+	// Entries of the committed go.sum whose hashes differ from the regenerated ones.
+	Stale []gosum.ModuleEntry
+	// Entries of the regenerated go.sum which the committed go.sum doesn't have.
+	Missing []gosum.ModuleEntry
+	// The content of the regenerated go.sum.
+	Regenerated []byte
+	// True if go.sum wasn't regenerated, because of dry run. The report then has no entries.
+	Skipped bool
+}
+
+// Returns true if the committed go.sum has exactly the entries of the regenerated go.sum.
+// A skipped report isn't clean, since go.sum wasn't checked.
+func (report *GoSumReport) IsClean() bool {
+	return !report.Skipped && len(report.Extraneous) == 0 && len(report.Stale) == 0 && len(report.Missing) == 0
+}
+
+// Regenerates the go.sum file of the project by running go mod tidy without it, in a sandbox unless
+// options.ReuseModCache is set, and compares the result with the committed go.sum.
+// The go.mod and go.sum files are left unchanged. Write the report's Regenerated content to go.sum to prune it.
+// In dry run, go mod tidy doesn't run and a Skipped report is returned.
+func RegenerateGoSum(ctx context.Context, options RegenerateGoSumOptions) (*GoSumReport, error) {
+	projectDir, err := getProjectRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if SkipInDryRunWithContext(ctx, "Regenerating go.sum in "+projectDir) {
+		return &GoSumReport{Skipped: true}, nil
+	}
+	sumPath := filepath.Join(projectDir, "go.sum")
+	committedContent, err := readFileIfExists(GetFileSystem(ctx), sumPath)
+	if err != nil {
+		return nil, err
+	}
+	committed, err := gosum.ParseGoSum(bytes.NewReader(committedContent))
+	if err != nil {
+		return nil, err
+	}
+	if options.PrivateModules != nil {
+		if ctx, err = WithPrivateModules(ctx, options.PrivateModules); err != nil {
+			return nil, err
+		}
+	}
+
+	var regeneratedContent []byte
+	regenerate := func(ctx context.Context) (err error) {
+		regeneratedContent, err = regenerateGoSum(ctx, projectDir)
+		return err
+	}
+	if options.ReuseModCache {
+		err = regenerate(ctx)
+	} else {
+		err = RunInSandbox(ctx, regenerate)
+	}
+	if err != nil {
+		return nil, err
+	}
+	regenerated, err := gosum.ParseGoSum(bytes.NewReader(regeneratedContent))
+	if err != nil {
+		return nil, err
+	}
+	report := compareGoSums(committed, regenerated)
+	report.Regenerated = regeneratedContent
+	return report, nil
+}
+
+// Runs go mod tidy without the go.sum file and returns the go.sum it created.
+// The go.mod and go.sum files are restored when done.
+func regenerateGoSum(ctx context.Context, projectDir string) ([]byte, error) {
+	backups, err := NewFileBackupManagerWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer backups.Rollback()
+	defer backups.RecoverAndRollback()
+	if err = backups.Backup(filepath.Join(projectDir, "go.mod")); err != nil {
+		return nil, err
+	}
+	if err = backups.BackupAndRemove(filepath.Join(projectDir, "go.sum")); err != nil {
+		return nil, err
+	}
+
+	getLogger(ctx).Info("Regenerating go.sum in", projectDir)
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = []string{"mod", "tidy"}
+	err = runWithRetries(ctx, "go mod tidy", func() error {
+		_, _, err := runCmdWithOutputParser(goCmd, true)
+		return err
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(contextError(ctx, err))
+	}
+	return readFileIfExists(GetFileSystem(ctx), filepath.Join(projectDir, "go.sum"))
+}
+
+// Compares the entries of the committed go.sum with the entries of the regenerated one, keeping the order of the files.
+func compareGoSums(committed, regenerated []gosum.ModuleEntry) *GoSumReport {
+	regeneratedHashes := map[gosum.ModuleEntry]string{}
+	for _, entry := range regenerated {
+		regeneratedHashes[withoutHash(entry)] = entry.Hash
+	}
+	committedHashes := map[gosum.ModuleEntry]string{}
+	report := &GoSumReport{}
+	for _, entry := range committed {
+		committedHashes[withoutHash(entry)] = entry.Hash
+		hash, exists := regeneratedHashes[withoutHash(entry)]
+		switch {
+		case !exists:
+			report.Extraneous = append(report.Extraneous, entry)
+		case hash != entry.Hash:
+			report.Stale = append(report.Stale, entry)
+		}
+	}
+	for _, entry := range regenerated {
+		if _, exists := committedHashes[withoutHash(entry)]; !exists {
+			report.Missing = append(report.Missing, entry)
+		}
+	}
+	return report
+}
+
+// Returns the entry without its hash, which identifies the entry in a go.sum file.
+func withoutHash(entry gosum.ModuleEntry) gosum.ModuleEntry {
+	entry.Hash = ""
+	return entry
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/gosum"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const committedGoSum = `github.com/pkg/errors v0.8.0 h1:WdK/asTD0HN+q6hsWO3/vpuAkAr+tw6aNJNDFFf0+qw=
+github.com/pkg/errors v0.8.0/go.mod h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=
+rsc.io/quote v1.5.2 h1:stale=
+rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=
+`
+
+const regeneratedGoSum = `golang.org/x/text v0.3.2/go.mod h1:bEr9sfX3Q8Zfm5fL9x+3itogRgK3+ptLWKqgva+5dAk=
+rsc.io/quote v1.5.2 h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=
+rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=
+`
+
+// Writes the regenerated go.sum when running go mod tidy, and records the environment of the command.
+type tidyExecutor struct {
+	env map[string]string
+}
+
+func (executor *tidyExecutor) Run(ctx context.Context, cmd []string, env map[string]string, dir string) (string, string, int, error) {
+	executor.env = env
+	if _, err := os.Stat(filepath.Join(dir, "go.sum")); !os.IsNotExist(err) {
+		return "", "go.sum wasn't removed", 1, nil
+	}
+	return "", "", 0, ioutil.WriteFile(filepath.Join(dir, "go.sum"), []byte(regeneratedGoSum), 0644)
+}
+
+func TestRegenerateGoSum(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "regenerateTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	for name, content := range map[string]string{"go.mod": "module example.com/m\n", "go.sum": committedGoSum} {
+		if err = ioutil.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	executor := &tidyExecutor{}
+	ctx := WithOptions(context.Background(), &Options{Dir: projectDir, Executor: executor})
+	report, err := RegenerateGoSum(ctx, RegenerateGoSumOptions{PrivateModules: &PrivateModules{Private: []string{"example.com"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &GoSumReport{
+		Extraneous: []gosum.ModuleEntry{
+			{Path: "github.com/pkg/errors", Version: "v0.8.0", Hash: "h1:WdK/asTD0HN+q6hsWO3/vpuAkAr+tw6aNJNDFFf0+qw="},
+			{Path: "github.com/pkg/errors", Version: "v0.8.0", Hash: "h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=", IsMod: true},
+		},
+		Stale:       []gosum.ModuleEntry{{Path: "rsc.io/quote", Version: "v1.5.2", Hash: "h1:stale="}},
+		Missing:     []gosum.ModuleEntry{{Path: "golang.org/x/text", Version: "v0.3.2", Hash: "h1:bEr9sfX3Q8Zfm5fL9x+3itogRgK3+ptLWKqgva+5dAk=", IsMod: true}},
+		Regenerated: []byte(regeneratedGoSum),
+	}
+	if !reflect.DeepEqual(expected, report) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, report)
+	}
+	if report.IsClean() {
+		t.Error("Expected a report with differences")
+	}
+	if executor.env["GOPRIVATE"] != "example.com" || executor.env["GOMODCACHE"] == "" {
+		t.Errorf("Expected running in a sandbox with the private modules, Got: %v", executor.env)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(projectDir, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != committedGoSum {
+		t.Errorf("Expected go.sum to be restored, Got:\n%s", content)
+	}
+}
+
+func TestRegenerateGoSumDryRun(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "regenerateTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	for name, content := range map[string]string{"go.mod": "module example.com/m\n", "go.sum": committedGoSum} {
+		if err = ioutil.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	executor := &tidyExecutor{}
+	ctx := WithOptions(context.Background(), &Options{Dir: projectDir, Executor: executor})
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	report, err := RegenerateGoSum(ctx, RegenerateGoSumOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&GoSumReport{Skipped: true}, report) || report.IsClean() {
+		t.Errorf("Expected a skipped report, Got: %+v", report)
+	}
+	if executor.env != nil {
+		t.Error("Expected go mod tidy not to run in dry run")
+	}
+	content, err := ioutil.ReadFile(filepath.Join(projectDir, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != committedGoSum {
+		t.Errorf("Expected go.sum to be unchanged, Got:\n%s", content)
+	}
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/gocmd/log"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+)
+
+// The conclusion of comparing a checksum mismatch with the module downloaded directly from its origin.
+type ChecksumVerdict string
+
+const (
+	// The direct download matches the expected hash, so the proxy or the module cache served a corrupted module.
+	CorruptedDownload ChecksumVerdict = "corrupted-download"
+	// The direct download matches the downloaded hash, so the expected hash is stale, as when a version is re-tagged.
+	StaleExpectedHash ChecksumVerdict = "stale-expected-hash"
+	// The direct download matches neither hash.
+	UnknownMismatch ChecksumVerdict = "unknown"
+)
+
+// Options of RemediateChecksumMismatch.
+type RemediationOptions struct {
+	// Remove the module from the module cache if the cached module is corrupted, so the next go command downloads it again.
+	CleanCache bool
+}
+
+// The result of RemediateChecksumMismatch.
+type ChecksumMismatchReport struct {
+	// The module in the "path@version" notation.
+	Module string
+	// True if the mismatch is of the module's go.mod file.
+	IsMod bool
+	// The hash of the module downloaded by the failed go command.
+	Downloaded string
+	// The expected hash and its source, such as "go.sum" or "sum.golang.org".
+	Expected       string
+	ExpectedSource string
+	// The hash of the module downloaded directly from its origin, bypassing the GOPROXY and the module cache.
+	Direct  string
+	Verdict ChecksumVerdict
+	// True if the module was removed from the module cache.
+	CacheCleaned bool
+}
+
+// Investigates a checksum mismatch reported by a go command. The module is downloaded again from its origin, with
+// GOPROXY=direct, into a sandbox, and its hash is compared with the downloaded and expected hashes of the mismatch.
+// If the cached module turns out to be corrupted and options.CleanCache is set, the module is removed from the module cache.
+// Fails if direct VCS fetches are forbidden by WithVcs.
+func RemediateChecksumMismatch(ctx context.Context, mismatch *ChecksumMismatchError, options RemediationOptions) (*ChecksumMismatchReport, error) {
+	report := &ChecksumMismatchReport{
+		Module:         mismatch.Module,
+		IsMod:          mismatch.IsMod,
+		Downloaded:     mismatch.Actual,
+		Expected:       mismatch.Expected,
+		ExpectedSource: mismatch.ExpectedSource,
+	}
+	module, version := splitModuleId(mismatch.Module)
+	if version == "" {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid module in checksum mismatch: %q.", mismatch.Module))
+	}
+	getLogger(ctx).WithField("module", mismatch.Module).Info("Downloading the module directly from its origin, to investigate the checksum mismatch")
+	direct, err := downloadDirectHash(ctx, mismatch)
+	if err != nil {
+		return nil, err
+	}
+	report.Direct = direct
+	switch report.Direct {
+	case report.Expected:
+		report.Verdict = CorruptedDownload
+	case report.Downloaded:
+		report.Verdict = StaleExpectedHash
+	default:
+		report.Verdict = UnknownMismatch
+	}
+	getLogger(ctx).WithFields(log.Fields{"module": mismatch.Module, "verdict": string(report.Verdict)}).Info("Investigated the checksum mismatch")
+	if report.Verdict != CorruptedDownload || !options.CleanCache {
+		return report, nil
+	}
+	cache, err := GetModCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = cache.RemoveModule(module, version); err != nil {
+		return nil, err
+	}
+	report.CacheCleaned = true
+	return report, nil
+}
+
+// Downloads the module of the mismatch with GOPROXY=direct into a sandbox, and returns its hash.
+// The module is downloaded outside of the project, so it isn't checked against go.sum, and without a checksum database.
+func downloadDirectHash(ctx context.Context, mismatch *ChecksumMismatchError) (hash string, err error) {
+	if GetOptions(ctx).DirectFallback == ForbidDirectFallback {
+		return "", errorutils.CheckError(fmt.Errorf("Cannot download %s directly from its origin: direct VCS fetches are forbidden.", mismatch.Module))
+	}
+	sandbox, err := NewSandboxWithContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if closeErr := sandbox.close(err != nil); err == nil {
+			err = closeErr
+		}
+	}()
+	ctx = WithModuleDir(sandbox.WithContext(ctx), sandbox.Dir)
+	ctx = WithEnv(ctx, "GOPROXY", "direct")
+	ctx = WithEnv(ctx, "GO111MODULE", "on")
+	ctx = WithoutChecksumDatabase(ctx)
+	downloads, err := DownloadModules(ctx, mismatch.Module)
+	if err != nil {
+		return "", err
+	}
+	if len(downloads) != 1 {
+		return "", errorutils.CheckError(fmt.Errorf("Unexpected download results for %s: %v", mismatch.Module, downloads))
+	}
+	if downloads[0].Error != "" {
+		return "", errorutils.CheckError(errors.New(downloads[0].Error))
+	}
+	if mismatch.IsMod {
+		return downloads[0].GoModSum, nil
+	}
+	return downloads[0].Sum, nil
+}
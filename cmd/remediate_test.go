@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemediateChecksumMismatch(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "remediateTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer removeReadOnly(cacheDir)
+	cachedZip := filepath.Join(cacheDir, "cache", "download", "rsc.io", "quote", "@v", "v1.5.2.zip")
+	extractedDir := filepath.Join(cacheDir, "rsc.io", "quote@v1.5.2")
+	for _, dir := range []string{filepath.Dir(cachedZip), extractedDir} {
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = ioutil.WriteFile(cachedZip, []byte("corrupted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chmod(extractedDir, 0555); err != nil {
+		t.Fatal(err)
+	}
+	env, err := json.Marshal(map[string]string{"GOMODCACHE": cacheDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: string(env)}, "env", "-json")
+	executor.On(ExecutorResult{Stdout: `{"Path": "rsc.io/quote", "Version": "v1.5.2", "Sum": "h1:good=", "GoModSum": "h1:mod="}`}, "mod", "download", "-json", "rsc.io/quote@v1.5.2")
+	ctx := WithExecutor(context.Background(), executor)
+
+	tests := []struct {
+		name            string
+		mismatch        ChecksumMismatchError
+		cleanCache      bool
+		expectedVerdict ChecksumVerdict
+	}{
+		{"stale", ChecksumMismatchError{Module: "rsc.io/quote@v1.5.2", Actual: "h1:good=", Expected: "h1:old=", ExpectedSource: "go.sum"}, true, StaleExpectedHash},
+		{"unknown", ChecksumMismatchError{Module: "rsc.io/quote@v1.5.2", IsMod: true, Actual: "h1:bad=", Expected: "h1:other=", ExpectedSource: "go.sum"}, true, UnknownMismatch},
+		{"corruptedNoClean", ChecksumMismatchError{Module: "rsc.io/quote@v1.5.2", Actual: "h1:bad=", Expected: "h1:good=", ExpectedSource: "go.sum"}, false, CorruptedDownload},
+		{"corrupted", ChecksumMismatchError{Module: "rsc.io/quote@v1.5.2", Actual: "h1:bad=", Expected: "h1:good=", ExpectedSource: "sum.golang.org"}, true, CorruptedDownload},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report, err := RemediateChecksumMismatch(ctx, &test.mismatch, RemediationOptions{CleanCache: test.cleanCache})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Verdict != test.expectedVerdict || report.Module != test.mismatch.Module || report.Expected != test.mismatch.Expected {
+				t.Errorf("Unexpected report: %+v", report)
+			}
+			expectedCleaned := test.expectedVerdict == CorruptedDownload && test.cleanCache
+			if report.CacheCleaned != expectedCleaned {
+				t.Errorf("Expected CacheCleaned: %t, Got: %t", expectedCleaned, report.CacheCleaned)
+			}
+			_, err = os.Stat(cachedZip)
+			if os.IsNotExist(err) != expectedCleaned {
+				t.Errorf("Expected the cached zip to be removed: %t, Got: %v", expectedCleaned, err)
+			}
+		})
+	}
+
+	for _, call := range executor.Calls() {
+		if call.Cmd[1] == "mod" && (call.Env["GOPROXY"] != "direct" || call.Env["GOSUMDB"] != "off" || call.Dir == "") {
+			t.Errorf("Expected a direct download in a sandbox, Got: %+v", call)
+		}
+	}
+	if _, err = RemediateChecksumMismatch(ctx, &ChecksumMismatchError{Module: "rsc.io/quote"}, RemediationOptions{}); err == nil {
+		t.Error("Expected an error for a module without a version")
+	}
+}
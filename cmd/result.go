@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The outcome of a single run of a go command, kept for analyzing failed builds after the fact.
+type RunResult struct {
+	// The command and its arguments, such as ["go", "build", "./..."], with the credentials masked.
+	Cmd []string
+	// The output of the command, after it was passed through the patterns.
+	Stdout string
+	Stderr string
+	// The wall time of the run.
+	Duration time.Duration
+	// The exit code of the command, or -1 if it couldn't run or was killed.
+	ExitCode int
+	// The names of the patterns which matched the output, in the order they first matched.
+	// Patterns which aren't registered in the registry of the options are named by their regexps.
+	MatchedPatterns []string
+	// The error the run failed with, or nil if it succeeded.
+	Err error
+}
+
+// Returns true if the run succeeded.
+func (result *RunResult) Succeeded() bool {
+	return result.Err == nil
+}
+
+// Collects the results of the go commands. Safe for concurrent use.
+type RunResults struct {
+	mutex   sync.Mutex
+	results []*RunResult
+}
+
+// Returns the results collected so far, in the order the commands finished.
+func (results *RunResults) List() []*RunResult {
+	results.mutex.Lock()
+	defer results.mutex.Unlock()
+	return append([]*RunResult(nil), results.results...)
+}
+
+// Returns the results of the failed runs collected so far, in the order the commands finished.
+func (results *RunResults) Failed() []*RunResult {
+	results.mutex.Lock()
+	defer results.mutex.Unlock()
+	var failed []*RunResult
+	for _, result := range results.results {
+		if !result.Succeeded() {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+func (results *RunResults) add(result *RunResult) {
+	results.mutex.Lock()
+	defer results.mutex.Unlock()
+	results.results = append(results.results, result)
+}
+
+// Returns a copy of ctx carrying the options of ctx, and the collector of the results of all the go commands run with it,
+// including each attempt of the retried commands.
+func WithRunResults(ctx context.Context) (context.Context, *RunResults) {
+	results := &RunResults{}
+	options := *GetOptions(ctx)
+	options.Results = results
+	return WithOptions(ctx, &options), results
+}
+
+// Runs the go command as RunGo does, and returns the result of its last attempt.
+// The result is returned with the error as well, so the output of a failed command can be inspected.
+// In dry run mode, the command isn't run and the result is empty.
+func RunGoWithResult(ctx context.Context, goArg []string) (*RunResult, error) {
+	if SkipInDryRunWithContext(ctx, "Running 'go "+strings.Join(goArg, " ")+"'") {
+		return &RunResult{}, nil
+	}
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = goArg
+	patterns, err := getPatterns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result *RunResult
+	err = runWithRetries(ctx, "go "+strings.Join(goArg, " "), func() error {
+		var err error
+		result, err = runCmdWithResult(goCmd, true, patterns...)
+		return checksumError(err, result.Stderr)
+	})
+	return result, errorutils.CheckError(contextError(ctx, err))
+}
+
+func getCmdArgs(goCmd *Cmd) []string {
+	args := append([]string{"go"}, goCmd.Command...)
+	args = append(args, goCmd.CommandFlags...)
+	return maskCredentials(args)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"context"
+	gofrogio "github.com/jfrog/gofrog/io"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestRunGoWithResult(t *testing.T) {
+	runner, err := NewRunner()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := "go: module github.com/golang/protobuf is deprecated: Use the \"google.golang.org/protobuf\" module instead.\n" +
+		"main.go:3:2: undefined: foo\n"
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: "output\n", Stderr: stderr, ExitCode: 1}, "build", "./...")
+	runner.Executor = executor
+	ctx, results := WithRunResults(context.Background())
+
+	result, err := runner.RunGoWithResult(ctx, "build", "./...")
+	if _, ok := err.(*ExitError); !ok {
+		t.Fatalf("Expecting an ExitError, got: %v", err)
+	}
+	if result == nil {
+		t.Fatal("Expecting the result of the failed command to be returned")
+	}
+	if !reflect.DeepEqual([]string{"go", "build", "./..."}, result.Cmd) {
+		t.Errorf("Unexpected command: %v", result.Cmd)
+	}
+	if result.Stdout != "output\n" || result.Stderr != stderr {
+		t.Errorf("Expecting the output to be kept, got: %q, %q", result.Stdout, result.Stderr)
+	}
+	if result.ExitCode != 1 {
+		t.Errorf("Expecting exit code 1, got: %d", result.ExitCode)
+	}
+	if !reflect.DeepEqual([]string{DeprecatedPattern}, result.MatchedPatterns) {
+		t.Errorf("Unexpected matched patterns: %v", result.MatchedPatterns)
+	}
+	if result.Succeeded() || result.Err == nil {
+		t.Error("Expecting the result to hold the error")
+	}
+	if len(results.List()) != 1 || results.List()[0] != result {
+		t.Errorf("Expecting the result to be collected, got: %v", results.List())
+	}
+	if len(results.Failed()) != 1 {
+		t.Errorf("Expecting one failed result, got: %d", len(results.Failed()))
+	}
+}
+
+func TestRunCmdWithResultUnregisteredPattern(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.DefaultResult = ExecutorResult{Stdout: "first\nsecond\nfirst\n"}
+	ctx, results := WithRunResults(WithExecutor(context.Background(), executor))
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goCmd.Command = []string{"list"}
+	pattern := &gofrogio.CmdOutputPattern{
+		RegExp: regexp.MustCompile(`^first$`),
+		ExecFunc: func(pattern *gofrogio.CmdOutputPattern) (string, error) {
+			return pattern.Line, nil
+		},
+	}
+	result, err := runCmdWithResult(goCmd, false, pattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"^first$"}, result.MatchedPatterns) {
+		t.Errorf("Expecting the unregistered pattern to be named by its regexp, got: %v", result.MatchedPatterns)
+	}
+	if !result.Succeeded() || result.ExitCode != 0 {
+		t.Errorf("Expecting the run to succeed, got: %+v", result)
+	}
+	if len(results.Failed()) != 0 {
+		t.Errorf("Expecting no failed results, got: %v", results.Failed())
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/log"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The messages of untyped errors which are likely to succeed when retried, such as a 404 from a proxy which is still
+// indexing a module, a failed git fetch, a network timeout or a server error of a proxy.
+var transientErrorRegExp = regexp.MustCompile(`(^404( Not Found)?\s?:)|(exit status 128)|(i/o timeout)|(connection reset by peer)|(TLS handshake timeout)|(\b50[234] (Bad Gateway|Service Unavailable|Gateway Timeout)\b)`)
+
+// The exit status of git when fetching fails, as when the connection to the server fails.
+const gitFetchFailedExitStatus = 128
+
+var retryPolicy = NewRetryPolicy(1)
+var retryPolicyMutex sync.Mutex
+
+// Defines how failing go commands are retried.
+type RetryPolicy struct {
+	// The maximum number of times a command runs, including the first run.
+	MaxAttempts int
+	// The time to wait before the first retry. The wait time is doubled after each retry.
+	Backoff time.Duration
+	// The maximum time to wait between retries.
+	MaxBackoff time.Duration
+	// Returns true if a command which failed with the error should be retried.
+	IsRetryable func(err error) bool
+}
+
+// Creates a policy retrying transient errors, with an exponential backoff starting at one second.
+func NewRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: maxAttempts,
+		Backoff:     time.Second,
+		MaxBackoff:  30 * time.Second,
+		IsRetryable: IsTransientError,
+	}
+}
+
+// Returns true if the error is likely to be resolved by running the command again.
+// The typed errors are classified by their status: a ModuleNotFoundError is transient if the status is 404, and a
+// GitFetchError if git exited with 128. The other GoErrors aren't transient. An ExitError is classified by the typed
+// errors it wraps, or else is transient if the output of the go command is, and the other errors if their message is.
+// A MultiError is transient if all of its errors are.
+func IsTransientError(err error) bool {
+	switch typedErr := err.(type) {
+	case nil:
+		return false
+	case *MultiError:
+		for _, e := range typedErr.Errors {
+			if !IsTransientError(e) {
+				return false
+			}
+		}
+		return len(typedErr.Errors) > 0
+	case *ModuleNotFoundError:
+		return typedErr.StatusCode == http.StatusNotFound
+	case *GitFetchError:
+		return typedErr.ExitStatus == gitFetchFailedExitStatus
+	case *ExitError:
+		switch typedErr.Err.(type) {
+		case GoError, *MultiError:
+			return IsTransientError(typedErr.Err)
+		}
+		return transientErrorRegExp.MatchString(typedErr.Stderr)
+	case GoError:
+		return false
+	default:
+		return transientErrorRegExp.MatchString(err.Error())
+	}
+}
+
+// Sets the policy used by the go commands of this package. By default, failing commands are not retried.
+//
+// Deprecated: the policy applies to all the go commands of the process. Use the RetryPolicy of a Runner instead.
+func SetRetryPolicy(policy *RetryPolicy) {
+	retryPolicyMutex.Lock()
+	defer retryPolicyMutex.Unlock()
+	retryPolicy = policy
+}
+
+func getRetryPolicy() *RetryPolicy {
+	retryPolicyMutex.Lock()
+	defer retryPolicyMutex.Unlock()
+	return retryPolicy
+}
+
+// Runs the operation until it succeeds, fails with a non retryable error, the attempts are exhausted or ctx is done.
+func (policy *RetryPolicy) Run(ctx context.Context, description string, operation func() error) error {
+	backoff := policy.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = operation()
+		if err == nil || attempt >= policy.MaxAttempts || policy.IsRetryable == nil || !policy.IsRetryable(err) {
+			return err
+		}
+		if collector := GetOptions(ctx).Metrics; collector != nil {
+			collector.AddCount(RetriesMetric, 1, map[string]string{CommandLabel: getCmdName(&Cmd{Command: strings.Fields(strings.TrimPrefix(description, "go "))})})
+		}
+		logger := getLogger(ctx).WithFields(log.Fields{"command": description, "attempt": attempt, "maxAttempts": policy.MaxAttempts, "error": err.Error()})
+		logger.Warn(fmt.Sprintf("%s failed with: %s. Attempt %d out of %d, retrying in %s...", description, err.Error(), attempt, policy.MaxAttempts, backoff))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+func runWithRetries(ctx context.Context, description string, operation func() error) error {
+	policy := GetOptions(ctx).RetryPolicy
+	if policy == nil {
+		policy = getRetryPolicy()
+	}
+	return policy.Run(ctx, description, operation)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		maxAttempts      int
+		expectedAttempts int
+	}{
+		{"notFound", errors.New("404 Not Found: github.com/package@v1.0.0"), 3, 3},
+		{"gitFetch", errors.New("git fetch -f origin: exit status 128"), 2, 2},
+		{"notRetryable", errors.New("unknown revision: github.com/package@v1.0.0"), 3, 1},
+		{"singleAttempt", errors.New("404 Not Found: github.com/package@v1.0.0"), 1, 1},
+		{"multiError", &MultiError{Errors: []error{errors.New("404 Not Found: github.com/a@v1.0.0"), errors.New("404 Not Found: github.com/b@v1.0.0")}}, 2, 2},
+		{"partiallyRetryableMultiError", &MultiError{Errors: []error{errors.New("404 Not Found: github.com/a@v1.0.0"), errors.New("unknown revision: github.com/b@v1.0.0")}}, 2, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := NewRetryPolicy(test.maxAttempts)
+			policy.Backoff = time.Millisecond
+			attempts := 0
+			err := policy.Run(context.Background(), test.name, func() error {
+				attempts++
+				return test.err
+			})
+			if err != test.err {
+				t.Errorf("Test name: %s: Expected error: %v, Got: %v", test.name, test.err, err)
+			}
+			if attempts != test.expectedAttempts {
+				t.Errorf("Test name: %s: Expected %d attempts, Got: %d", test.name, test.expectedAttempts, attempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyStopsOnSuccess(t *testing.T) {
+	policy := NewRetryPolicy(5)
+	policy.Backoff = time.Millisecond
+	attempts := 0
+	err := policy.Run(context.Background(), "success", func() error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("404 Not Found: github.com/package@v1.0.0")
+		}
+		return nil
+	})
+	if err != nil || attempts != 2 {
+		t.Error("Expecting success after 2 attempts, got:", attempts, err)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"moduleNotFound", &ModuleNotFoundError{Module: "github.com/a@v1.0.0", StatusCode: 404, Status: "404 Not Found"}, true},
+		{"moduleGone", &ModuleNotFoundError{Module: "github.com/a@v1.0.0", StatusCode: 410, Status: "410 Gone"}, false},
+		{"gitFetch", &GitFetchError{Repo: "origin", ExitStatus: 128}, true},
+		{"gitFetchOtherStatus", &GitFetchError{Repo: "origin", ExitStatus: 1}, false},
+		{"unknownRevision", &UnknownRevisionError{Module: "github.com/a@v1.0.0", Revision: "v1.0.0", Line: "exit status 128"}, false},
+		{"exitErrorTimeout", &ExitError{Command: "go mod download", ExitCode: 1, Stderr: "dial tcp: i/o timeout\n", Err: errors.New("exit status 1")}, true},
+		{"exitErrorBadGateway", &ExitError{Command: "go mod download", ExitCode: 1, Stderr: "reading https://proxy/a/@v/list: 502 Bad Gateway\n", Err: errors.New("exit status 1")}, true},
+		{"exitErrorCompilation", &ExitError{Command: "go build", ExitCode: 1, Stderr: "main.go:3:2: undefined: foo\n", Err: errors.New("exit status 1")}, false},
+		{"exitErrorWithModuleGone", &ExitError{Command: "go mod download", ExitCode: 1, Stderr: "502 Bad Gateway\n", Err: &ModuleNotFoundError{StatusCode: 410, Status: "410 Gone"}}, false},
+		{"untypedServerError", errors.New("reading https://proxy/a/@v/list: 503 Service Unavailable"), true},
+		{"untypedVersion", errors.New("github.com/a@v1.502.0: invalid go version"), false},
+		{"multiError", &MultiError{Errors: []error{&GitFetchError{ExitStatus: 128}, &ModuleNotFoundError{StatusCode: 404}}}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := IsTransientError(test.err); actual != test.expected {
+				t.Errorf("Expected: %t, Got: %t", test.expected, actual)
+			}
+		})
+	}
+}
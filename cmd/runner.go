@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/log"
+	"time"
+)
+
+// Runs go commands with its own configuration, independently of the package-level configuration
+// set by SetGoExecutable, SetRetryPolicy and GetPatternRegistry.
+// Runners with different configurations can be used concurrently from multiple goroutines.
+// Fields left nil fall back to the package-level configuration, so runners should be created by NewRunner.
+type Runner struct {
+	// The go binary. If nil, the go binary in the PATH is used.
+	Executable *GoExecutable
+	// Environment variables set only for the go commands.
+	Env map[string]string
+	// The working directory of the go commands. Defaults to the working directory of the process.
+	Dir string
+	// The patterns the output of the go commands is matched against.
+	Patterns *PatternRegistry
+	// Defines how failing go commands are retried.
+	RetryPolicy *RetryPolicy
+	// Receives the log entries of the go commands.
+	Logger log.Logger
+	// Runs the go commands. If nil, they run as processes.
+	Executor Executor
+	// Traces the go commands. If nil, they aren't traced.
+	Tracer Tracer
+	// Receives the metrics of the go commands. If nil, they aren't collected.
+	Metrics MetricsCollector
+	// The time each go command may run before it is interrupted, and then killed after the grace period. If zero, there's no timeout.
+	Timeout            time.Duration
+	TimeoutGracePeriod time.Duration
+}
+
+// Creates a runner with the built-in patterns, which doesn't retry failing commands and logs to the logger set by log.SetLogger.
+func NewRunner() (*Runner, error) {
+	registry, err := NewDefaultPatternRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{Patterns: registry, RetryPolicy: NewRetryPolicy(1), Logger: log.GetLogger()}, nil
+}
+
+// Returns a copy of ctx carrying the options of ctx, overridden by the fields set on the runner. Fields left empty
+// keep the options of ctx, and the environment variables of the runner are added to those of ctx.
+// All the functions of this package run with the returned context use the runner's configuration.
+func (runner *Runner) WithContext(ctx context.Context) context.Context {
+	options := *GetOptions(ctx)
+	env := map[string]string{}
+	for key, value := range options.Env {
+		env[key] = value
+	}
+	for key, value := range runner.Env {
+		env[key] = value
+	}
+	options.Env = env
+	if runner.Dir != "" {
+		options.Dir = runner.Dir
+	}
+	if runner.Executable != nil {
+		options.Executable = runner.Executable
+	}
+	if runner.Patterns != nil {
+		options.Patterns = runner.Patterns
+	}
+	if runner.RetryPolicy != nil {
+		options.RetryPolicy = runner.RetryPolicy
+	}
+	if runner.Logger != nil {
+		options.Logger = runner.Logger
+	}
+	if runner.Executor != nil {
+		options.Executor = runner.Executor
+	}
+	if runner.Tracer != nil {
+		options.Tracer = runner.Tracer
+	}
+	if runner.Metrics != nil {
+		options.Metrics = runner.Metrics
+	}
+	if runner.Timeout != 0 {
+		options.Timeout = runner.Timeout
+	}
+	if runner.TimeoutGracePeriod != 0 {
+		options.TimeoutGracePeriod = runner.TimeoutGracePeriod
+	}
+	return WithOptions(ctx, &options)
+}
+
+// Runs the go command with the runner's configuration.
+func (runner *Runner) RunGo(ctx context.Context, args ...string) error {
+	return RunGo(runner.WithContext(ctx), args)
+}
+
+// Runs the go command with the runner's configuration, and returns the result of its last attempt.
+func (runner *Runner) RunGoWithResult(ctx context.Context, args ...string) (*RunResult, error) {
+	return RunGoWithResult(runner.WithContext(ctx), args)
+}
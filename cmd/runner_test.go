@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunnersRunConcurrently(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "runnerTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		dir := filepath.Join(tempDir, strconv.Itoa(i))
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/test"+strconv.Itoa(i)+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runner, err := NewRunner()
+		if err != nil {
+			t.Fatal(err)
+		}
+		runner.Dir = dir
+		runner.Env = map[string]string{"GOPRIVATE": "example.com/private" + strconv.Itoa(i)}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := runner.WithContext(context.Background())
+			env, err := GetGoEnv(ctx)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if env.GOMOD != filepath.Join(runner.Dir, "go.mod") || env.GOPRIVATE != runner.Env["GOPRIVATE"] {
+				t.Errorf("Runner %d ran with an unexpected go env: %+v", i, env)
+			}
+			projectRoot, err := getProjectRoot(ctx)
+			if err != nil {
+				t.Error(err)
+			}
+			if projectRoot != runner.Dir {
+				t.Errorf("Expected project root: %s, Got: %s", runner.Dir, projectRoot)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestRunnerWithContextKeepsUnsetOptions(t *testing.T) {
+	executor := NewFakeExecutor()
+	ctx := WithOptions(context.Background(), &Options{Dir: "ctxDir", Executor: executor, Timeout: time.Minute, Env: map[string]string{"A": "ctx", "B": "ctx"}})
+	runner := &Runner{Dir: "runnerDir", Env: map[string]string{"B": "runner"}}
+	options := GetOptions(runner.WithContext(ctx))
+	if options.Dir != "runnerDir" {
+		t.Errorf("Expecting the dir of the runner, got: %s", options.Dir)
+	}
+	if options.Executor != executor || options.Timeout != time.Minute {
+		t.Errorf("Expecting the unset fields of the runner to keep the options of the context, got: %+v", options)
+	}
+	if !reflect.DeepEqual(map[string]string{"A": "ctx", "B": "runner"}, options.Env) {
+		t.Errorf("Unexpected env: %v", options.Env)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"os"
+	"path/filepath"
+)
+
+// A temporary GOPATH, module cache and build cache, used by go commands instead of the shared ones.
+// Concurrent builds running on the same machine, each in its own sandbox, don't contend on the cache locks
+// and can't corrupt each other's caches.
+type Sandbox struct {
+	Dir        string
+	GoPath     string
+	GoModCache string
+	GoCache    string
+	tempDirs   TempDirProvider
+	ctx        context.Context
+}
+
+// Creates a sandbox in a new temporary directory. The sandbox must be removed by calling Close.
+func NewSandbox() (*Sandbox, error) {
+	return newSandbox(context.Background(), &TempDirs{})
+}
+
+// Creates a sandbox in a new directory of the TempDirProvider of ctx. The sandbox must be removed by calling Close.
+func NewSandboxWithContext(ctx context.Context) (*Sandbox, error) {
+	return newSandbox(ctx, getTempDirProvider(ctx))
+}
+
+func newSandbox(ctx context.Context, tempDirs TempDirProvider) (*Sandbox, error) {
+	dir, err := tempDirs.MkdirTemp("gocmd-sandbox")
+	if err != nil {
+		return nil, err
+	}
+	sandbox := &Sandbox{
+		Dir:        dir,
+		GoPath:     filepath.Join(dir, "gopath"),
+		GoModCache: filepath.Join(dir, "gopath", "pkg", "mod"),
+		GoCache:    filepath.Join(dir, "gocache"),
+		tempDirs:   tempDirs,
+		ctx:        ctx,
+	}
+	for _, path := range []string{sandbox.GoModCache, sandbox.GoCache} {
+		if err = os.MkdirAll(path, 0755); err != nil {
+			tempDirs.Remove(dir, true)
+			return nil, errorutils.CheckError(err)
+		}
+	}
+	getLogger(ctx).Debug("Created a Go sandbox in", dir)
+	return sandbox, nil
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands use the GOPATH and caches of the sandbox.
+func (sandbox *Sandbox) WithContext(ctx context.Context) context.Context {
+	ctx = WithEnv(ctx, "GOPATH", sandbox.GoPath)
+	ctx = WithEnv(ctx, "GOMODCACHE", sandbox.GoModCache)
+	return WithEnv(ctx, "GOCACHE", sandbox.GoCache)
+}
+
+// Removes the sandbox directory.
+// The module cache is read-only, so its files are made writable before they are removed.
+func (sandbox *Sandbox) Close() error {
+	return sandbox.close(false)
+}
+
+// Removes the sandbox directory with its TempDirProvider, which may keep it if the operation using it failed.
+func (sandbox *Sandbox) close(failed bool) error {
+	err := filepath.Walk(sandbox.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Mode()&0200 == 0 {
+			return os.Chmod(path, info.Mode()|0200)
+		}
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return errorutils.CheckError(err)
+	}
+	getLogger(sandbox.ctx).Debug("Removing the Go sandbox in", sandbox.Dir)
+	return sandbox.tempDirs.Remove(sandbox.Dir, failed)
+}
+
+// Runs fn with a context whose go commands use a new sandbox, and removes the sandbox once fn returns.
+func RunInSandbox(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	sandbox, err := NewSandboxWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := sandbox.close(err != nil); err == nil {
+			err = closeErr
+		}
+	}()
+	return fn(sandbox.WithContext(ctx))
+}
@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunInSandbox(t *testing.T) {
+	var sandboxDir string
+	err := RunInSandbox(context.Background(), func(ctx context.Context) error {
+		env, err := GetGoEnv(ctx)
+		if err != nil {
+			return err
+		}
+		sandboxDir = filepath.Dir(env.GOPATH)
+		if env.GOMODCACHE != filepath.Join(env.GOPATH, "pkg", "mod") || filepath.Dir(env.GOCACHE) != sandboxDir {
+			t.Errorf("Unexpected go env in the sandbox: %+v", env)
+		}
+		// Modules extracted to the module cache are read-only.
+		moduleDir := filepath.Join(env.GOMODCACHE, "example.com", "dep@v1.0.0")
+		if err = os.MkdirAll(moduleDir, 0755); err != nil {
+			return err
+		}
+		return os.Chmod(moduleDir, 0555)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(sandboxDir); !os.IsNotExist(err) {
+		t.Errorf("Expected the sandbox %s to be removed", sandboxDir)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strings"
+)
+
+// Describes which builds of the main module need a dependency.
+type DependencyScope string
+
+const (
+	// Needed by the packages of the main module.
+	MainScope DependencyScope = "main"
+	// Needed only by the tests of the main module.
+	TestScope DependencyScope = "test"
+	// Needed only by the tools of the main module, which are imported by files with the ToolsBuildTag build tag.
+	ToolScope DependencyScope = "tool"
+)
+
+// The build tag of the files importing the tools of the project, such as tools.go.
+const ToolsBuildTag = "tools"
+
+// Prints the "path@version" of the module of each package, or nothing for packages of the main module and the standard library.
+const depsModuleTemplate = "{{with .Module}}{{if not .Main}}{{.Path}}@{{.Version}}{{end}}{{end}}"
+
+// Prints the error of each package which failed loading, prefixed by packageErrorPrefix, followed by the module of the package.
+const depsModuleWithErrorsTemplate = "{{with .Error}}" + packageErrorPrefix + "{{.Err}}\n{{end}}" + depsModuleTemplate
+
+const packageErrorPrefix = "error: "
+
+// Files such as tools.go import the main packages of the tools, which go list reports as errors.
+const programImportError = "is a program, not an importable package"
+
+// Classifies the dependencies of the main module by the packages importing them, using go list -deps ./...
+// with and without -test and the tools build tag. The tools are listed with -e, since importing their main packages
+// is an error for go list. Other errors of the listed packages fail the classification.
+// Returns the scope of each dependency by its "path@version" notation. Modules with no imported packages, which are needed
+// only by the module graph, are not returned.
+func GetDependenciesScopes(ctx context.Context) (map[string]DependencyScope, error) {
+	scopes := map[string]DependencyScope{}
+	// The tests and the tools builds include the packages of the main build, so the narrower scopes are listed first.
+	for _, scope := range []struct {
+		scope DependencyScope
+		args  []string
+	}{
+		{MainScope, []string{"list", "-deps", "-f", depsModuleTemplate, "./..."}},
+		{TestScope, []string{"list", "-deps", "-test", "-f", depsModuleTemplate, "./..."}},
+		{ToolScope, []string{"list", "-e", "-deps", "-tags", ToolsBuildTag, "-f", depsModuleWithErrorsTemplate, "./..."}},
+	} {
+		output, err := runWithUnchangedModFiles(ctx, false, scope.args...)
+		if err != nil {
+			return nil, err
+		}
+		for _, module := range getLines([]byte(output)) {
+			if strings.HasPrefix(module, packageErrorPrefix) {
+				if !strings.Contains(module, programImportError) {
+					return nil, errorutils.CheckError(errors.New(strings.TrimPrefix(module, packageErrorPrefix)))
+				}
+				continue
+			}
+			if _, exists := scopes[module]; !exists {
+				scopes[module] = scope.scope
+			}
+		}
+	}
+	return scopes, nil
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetDependenciesScopes(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: "\n\ngithub.com/pkg/errors@v0.9.1\ngolang.org/x/text@v0.3.2\n"},
+		"list", "-deps", "-f", depsModuleTemplate, "./...")
+	executor.On(ExecutorResult{Stdout: "github.com/pkg/errors@v0.9.1\ngithub.com/stretchr/testify@v1.4.0\ngolang.org/x/text@v0.3.2\n"},
+		"list", "-deps", "-test", "-f", depsModuleTemplate, "./...")
+	executor.On(ExecutorResult{Stdout: "github.com/pkg/errors@v0.9.1\n" + packageErrorPrefix + `import "golang.org/x/tools/cmd/stringer" ` + programImportError + "\ngolang.org/x/tools@v0.1.0\n"},
+		"list", "-e", "-deps", "-tags", ToolsBuildTag, "-f", depsModuleWithErrorsTemplate, "./...")
+
+	scopes, err := GetDependenciesScopes(WithExecutor(context.Background(), executor))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]DependencyScope{
+		"github.com/pkg/errors@v0.9.1":       MainScope,
+		"golang.org/x/text@v0.3.2":           MainScope,
+		"github.com/stretchr/testify@v1.4.0": TestScope,
+		"golang.org/x/tools@v0.1.0":          ToolScope,
+	}
+	if !reflect.DeepEqual(expected, scopes) {
+		t.Errorf("Expected: %v, Got: %v", expected, scopes)
+	}
+}
+
+func TestGetDependenciesScopesErrors(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: packageErrorPrefix + "no required module provides package rsc.io/quote\n"},
+		"list", "-e", "-deps", "-tags", ToolsBuildTag, "-f", depsModuleWithErrorsTemplate, "./...")
+	_, err := GetDependenciesScopes(WithExecutor(context.Background(), executor))
+	if err == nil || !strings.Contains(err.Error(), "rsc.io/quote") {
+		t.Error("Expecting the errors other than importing main packages to fail, got:", err)
+	}
+}
+
+// Lists the packages of a project whose tools.go imports the main package of a tool.
+func TestGetDependenciesScopesWithToolsFile(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("The test runs go list")
+	}
+	dir, err := filepath.Abs(filepath.Join("testdata", "tools"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithEnv(WithModuleDir(context.Background(), dir), "GOTOOLCHAIN", "local")
+	scopes, err := GetDependenciesScopes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scopes) != 0 {
+		t.Error("Expecting the project to have no dependencies, got:", scopes)
+	}
+}
+
+func TestGetDependenciesScopesReadonly(t *testing.T) {
+	projectDir, ctx := createFileProxyProject(t)
+	defer os.RemoveAll(filepath.Dir(projectDir))
+	err := RunInSandbox(ctx, func(ctx context.Context) error {
+		scopes, err := GetDependenciesScopes(ctx)
+		if err != nil {
+			return err
+		}
+		expected := map[string]DependencyScope{"example.com/dep@v1.0.0": MainScope}
+		if !reflect.DeepEqual(expected, scopes) {
+			t.Errorf("Expected: %v, Got: %v", expected, scopes)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
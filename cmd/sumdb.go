@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/mod/sumdb/note"
+	"net/url"
+)
+
+// Checksum databases known to the go command, which can be used by name, without their keys.
+var knownChecksumDatabases = []string{"sum.golang.org", "sum.golang.google.cn"}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands verify modules against the checksum database.
+// key is the name of a known database, such as "sum.golang.org", or the verifier key of the database,
+// such as "sum.example.com+d43c1a2f+AbB3...", which is validated as the go command does. The database is accessed at
+// dbUrl, or at https://<name> if dbUrl is empty.
+func WithChecksumDatabase(ctx context.Context, key, dbUrl string) (context.Context, error) {
+	if !contains(knownChecksumDatabases, key) {
+		if _, err := note.NewVerifier(key); err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("Invalid checksum database key %q: %s", key, err.Error()))
+		}
+	}
+	gosumdb := key
+	if dbUrl != "" {
+		parsedUrl, err := url.Parse(dbUrl)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+			return nil, errorutils.CheckError(fmt.Errorf("Invalid checksum database URL %s: the scheme must be http or https.", dbUrl))
+		}
+		gosumdb += " " + dbUrl
+	}
+	return WithEnv(ctx, "GOSUMDB", gosumdb), nil
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands don't verify modules against a checksum database.
+// The modules are still verified against go.sum.
+func WithoutChecksumDatabase(ctx context.Context) context.Context {
+	return WithEnv(ctx, "GOSUMDB", "off")
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWithChecksumDatabase(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		dbUrl    string
+		expected string
+	}{
+		{"knownName", "sum.golang.org", "", "sum.golang.org"},
+		{"customKeyAndUrl", "sum.example.com+aecbf3cc+AYaNpgJYoG62OySqWTmWIPA/Y2TLLvsrdJ13L6fmdBHn", "https://sumdb.example.com", "sum.example.com+aecbf3cc+AYaNpgJYoG62OySqWTmWIPA/Y2TLLvsrdJ13L6fmdBHn https://sumdb.example.com"},
+		{"keyWithPlus", "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8", "", "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8"},
+		{"invalidKey", "sum.example.com", "", ""},
+		{"invalidKeyHash", "sum.example.com+d43c1a2f+AbB3", "", ""},
+		{"invalidUrl", "sum.golang.org", "ftp://sumdb.example.com", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, err := WithChecksumDatabase(context.Background(), test.key, test.dbUrl)
+			if test.expected == "" {
+				if err == nil {
+					t.Errorf("Test name: %s: Expecting an error", test.name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := GetOptions(ctx).Env["GOSUMDB"]; actual != test.expected {
+				t.Errorf("Test name: %s: Expected: %s, Got: %s", test.name, test.expected, actual)
+			}
+		})
+	}
+	if actual := GetOptions(WithoutChecksumDatabase(context.Background())).Env["GOSUMDB"]; actual != "off" {
+		t.Error("Expecting GOSUMDB to be off, got:", actual)
+	}
+}
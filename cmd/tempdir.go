@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/log"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Creates and removes the temp directories of the operations, such as the backups of go.mod and go.sum and the sandboxes.
+type TempDirProvider interface {
+	// Creates a new temp directory whose name starts with the prefix.
+	MkdirTemp(prefix string) (string, error)
+	// Removes a directory created by MkdirTemp once its operation is done. failed is true if the operation failed.
+	Remove(dir string, failed bool) error
+}
+
+// Creates the temp directories under a root directory. The zero value creates them in the temp directory of the OS,
+// and removes them when done.
+type TempDirs struct {
+	// The root of the temp directories. Defaults to the temp directory of the OS.
+	Root string
+	// If set, the temp directories are created in a subdirectory of the root with this name, such as the id of a CI job,
+	// which is removed by Close.
+	Run string
+	// Keeps the temp directories of failed operations, for investigating the failures.
+	RetainOnFailure bool
+	mutex           sync.Mutex
+	retained        []string
+}
+
+func (dirs *TempDirs) MkdirTemp(prefix string) (string, error) {
+	root := dirs.Root
+	if root == "" {
+		root = os.TempDir()
+	}
+	if dirs.Run != "" {
+		root = filepath.Join(root, dirs.Run)
+		if err := os.MkdirAll(root, 0755); err != nil {
+			return "", errorutils.CheckError(err)
+		}
+	}
+	dir, err := ioutil.TempDir(root, prefix)
+	return dir, errorutils.CheckError(err)
+}
+
+func (dirs *TempDirs) Remove(dir string, failed bool) error {
+	if failed && dirs.RetainOnFailure {
+		log.Warn("Keeping the temp directory of the failed operation:", dir)
+		dirs.mutex.Lock()
+		defer dirs.mutex.Unlock()
+		dirs.retained = append(dirs.retained, dir)
+		return nil
+	}
+	return errorutils.CheckError(os.RemoveAll(dir))
+}
+
+// Returns the temp directories kept for failed operations.
+func (dirs *TempDirs) Retained() []string {
+	dirs.mutex.Lock()
+	defer dirs.mutex.Unlock()
+	return append([]string(nil), dirs.retained...)
+}
+
+// Removes the directory of the run, unless temp directories of failed operations are kept in it.
+func (dirs *TempDirs) Close() error {
+	if dirs.Run == "" || len(dirs.Retained()) > 0 {
+		return nil
+	}
+	root := dirs.Root
+	if root == "" {
+		root = os.TempDir()
+	}
+	return errorutils.CheckError(os.RemoveAll(filepath.Join(root, dirs.Run)))
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose operations create their temp directories with the provider.
+func WithTempDirProvider(ctx context.Context, provider TempDirProvider) context.Context {
+	options := *GetOptions(ctx)
+	options.TempDirs = provider
+	return WithOptions(ctx, &options)
+}
+
+// Returns the TempDirProvider of ctx, or a TempDirs removing its directories once done if none was set.
+func GetTempDirProvider(ctx context.Context) TempDirProvider {
+	return getTempDirProvider(ctx)
+}
+
+func getTempDirProvider(ctx context.Context) TempDirProvider {
+	if provider := GetOptions(ctx).TempDirs; provider != nil {
+		return provider
+	}
+	return &TempDirs{}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTempDirs(t *testing.T) {
+	root, err := ioutil.TempDir("", "tempDirsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	tempDirs := &TempDirs{Root: root, Run: "job-1", RetainOnFailure: true}
+	ctx := WithTempDirProvider(context.Background(), tempDirs)
+
+	backups, err := NewFileBackupManagerWithContext(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(backups.GetBackupDir()) != filepath.Join(root, "job-1") || !strings.HasPrefix(filepath.Base(backups.GetBackupDir()), "gocmd-backup") {
+		t.Errorf("Expected the backups in the run directory, Got: %s", backups.GetBackupDir())
+	}
+	if err = backups.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(backups.GetBackupDir()); !os.IsNotExist(err) {
+		t.Error("Expected the backups directory to be removed, Got:", err)
+	}
+
+	var sandboxDir string
+	expectedErr := errors.New("failed")
+	err = RunInSandbox(ctx, func(ctx context.Context) error {
+		sandboxDir = filepath.Dir(filepath.Dir(filepath.Dir(GetOptions(ctx).Env["GOMODCACHE"])))
+		return expectedErr
+	})
+	if err != expectedErr {
+		t.Errorf("Expected: %v, Got: %v", expectedErr, err)
+	}
+	if retained := tempDirs.Retained(); len(retained) != 1 || retained[0] != sandboxDir {
+		t.Errorf("Expected the sandbox %s of the failed operation to be kept, Got: %v", sandboxDir, retained)
+	}
+	if _, err = os.Stat(sandboxDir); err != nil {
+		t.Error("Expected the sandbox to exist, Got:", err)
+	}
+	// The run directory is kept with the sandbox.
+	if err = tempDirs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "job-1")); err != nil {
+		t.Error("Expected the run directory to exist, Got:", err)
+	}
+
+	tempDirs = &TempDirs{Root: root, Run: "job-2"}
+	if err = RunInSandbox(WithTempDirProvider(context.Background(), tempDirs), func(ctx context.Context) error { return expectedErr }); err != expectedErr {
+		t.Errorf("Expected: %v, Got: %v", expectedErr, err)
+	}
+	if err = tempDirs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(filepath.Join(root, "job-2")); !os.IsNotExist(err) {
+		t.Error("Expected the run directory to be removed, Got:", err)
+	}
+}
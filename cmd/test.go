@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var coverageRegExp = regexp.MustCompile(`coverage: ([0-9.]+)% of statements`)
+
+// The status of a test or a package of tests.
+type TestStatus string
+
+const (
+	TestPassed  TestStatus = "pass"
+	TestFailed  TestStatus = "fail"
+	TestSkipped TestStatus = "skip"
+)
+
+// Flags of go test.
+type TestOptions struct {
+	// The packages to test, "./..." if empty.
+	Packages []string
+	// Runs only the tests matching the regular expression (-run).
+	Run string
+	// Writes a coverage profile to the file (-coverprofile).
+	CoverProfile string
+	// The coverage mode (-covermode), such as "atomic".
+	CoverMode string
+	Race      bool
+	// The timeout of each package's test binary (-timeout). The go command's default is used if 0.
+	Timeout time.Duration
+	// Additional flags, such as "-count=1".
+	Flags []string
+}
+
+// An event of go test -json, as described by go doc test2json.
+type TestEvent struct {
+	Time    time.Time
+	Action  string
+	Package string
+	Test    string
+	// The seconds the test or the package took.
+	Elapsed float64
+	Output  string
+	// The package of "build-output" events, which have no Package.
+	ImportPath string
+}
+
+// The result of a single test.
+type TestResult struct {
+	Name    string
+	Status  TestStatus
+	Elapsed time.Duration
+	Output  string
+}
+
+// The result of the tests of a package.
+type PackageTestResult struct {
+	Package string
+	Status  TestStatus
+	Elapsed time.Duration
+	// The output of the package which is not of a specific test, such as build errors and the coverage summary.
+	Output string
+	// The percentage of statements covered by the tests, if coverage is enabled.
+	Coverage float64
+	Tests    []TestResult
+}
+
+// The results of go test, per package in the order the packages were tested.
+type TestReport struct {
+	Packages []PackageTestResult
+}
+
+// Returns true if no package failed.
+func (report *TestReport) IsSuccess() bool {
+	for _, pkg := range report.Packages {
+		if pkg.Status == TestFailed {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the failed tests, in the "package.Test" notation, and the packages which failed without a failed test,
+// such as packages which failed to build.
+func (report *TestReport) Failures() []string {
+	var failures []string
+	for _, pkg := range report.Packages {
+		failedTests := 0
+		for _, test := range pkg.Tests {
+			if test.Status == TestFailed {
+				failures = append(failures, pkg.Package+"."+test.Name)
+				failedTests++
+			}
+		}
+		if pkg.Status == TestFailed && failedTests == 0 {
+			failures = append(failures, pkg.Package)
+		}
+	}
+	return failures
+}
+
+// Runs go test -json and returns the results of the packages and their tests.
+// Failing tests are reported in the returned report rather than as an error. An error is returned if go test failed
+// without reporting a failed package, for example because of invalid flags.
+func RunTests(ctx context.Context, options TestOptions) (*TestReport, error) {
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = append([]string{"test", "-json"}, getTestFlags(options)...)
+	description := "go " + strings.Join(goCmd.Command, " ")
+	if SkipInDryRunWithContext(ctx, "Running '"+description+"'") {
+		return &TestReport{}, nil
+	}
+	getLogger(ctx).Info("Running '" + description + "'")
+	output, errorOutput, err := runCmdWithOutputParser(goCmd, false)
+	report := parseTestEvents(output)
+	if err != nil && report.IsSuccess() {
+		err = contextError(ctx, err)
+		if errorOutput = strings.TrimSpace(errorOutput); errorOutput != "" {
+			err = fmt.Errorf("%s: %s", err.Error(), errorOutput)
+		}
+		return report, errorutils.CheckError(err)
+	}
+	return report, nil
+}
+
+func getTestFlags(options TestOptions) []string {
+	var flags []string
+	if options.Run != "" {
+		flags = append(flags, "-run", options.Run)
+	}
+	if options.CoverProfile != "" {
+		flags = append(flags, "-coverprofile", options.CoverProfile)
+	}
+	if options.CoverMode != "" {
+		flags = append(flags, "-covermode", options.CoverMode)
+	}
+	if options.Race {
+		flags = append(flags, "-race")
+	}
+	if options.Timeout > 0 {
+		flags = append(flags, "-timeout", options.Timeout.String())
+	}
+	flags = append(flags, options.Flags...)
+	if len(options.Packages) == 0 {
+		return append(flags, "./...")
+	}
+	return append(flags, options.Packages...)
+}
+
+// Parses the output of go test -json. Lines which are not events, such as build errors of older go versions, are ignored.
+func parseTestEvents(output string) *TestReport {
+	report := &TestReport{}
+	packages := map[string]*PackageTestResult{}
+	tests := map[string]*TestResult{}
+	var order []string
+	testsOrder := map[string][]string{}
+	for _, line := range strings.Split(output, "\n") {
+		var event TestEvent
+		if !strings.HasPrefix(strings.TrimSpace(line), "{") || json.Unmarshal([]byte(line), &event) != nil {
+			continue
+		}
+		if fields := strings.Fields(event.ImportPath); event.Package == "" && len(fields) > 0 {
+			// The import path of a test build is followed by the test binary, such as "a [a.test]".
+			event.Package = fields[0]
+		}
+		if event.Package == "" {
+			continue
+		}
+		pkg, exists := packages[event.Package]
+		if !exists {
+			pkg = &PackageTestResult{Package: event.Package}
+			packages[event.Package] = pkg
+			order = append(order, event.Package)
+		}
+		if event.Test == "" {
+			updatePackageResult(pkg, event)
+			continue
+		}
+		key := event.Package + " " + event.Test
+		test, exists := tests[key]
+		if !exists {
+			test = &TestResult{Name: event.Test}
+			tests[key] = test
+			testsOrder[event.Package] = append(testsOrder[event.Package], key)
+		}
+		switch event.Action {
+		case "output":
+			test.Output += event.Output
+		case string(TestPassed), string(TestFailed), string(TestSkipped):
+			test.Status = TestStatus(event.Action)
+			test.Elapsed = toDuration(event.Elapsed)
+		}
+	}
+	for _, name := range order {
+		pkg := packages[name]
+		for _, key := range testsOrder[name] {
+			pkg.Tests = append(pkg.Tests, *tests[key])
+		}
+		report.Packages = append(report.Packages, *pkg)
+	}
+	return report
+}
+
+func updatePackageResult(pkg *PackageTestResult, event TestEvent) {
+	switch event.Action {
+	case "output", "build-output":
+		pkg.Output += event.Output
+		if match := coverageRegExp.FindStringSubmatch(event.Output); match != nil {
+			pkg.Coverage, _ = strconv.ParseFloat(match[1], 64)
+		}
+	case string(TestPassed), string(TestFailed), string(TestSkipped):
+		pkg.Status = TestStatus(event.Action)
+		pkg.Elapsed = toDuration(event.Elapsed)
+	}
+}
+
+func toDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testEventsOutput = `{"Action":"start","Package":"example.com/a"}
+{"Action":"run","Package":"example.com/a","Test":"TestPass"}
+{"Action":"output","Package":"example.com/a","Test":"TestPass","Output":"=== RUN   TestPass\n"}
+{"Action":"pass","Package":"example.com/a","Test":"TestPass","Elapsed":0.5}
+{"Action":"run","Package":"example.com/a","Test":"TestFail"}
+{"Action":"output","Package":"example.com/a","Test":"TestFail","Output":"    a_test.go:10: failed\n"}
+{"Action":"fail","Package":"example.com/a","Test":"TestFail","Elapsed":0.25}
+{"Action":"run","Package":"example.com/a","Test":"TestSkip"}
+{"Action":"skip","Package":"example.com/a","Test":"TestSkip"}
+{"Action":"output","Package":"example.com/a","Output":"coverage: 75.5% of statements\n"}
+{"Action":"fail","Package":"example.com/a","Elapsed":1.5}
+# example.com/b
+{"ImportPath":"example.com/b [example.com/b.test]","Action":"build-output","Output":"b.go:3:1: syntax error\n"}
+{"Action":"fail","Package":"example.com/b","Elapsed":0,"FailedBuild":"example.com/b [example.com/b.test]"}
+{"Action":"skip","Package":"example.com/c","Output":"?   \texample.com/c\t[no test files]\n"}
+`
+
+func TestParseTestEvents(t *testing.T) {
+	report := parseTestEvents(testEventsOutput)
+	expected := &TestReport{Packages: []PackageTestResult{
+		{Package: "example.com/a", Status: TestFailed, Elapsed: 1500 * time.Millisecond, Output: "coverage: 75.5% of statements\n", Coverage: 75.5, Tests: []TestResult{
+			{Name: "TestPass", Status: TestPassed, Elapsed: 500 * time.Millisecond, Output: "=== RUN   TestPass\n"},
+			{Name: "TestFail", Status: TestFailed, Elapsed: 250 * time.Millisecond, Output: "    a_test.go:10: failed\n"},
+			{Name: "TestSkip", Status: TestSkipped},
+		}},
+		{Package: "example.com/b", Status: TestFailed, Output: "b.go:3:1: syntax error\n"},
+		{Package: "example.com/c", Status: TestSkipped},
+	}}
+	if !reflect.DeepEqual(expected, report) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, report)
+	}
+	if report.IsSuccess() {
+		t.Error("Expecting the report to fail")
+	}
+	expectedFailures := []string{"example.com/a.TestFail", "example.com/b"}
+	if failures := report.Failures(); !reflect.DeepEqual(expectedFailures, failures) {
+		t.Errorf("Expected: %v, Got: %v", expectedFailures, failures)
+	}
+}
+
+func TestGetTestFlags(t *testing.T) {
+	options := TestOptions{Packages: []string{"./a"}, Run: "TestA", CoverProfile: "cover.out", Race: true, Timeout: time.Minute, Flags: []string{"-count=1"}}
+	expected := []string{"-run", "TestA", "-coverprofile", "cover.out", "-race", "-timeout", "1m0s", "-count=1", "./a"}
+	if actual := getTestFlags(options); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
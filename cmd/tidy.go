@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	gomodfile "golang.org/x/mod/modfile"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Represents the changes go mod tidy made to the go.mod and go.sum files.
+// Requirements are in the "path@version" notation and sums are the go.sum lines.
+type TidyResult struct {
+	AddedRequirements   []string
+	RemovedRequirements []string
+	AddedSums           []string
+	RemovedSums         []string
+	// True if go mod tidy didn't run, because of dry run. The result then has no changes.
+	Skipped bool
+}
+
+// Returns true if go mod tidy didn't change the go.mod and go.sum files.
+// A skipped result isn't clean, since go mod tidy didn't run.
+func (result *TidyResult) IsClean() bool {
+	return !result.Skipped && len(result.AddedRequirements) == 0 && len(result.RemovedRequirements) == 0 &&
+		len(result.AddedSums) == 0 && len(result.RemovedSums) == 0
+}
+
+// Runs go mod tidy and returns the requirements and sums it added or removed.
+// In dry run, go mod tidy doesn't run and a skipped result is returned.
+func RunGoModTidy(ctx context.Context) (*TidyResult, error) {
+	projectDir, err := getProjectRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	modPath := filepath.Join(projectDir, "go.mod")
+	sumPath := filepath.Join(projectDir, "go.sum")
+	modBefore, err := readFileIfExists(GetFileSystem(ctx), modPath)
+	if err != nil {
+		return nil, err
+	}
+	sumBefore, err := readFileIfExists(GetFileSystem(ctx), sumPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if SkipInDryRunWithContext(ctx, "Running 'go mod tidy' in "+projectDir) {
+		return &TidyResult{Skipped: true}, nil
+	}
+	getLogger(ctx).Info("Running 'go mod tidy' in", projectDir)
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = []string{"mod", "tidy"}
+	err = runWithRetries(ctx, "go mod tidy", func() error {
+		_, _, err := runCmdWithOutputParser(goCmd, true)
+		return err
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(contextError(ctx, err))
+	}
+
+	modAfter, err := readFileIfExists(GetFileSystem(ctx), modPath)
+	if err != nil {
+		return nil, err
+	}
+	sumAfter, err := readFileIfExists(GetFileSystem(ctx), sumPath)
+	if err != nil {
+		return nil, err
+	}
+	requirementsBefore, err := getRequirements(modBefore)
+	if err != nil {
+		return nil, err
+	}
+	requirementsAfter, err := getRequirements(modAfter)
+	if err != nil {
+		return nil, err
+	}
+	result := &TidyResult{}
+	result.AddedRequirements, result.RemovedRequirements = diffLines(requirementsBefore, requirementsAfter)
+	result.AddedSums, result.RemovedSums = diffLines(getLines(sumBefore), getLines(sumAfter))
+	return result, nil
+}
+
+// Returns the requirements of the go.mod content in the "path@version" notation.
+func getRequirements(modContent []byte) ([]string, error) {
+	modFile, err := gomodfile.Parse("go.mod", modContent, nil)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var requirements []string
+	for _, require := range modFile.Require {
+		requirements = append(requirements, require.Mod.Path+"@"+require.Mod.Version)
+	}
+	return requirements, nil
+}
+
+// Returns the non empty lines of the content, without leading and trailing spaces.
+func getLines(content []byte) []string {
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Returns the sorted lines that appear only after and the sorted lines that appear only before.
+func diffLines(before, after []string) (added, removed []string) {
+	beforeSet := toSet(before)
+	afterSet := toSet(after)
+	for line := range afterSet {
+		if !beforeSet[line] {
+			added = append(added, line)
+		}
+	}
+	for line := range beforeSet {
+		if !afterSet[line] {
+			removed = append(removed, line)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return
+}
+
+func toSet(lines []string) map[string]bool {
+	set := map[string]bool{}
+	for _, line := range lines {
+		set[line] = true
+	}
+	return set
+}
+
+// Returns the content of the file, or nil if the file doesn't exist.
+func readFileIfExists(files fsys.FileSystem, path string) ([]byte, error) {
+	exists, err := fsys.IsFileExists(files, path)
+	if err != nil || !exists {
+		return nil, err
+	}
+	return fsys.ReadFile(files, path)
+}
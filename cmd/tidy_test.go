@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetRequirements(t *testing.T) {
+	modContent := `module github.com/you/hello
+
+require rsc.io/quote v1.5.2
+
+require (
+	github.com/pkg/errors v0.8.1
+	golang.org/x/lint v0.0.0-20181217174547-8f45f776aaf1 // indirect
+)
+
+replace github.com/jfrog/jfrog-client-go => github.com/jfrog/jfrog-client-go v0.3.1
+`
+	expected := []string{"rsc.io/quote@v1.5.2", "github.com/pkg/errors@v0.8.1", "golang.org/x/lint@v0.0.0-20181217174547-8f45f776aaf1"}
+	actual, err := getRequirements([]byte(modContent))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expecting: %v, Got: %v", expected, actual)
+	}
+}
+
+func TestGetRequirementsInvalidGoMod(t *testing.T) {
+	if _, err := getRequirements([]byte("module github.com/you/hello\n\nrequire rsc.io/quote\n")); err == nil {
+		t.Error("Expecting an error for a requirement without a version")
+	}
+}
+
+func TestRunGoModTidyDryRun(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "tidyTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	if err = ioutil.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	executor := NewFakeExecutor()
+	ctx := WithOptions(context.Background(), &Options{Dir: projectDir, Executor: executor})
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	result, err := RunGoModTidy(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&TidyResult{Skipped: true}, result) || result.IsClean() {
+		t.Errorf("Expected a skipped result, Got: %+v", result)
+	}
+	if len(executor.Calls()) != 0 {
+		t.Error("Expected go mod tidy not to run in dry run")
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name            string
+		before          []string
+		after           []string
+		expectedAdded   []string
+		expectedRemoved []string
+	}{
+		{"clean", []string{"a@v1", "b@v1"}, []string{"b@v1", "a@v1"}, nil, nil},
+		{"added", []string{"a@v1"}, []string{"a@v1", "c@v1", "b@v1"}, []string{"b@v1", "c@v1"}, nil},
+		{"removed", []string{"a@v1", "b@v1"}, []string{"a@v1"}, nil, []string{"b@v1"}},
+		{"upgraded", []string{"a@v1"}, []string{"a@v2"}, []string{"a@v2"}, []string{"a@v1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			added, removed := diffLines(test.before, test.after)
+			if !reflect.DeepEqual(test.expectedAdded, added) || !reflect.DeepEqual(test.expectedRemoved, removed) {
+				t.Errorf("Test name: %s: Expected: %v %v, Got: %v %v", test.name, test.expectedAdded, test.expectedRemoved, added, removed)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// The time a timed out go command is given to exit after being interrupted, before it is killed.
+const DefaultTimeoutGracePeriod = 10 * time.Second
+
+// A go command didn't finish within its timeout.
+type TimeoutError struct {
+	// The go command without its arguments, such as "go mod graph".
+	Command string
+	Timeout time.Duration
+	// True if the command didn't exit within the grace period after being interrupted, and was killed.
+	Killed bool
+	// The output the command printed before it was stopped.
+	Stdout string
+	Stderr string
+}
+
+func (err *TimeoutError) Error() string {
+	return fmt.Sprintf("'%s' timed out after %s.", err.Command, err.Timeout)
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands are each limited to run for the timeout.
+// A go command which times out is interrupted with SIGINT, so it can release the module cache locks,
+// and is killed if it doesn't exit within the grace period. A zero grace period uses DefaultTimeoutGracePeriod.
+// The go command then fails with a TimeoutError.
+func WithTimeout(ctx context.Context, timeout, gracePeriod time.Duration) context.Context {
+	options := *GetOptions(ctx)
+	options.Timeout = timeout
+	options.TimeoutGracePeriod = gracePeriod
+	return WithOptions(ctx, &options)
+}
+
+// Interrupts a process once it times out, and kills it if it doesn't exit within the grace period.
+type timeoutWatcher struct {
+	done     chan struct{}
+	timedOut int32
+	killed   int32
+}
+
+// Starts watching the process. If timeout isn't positive, the process is never stopped.
+func watchTimeout(process *os.Process, timeout, gracePeriod time.Duration) *timeoutWatcher {
+	watcher := &timeoutWatcher{done: make(chan struct{})}
+	if timeout <= 0 {
+		return watcher
+	}
+	if gracePeriod <= 0 {
+		gracePeriod = DefaultTimeoutGracePeriod
+	}
+	go func() {
+		select {
+		case <-watcher.done:
+			return
+		case <-time.After(timeout):
+		}
+		atomic.StoreInt32(&watcher.timedOut, 1)
+		// Interrupting isn't supported on Windows, so the process is killed right away.
+		if err := process.Signal(os.Interrupt); err == nil {
+			select {
+			case <-watcher.done:
+				return
+			case <-time.After(gracePeriod):
+			}
+		}
+		atomic.StoreInt32(&watcher.killed, 1)
+		process.Kill()
+	}()
+	return watcher
+}
+
+// Stops watching the process, which has exited. Returns whether it timed out, and whether it was killed.
+func (watcher *timeoutWatcher) stop() (timedOut, killed bool) {
+	close(watcher.done)
+	return atomic.LoadInt32(&watcher.timedOut) == 1, atomic.LoadInt32(&watcher.killed) == 1
+}
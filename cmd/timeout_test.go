@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Interrupting processes isn't supported on Windows")
+	}
+	tests := []struct {
+		name           string
+		script         string
+		expectedKilled bool
+		expectedStdout string
+	}{
+		{"interrupted", "sleep 5 & trap 'kill $!; echo interrupted; exit 1' INT; echo started; wait", false, "started\ninterrupted\n"},
+		{"killed", "trap '' INT; echo started; exec sleep 5", true, "started\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := WithTimeout(context.Background(), 500*time.Millisecond, 500*time.Millisecond)
+			goCmd := &Cmd{Context: ctx, Go: "sh", Command: []string{"-c", test.script}}
+			start := time.Now()
+			_, _, err := runCmdWithOutputParser(goCmd, false)
+			if time.Since(start) > 4*time.Second {
+				t.Errorf("The command wasn't stopped after its timeout")
+			}
+			timeoutErr, ok := err.(*TimeoutError)
+			if !ok {
+				t.Fatalf("Expected a TimeoutError, Got: %v", err)
+			}
+			if timeoutErr.Killed != test.expectedKilled || timeoutErr.Stdout != test.expectedStdout || timeoutErr.Timeout != 500*time.Millisecond {
+				t.Errorf("Unexpected error: %+v", timeoutErr)
+			}
+		})
+	}
+}
+
+func TestTimeoutWithExecutor(t *testing.T) {
+	ctx := WithExecutor(WithTimeout(context.Background(), 10*time.Millisecond, 0), executorFunc(func(ctx context.Context) {
+		<-ctx.Done()
+	}))
+	err := RunGo(ctx, []string{"mod", "download"})
+	if timeoutErr, ok := err.(*TimeoutError); !ok || !strings.Contains(timeoutErr.Error(), "'go mod download' timed out") {
+		t.Errorf("Expected a TimeoutError, Got: %v", err)
+	}
+}
+
+type executorFunc func(ctx context.Context)
+
+func (run executorFunc) Run(ctx context.Context, cmd []string, env map[string]string, dir string) (string, string, int, error) {
+	run(ctx)
+	return "", "", -1, ctx.Err()
+}
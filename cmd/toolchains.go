@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Installs several go versions side by side with the golang.org/dl wrappers, such as go1.21.3, and runs the same flow
+// with each of them, as for testing the compatibility of a library with the go versions it supports.
+// The wrappers are installed by the go binary of the context. Safe for concurrent use.
+type ToolchainManager struct {
+	// The dir the wrappers are installed to. Defaults to the GOBIN of the go env, or the bin dir of its GOPATH.
+	BinDir    string
+	mutex     sync.Mutex
+	installed map[string]*GoExecutable
+	// Serializes the installations of each version, so that a version is installed once.
+	installing map[string]*sync.Mutex
+}
+
+// The outcome of running a flow with one of the go versions of RunMatrix.
+type MatrixResult struct {
+	// The go version, such as "1.21.3".
+	Version string
+	// The go binary the flow ran with. Nil if the version couldn't be installed.
+	Executable *GoExecutable
+	// The results of the go commands the flow ran.
+	Results []*RunResult
+	// The time the flow took, excluding the installation of the version.
+	Duration time.Duration
+	// The error the installation or the flow failed with, or nil if it succeeded.
+	Err error
+}
+
+// Creates a manager installing the wrappers to binDir, or to the default dir if empty.
+func NewToolchainManager(binDir string) *ToolchainManager {
+	return &ToolchainManager{BinDir: binDir, installed: map[string]*GoExecutable{}, installing: map[string]*sync.Mutex{}}
+}
+
+// Installs the go version, such as "1.21.3", unless it's already installed, and returns its go binary.
+// The version's wrapper is installed with go install golang.org/dl/go<version>@latest, and downloads the version's SDK.
+// In dry run, returns the wrapper's go binary without recording the version as installed.
+func (manager *ToolchainManager) Install(ctx context.Context, version string) (*GoExecutable, error) {
+	if !IsGoVersion(version) {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid go version: %q. Expecting a release such as 1.21.3.", version))
+	}
+	// Since Go 1.21, the first release of a version is x.y.0 rather than x.y, so golang.org/dl has no go1.21 wrapper.
+	if numbers, preRelease := splitGoVersion(version); len(numbers) < 3 && preRelease == "" && CompareGoVersions(version, "1.21") >= 0 {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid go version: %q. Expecting a release with a patch version, such as %s.0.", version, version))
+	}
+	installing := manager.getInstallingMutex(version)
+	installing.Lock()
+	defer installing.Unlock()
+	if executable, ok := manager.Executable(version); ok {
+		return executable, nil
+	}
+	binDir, err := manager.getBinDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wrapper := "go" + version
+	wrapperPath := filepath.Join(binDir, wrapper)
+	if runtime.GOOS == "windows" {
+		wrapperPath += ".exe"
+	}
+	exists, err := fsys.IsFileExists(fsys.OsFileSystem{}, wrapperPath)
+	if err != nil {
+		return nil, err
+	}
+	// In dry run, the flows run with the wrapper's path, as if the version was installed.
+	dryRunExecutable := &GoExecutable{Path: wrapperPath, Version: version}
+	if !exists {
+		if SkipInDryRunWithContext(ctx, "Installing "+wrapper+" to "+binDir) {
+			return dryRunExecutable, nil
+		}
+		getLogger(ctx).Info("Installing", wrapper, "to", binDir)
+		goCmd, err := NewCmd(WithEnv(ctx, "GOBIN", binDir))
+		if err != nil {
+			return nil, err
+		}
+		goCmd.Command = []string{"install", "golang.org/dl/" + wrapper + "@latest"}
+		if _, _, err = runCmdWithOutputParser(goCmd, false); err != nil {
+			return nil, errorutils.CheckError(contextError(ctx, err))
+		}
+	}
+	if SkipInDryRunWithContext(ctx, "Running '"+wrapper+" download'") {
+		return dryRunExecutable, nil
+	}
+	// Downloading an SDK which is already downloaded only reports that it is.
+	downloadCmd := &Cmd{Context: ctx, Go: wrapperPath, Env: GetOptions(ctx).Env, Dir: GetOptions(ctx).Dir, Command: []string{"download"}}
+	if _, _, err = runCmdWithOutputParser(downloadCmd, false); err != nil {
+		return nil, errorutils.CheckError(contextError(ctx, err))
+	}
+	executable, err := FindGoExecutable(ctx, wrapperPath)
+	if err != nil {
+		return nil, err
+	}
+	manager.setInstalled(version, executable)
+	return executable, nil
+}
+
+func (manager *ToolchainManager) setInstalled(version string, executable *GoExecutable) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.installed == nil {
+		manager.installed = map[string]*GoExecutable{}
+	}
+	manager.installed[version] = executable
+}
+
+// Returns the mutex serializing the installations of the version.
+func (manager *ToolchainManager) getInstallingMutex(version string) *sync.Mutex {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.installing == nil {
+		manager.installing = map[string]*sync.Mutex{}
+	}
+	if manager.installing[version] == nil {
+		manager.installing[version] = &sync.Mutex{}
+	}
+	return manager.installing[version]
+}
+
+// Returns the go binary of the version if it was installed by the manager.
+func (manager *ToolchainManager) Executable(version string) (*GoExecutable, bool) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	executable, ok := manager.installed[version]
+	return executable, ok
+}
+
+// Returns the go binaries installed by the manager, from the oldest version to the newest.
+func (manager *ToolchainManager) Installed() []*GoExecutable {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	var executables []*GoExecutable
+	for _, executable := range manager.installed {
+		executables = append(executables, executable)
+	}
+	sort.Slice(executables, func(i, j int) bool {
+		return CompareGoVersions(executables[i].Version, executables[j].Version) < 0
+	})
+	return executables
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands run with the installed go version.
+func (manager *ToolchainManager) WithVersion(ctx context.Context, version string) (context.Context, error) {
+	executable, ok := manager.Executable(version)
+	if !ok {
+		return nil, errorutils.CheckError(fmt.Errorf("Go %s isn't installed.", version))
+	}
+	return withToolchain(ctx, executable), nil
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands run with the go binary.
+func withToolchain(ctx context.Context, executable *GoExecutable) context.Context {
+	// The go.mod file mustn't switch the go commands to another toolchain.
+	return WithEnv(WithExecutable(ctx, executable), "GOTOOLCHAIN", "local")
+}
+
+// Installs each of the go versions, and runs the flow with it, one version after the other.
+// The flow should run its go commands with the context it's called with. Returns the result of each version,
+// in the order of the versions, and an error if the flow failed with any of them.
+func (manager *ToolchainManager) RunMatrix(ctx context.Context, versions []string, flow func(ctx context.Context) error) ([]MatrixResult, error) {
+	results := make([]MatrixResult, len(versions))
+	var failed []string
+	for i, version := range versions {
+		if err := ctx.Err(); err != nil {
+			return results[:i], err
+		}
+		results[i] = manager.runWithVersion(ctx, version, flow)
+		if results[i].Err != nil {
+			getLogger(ctx).Error(fmt.Sprintf("Failed running with go %s: %s", version, results[i].Err.Error()))
+			failed = append(failed, version)
+		}
+	}
+	if len(failed) > 0 {
+		return results, errorutils.CheckError(fmt.Errorf("Failed with %d out of %d go versions: %s", len(failed), len(versions), strings.Join(failed, ", ")))
+	}
+	return results, nil
+}
+
+func (manager *ToolchainManager) runWithVersion(ctx context.Context, version string, flow func(ctx context.Context) error) MatrixResult {
+	result := MatrixResult{Version: version}
+	result.Executable, result.Err = manager.Install(ctx, version)
+	if result.Err != nil {
+		return result
+	}
+	// In dry run, the version isn't recorded as installed, so the flow runs with the returned go binary.
+	versionCtx, runResults := WithRunResults(withToolchain(ctx, result.Executable))
+	start := time.Now()
+	result.Err = flow(versionCtx)
+	result.Duration = time.Since(start)
+	result.Results = runResults.List()
+	return result
+}
+
+// Returns the BinDir of the manager, or the GOBIN of the go env, or the bin dir of its first GOPATH entry.
+func (manager *ToolchainManager) getBinDir(ctx context.Context) (string, error) {
+	if manager.BinDir != "" {
+		return manager.BinDir, nil
+	}
+	goEnv, err := GetGoEnv(ctx)
+	if err != nil {
+		return "", err
+	}
+	if goEnv.GOBIN != "" {
+		return goEnv.GOBIN, nil
+	}
+	goPath := filepath.SplitList(goEnv.GOPATH)
+	if len(goPath) == 0 || goPath[0] == "" {
+		return "", errorutils.CheckError(errors.New("Cannot install go versions: neither GOBIN nor GOPATH are set."))
+	}
+	return filepath.Join(goPath[0], "bin"), nil
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Answers go version with the version of the wrapper which runs it, such as go1.21.3, and records the other commands.
+type wrapperExecutor struct {
+	mutex sync.Mutex
+	calls []string
+}
+
+func (executor *wrapperExecutor) Run(ctx context.Context, cmd []string, env map[string]string, dir string) (string, string, int, error) {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+	name := strings.TrimSuffix(filepath.Base(cmd[0]), ".exe")
+	if cmd[1] == "version" {
+		return "go version " + name + " linux/amd64\n", "", 0, nil
+	}
+	executor.calls = append(executor.calls, name+" "+strings.Join(cmd[1:], " ")+" GOBIN="+env["GOBIN"])
+	return "", "", 0, nil
+}
+
+func TestToolchainManagerInstall(t *testing.T) {
+	binDir, err := ioutil.TempDir("", "toolchainsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(binDir)
+	executor := &wrapperExecutor{}
+	ctx := WithExecutor(context.Background(), executor)
+	manager := NewToolchainManager(binDir)
+
+	executable, err := manager.Install(ctx, "1.21.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if executable.Version != "1.21.3" || filepath.Base(executable.Path) != "go1.21.3" && filepath.Base(executable.Path) != "go1.21.3.exe" {
+		t.Errorf("Unexpected executable: %+v", executable)
+	}
+	expected := []string{"go install golang.org/dl/go1.21.3@latest GOBIN=" + binDir, "go1.21.3 download GOBIN="}
+	if !reflect.DeepEqual(expected, executor.calls) {
+		t.Errorf("Expecting: %v, Got: %v", expected, executor.calls)
+	}
+	if _, err = manager.Install(ctx, "1.21.3"); err != nil || len(executor.calls) != 2 {
+		t.Errorf("Expecting an installed version not to be installed again, got: %v, %v", err, executor.calls)
+	}
+	for _, version := range []string{"latest", "1.21", "1.22"} {
+		if _, err = manager.Install(ctx, version); err == nil {
+			t.Errorf("Expecting an error for the invalid version %s", version)
+		}
+	}
+
+	versionCtx, err := manager.WithVersion(ctx, "1.21.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if options := GetOptions(versionCtx); options.Executable != executable || options.Env["GOTOOLCHAIN"] != "local" {
+		t.Errorf("Expecting the context to run the go commands with go 1.21.3, got: %+v", options)
+	}
+	if _, err = manager.WithVersion(ctx, "1.22.1"); err == nil {
+		t.Error("Expecting an error for a version which isn't installed")
+	}
+}
+
+func TestToolchainManagerInstallConcurrently(t *testing.T) {
+	binDir, err := ioutil.TempDir("", "toolchainsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(binDir)
+	executor := &wrapperExecutor{}
+	ctx := WithExecutor(context.Background(), executor)
+	manager := NewToolchainManager(binDir)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = manager.Install(ctx, "1.21.3")
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(executor.calls) != 2 {
+		t.Errorf("Expecting the version to be installed once, got: %v", executor.calls)
+	}
+}
+
+func TestToolchainManagerInstallDryRun(t *testing.T) {
+	binDir, err := ioutil.TempDir("", "toolchainsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(binDir)
+	// The wrapper of go 1.20.1 is installed, but its SDK may not be downloaded.
+	if err = ioutil.WriteFile(filepath.Join(binDir, "go1.20.1"), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(binDir, "go1.20.1.exe"), nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	executor := &wrapperExecutor{}
+	ctx := WithExecutor(context.Background(), executor)
+	manager := NewToolchainManager(binDir)
+	SetDryRun(true)
+	defer SetDryRun(false)
+
+	for _, version := range []string{"1.21.3", "1.20.1"} {
+		executable, err := manager.Install(ctx, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.TrimSuffix(filepath.Base(executable.Path), ".exe") != "go"+version {
+			t.Errorf("Expecting the go binary of the go%s wrapper in dry run, got: %+v", version, executable)
+		}
+		if _, err = manager.WithVersion(ctx, version); err == nil {
+			t.Errorf("Expecting go %s not to be recorded as installed in dry run", version)
+		}
+	}
+	if len(executor.calls) != 0 || len(manager.Installed()) != 0 {
+		t.Errorf("Expecting no commands and no installed versions in dry run, got: %v, %v", executor.calls, manager.Installed())
+	}
+	if _, err = manager.RunMatrix(ctx, []string{"1.21.3"}, func(ctx context.Context) error {
+		if version := GetOptions(ctx).Executable.Version; version != "1.21.3" {
+			return errors.New("Unexpected go version: " + version)
+		}
+		return nil
+	}); err != nil {
+		t.Errorf("Expecting the flow to run with the wrapper in dry run, got: %v", err)
+	}
+
+	SetDryRun(false)
+	if _, err = manager.Install(ctx, "1.21.3"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"go install golang.org/dl/go1.21.3@latest GOBIN=" + binDir, "go1.21.3 download GOBIN="}
+	if !reflect.DeepEqual(expected, executor.calls) {
+		t.Errorf("Expecting the version to be installed after the dry run: %v, Got: %v", expected, executor.calls)
+	}
+}
+
+func TestToolchainManagerRunMatrix(t *testing.T) {
+	binDir, err := ioutil.TempDir("", "toolchainsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(binDir)
+	ctx := WithExecutor(context.Background(), &wrapperExecutor{})
+	manager := NewToolchainManager(binDir)
+
+	results, err := manager.RunMatrix(ctx, []string{"1.22.1", "1.21.3"}, func(ctx context.Context) error {
+		if err := RunGo(ctx, []string{"build", "./..."}); err != nil {
+			return err
+		}
+		if GetOptions(ctx).Executable.Version == "1.21.3" {
+			return errors.New("unsupported")
+		}
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "1 out of 2") {
+		t.Errorf("Expecting the failure of one version to be reported, got: %v", err)
+	}
+	if len(results) != 2 || results[0].Version != "1.22.1" || results[0].Err != nil || results[1].Version != "1.21.3" || results[1].Err == nil {
+		t.Fatalf("Unexpected results: %+v", results)
+	}
+	for _, result := range results {
+		if len(result.Results) != 1 || result.Results[0].Cmd[1] != "build" {
+			t.Errorf("Expecting the go commands of the flow to be collected, got: %+v", result.Results)
+		}
+	}
+	installed := manager.Installed()
+	if len(installed) != 2 || installed[0].Version != "1.21.3" || installed[1].Version != "1.22.1" {
+		t.Errorf("Expecting the installed versions to be sorted, got: %+v", installed)
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+)
+
+// Attributes set on the spans of the go commands.
+const (
+	CommandAttribute      = "go.command"
+	DirAttribute          = "go.dir"
+	ExitCodeAttribute     = "go.exit_code"
+	ModulesCountAttribute = "go.modules.count"
+)
+
+// Starts the spans traced around the go commands and the operations running them.
+// Implement it over a tracing library, such as OpenTelemetry, to show the go commands in distributed traces.
+type Tracer interface {
+	// Starts a span which is a child of the span carried by ctx, if any, and returns a context carrying the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// A span started by a Tracer.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	// Ends the span. err is the error the traced operation failed with, or nil if it succeeded.
+	End(err error)
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands are traced by the tracer.
+func WithTracer(ctx context.Context, tracer Tracer) context.Context {
+	options := *GetOptions(ctx)
+	options.Tracer = tracer
+	return WithOptions(ctx, &options)
+}
+
+// Starts a span with the tracer of the options carried by ctx. If there's no tracer, returns ctx and a span doing nothing.
+func startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if tracer := GetOptions(ctx).Tracer; tracer != nil && ctx != nil {
+		return tracer.Start(ctx, name)
+	}
+	return ctx, noopSpan{}
+}
+
+// Starts the span of a go command, named after the command without its arguments, such as "go mod graph".
+func startCmdSpan(goCmd *Cmd) (context.Context, Span) {
+	ctx, span := startSpan(goCmd.Context, getCmdName(goCmd))
+	span.SetAttribute(CommandAttribute, strings.Join(append(append([]string{"go"}, goCmd.Command...), goCmd.CommandFlags...), " "))
+	if goCmd.Dir != "" {
+		span.SetAttribute(DirAttribute, goCmd.Dir)
+	}
+	return ctx, span
+}
+
+// Returns the go command without its arguments, such as "go build" or "go mod graph".
+func getCmdName(goCmd *Cmd) string {
+	name := "go"
+	if len(goCmd.Command) > 0 {
+		name += " " + goCmd.Command[0]
+		if goCmd.Command[0] == "mod" && len(goCmd.Command) > 1 {
+			name += " " + goCmd.Command[1]
+		}
+	}
+	return name
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+
+func (noopSpan) End(err error) {}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type recordedSpan struct {
+	name       string
+	parent     string
+	attributes map[string]interface{}
+	err        error
+}
+
+type spanKey struct{}
+
+type recordingTracer struct {
+	mutex sync.Mutex
+	spans []*recordedSpan
+}
+
+func (tracer *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordedSpan{name: name, attributes: map[string]interface{}{}}
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		span.parent = parent.name
+	}
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+	tracer.spans = append(tracer.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (span *recordedSpan) SetAttribute(key string, value interface{}) {
+	span.attributes[key] = value
+}
+
+func (span *recordedSpan) End(err error) {
+	span.err = err
+}
+
+func TestTracer(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: "example.com/test github.com/pkg/errors@v0.8.1\nexample.com/test golang.org/x/text@v0.3.0\n"}, "mod", "graph")
+	tracer := &recordingTracer{}
+	ctx := WithOptions(context.Background(), &Options{Executor: executor, Dir: "testdata/project"})
+	ctx = WithTracer(ctx, tracer)
+
+	dependencies, err := GetDependencies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dependencies) != 2 {
+		t.Errorf("Expected 2 dependencies, Got: %v", dependencies)
+	}
+	expected := []*recordedSpan{
+		{name: "gocmd.GetDependencies", attributes: map[string]interface{}{ModulesCountAttribute: 2}},
+		{name: "go mod graph", parent: "gocmd.GetDependencies", attributes: map[string]interface{}{CommandAttribute: "go mod graph", DirAttribute: "testdata/project", ExitCodeAttribute: 0}},
+	}
+	if !reflect.DeepEqual(tracer.spans, expected) {
+		for _, span := range tracer.spans {
+			t.Errorf("Unexpected span: %+v", span)
+		}
+	}
+}
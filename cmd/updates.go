@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/semver"
+	"strconv"
+	"strings"
+)
+
+// The kind of version bump of a module update.
+type UpdateType int
+
+const (
+	PatchUpdate UpdateType = iota
+	MinorUpdate
+	MajorUpdate
+)
+
+func (updateType UpdateType) String() string {
+	switch updateType {
+	case MinorUpdate:
+		return "minor"
+	case MajorUpdate:
+		return "major"
+	}
+	return "patch"
+}
+
+// A dependency with a newer version available.
+type ModuleUpdate struct {
+	Path     string
+	Version  string
+	Update   string
+	Type     UpdateType
+	Indirect bool
+	// The module path of the update, if it differs from Path, as for github.com/user/repo/v2 updating
+	// github.com/user/repo.
+	UpdatePath string
+}
+
+func (update ModuleUpdate) String() string {
+	if update.UpdatePath != "" {
+		return update.Path + "@" + update.Version + " -> " + update.UpdatePath + "@" + update.Update
+	}
+	return update.Path + "@" + update.Version + " -> " + update.Update
+}
+
+// Returns the module@version argument of go get applying the update.
+func (update ModuleUpdate) getQuery() string {
+	if update.UpdatePath != "" {
+		return update.UpdatePath + "@" + update.Update
+	}
+	return update.Path + "@" + update.Update
+}
+
+// Runs go list -m -u -json all and returns the dependencies of the main module that have newer versions.
+// The go command reports updates within the same module path only, so the path of the next major version of each
+// dependency, such as github.com/user/repo/v3 for github.com/user/repo/v2, is also queried with
+// go list -m -e -json <path>@latest, and reported as a major update if found.
+// The go.mod and go.sum files are left unchanged.
+func CheckUpdates(ctx context.Context) ([]ModuleUpdate, error) {
+	output, err := runWithUnchangedModFiles(ctx, false, "list", "-m", "-u", "-json", "all")
+	if err != nil {
+		return nil, err
+	}
+	modules, err := parseListedModules(strings.NewReader(output))
+	if err != nil {
+		return nil, err
+	}
+	updates := getModuleUpdates(modules)
+	majorUpdates, err := getMajorUpdates(ctx, modules)
+	if err != nil {
+		return nil, err
+	}
+	return append(updates, majorUpdates...), nil
+}
+
+// Queries the latest version of the next major version path of each dependency, and returns the ones found.
+func getMajorUpdates(ctx context.Context, modules []ListedModule) ([]ModuleUpdate, error) {
+	args := []string{"list", "-m", "-e", "-json"}
+	dependencies := map[string]ListedModule{}
+	for _, module := range modules {
+		if module.Main || module.Version == "" {
+			continue
+		}
+		if nextPath := getNextMajorPath(module.Path, module.Version); nextPath != "" {
+			dependencies[nextPath] = module
+			args = append(args, nextPath+"@latest")
+		}
+	}
+	if len(dependencies) == 0 {
+		return nil, nil
+	}
+	output, err := runWithUnchangedModFiles(ctx, false, args...)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := parseListedModules(strings.NewReader(output))
+	if err != nil {
+		return nil, err
+	}
+	var updates []ModuleUpdate
+	for _, next := range latest {
+		module, ok := dependencies[next.Path]
+		// Paths without any version are reported with an error.
+		if !ok || next.Error != nil || next.Version == "" {
+			continue
+		}
+		updates = append(updates, ModuleUpdate{
+			Path:       module.Path,
+			Version:    module.Version,
+			Update:     next.Version,
+			Type:       MajorUpdate,
+			Indirect:   module.Indirect,
+			UpdatePath: next.Path,
+		})
+	}
+	return updates, nil
+}
+
+// Returns the path of the next major version of the module at the version, such as github.com/user/repo/v2 for
+// github.com/user/repo at v1.2.3 or v0.1.0, github.com/user/repo/v4 for github.com/user/repo at v3.0.0+incompatible,
+// or gopkg.in/yaml.v3 for gopkg.in/yaml.v2. Returns an empty string if the path is invalid.
+func getNextMajorPath(modulePath, version string) string {
+	prefix, pathMajor, ok := semver.SplitPathMajor(modulePath)
+	major, err := strconv.Atoi(strings.TrimPrefix(semver.Major(version), "v"))
+	if !ok || err != nil {
+		return ""
+	}
+	if major < 1 {
+		major = 1
+	}
+	if strings.HasPrefix(pathMajor, ".v") || strings.HasPrefix(modulePath, "gopkg.in/") {
+		return prefix + ".v" + strconv.Itoa(major+1)
+	}
+	return prefix + "/v" + strconv.Itoa(major+1)
+}
+
+func getModuleUpdates(modules []ListedModule) []ModuleUpdate {
+	var updates []ModuleUpdate
+	for _, module := range modules {
+		if module.Main || module.Update == nil {
+			continue
+		}
+		updates = append(updates, ModuleUpdate{
+			Path:     module.Path,
+			Version:  module.Version,
+			Update:   module.Update.Version,
+			Type:     getUpdateType(module.Version, module.Update.Version),
+			Indirect: module.Indirect,
+		})
+	}
+	return updates
+}
+
+func getUpdateType(version, update string) UpdateType {
+	switch {
+	case semver.Major(version) != semver.Major(update):
+		return MajorUpdate
+	case semver.MajorMinor(version) != semver.MajorMinor(update):
+		return MinorUpdate
+	}
+	return PatchUpdate
+}
@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const listUpdatesOutput = `{
+	"Path": "github.com/you/hello",
+	"Main": true
+}
+{
+	"Path": "rsc.io/quote",
+	"Version": "v1.5.2",
+	"Update": {"Path": "rsc.io/quote", "Version": "v1.5.3"}
+}
+{
+	"Path": "golang.org/x/text",
+	"Version": "v0.0.0-20170915032832-14c0d48ead0c",
+	"Indirect": true,
+	"Update": {"Path": "golang.org/x/text", "Version": "v0.3.7"}
+}
+{
+	"Path": "github.com/mholt/archiver",
+	"Version": "v2.1.0+incompatible",
+	"Update": {"Path": "github.com/mholt/archiver", "Version": "v3.1.1+incompatible"}
+}
+{
+	"Path": "rsc.io/sampler",
+	"Version": "v1.3.0"
+}
+`
+
+func TestGetModuleUpdates(t *testing.T) {
+	modules, err := parseListedModules(strings.NewReader(listUpdatesOutput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ModuleUpdate{
+		{"rsc.io/quote", "v1.5.2", "v1.5.3", PatchUpdate, false, ""},
+		{"golang.org/x/text", "v0.0.0-20170915032832-14c0d48ead0c", "v0.3.7", MinorUpdate, true, ""},
+		{"github.com/mholt/archiver", "v2.1.0+incompatible", "v3.1.1+incompatible", MajorUpdate, false, ""},
+	}
+	if actual := getModuleUpdates(modules); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
+
+func TestGetNextMajorPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		version  string
+		expected string
+	}{
+		{"rsc.io/quote", "v1.5.2", "rsc.io/quote/v2"},
+		{"golang.org/x/text", "v0.3.7", "golang.org/x/text/v2"},
+		{"github.com/mholt/archiver", "v3.1.1+incompatible", "github.com/mholt/archiver/v4"},
+		{"github.com/user/repo/v2", "v2.0.1", "github.com/user/repo/v3"},
+		{"gopkg.in/yaml.v2", "v2.4.0", "gopkg.in/yaml.v3"},
+		{"github.com/user/repo/v1", "v1.0.0", ""},
+		{"rsc.io/quote", "latest", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			if actual := getNextMajorPath(test.path, test.version); actual != test.expected {
+				t.Errorf("Expected: %s, Got: %s", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCheckUpdates(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "updatesTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	if err = ioutil.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module github.com/you/hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: listUpdatesOutput}, "list", "-m", "-u", "-json", "all")
+	latestOutput := `{
+	"Path": "rsc.io/quote/v2",
+	"Error": {"Err": "module rsc.io/quote/v2: no matching versions for query latest"}
+}
+{
+	"Path": "golang.org/x/text/v2",
+	"Error": {"Err": "module golang.org/x/text/v2: not found"}
+}
+{
+	"Path": "github.com/mholt/archiver/v3",
+	"Version": "v3.3.1"
+}
+{
+	"Path": "rsc.io/sampler/v2",
+	"Error": {"Err": "module rsc.io/sampler/v2: not found"}
+}
+`
+	executor.On(ExecutorResult{Stdout: latestOutput}, "list", "-m", "-e", "-json", "rsc.io/quote/v2@latest", "golang.org/x/text/v2@latest",
+		"github.com/mholt/archiver/v3@latest", "rsc.io/sampler/v2@latest")
+	ctx := WithOptions(context.Background(), &Options{Executor: executor, Dir: projectDir})
+
+	updates, err := CheckUpdates(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 4 {
+		t.Fatalf("Expected 4 updates, Got: %v", updates)
+	}
+	expected := ModuleUpdate{"github.com/mholt/archiver", "v2.1.0+incompatible", "v3.3.1", MajorUpdate, false, "github.com/mholt/archiver/v3"}
+	if !reflect.DeepEqual(expected, updates[3]) {
+		t.Errorf("Expected: %v, Got: %v", expected, updates[3])
+	}
+}
+
+func TestCheckUpdatesReadonly(t *testing.T) {
+	projectDir, ctx := createFileProxyProject(t)
+	defer os.RemoveAll(filepath.Dir(projectDir))
+	err := RunInSandbox(ctx, func(ctx context.Context) error {
+		updates, err := CheckUpdates(ctx)
+		if err != nil {
+			return err
+		}
+		if len(updates) != 1 || updates[0].Path != "example.com/dep" || updates[0].Version != "v1.0.0" || updates[0].Update != "v1.1.0" {
+			t.Errorf("Expected the update of example.com/dep from v1.0.0 to v1.1.0, Got: %+v", updates)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/semver"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"path/filepath"
+	"strings"
+)
+
+// The result of applying a single module update by ApplyUpdates.
+type UpdateResult struct {
+	Update ModuleUpdate
+	// The reason the update was rolled back, or nil if it was applied.
+	Err error
+}
+
+// Returns true if the update was applied.
+func (result *UpdateResult) IsApplied() bool {
+	return result.Err == nil
+}
+
+// Applies the updates one by one: runs go get module@version, with the UpdatePath of major updates as the module, go mod tidy, go mod verify and go build ./...
+// An update whose version go.mod no longer requires after go mod tidy fails, such as a major update of a module whose
+// imports weren't rewritten to its new major version path, which tidy then removes.
+// If any of them fails, the go.mod and go.sum files are rolled back to their state before the update and the next
+// update is applied. Returns the result of each update. An error is returned only if the files could not be backed up
+// or restored, in which case the remaining updates are not applied.
+func ApplyUpdates(ctx context.Context, updates []ModuleUpdate) ([]UpdateResult, error) {
+	projectDir, err := getProjectRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var results []UpdateResult
+	for _, update := range updates {
+		if err = ctx.Err(); err != nil {
+			return results, errorutils.CheckError(err)
+		}
+		result, err := applyUpdate(ctx, projectDir, update)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+func applyUpdate(ctx context.Context, projectDir string, update ModuleUpdate) (result *UpdateResult, err error) {
+	backups, err := NewFileBackupManagerWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer backups.RecoverAndRollback()
+	for _, file := range []string{"go.mod", "go.sum"} {
+		if err = backups.Backup(filepath.Join(projectDir, file)); err != nil {
+			backups.Rollback()
+			return nil, err
+		}
+	}
+
+	getLogger(ctx).Info("Updating", update.Path, "from", update.Version, "to", update.getQuery())
+	result = &UpdateResult{Update: update, Err: runUpdate(ctx, projectDir, update)}
+	if result.Err == nil {
+		return result, backups.Commit()
+	}
+	getLogger(ctx).Info("Rolling back the update of", update.Path+":", result.Err.Error())
+	return result, backups.Rollback()
+}
+
+func runUpdate(ctx context.Context, projectDir string, update ModuleUpdate) error {
+	if err := RunGo(ctx, []string{"get", update.getQuery()}); err != nil {
+		return err
+	}
+	if _, err := RunGoModTidy(ctx); err != nil {
+		return err
+	}
+	if err := checkUpdateRequired(ctx, projectDir, update); err != nil {
+		return err
+	}
+	failures, err := VerifyModules(ctx)
+	if err != nil {
+		return err
+	}
+	if len(failures) > 0 {
+		return errorutils.CheckError(fmt.Errorf("go mod verify failed for %d modules, such as %s: %s", len(failures), failures[0].Module+"@"+failures[0].Version, failures[0].Reason))
+	}
+	return RunGo(ctx, []string{"build", "./..."})
+}
+
+// Returns an error if go.mod doesn't require the version of the update, or a higher one, after go mod tidy, which
+// removes the requirements of the modules which aren't imported.
+func checkUpdateRequired(ctx context.Context, projectDir string, update ModuleUpdate) error {
+	content, err := readFileIfExists(GetFileSystem(ctx), filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return err
+	}
+	requirements, err := getRequirements(content)
+	if err != nil {
+		return err
+	}
+	path := update.Path
+	if update.UpdatePath != "" {
+		path = update.UpdatePath
+	}
+	for _, requirement := range requirements {
+		nameAndVersion := strings.SplitN(requirement, "@", 2)
+		if nameAndVersion[0] == path && semver.Compare(nameAndVersion[1], update.Update) >= 0 {
+			return nil
+		}
+	}
+	if update.Type == MajorUpdate {
+		return errorutils.CheckError(fmt.Errorf("go mod tidy removed the requirement of %s, which no package imports. Rewrite the imports of %s to %s before applying the major update.", path, update.Path, path))
+	}
+	return errorutils.CheckError(fmt.Errorf("go.mod doesn't require %s after the update.", update.getQuery()))
+}
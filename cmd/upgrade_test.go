@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestApplyUpdates(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "applyUpdatesTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	modContent := "module example.com/test\n\ngo 1.12\n\nrequire example.com/dep v1.0.0\n\nreplace example.com/dep => ./dep\n"
+	files := map[string]string{
+		"go.mod":     modContent,
+		"main.go":    "package main\n\nimport _ \"example.com/dep\"\n\nfunc main() {}\n",
+		"dep/go.mod": "module example.com/dep\n",
+		"dep/dep.go": "package dep\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(projectDir, filepath.FromSlash(path))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	if err = os.Chdir(projectDir); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithEnv(WithEnv(context.Background(), "GOPROXY", "off"), "GOFLAGS", "-mod=mod")
+	updates := []ModuleUpdate{
+		{Path: "example.com/missing", Version: "v1.0.0", Update: "v1.1.0"},
+		{Path: "example.com/dep", Version: "v1.0.0", Update: "v1.1.0"},
+	}
+	results, err := ApplyUpdates(ctx, updates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 || results[0].IsApplied() || !results[1].IsApplied() {
+		t.Fatalf("Expected only the second update to be applied, Got: %v", results)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "module example.com/test\n\ngo 1.12\n\nrequire example.com/dep v1.1.0\n\nreplace example.com/dep => ./dep\n"
+	if string(content) != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, content)
+	}
+}
+
+func TestApplyMajorUpdate(t *testing.T) {
+	projectDir, err := ioutil.TempDir("", "applyUpdatesTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(projectDir)
+	if err = ioutil.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module example.com/test\n\nrequire github.com/x/y v1.3.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	executor := NewFakeExecutor()
+	ctx := WithOptions(context.Background(), &Options{Executor: executor, Dir: projectDir})
+	updates := []ModuleUpdate{{Path: "github.com/x/y", Version: "v1.3.0", Update: "v2.1.0", Type: MajorUpdate, UpdatePath: "github.com/x/y/v2"}}
+	results, err := ApplyUpdates(ctx, updates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The fake go get doesn't add the requirement of the new major version path.
+	if len(results) != 1 || results[0].IsApplied() || !strings.Contains(results[0].Err.Error(), "Rewrite the imports of github.com/x/y to github.com/x/y/v2") {
+		t.Fatalf("Expected the update to be rolled back, Got: %v", results)
+	}
+	expected := []string{"get github.com/x/y/v2@v2.1.0"}
+	if actual := []string{strings.Join(executor.Calls()[0].Cmd[1:], " ")}; !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
+
+func TestApplyMajorUpdateImports(t *testing.T) {
+	modContent := "module example.com/test\n\ngo 1.12\n\nrequire example.com/dep v1.0.0\n\nreplace example.com/dep => ./dep\n\nreplace example.com/dep/v2 => ./dep2\n"
+	tests := []struct {
+		name     string
+		imported string
+		applied  bool
+	}{
+		{"rewritten", "example.com/dep/v2", true},
+		{"notRewritten", "example.com/dep", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			projectDir, err := ioutil.TempDir("", "applyUpdatesTest")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(projectDir)
+			files := map[string]string{
+				"go.mod":      modContent,
+				"main.go":     "package main\n\nimport _ \"" + test.imported + "\"\n\nfunc main() {}\n",
+				"dep/go.mod":  "module example.com/dep\n",
+				"dep/dep.go":  "package dep\n",
+				"dep2/go.mod": "module example.com/dep/v2\n",
+				"dep2/dep.go": "package dep\n",
+			}
+			for path, content := range files {
+				path = filepath.Join(projectDir, filepath.FromSlash(path))
+				if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ctx := WithOptions(context.Background(), &Options{Dir: projectDir, Env: map[string]string{"GOPROXY": "off", "GOFLAGS": "-mod=mod"}})
+			updates := []ModuleUpdate{{Path: "example.com/dep", Version: "v1.0.0", Update: "v2.0.0", Type: MajorUpdate, UpdatePath: "example.com/dep/v2"}}
+			results, err := ApplyUpdates(ctx, updates)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 || results[0].IsApplied() != test.applied {
+				t.Fatalf("Test name: %s: Expected applied: %t, Got: %v", test.name, test.applied, results)
+			}
+			content, err := ioutil.ReadFile(filepath.Join(projectDir, "go.mod"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if requiresV2 := strings.Contains(string(content), "example.com/dep/v2 v2.0.0"); requiresV2 != test.applied {
+				t.Errorf("Test name: %s: Expected go.mod to require example.com/dep/v2: %t, Got:\n%s", test.name, test.applied, content)
+			}
+		})
+	}
+}
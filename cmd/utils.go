@@ -1,71 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/jfrog/gocmd/fsys"
 	gofrogio "github.com/jfrog/gofrog/io"
 	"github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
-	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
-	"github.com/jfrog/jfrog-client-go/utils/log"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
-func prepareRegExp() error {
-	err := prepareGlobalRegExp()
-	if err != nil {
-		return err
-	}
-	return prepareNotFoundZipRegExp()
-}
-
-// Compiles all the regex once
-func prepareGlobalRegExp() error {
-	var err error
-	if protocolRegExp == nil {
-		log.Debug("Initializing protocol regexp")
-		protocolRegExp, err = initRegExp(utils.CredentialsInUrlRegexp, MaskCredentials)
-		if err != nil {
-			return err
-		}
-	}
-
-	if notFoundRegExp == nil {
-		log.Debug("Initializing not found regexp")
-		notFoundRegExp, err = initRegExp(`^go: ([^\/\r\n]+\/[^\r\n\s:]*).*(404( Not Found)?[\s]?)$`, Error)
-		if err != nil {
-			return err
-		}
-	}
-
-	if unrecognizedImportRegExp == nil {
-		log.Debug("Initializing unrecognized import path regexp")
-		unrecognizedImportRegExp, err = initRegExp(`[^go:]([^\/\r\n]+\/[^\r\n\s:]*).*(unrecognized import path)`, Error)
-		if err != nil {
-			return err
-		}
-	}
-
-	if unknownRevisionRegExp == nil {
-		log.Debug("Initializing unknown revision regexp")
-		unknownRevisionRegExp, err = initRegExp(`[^go:]([^\/\r\n]+\/[^\r\n\s:]*).*(unknown revision)`, Error)
-	}
-
-	return err
-}
-
-func prepareNotFoundZipRegExp() error {
-	var err error
-	if notFoundZipRegExp == nil {
-		log.Debug("Initializing not found zip file")
-		notFoundZipRegExp, err = initRegExp(`unknown import path ["]([^\/\r\n]+\/[^\r\n\s:]*)["].*(404( Not Found)?[\s]?)$`, Error)
-	}
-	return err
-}
-
 func initRegExp(regex string, execFunc func(pattern *gofrogio.CmdOutputPattern) (string, error)) (*gofrogio.CmdOutputPattern, error) {
 	regExp, err := utils.GetRegExp(regex)
 	if err != nil {
@@ -80,46 +27,72 @@ func initRegExp(regex string, execFunc func(pattern *gofrogio.CmdOutputPattern)
 	return outputPattern, nil
 }
 
-// Mask the credentials information from the line.
+// Mask the credentials information from the line, and the other secrets masked by MaskSecrets.
 func MaskCredentials(pattern *gofrogio.CmdOutputPattern) (string, error) {
-	return utils.MaskCredentials(pattern.Line, pattern.MatchedResults[0]), nil
+	return MaskSecrets(utils.MaskCredentials(pattern.Line, pattern.MatchedResults[0])), nil
 }
 
+// Handles a pattern as a failure of the command, whose message is taken from the groups of the pattern.
+// The matched line is written to the stderr writer of the command's options, as with the other error handlers.
 func Error(pattern *gofrogio.CmdOutputPattern) (string, error) {
-	_, err := fmt.Fprint(os.Stderr, pattern.Line)
-	if err != nil {
-		return "", errorutils.CheckError(err)
-	}
 	if len(pattern.MatchedResults) >= 3 {
 		return "", errors.New(pattern.MatchedResults[2] + ":" + strings.TrimSpace(pattern.MatchedResults[1]))
 	}
 	return "", errors.New(fmt.Sprintf("Regex found the following values: %s", pattern.MatchedResults))
 }
 
+// When the go process was killed because ctx was cancelled or timed out, returns the context's error
+// instead of the less descriptive process error.
+func contextError(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
 func GetSumContentAndRemove(rootProjectDir string) (sumFileContent []byte, sumFileStat os.FileInfo, err error) {
-	sumFileExists, err := fileutils.IsFileExists(filepath.Join(rootProjectDir, "go.sum"), false)
+	return GetSumContentAndRemoveWithContext(context.Background(), rootProjectDir)
+}
+
+// Returns the content and the stat of the go.sum file in rootProjectDir, and removes it, through the FileSystem of ctx.
+// The removal is reported to the EventListeners of ctx. If the file doesn't exist, the content and the stat are nil.
+func GetSumContentAndRemoveWithContext(ctx context.Context, rootProjectDir string) (sumFileContent []byte, sumFileStat os.FileInfo, err error) {
+	files := GetFileSystem(ctx)
+	sumFileExists, err := fsys.IsFileExists(files, filepath.Join(rootProjectDir, "go.sum"))
 	if err != nil {
 		return
 	}
 	if sumFileExists {
-		log.Debug("Sum file exists:", rootProjectDir)
-		sumFileContent, sumFileStat, err = GetFileDetails(filepath.Join(rootProjectDir, "go.sum"))
+		getLogger(ctx).Debug("Sum file exists:", rootProjectDir)
+		sumFileContent, sumFileStat, err = getFileDetails(files, filepath.Join(rootProjectDir, "go.sum"))
 		if err != nil {
 			return
 		}
-		log.Debug("Removing file:", filepath.Join(rootProjectDir, "go.sum"))
-		err = os.Remove(filepath.Join(rootProjectDir, "go.sum"))
+		if SkipInDryRunWithContext(ctx, "Removing file: "+filepath.Join(rootProjectDir, "go.sum")) {
+			return
+		}
+		getLogger(ctx).Debug("Removing file:", filepath.Join(rootProjectDir, "go.sum"))
+		err = files.Remove(filepath.Join(rootProjectDir, "go.sum"))
 		if err != nil {
 			return
 		}
+		EmitEvent(ctx, Event{Type: FileRemovedEvent, Path: filepath.Join(rootProjectDir, "go.sum")})
 		return
 	}
 	return
 }
 
 func RestoreSumFile(rootProjectDir string, sumFileContent []byte, sumFileStat os.FileInfo) error {
-	log.Debug("Restoring file:", filepath.Join(rootProjectDir, "go.sum"))
-	err := ioutil.WriteFile(filepath.Join(rootProjectDir, "go.sum"), sumFileContent, sumFileStat.Mode())
+	return RestoreSumFileWithContext(context.Background(), rootProjectDir, sumFileContent, sumFileStat)
+}
+
+// Writes back the go.sum file removed by GetSumContentAndRemoveWithContext, to the FileSystem of ctx.
+func RestoreSumFileWithContext(ctx context.Context, rootProjectDir string, sumFileContent []byte, sumFileStat os.FileInfo) error {
+	if SkipInDryRunWithContext(ctx, "Restoring file: "+filepath.Join(rootProjectDir, "go.sum")) {
+		return nil
+	}
+	getLogger(ctx).Debug("Restoring file:", filepath.Join(rootProjectDir, "go.sum"))
+	err := GetFileSystem(ctx).WriteFile(filepath.Join(rootProjectDir, "go.sum"), sumFileContent, sumFileStat.Mode())
 	if err != nil {
 		return err
 	}
@@ -127,12 +100,15 @@ func RestoreSumFile(rootProjectDir string, sumFileContent []byte, sumFileStat os
 }
 
 func GetFileDetails(filePath string) (modFileContent []byte, modFileStat os.FileInfo, err error) {
-	modFileStat, err = os.Stat(filePath)
+	return getFileDetails(fsys.OsFileSystem{}, filePath)
+}
+
+func getFileDetails(files fsys.FileSystem, filePath string) (modFileContent []byte, modFileStat os.FileInfo, err error) {
+	modFileStat, err = files.Stat(filePath)
 	if errorutils.CheckError(err) != nil {
 		return
 	}
-	modFileContent, err = ioutil.ReadFile(filePath)
-	errorutils.CheckError(err)
+	modFileContent, err = fsys.ReadFile(files, filePath)
 	return
 }
 
@@ -141,7 +117,8 @@ func outputToMap(output string) map[string]bool {
 	var result []string
 	mapOfDeps := map[string]bool{}
 	for _, line := range lineOutput {
-		splitLine := strings.Split(line, " ")
+		// Trim the carriage returns of Windows line endings.
+		splitLine := strings.Split(strings.TrimSpace(line), " ")
 		if len(splitLine) == 2 {
 			mapOfDeps[splitLine[1]] = true
 			result = append(result, splitLine[1])
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strings"
+)
+
+// The version control systems GOVCS may allow, and the special values allowing all of them or none.
+var govcsNames = map[string]bool{"git": true, "hg": true, "svn": true, "bzr": true, "fossil": true, "all": true, "off": true}
+
+// Controls whether the go commands fall back to fetching modules directly from their VCS, as the "direct" entry
+// of GOPROXY does.
+type DirectFallback int
+
+const (
+	// Keeps GOPROXY as configured.
+	DefaultDirectFallback DirectFallback = iota
+	// Removes "direct" from GOPROXY, so that modules are fetched only through the proxies, and sets GOVCS to "*:off",
+	// so that the modules matching GONOPROXY or GOPRIVATE, which bypass the proxies, aren't fetched from their VCS either.
+	ForbidDirectFallback
+	// Adds "direct" to the end of GOPROXY, so that modules missing from the proxies are fetched from their VCS.
+	ForceDirectFallback
+)
+
+// A GOVCS rule, allowing the version control systems for the modules matching the pattern.
+type GovcsRule struct {
+	// A glob matching a prefix of the module paths, such as "github.com" or "*.corp.example.com",
+	// or "public" or "private" to match the modules by GOPRIVATE.
+	Pattern string
+	// Such as "git" and "hg", or "all" or "off".
+	Vcs []string
+}
+
+func (rule GovcsRule) String() string {
+	return rule.Pattern + ":" + strings.Join(rule.Vcs, "|")
+}
+
+// Describes how the go commands fetch modules from their version control systems.
+type VcsOptions struct {
+	// Applied as GOVCS. Rules are matched in order, and the go command defaults apply to modules matching none.
+	Govcs          []GovcsRule
+	DirectFallback DirectFallback
+}
+
+// The GOVCS of ForbidDirectFallback, disallowing all the version control systems for all the modules.
+const forbiddenGovcs = "*:off"
+
+// Validates the rules. Rules allowing version control systems can't be combined with ForbidDirectFallback.
+func (options *VcsOptions) Validate() error {
+	for _, rule := range options.Govcs {
+		if options.DirectFallback == ForbidDirectFallback && !(len(rule.Vcs) == 1 && rule.Vcs[0] == "off") {
+			return errorutils.CheckError(fmt.Errorf("The GOVCS rule of %s allows version control systems, while direct VCS fetches are forbidden.", rule.Pattern))
+		}
+		if rule.Pattern == "" || strings.ContainsAny(rule.Pattern, ",: \t") {
+			return errorutils.CheckError(fmt.Errorf("Invalid GOVCS pattern %q.", rule.Pattern))
+		}
+		if rule.Pattern != "public" && rule.Pattern != "private" {
+			if err := validatePattern(rule.Pattern); err != nil {
+				return err
+			}
+		}
+		if len(rule.Vcs) == 0 {
+			return errorutils.CheckError(fmt.Errorf("The GOVCS rule of %s allows no version control system.", rule.Pattern))
+		}
+		for _, vcs := range rule.Vcs {
+			if !govcsNames[vcs] {
+				return errorutils.CheckError(fmt.Errorf("Unknown version control system %q in the GOVCS rule of %s.", vcs, rule.Pattern))
+			}
+		}
+	}
+	return nil
+}
+
+// Returns a copy of ctx carrying the options of ctx, with GOVCS and GOPROXY set by the VCS options.
+// ForbidDirectFallback sets GOVCS to "*:off", replacing the rules of the options, which may only disallow.
+// Changing the direct fallback runs go env to read the GOPROXY of ctx.
+func WithVcs(ctx context.Context, vcsOptions VcsOptions) (context.Context, error) {
+	if err := vcsOptions.Validate(); err != nil {
+		return nil, err
+	}
+	if len(vcsOptions.Govcs) > 0 {
+		var rules []string
+		for _, rule := range vcsOptions.Govcs {
+			rules = append(rules, rule.String())
+		}
+		ctx = WithEnv(ctx, "GOVCS", strings.Join(rules, ","))
+	}
+	if vcsOptions.DirectFallback == DefaultDirectFallback {
+		return ctx, nil
+	}
+	if vcsOptions.DirectFallback == ForbidDirectFallback {
+		ctx = WithEnv(ctx, "GOVCS", forbiddenGovcs)
+	}
+	env, err := GetGoEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = WithEnv(ctx, "GOPROXY", withDirectFallback(env.GOPROXY, vcsOptions.DirectFallback))
+	options := *GetOptions(ctx)
+	options.DirectFallback = vcsOptions.DirectFallback
+	return WithOptions(ctx, &options), nil
+}
+
+// Returns the GOPROXY list with "direct" removed or added to its end. The entries of the list are separated by commas,
+// falling back to the next entry on 404 and 410 errors, or by pipes, falling back on any error.
+// A list with no entries left is "off".
+func withDirectFallback(goproxy string, fallback DirectFallback) string {
+	var entries, separators []string
+	start := 0
+	for i, char := range goproxy {
+		if char == ',' || char == '|' {
+			entries, separators = append(entries, goproxy[start:i]), append(separators, string(char))
+			start = i + 1
+		}
+	}
+	entries = append(entries, goproxy[start:])
+
+	var result strings.Builder
+	hasDirect := false
+	for i, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || entry == "off" || (entry == "direct" && fallback == ForbidDirectFallback) {
+			continue
+		}
+		hasDirect = hasDirect || entry == "direct"
+		if result.Len() > 0 {
+			result.WriteString(separators[i-1])
+		}
+		result.WriteString(entry)
+	}
+	if fallback == ForceDirectFallback && !hasDirect {
+		if result.Len() > 0 {
+			result.WriteString(",")
+		}
+		result.WriteString("direct")
+	}
+	if result.Len() == 0 {
+		return "off"
+	}
+	return result.String()
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVcsOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []GovcsRule
+		isValid bool
+	}{
+		{"valid", []GovcsRule{{"github.com", []string{"git"}}, {"private", []string{"git", "hg"}}, {"public", []string{"off"}}}, true},
+		{"glob", []GovcsRule{{"*.corp.example.com", []string{"all"}}}, true},
+		{"emptyPattern", []GovcsRule{{"", []string{"git"}}}, false},
+		{"patternWithColon", []GovcsRule{{"github.com:git", []string{"git"}}}, false},
+		{"noVcs", []GovcsRule{{"github.com", nil}}, false},
+		{"unknownVcs", []GovcsRule{{"github.com", []string{"cvs"}}}, false},
+	}
+	for _, test := range tests {
+		options := VcsOptions{Govcs: test.rules}
+		if err := options.Validate(); (err == nil) != test.isValid {
+			t.Errorf("Test name: %s: Expected valid: %t, Got: %v", test.name, test.isValid, err)
+		}
+	}
+}
+
+func TestWithDirectFallback(t *testing.T) {
+	tests := []struct {
+		goproxy  string
+		fallback DirectFallback
+		expected string
+	}{
+		{"https://proxy.golang.org,direct", ForbidDirectFallback, "https://proxy.golang.org"},
+		{"https://proxy.mycorp.com|direct", ForbidDirectFallback, "https://proxy.mycorp.com"},
+		{"direct", ForbidDirectFallback, "off"},
+		{"https://a.mycorp.com|https://b.mycorp.com,direct", ForbidDirectFallback, "https://a.mycorp.com|https://b.mycorp.com"},
+		{"https://proxy.mycorp.com", ForceDirectFallback, "https://proxy.mycorp.com,direct"},
+		{"https://proxy.mycorp.com,off", ForceDirectFallback, "https://proxy.mycorp.com,direct"},
+		{"https://proxy.golang.org,direct", ForceDirectFallback, "https://proxy.golang.org,direct"},
+		{"off", ForceDirectFallback, "direct"},
+	}
+	for _, test := range tests {
+		if actual := withDirectFallback(test.goproxy, test.fallback); actual != test.expected {
+			t.Errorf("Test name: %s: Expected: %s, Got: %s", test.goproxy, test.expected, actual)
+		}
+	}
+}
+
+func TestWithVcs(t *testing.T) {
+	executor := NewFakeExecutor()
+	executor.On(ExecutorResult{Stdout: `{"GOPROXY": "https://proxy.mycorp.com,direct"}`}, "env", "-json")
+	ctx := WithExecutor(context.Background(), executor)
+	govcsCtx, err := WithVcs(ctx, VcsOptions{Govcs: []GovcsRule{{"github.mycorp.com", []string{"git"}}, {"*", []string{"off"}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if govcs := GetOptions(govcsCtx).Env["GOVCS"]; govcs != "github.mycorp.com:git,*:off" {
+		t.Error("Unexpected GOVCS:", govcs)
+	}
+	if _, err = WithVcs(ctx, VcsOptions{Govcs: []GovcsRule{{"github.mycorp.com", []string{"git"}}}, DirectFallback: ForbidDirectFallback}); err == nil {
+		t.Error("Expecting an error for a rule allowing git while direct fetches are forbidden")
+	}
+
+	ctx, err = WithVcs(ctx, VcsOptions{DirectFallback: ForbidDirectFallback})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := GetOptions(ctx)
+	// The modules matching GONOPROXY and GOPRIVATE must not be fetched from their VCS either.
+	if options.Env["GOVCS"] != "*:off" {
+		t.Error("Unexpected GOVCS:", options.Env["GOVCS"])
+	}
+	if options.Env["GOPROXY"] != "https://proxy.mycorp.com" {
+		t.Error("Unexpected GOPROXY:", options.Env["GOPROXY"])
+	}
+	if options.DirectFallback != ForbidDirectFallback {
+		t.Error("Expecting the direct fallback to be forbidden, got:", options.DirectFallback)
+	}
+
+	_, err = RemediateChecksumMismatch(ctx, &ChecksumMismatchError{Module: "github.com/pkg/errors@v0.8.1"}, RemediationOptions{})
+	if err == nil || !strings.Contains(err.Error(), "direct VCS fetches are forbidden") {
+		t.Error("Expecting the remediation to fail when direct fetches are forbidden, got:", err)
+	}
+}
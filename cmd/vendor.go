@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Represents a module listed in vendor/modules.txt.
+type VendorModule struct {
+	Path    string
+	Version string
+	// The module replacing this module, in the "path@version" notation, or a local path.
+	Replacement string
+	// True if the module is explicitly required by go.mod.
+	Explicit bool
+	Packages []string
+}
+
+// Represents the differences between the go.mod requirements and the vendored modules.
+type VendorReport struct {
+	// Modules required by go.mod but not vendored.
+	Missing []string
+	// Modules vendored as explicitly required but not required by go.mod.
+	Unrequired []string
+	// Modules vendored with a different version than the one required by go.mod.
+	Mismatched []string
+}
+
+// Returns true if the vendor directory matches the go.mod requirements.
+func (report *VendorReport) IsConsistent() bool {
+	return len(report.Missing) == 0 && len(report.Unrequired) == 0 && len(report.Mismatched) == 0
+}
+
+// Runs go mod vendor, to regenerate the vendor directory of the project.
+func RunGoModVendor(ctx context.Context) error {
+	projectDir, err := getProjectRoot(ctx)
+	if err != nil {
+		return err
+	}
+	if SkipInDryRunWithContext(ctx, "Running 'go mod vendor' in "+projectDir) {
+		return nil
+	}
+	getLogger(ctx).Info("Running 'go mod vendor' in", projectDir)
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return err
+	}
+	goCmd.Command = []string{"mod", "vendor"}
+	_, _, err = runCmdWithOutputParser(goCmd, true)
+	return errorutils.CheckError(contextError(ctx, err))
+}
+
+// Returns the modules listed in the vendor/modules.txt file of the project, read from the FileSystem of ctx.
+func GetVendorModules(ctx context.Context, projectDir string) ([]VendorModule, error) {
+	content, err := readFileIfExists(GetFileSystem(ctx), filepath.Join(projectDir, "vendor", "modules.txt"))
+	if err != nil {
+		return nil, err
+	}
+	if content == nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Could not find vendor/modules.txt in %s.", projectDir))
+	}
+	return parseVendorModules(content), nil
+}
+
+// Returns the vendored dependencies of the project in the "path@version" notation,
+// the same way GetDependenciesGraph does. No go command is executed, so this works without network access.
+// Modules replaced by local paths are skipped.
+func GetVendorDependencies(ctx context.Context, projectDir string) (map[string]bool, error) {
+	modules, err := GetVendorModules(ctx, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	dependencies := map[string]bool{}
+	for _, module := range modules {
+		if module.Replacement != "" {
+			if strings.Contains(module.Replacement, "@") {
+				dependencies[module.Replacement] = true
+			}
+			continue
+		}
+		if module.Version != "" {
+			dependencies[module.Path+"@"+module.Version] = true
+		}
+	}
+	return dependencies, nil
+}
+
+// Returns the vendored dependencies of the project of the working directory of ctx, after checking that they are
+// consistent with its go.mod requirements.
+func getConsistentVendorDependencies(ctx context.Context) (map[string]bool, error) {
+	projectDir, err := getProjectRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report, err := ReconcileVendor(ctx, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	if !report.IsConsistent() {
+		var details []string
+		details = append(details, report.Missing...)
+		details = append(details, report.Unrequired...)
+		details = append(details, report.Mismatched...)
+		return nil, errorutils.CheckError(fmt.Errorf("The vendor directory of %s is inconsistent with go.mod: %s. Run 'go mod vendor' to update it.", projectDir, strings.Join(details, ", ")))
+	}
+	return GetVendorDependencies(ctx, projectDir)
+}
+
+// Compares the go.mod requirements of the project with its vendored modules, reading both from the FileSystem of ctx.
+func ReconcileVendor(ctx context.Context, projectDir string) (*VendorReport, error) {
+	modules, err := GetVendorModules(ctx, projectDir)
+	if err != nil {
+		return nil, err
+	}
+	modContent, err := readFileIfExists(GetFileSystem(ctx), filepath.Join(projectDir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	requirements, err := getRequirements(modContent)
+	if err != nil {
+		return nil, err
+	}
+	return reconcileVendor(requirements, modules), nil
+}
+
+func reconcileVendor(requirements []string, modules []VendorModule) *VendorReport {
+	report := &VendorReport{}
+	vendored := map[string]VendorModule{}
+	for _, module := range modules {
+		vendored[module.Path] = module
+	}
+	required := map[string]bool{}
+	for _, requirement := range requirements {
+		nameAndVersion := strings.SplitN(requirement, "@", 2)
+		required[nameAndVersion[0]] = true
+		module, exists := vendored[nameAndVersion[0]]
+		if !exists {
+			report.Missing = append(report.Missing, requirement)
+			continue
+		}
+		if len(nameAndVersion) == 2 && module.Version != nameAndVersion[1] {
+			report.Mismatched = append(report.Mismatched, fmt.Sprintf("%s: go.mod requires %s, vendor has %s", nameAndVersion[0], nameAndVersion[1], module.Version))
+		}
+	}
+	for _, module := range modules {
+		if module.Explicit && !required[module.Path] {
+			report.Unrequired = append(report.Unrequired, strings.TrimSuffix(module.Path+"@"+module.Version, "@"))
+		}
+	}
+	sort.Strings(report.Missing)
+	sort.Strings(report.Unrequired)
+	sort.Strings(report.Mismatched)
+	return report
+}
+
+// Parses the content of vendor/modules.txt. Module lines start with "# ", followed by
+// optional "## " annotation lines and by the vendored packages of the module.
+func parseVendorModules(content []byte) []VendorModule {
+	var modules []VendorModule
+	for _, line := range getLines(content) {
+		switch {
+		case strings.HasPrefix(line, "## "):
+			if len(modules) > 0 && strings.Contains(line, "explicit") {
+				modules[len(modules)-1].Explicit = true
+			}
+		case strings.HasPrefix(line, "# "):
+			modules = append(modules, parseVendorModuleLine(strings.TrimPrefix(line, "# ")))
+		case len(modules) > 0:
+			modules[len(modules)-1].Packages = append(modules[len(modules)-1].Packages, line)
+		}
+	}
+	return modules
+}
+
+// Parses a module line such as "path version", "path version => path version" or "path => ../local/path".
+func parseVendorModuleLine(line string) VendorModule {
+	module := VendorModule{}
+	sides := strings.SplitN(line, "=>", 2)
+	fields := strings.Fields(sides[0])
+	if len(fields) > 0 {
+		module.Path = fields[0]
+	}
+	if len(fields) > 1 {
+		module.Version = fields[1]
+	}
+	if len(sides) == 2 {
+		replacement := strings.Fields(sides[1])
+		module.Replacement = strings.Join(replacement, "@")
+	}
+	return module
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/fsys"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const modulesTxt = `# github.com/pkg/errors v0.8.1
+## explicit
+github.com/pkg/errors
+# golang.org/x/text v0.3.0 => golang.org/x/text v0.3.2
+golang.org/x/text/language
+golang.org/x/text/internal/tag
+# rsc.io/quote v1.5.2
+## explicit
+rsc.io/quote
+# example.com/local => ../local
+## explicit
+example.com/local
+`
+
+func TestParseVendorModules(t *testing.T) {
+	expected := []VendorModule{
+		{Path: "github.com/pkg/errors", Version: "v0.8.1", Explicit: true, Packages: []string{"github.com/pkg/errors"}},
+		{Path: "golang.org/x/text", Version: "v0.3.0", Replacement: "golang.org/x/text@v0.3.2", Packages: []string{"golang.org/x/text/language", "golang.org/x/text/internal/tag"}},
+		{Path: "rsc.io/quote", Version: "v1.5.2", Explicit: true, Packages: []string{"rsc.io/quote"}},
+		{Path: "example.com/local", Replacement: "../local", Explicit: true, Packages: []string{"example.com/local"}},
+	}
+	actual := parseVendorModules([]byte(modulesTxt))
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expecting: \n%v \nGot: \n%v", expected, actual)
+	}
+}
+
+func TestReconcileVendor(t *testing.T) {
+	requirements := []string{"github.com/pkg/errors@v0.8.1", "rsc.io/quote@v1.5.3", "github.com/missing/module@v1.0.0"}
+	report := reconcileVendor(requirements, parseVendorModules([]byte(modulesTxt)))
+	expected := &VendorReport{
+		Missing:    []string{"github.com/missing/module@v1.0.0"},
+		Unrequired: []string{"example.com/local"},
+		Mismatched: []string{"rsc.io/quote: go.mod requires v1.5.3, vendor has v1.5.2"},
+	}
+	if !reflect.DeepEqual(expected, report) {
+		t.Errorf("Expecting: \n%v \nGot: \n%v", expected, report)
+	}
+	if report.IsConsistent() {
+		t.Error("Expecting the vendor directory to be inconsistent")
+	}
+}
+
+func TestGetDependenciesVendorResolution(t *testing.T) {
+	projectDir := filepath.Join(string(filepath.Separator), "project")
+	modPath, modulesTxtPath := filepath.Join(projectDir, "go.mod"), filepath.Join(projectDir, "vendor", "modules.txt")
+	modContent := `module example.com/project
+
+require (
+	github.com/pkg/errors v0.8.1
+	golang.org/x/text v0.3.0
+	rsc.io/quote v1.5.2
+)
+
+replace golang.org/x/text v0.3.0 => golang.org/x/text v0.3.2
+`
+	vendorContent := `# github.com/pkg/errors v0.8.1
+## explicit
+github.com/pkg/errors
+# golang.org/x/text v0.3.0 => golang.org/x/text v0.3.2
+## explicit
+golang.org/x/text/language
+# rsc.io/quote v1.5.2
+## explicit
+rsc.io/quote
+`
+	fileSystem := fsys.NewMemFileSystem(map[string]string{modPath: modContent, modulesTxtPath: vendorContent})
+	executor := NewFakeExecutor()
+	ctx := WithOptions(context.Background(), &Options{Dir: projectDir, FileSystem: fileSystem, Executor: executor})
+	ctx = WithResolution(ctx, VendorResolution)
+
+	dependencies, err := GetDependencies(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]bool{"github.com/pkg/errors@v0.8.1": true, "golang.org/x/text@v0.3.2": true, "rsc.io/quote@v1.5.2": true}
+	if !reflect.DeepEqual(expected, dependencies) {
+		t.Errorf("Expecting: \n%v \nGot: \n%v", expected, dependencies)
+	}
+	if calls := executor.Calls(); len(calls) != 0 {
+		t.Error("Expecting no go command to run, got:", calls)
+	}
+
+	// A vendor directory which is inconsistent with go.mod fails the resolution.
+	if err = fileSystem.WriteFile(modPath, []byte(strings.Replace(modContent, "rsc.io/quote v1.5.2", "rsc.io/quote v1.5.3", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = GetDependencies(ctx); err == nil || !strings.Contains(err.Error(), "rsc.io/quote: go.mod requires v1.5.3, vendor has v1.5.2") {
+		t.Error("Expecting an inconsistent vendor directory error, got:", err)
+	}
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"regexp"
+	"strings"
+)
+
+// Matches lines such as "rsc.io/quote v1.5.2: dir has been modified (/home/you/go/pkg/mod/rsc.io/quote@v1.5.2)".
+var verifyFailureRegExp = regexp.MustCompile(`^(\S+) (v\S+): (.+?)(?: \((.+)\))?$`)
+
+// A module in the module cache which failed verification.
+type VerifyFailure struct {
+	Module  string
+	Version string
+	// The reason reported by the go command, such as "dir has been modified" or "zip has been modified".
+	Reason string
+	// The path in the module cache of the extracted module directory or of the zip, if reported.
+	Path string
+	Line string
+}
+
+// Returns true if the module files in the cache were changed after they were downloaded.
+func (failure *VerifyFailure) IsModified() bool {
+	return strings.HasSuffix(failure.Reason, "has been modified")
+}
+
+// Runs go mod verify, which checks that the dependencies in the module cache were not modified since downloaded.
+// Returns the modules which failed verification, or an empty slice if all the modules were verified.
+func VerifyModules(ctx context.Context) ([]VerifyFailure, error) {
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = []string{"mod", "verify"}
+	getLogger(ctx).Debug("Running go mod verify")
+	output, errorOutput, err := runCmdWithOutputParser(goCmd, false)
+	failures := parseVerifyFailures(output + errorOutput)
+	if err != nil && len(failures) == 0 {
+		return nil, errorutils.CheckError(contextError(ctx, err))
+	}
+	return failures, nil
+}
+
+func parseVerifyFailures(output string) []VerifyFailure {
+	failures := []VerifyFailure{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		match := verifyFailureRegExp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		failures = append(failures, VerifyFailure{Module: match[1], Version: match[2], Reason: match[3], Path: match[4], Line: line})
+	}
+	return failures
+}
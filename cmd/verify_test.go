@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVerifyFailures(t *testing.T) {
+	output := `rsc.io/quote v1.5.2: dir has been modified (/home/you/go/pkg/mod/rsc.io/quote@v1.5.2)
+golang.org/x/text v0.3.1: zip has been modified (/home/you/go/pkg/mod/cache/download/golang.org/x/text/@v/v0.3.1.zip)
+rsc.io/sampler v1.3.0: missing ziphash: open hash: no such file or directory
+`
+	expected := []VerifyFailure{
+		{"rsc.io/quote", "v1.5.2", "dir has been modified", "/home/you/go/pkg/mod/rsc.io/quote@v1.5.2", "rsc.io/quote v1.5.2: dir has been modified (/home/you/go/pkg/mod/rsc.io/quote@v1.5.2)"},
+		{"golang.org/x/text", "v0.3.1", "zip has been modified", "/home/you/go/pkg/mod/cache/download/golang.org/x/text/@v/v0.3.1.zip", "golang.org/x/text v0.3.1: zip has been modified (/home/you/go/pkg/mod/cache/download/golang.org/x/text/@v/v0.3.1.zip)"},
+		{"rsc.io/sampler", "v1.3.0", "missing ziphash: open hash: no such file or directory", "", "rsc.io/sampler v1.3.0: missing ziphash: open hash: no such file or directory"},
+	}
+	actual := parseVerifyFailures(output)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+	if !actual[0].IsModified() || actual[2].IsModified() {
+		t.Error("Unexpected IsModified results")
+	}
+	if failures := parseVerifyFailures("all modules verified\n"); len(failures) != 0 {
+		t.Error("Expecting no failures, got:", failures)
+	}
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flags of go vet.
+type VetOptions struct {
+	// The packages to vet, "./..." if empty.
+	Packages []string
+	// Runs only these analyzers, such as "printf" and "shadow". All the analyzers run if empty.
+	Enable []string
+	// Doesn't run these analyzers.
+	Disable []string
+	// Additional flags, such as "-tags=integration".
+	Flags []string
+}
+
+// A diagnostic reported by a go vet analyzer.
+type VetDiagnostic struct {
+	Package  string
+	Analyzer string
+	// The position in the "file:line:column" notation.
+	Position string
+	File     string
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (diagnostic VetDiagnostic) String() string {
+	return diagnostic.Position + ": " + diagnostic.Message + " (" + diagnostic.Analyzer + ")"
+}
+
+// Runs go vet -json and returns the diagnostics, sorted by package, file and position.
+// An error is returned if go vet failed, for example because of compilation errors, or if an analyzer failed.
+func RunVet(ctx context.Context, options VetOptions) ([]VetDiagnostic, error) {
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = append([]string{"vet", "-json"}, getVetFlags(options)...)
+	getLogger(ctx).Info("Running 'go " + strings.Join(goCmd.Command, " ") + "'")
+	output, errorOutput, err := runCmdWithOutputParser(goCmd, false)
+	diagnostics, parseErr := parseVetOutput(output + "\n" + errorOutput)
+	if err != nil {
+		err = contextError(ctx, err)
+		if errorOutput = strings.TrimSpace(errorOutput); errorOutput != "" {
+			err = fmt.Errorf("%s: %s", err.Error(), errorOutput)
+		}
+		return diagnostics, errorutils.CheckError(err)
+	}
+	return diagnostics, parseErr
+}
+
+func getVetFlags(options VetOptions) []string {
+	var flags []string
+	for _, analyzer := range options.Enable {
+		flags = append(flags, "-"+analyzer)
+	}
+	for _, analyzer := range options.Disable {
+		flags = append(flags, "-"+analyzer+"=false")
+	}
+	flags = append(flags, options.Flags...)
+	if len(options.Packages) == 0 {
+		return append(flags, "./...")
+	}
+	return append(flags, options.Packages...)
+}
+
+// Parses the output of go vet -json, which is a JSON object per package, mapping each analyzer to its diagnostics or
+// to its error. Comment lines starting with "#" and messages of the go command, such as "go: downloading", are skipped.
+// Returns the diagnostics, and an error listing the analyzers which failed, if any.
+func parseVetOutput(output string) ([]VetDiagnostic, error) {
+	var jsonLines []string
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "go: ") {
+			jsonLines = append(jsonLines, line)
+		}
+	}
+	var diagnostics []VetDiagnostic
+	var analyzerErrors []string
+	decoder := json.NewDecoder(strings.NewReader(strings.Join(jsonLines, "\n")))
+	for {
+		var packages map[string]map[string]json.RawMessage
+		err := decoder.Decode(&packages)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return diagnostics, errorutils.CheckError(fmt.Errorf("Failed parsing the output of go vet: %s", err.Error()))
+		}
+		for pkg, analyzers := range packages {
+			for analyzer, raw := range analyzers {
+				var analyzerError struct {
+					Error string `json:"error"`
+				}
+				if json.Unmarshal(raw, &analyzerError) == nil {
+					analyzerErrors = append(analyzerErrors, pkg+": "+analyzer+": "+analyzerError.Error)
+					continue
+				}
+				var reported []struct {
+					Posn    string `json:"posn"`
+					Message string `json:"message"`
+				}
+				if err = json.Unmarshal(raw, &reported); err != nil {
+					return diagnostics, errorutils.CheckError(fmt.Errorf("Failed parsing the output of go vet: %s", err.Error()))
+				}
+				for _, diagnostic := range reported {
+					diagnostics = append(diagnostics, newVetDiagnostic(pkg, analyzer, diagnostic.Posn, diagnostic.Message))
+				}
+			}
+		}
+	}
+	sort.Slice(diagnostics, func(i, j int) bool {
+		first, second := diagnostics[i], diagnostics[j]
+		if first.Package != second.Package {
+			return first.Package < second.Package
+		}
+		if first.File != second.File {
+			return first.File < second.File
+		}
+		if first.Line != second.Line {
+			return first.Line < second.Line
+		}
+		return first.Column < second.Column
+	})
+	if len(analyzerErrors) > 0 {
+		sort.Strings(analyzerErrors)
+		return diagnostics, errorutils.CheckError(fmt.Errorf("go vet analyzers failed:\n%s", strings.Join(analyzerErrors, "\n")))
+	}
+	return diagnostics, nil
+}
+
+// Creates a diagnostic, splitting the position in the "file:line:column" notation.
+func newVetDiagnostic(pkg, analyzer, position, message string) VetDiagnostic {
+	diagnostic := VetDiagnostic{Package: pkg, Analyzer: analyzer, Position: position, File: position, Message: message}
+	parts := strings.Split(position, ":")
+	if len(parts) >= 3 {
+		line, lineErr := strconv.Atoi(parts[len(parts)-2])
+		column, columnErr := strconv.Atoi(parts[len(parts)-1])
+		if lineErr == nil && columnErr == nil {
+			diagnostic.File = strings.Join(parts[:len(parts)-2], ":")
+			diagnostic.Line = line
+			diagnostic.Column = column
+		}
+	}
+	return diagnostic
+}
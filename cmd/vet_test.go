@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+const vetOutput = `go: downloading golang.org/x/text v0.3.0
+# example.com/a
+{
+	"example.com/a": {
+		"printf": [
+			{
+				"posn": "/tmp/a/a.go:6:14",
+				"end": "/tmp/a/a.go:6:16",
+				"message": "fmt.Printf format %d has arg \"x\" of wrong type string"
+			}
+		],
+		"assign": [
+			{
+				"posn": "/tmp/a/a.go:3:2",
+				"message": "self-assignment of x"
+			}
+		]
+	}
+}
+# example.com/b
+{
+	"example.com/b": {
+		"unreachable": [
+			{
+				"posn": "C:\\b\\b.go:10:1",
+				"message": "unreachable code"
+			}
+		]
+	}
+}
+`
+
+func TestParseVetOutput(t *testing.T) {
+	diagnostics, err := parseVetOutput(vetOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []VetDiagnostic{
+		{"example.com/a", "assign", "/tmp/a/a.go:3:2", "/tmp/a/a.go", 3, 2, "self-assignment of x"},
+		{"example.com/a", "printf", "/tmp/a/a.go:6:14", "/tmp/a/a.go", 6, 14, `fmt.Printf format %d has arg "x" of wrong type string`},
+		{"example.com/b", "unreachable", `C:\b\b.go:10:1`, `C:\b\b.go`, 10, 1, "unreachable code"},
+	}
+	if !reflect.DeepEqual(expected, diagnostics) {
+		t.Errorf("Expected: %v, Got: %v", expected, diagnostics)
+	}
+}
+
+func TestParseVetOutputAnalyzerError(t *testing.T) {
+	_, err := parseVetOutput(`{"example.com/a": {"printf": {"error": "analysis failed"}}}`)
+	if err == nil {
+		t.Error("Expecting an error for a failed analyzer")
+	}
+}
+
+func TestGetVetFlags(t *testing.T) {
+	options := VetOptions{Enable: []string{"printf", "shadow"}, Disable: []string{"composites"}, Flags: []string{"-tags=integration"}}
+	expected := []string{"-printf", "-shadow", "-composites=false", "-tags=integration", "./..."}
+	if actual := getVetFlags(options); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	gofrogio "github.com/jfrog/gofrog/io"
+	"strings"
+	"sync"
+)
+
+// A non-fatal message matched in the output of a go command, such as a deprecation notice.
+// Patterns handled by a WarningHandler return warnings, which are collected instead of failing the command.
+type Warning struct {
+	// The name of the pattern which matched the line.
+	Pattern string
+	// The module or package the warning is about, if known.
+	Module string
+	Line   string
+}
+
+func (warning *Warning) Error() string {
+	return warning.Pattern + ":" + warning.Line
+}
+
+func (warning *Warning) GetLine() string {
+	return warning.Line
+}
+
+// Collects the warnings of the go commands. Safe for concurrent use.
+type Warnings struct {
+	mutex    sync.Mutex
+	warnings []Warning
+}
+
+// Returns the warnings collected so far, in the order they were matched.
+func (warnings *Warnings) List() []Warning {
+	warnings.mutex.Lock()
+	defer warnings.mutex.Unlock()
+	return append([]Warning(nil), warnings.warnings...)
+}
+
+func (warnings *Warnings) add(warning Warning) {
+	warnings.mutex.Lock()
+	defer warnings.mutex.Unlock()
+	warnings.warnings = append(warnings.warnings, warning)
+}
+
+// Returns a copy of ctx carrying the options of ctx, and the collector of the warnings of the go commands run with it.
+func WithWarnings(ctx context.Context) (context.Context, *Warnings) {
+	warnings := &Warnings{}
+	options := *GetOptions(ctx)
+	options.Warnings = warnings
+	return WithOptions(ctx, &options), warnings
+}
+
+// Returns a function handling the matches of a pattern as warnings of the pattern's name.
+// The module is taken from the first group of the pattern, if it has one. The line is kept as is.
+func WarningHandler(name string) func(pattern *gofrogio.CmdOutputPattern) (string, error) {
+	return func(pattern *gofrogio.CmdOutputPattern) (string, error) {
+		warning := &Warning{Pattern: name, Line: strings.TrimSpace(pattern.Line)}
+		if len(pattern.MatchedResults) > 1 {
+			warning.Module = pattern.MatchedResults[1]
+		}
+		return pattern.Line, warning
+	}
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	registry, err := NewDefaultPatternRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := "go: downloading go1.21.1 (linux/amd64)\n" +
+		"go: finding module for package rsc.io/quote\n" +
+		"go: module github.com/golang/protobuf is deprecated: Use the \"google.golang.org/protobuf\" module instead.\n"
+	executor := NewFakeExecutor()
+	executor.DefaultResult = ExecutorResult{Stdout: "output\n", Stderr: stderr}
+	ctx, warnings := WithWarnings(WithExecutor(context.Background(), executor))
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goCmd.Command = []string{"build"}
+	stdout, actualStderr, err := runCmdWithOutputParser(goCmd, false, registry.Patterns()...)
+	if err != nil {
+		t.Fatal("Expecting the warnings not to fail the command, got:", err)
+	}
+	if stdout != "output\n" || actualStderr != stderr {
+		t.Errorf("Expecting the output to be kept, got: %q, %q", stdout, actualStderr)
+	}
+	expected := []Warning{
+		{Pattern: ToolchainSwitchPattern, Module: "go1.21.1", Line: "go: downloading go1.21.1 (linux/amd64)"},
+		{Pattern: FindingModulePattern, Module: "rsc.io/quote", Line: "go: finding module for package rsc.io/quote"},
+		{Pattern: DeprecatedPattern, Module: "github.com/golang/protobuf", Line: "go: module github.com/golang/protobuf is deprecated: Use the \"google.golang.org/protobuf\" module instead."},
+	}
+	if !reflect.DeepEqual(expected, warnings.List()) {
+		t.Errorf("Expecting: %+v, Got: %+v", expected, warnings.List())
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strings"
+)
+
+// Explains why a module is needed, as reported by go mod why -m.
+type WhyResult struct {
+	Module string
+	// False if the main module doesn't need the module.
+	Needed bool
+	// The shortest chain of packages from the main module to a package of the module, starting with the main module's package.
+	Chain []string
+}
+
+// Runs go mod why -m for the module and returns the chain of imports explaining why it is needed.
+func WhyModule(ctx context.Context, module string) (*WhyResult, error) {
+	results, err := WhyModules(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, errorutils.CheckError(errors.New("go mod why returned no results for " + module))
+	}
+	return &results[0], nil
+}
+
+// Runs go mod why -m for the modules and returns the results in the order of the modules.
+func WhyModules(ctx context.Context, modules ...string) ([]WhyResult, error) {
+	goCmd, err := NewCmd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goCmd.Command = append([]string{"mod", "why", "-m"}, modules...)
+	patterns, err := getPatterns(ctx)
+	if err != nil {
+		return nil, err
+	}
+	getLogger(ctx).Debug("Running go", strings.Join(goCmd.Command, " "))
+	var output string
+	err = runWithRetries(ctx, "go mod why", func() error {
+		var errorOutput string
+		output, errorOutput, err = runCmdWithOutputParser(goCmd, true, patterns...)
+		return checksumError(err, errorOutput)
+	})
+	if err != nil {
+		return nil, errorutils.CheckError(contextError(ctx, err))
+	}
+	return parseWhy(output), nil
+}
+
+// Parses the output of go mod why -m, which has a section per module:
+//
+//	# golang.org/x/text
+//	github.com/you/hello
+//	rsc.io/quote
+//	golang.org/x/text/language
+func parseWhy(output string) []WhyResult {
+	var results []WhyResult
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "# "):
+			results = append(results, WhyResult{Module: strings.TrimPrefix(line, "# "), Needed: true})
+		case len(results) == 0:
+			continue
+		case strings.HasPrefix(line, "(") && strings.HasSuffix(line, ")"):
+			// Such as "(main module does not need module golang.org/x/text)".
+			results[len(results)-1].Needed = false
+		default:
+			results[len(results)-1].Chain = append(results[len(results)-1].Chain, line)
+		}
+	}
+	return results
+}
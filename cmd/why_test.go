@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWhy(t *testing.T) {
+	output := `# golang.org/x/text
+github.com/you/hello
+rsc.io/quote
+rsc.io/sampler
+golang.org/x/text/language
+
+# github.com/pkg/errors
+(main module does not need module github.com/pkg/errors)
+`
+	expected := []WhyResult{
+		{Module: "golang.org/x/text", Needed: true, Chain: []string{"github.com/you/hello", "rsc.io/quote", "rsc.io/sampler", "golang.org/x/text/language"}},
+		{Module: "github.com/pkg/errors", Needed: false},
+	}
+	if actual := parseWhy(output); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
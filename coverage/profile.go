@@ -0,0 +1,193 @@
+package coverage
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The coverage modes of go test -covermode.
+const (
+	SetMode    = "set"
+	CountMode  = "count"
+	AtomicMode = "atomic"
+)
+
+// A coverage profile, as written by go test -coverprofile.
+type Profile struct {
+	Mode   string
+	Blocks []Block
+}
+
+// A block of statements and the number of times it ran, or 1 if it ran in the set mode.
+type Block struct {
+	// The file, in the "package/file.go" notation.
+	File string
+	// The position of the block in the "startLine.startColumn,endLine.endColumn" notation.
+	Range      string
+	Statements int
+	Count      int
+}
+
+// Parses a coverage profile.
+func ParseProfile(reader io.Reader) (*Profile, error) {
+	profile := &Profile{}
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "mode:") {
+			mode := strings.TrimSpace(strings.TrimPrefix(line, "mode:"))
+			if profile.Mode != "" && profile.Mode != mode {
+				return nil, errorutils.CheckError(fmt.Errorf("Coverage profile line %d: the mode %s differs from the mode %s.", lineNumber, mode, profile.Mode))
+			}
+			profile.Mode = mode
+			continue
+		}
+		block, err := parseBlock(line)
+		if err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("Coverage profile line %d: %s", lineNumber, err.Error()))
+		}
+		profile.Blocks = append(profile.Blocks, block)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if profile.Mode == "" {
+		return nil, errorutils.CheckError(errors.New("The coverage profile has no mode line."))
+	}
+	return profile, nil
+}
+
+// Parses the coverage profile file.
+func ParseProfileFile(path string) (*Profile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer file.Close()
+	return ParseProfile(file)
+}
+
+// Merges the coverage profile files, such as the profiles of several go test runs. The files must have the same mode.
+func MergeProfileFiles(paths ...string) (*Profile, error) {
+	var profiles []*Profile
+	for _, path := range paths {
+		profile, err := ParseProfileFile(path)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return Merge(profiles...)
+}
+
+// Merges the coverage profiles, which must have the same mode. The counts of a block appearing in several profiles
+// are summed, or set to 1 if the block ran in any of them in the set mode.
+// The blocks of the merged profile are sorted by file and position.
+func Merge(profiles ...*Profile) (*Profile, error) {
+	merged := &Profile{}
+	blocks := map[string]*Block{}
+	for _, profile := range profiles {
+		if merged.Mode != "" && merged.Mode != profile.Mode {
+			return nil, errorutils.CheckError(fmt.Errorf("Cannot merge coverage profiles of the %s and %s modes.", merged.Mode, profile.Mode))
+		}
+		merged.Mode = profile.Mode
+		for _, block := range profile.Blocks {
+			mergeBlock(blocks, profile.Mode, block)
+		}
+	}
+	for _, block := range blocks {
+		merged.Blocks = append(merged.Blocks, *block)
+	}
+	sort.Slice(merged.Blocks, func(i, j int) bool {
+		if merged.Blocks[i].File != merged.Blocks[j].File {
+			return merged.Blocks[i].File < merged.Blocks[j].File
+		}
+		return comparePositions(merged.Blocks[i].Range, merged.Blocks[j].Range) < 0
+	})
+	return merged, nil
+}
+
+// Adds the block to the blocks by their "file:range" key, merging it with an existing block of the same key as
+// go tool cover does: the counts are summed, or set to 1 if the block ran in the set mode.
+func mergeBlock(blocks map[string]*Block, mode string, block Block) {
+	key := block.File + ":" + block.Range
+	existing, exists := blocks[key]
+	if !exists {
+		blocks[key] = &block
+		return
+	}
+	if mode == SetMode {
+		if block.Count > 0 {
+			existing.Count = 1
+		}
+	} else {
+		existing.Count += block.Count
+	}
+}
+
+// Writes the profile in the go test -coverprofile format.
+func (profile *Profile) Write(writer io.Writer) error {
+	if _, err := fmt.Fprintf(writer, "mode: %s\n", profile.Mode); err != nil {
+		return errorutils.CheckError(err)
+	}
+	for _, block := range profile.Blocks {
+		if _, err := fmt.Fprintf(writer, "%s:%s %d %d\n", block.File, block.Range, block.Statements, block.Count); err != nil {
+			return errorutils.CheckError(err)
+		}
+	}
+	return nil
+}
+
+// Parses a line such as "github.com/jfrog/gocmd/cmd/cmd.go:19.55,20.65 1 1".
+func parseBlock(line string) (Block, error) {
+	fields := strings.Fields(line)
+	index := strings.LastIndex(line, ":")
+	if len(fields) != 3 || index < 0 {
+		return Block{}, fmt.Errorf("invalid block: %s", line)
+	}
+	block := Block{File: line[:index], Range: strings.Fields(line[index+1:])[0]}
+	statements, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return Block{}, fmt.Errorf("invalid number of statements: %s", line)
+	}
+	count, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return Block{}, fmt.Errorf("invalid count: %s", line)
+	}
+	block.Statements = statements
+	block.Count = count
+	return block, nil
+}
+
+// Compares ranges in the "startLine.startColumn,endLine.endColumn" notation by their numbers.
+func comparePositions(range1, range2 string) int {
+	numbers1 := rangeNumbers(range1)
+	numbers2 := rangeNumbers(range2)
+	for i := 0; i < len(numbers1) && i < len(numbers2); i++ {
+		if numbers1[i] != numbers2[i] {
+			return numbers1[i] - numbers2[i]
+		}
+	}
+	return len(numbers1) - len(numbers2)
+}
+
+func rangeNumbers(blockRange string) []int {
+	var numbers []int
+	for _, field := range strings.FieldsFunc(blockRange, func(char rune) bool { return char == '.' || char == ',' }) {
+		number, _ := strconv.Atoi(field)
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
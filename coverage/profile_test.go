@@ -0,0 +1,88 @@
+package coverage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const unitProfile = `mode: count
+example.com/a/a.go:10.2,12.16 2 1
+example.com/a/a.go:3.20,5.2 1 0
+example.com/b/b.go:3.20,5.2 3 0
+`
+
+const integrationProfile = `mode: count
+example.com/a/a.go:3.20,5.2 1 2
+example.com/b/b.go:3.20,5.2 3 1
+example.com/b/b.go:7.1,8.2 1 0
+`
+
+func TestMergeProfileFiles(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "coverageTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	var paths []string
+	for i, content := range []string{unitProfile, integrationProfile} {
+		path := filepath.Join(tempDir, string('a'+rune(i))+".out")
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	merged, err := MergeProfileFiles(paths...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buffer bytes.Buffer
+	if err = merged.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	expected := `mode: count
+example.com/a/a.go:3.20,5.2 1 2
+example.com/a/a.go:10.2,12.16 2 1
+example.com/b/b.go:3.20,5.2 3 1
+example.com/b/b.go:7.1,8.2 1 0
+`
+	if buffer.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buffer.String())
+	}
+}
+
+func TestMergeSetMode(t *testing.T) {
+	first, err := ParseProfile(strings.NewReader("mode: set\na/a.go:1.1,2.2 1 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ParseProfile(strings.NewReader("mode: set\na/a.go:1.1,2.2 1 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, err := Merge(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged.Blocks[0].Count != 1 {
+		t.Error("Expected the count of a set mode block to stay 1, Got:", merged.Blocks[0].Count)
+	}
+	count, err := ParseProfile(strings.NewReader(unitProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Merge(first, count); err == nil {
+		t.Error("Expecting an error for merging profiles of different modes")
+	}
+}
+
+func TestParseProfileErrors(t *testing.T) {
+	for _, content := range []string{"a/a.go:1.1,2.2 1 1\n", "mode: set\na/a.go:1.1,2.2 1\n", "mode: set\na/a.go:1.1,2.2 x 1\n"} {
+		if _, err := ParseProfile(strings.NewReader(content)); err == nil {
+			t.Errorf("Expecting an error for: %q", content)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package coverage
+
+import (
+	"fmt"
+	"path"
+	"sort"
+)
+
+// The number of statements and how many of them are covered.
+type Coverage struct {
+	Statements int
+	Covered    int
+}
+
+// Returns the percentage of covered statements, or 100 if there are no statements.
+func (coverage Coverage) Percent() float64 {
+	if coverage.Statements == 0 {
+		return 100
+	}
+	return float64(coverage.Covered) * 100 / float64(coverage.Statements)
+}
+
+// The total coverage of a profile and the coverage of each of its packages.
+type Summary struct {
+	Total    Coverage
+	Packages map[string]Coverage
+}
+
+// The minimum percentages of covered statements. A zero threshold is not checked.
+type Thresholds struct {
+	Total float64
+	// Applies to each of the packages.
+	Package float64
+}
+
+// The coverage is below the threshold.
+type BelowThresholdError struct {
+	// The package whose coverage is below the threshold, or an empty string for the total coverage.
+	Package   string
+	Coverage  float64
+	Threshold float64
+}
+
+func (err *BelowThresholdError) Error() string {
+	if err.Package == "" {
+		return fmt.Sprintf("The total coverage %.1f%% is below the threshold %.1f%%.", err.Coverage, err.Threshold)
+	}
+	return fmt.Sprintf("The coverage of %s %.1f%% is below the threshold %.1f%%.", err.Package, err.Coverage, err.Threshold)
+}
+
+// Returns the total coverage of the profile and the coverage of each of its packages.
+// Blocks repeated in the profile, as in the profiles of go test -coverpkg covering a package from several test
+// binaries, are merged first, so that their statements are counted once.
+func (profile *Profile) Summary() *Summary {
+	blocks := map[string]*Block{}
+	for _, block := range profile.Blocks {
+		mergeBlock(blocks, profile.Mode, block)
+	}
+	summary := &Summary{Packages: map[string]Coverage{}}
+	for _, block := range blocks {
+		pkg := path.Dir(block.File)
+		packageCoverage := summary.Packages[pkg]
+		packageCoverage.Statements += block.Statements
+		summary.Total.Statements += block.Statements
+		if block.Count > 0 {
+			packageCoverage.Covered += block.Statements
+			summary.Total.Covered += block.Statements
+		}
+		summary.Packages[pkg] = packageCoverage
+	}
+	return summary
+}
+
+// Returns the packages, sorted.
+func (summary *Summary) PackageNames() []string {
+	var packages []string
+	for pkg := range summary.Packages {
+		packages = append(packages, pkg)
+	}
+	sort.Strings(packages)
+	return packages
+}
+
+// Returns a BelowThresholdError if the total coverage, or the coverage of a package, is below its threshold.
+// The total coverage is checked first, and then the packages in their sorted order.
+func (summary *Summary) Check(thresholds Thresholds) error {
+	if thresholds.Total > 0 && summary.Total.Percent() < thresholds.Total {
+		return &BelowThresholdError{Coverage: summary.Total.Percent(), Threshold: thresholds.Total}
+	}
+	if thresholds.Package <= 0 {
+		return nil
+	}
+	for _, pkg := range summary.PackageNames() {
+		if percent := summary.Packages[pkg].Percent(); percent < thresholds.Package {
+			return &BelowThresholdError{Package: pkg, Coverage: percent, Threshold: thresholds.Package}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	profile, err := ParseProfile(strings.NewReader(unitProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary := profile.Summary()
+	expected := &Summary{
+		Total:    Coverage{Statements: 6, Covered: 2},
+		Packages: map[string]Coverage{"example.com/a": {3, 2}, "example.com/b": {3, 0}},
+	}
+	if !reflect.DeepEqual(expected, summary) {
+		t.Errorf("Expected: %v, Got: %v", expected, summary)
+	}
+
+	tests := []struct {
+		name       string
+		thresholds Thresholds
+		expected   error
+	}{
+		{"none", Thresholds{}, nil},
+		{"totalPassed", Thresholds{Total: 30}, nil},
+		{"totalFailed", Thresholds{Total: 50, Package: 50}, &BelowThresholdError{Coverage: 100.0 / 3, Threshold: 50}},
+		{"packageFailed", Thresholds{Total: 30, Package: 50}, &BelowThresholdError{Package: "example.com/b", Coverage: 0, Threshold: 50}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := summary.Check(test.thresholds); !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("Test name: %s: Expected: %v, Got: %v", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSummaryRepeatedBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  string
+		expected Coverage
+	}{
+		{"set", "mode: set\na/a.go:1.1,2.2 2 0\na/a.go:3.1,4.2 1 0\na/a.go:1.1,2.2 2 1\n", Coverage{Statements: 3, Covered: 2}},
+		{"count", "mode: count\na/a.go:1.1,2.2 2 0\na/a.go:3.1,4.2 1 0\na/a.go:1.1,2.2 2 3\n", Coverage{Statements: 3, Covered: 2}},
+		{"notCovered", "mode: atomic\na/a.go:1.1,2.2 2 0\na/a.go:1.1,2.2 2 0\n", Coverage{Statements: 2, Covered: 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile, err := ParseProfile(strings.NewReader(test.profile))
+			if err != nil {
+				t.Fatal(err)
+			}
+			summary := profile.Summary()
+			if summary.Total != test.expected || summary.Packages["a"] != test.expected {
+				t.Errorf("Test name: %s: Expected: %v, Got: %v", test.name, test.expected, summary)
+			}
+		})
+	}
+}
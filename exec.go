@@ -1,18 +1,19 @@
 package gocmd
 
 import (
+	"context"
 	"github.com/jfrog/gocmd/executers"
 	"github.com/jfrog/jfrog-client-go/artifactory"
 )
 
-func RecursivePublish(targetRepo, goModEditMessage string, serviceManager *artifactory.ArtifactoryServicesManager) error {
-	return executers.RecursivePublish(targetRepo, goModEditMessage, serviceManager)
+func RecursivePublish(ctx context.Context, targetRepo, goModEditMessage string, serviceManager *artifactory.ArtifactoryServicesManager) error {
+	return executers.RecursivePublish(ctx, targetRepo, goModEditMessage, serviceManager)
 }
 
-func RunWithFallbacksAndPublish(goArg []string, targetRepo string, noRegistry bool, serviceManager *artifactory.ArtifactoryServicesManager) error {
-	return executers.RunWithFallbacksAndPublish(goArg, targetRepo, noRegistry, serviceManager)
+func RunWithFallbacksAndPublish(ctx context.Context, goArg []string, targetRepo string, noRegistry bool, serviceManager *artifactory.ArtifactoryServicesManager) error {
+	return executers.RunWithFallbacksAndPublish(ctx, goArg, targetRepo, noRegistry, serviceManager)
 }
 
-func RunWithFallback(goArg []string, url string) error {
-	return executers.RunWithFallback(goArg, url)
+func RunWithFallback(ctx context.Context, goArg []string, url string) error {
+	return executers.RunWithFallback(ctx, goArg, url)
 }
@@ -2,10 +2,12 @@ package executers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/jfrog/gocmd/cache"
 	"github.com/jfrog/gocmd/cmd"
 	"github.com/jfrog/gocmd/executers/utils"
+	"github.com/jfrog/gocmd/log"
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/auth"
 	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
@@ -15,7 +17,6 @@ import (
 	multifilereader "github.com/jfrog/jfrog-client-go/utils/io"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils/checksum"
-	"github.com/jfrog/jfrog-client-go/utils/log"
 	"github.com/pkg/errors"
 	"io/ioutil"
 	"net/http"
@@ -31,24 +32,24 @@ const (
 )
 
 // Resolve artifacts from VCS and publish the missing artifacts to Artifactory
-func collectDependenciesAndPublish(targetRepo string, failOnError bool, dependenciesInterface GoPackage, serviceManager *artifactory.ArtifactoryServicesManager) error {
-	rootProjectDir, err := cmd.GetProjectRoot()
+func collectDependenciesAndPublish(ctx context.Context, targetRepo string, failOnError bool, dependenciesInterface GoPackage, serviceManager *artifactory.ArtifactoryServicesManager) error {
+	rootProjectDir, err := cmd.GetProjectRootWithContext(ctx)
 	if err != nil {
 		return err
 	}
 	cache := cache.DependenciesCache{}
-	dependenciesToPublish, err := collectProjectDependencies(targetRepo, rootProjectDir, &cache, serviceManager.GetConfig().GetArtDetails())
+	dependenciesToPublish, err := collectProjectDependencies(ctx, targetRepo, rootProjectDir, &cache, serviceManager.GetConfig().GetArtDetails())
 	if err != nil || len(dependenciesToPublish) == 0 {
 		return err
 	}
-	cachePath, packageDependencies, err := getDependencies(dependenciesToPublish)
+	cachePath, packageDependencies, err := getDependencies(ctx, dependenciesToPublish)
 	if err != nil {
 		if failOnError {
 			return err
 		}
-		log.Error("Received an error retrieving project dependencies:", err)
+		cmd.GetLogger(ctx).Error("Received an error retrieving project dependencies:", err)
 	}
-	err = populateAndPublish(targetRepo, cachePath, dependenciesInterface, packageDependencies, &cache, serviceManager)
+	err = populateAndPublish(ctx, targetRepo, cachePath, dependenciesInterface, packageDependencies, &cache, serviceManager)
 	if err != nil {
 		return err
 	}
@@ -56,11 +57,14 @@ func collectDependenciesAndPublish(targetRepo string, failOnError bool, dependen
 	return nil
 }
 
-func populateAndPublish(targetRepo, cachePath string, dependenciesInterface GoPackage, packageDependencies []Package, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error {
+func populateAndPublish(ctx context.Context, targetRepo, cachePath string, dependenciesInterface GoPackage, packageDependencies []Package, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error {
 	cache.IncrementTotal(len(packageDependencies))
 	for _, dep := range packageDependencies {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		dependenciesInterface = dependenciesInterface.New(cachePath, dep)
-		err := dependenciesInterface.PopulateModAndPublish(targetRepo, cache, serviceManager)
+		err := dependenciesInterface.PopulateModAndPublish(ctx, targetRepo, cache, serviceManager)
 		if err != nil {
 			// If using recursive publish - the error always nil. If we got here, means that this error happened when not using recursive publish.
 			return err
@@ -70,33 +74,36 @@ func populateAndPublish(targetRepo, cachePath string, dependenciesInterface GoPa
 }
 
 // Collects the dependencies of the project
-func collectProjectDependencies(targetRepo, rootProjectDir string, cache *cache.DependenciesCache, auth auth.ArtifactoryDetails) (map[string]bool, error) {
-	dependenciesMap, err := getDependenciesGraphWithFallback(targetRepo, auth)
+func collectProjectDependencies(ctx context.Context, targetRepo, rootProjectDir string, cache *cache.DependenciesCache, auth auth.ArtifactoryDetails) (map[string]bool, error) {
+	dependenciesMap, err := getDependenciesGraphWithFallback(ctx, targetRepo, auth)
 	if err != nil {
 		return nil, err
 	}
-	replaceDependencies, err := getReplaceDependencies()
+	replaceDependencies, err := getReplaceDependencies(rootProjectDir)
 	if err != nil {
 		return nil, err
 	}
 
 	// Merge replaceDependencies with dependenciesToPublish
 	mergeReplaceDependenciesWithGraphDependencies(replaceDependencies, dependenciesMap)
-	sumFileContent, sumFileStat, err := cmd.GetSumContentAndRemove(rootProjectDir)
+	backups, err := cmd.NewFileBackupManagerWithContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if len(sumFileContent) > 0 && sumFileStat != nil {
-		defer cmd.RestoreSumFile(rootProjectDir, sumFileContent, sumFileStat)
+	defer backups.Rollback()
+	defer backups.RecoverAndRollback()
+	err = backups.BackupAndRemove(filepath.Join(rootProjectDir, "go.sum"))
+	if err != nil {
+		return nil, err
 	}
-	projectDependencies, err := downloadDependencies(targetRepo, cache, dependenciesMap, auth)
+	projectDependencies, err := downloadDependencies(ctx, targetRepo, cache, dependenciesMap, auth)
 	if err != nil {
 		return projectDependencies, err
 	}
 	return projectDependencies, nil
 }
 
-func downloadDependencies(targetRepo string, cache *cache.DependenciesCache, depSlice map[string]bool, auth auth.ArtifactoryDetails) (map[string]bool, error) {
+func downloadDependencies(ctx context.Context, targetRepo string, cache *cache.DependenciesCache, depSlice map[string]bool, auth auth.ArtifactoryDetails) (map[string]bool, error) {
 	client, err := httpclient.ClientBuilder().Build()
 	if err != nil {
 		return nil, err
@@ -104,6 +111,9 @@ func downloadDependencies(targetRepo string, cache *cache.DependenciesCache, dep
 	cacheDependenciesMap := cache.GetMap()
 	dependenciesMap := map[string]bool{}
 	for module := range depSlice {
+		if err := ctx.Err(); err != nil {
+			return dependenciesMap, err
+		}
 		nameAndVersion := strings.Split(module, "@")
 		resp, err := performHeadRequest(auth, client, targetRepo, nameAndVersion[0], nameAndVersion[1])
 		if err != nil {
@@ -112,11 +122,11 @@ func downloadDependencies(targetRepo string, cache *cache.DependenciesCache, dep
 
 		if resp.StatusCode == 200 {
 			cacheDependenciesMap[goModEncode(nameAndVersion[0])+":"+goModEncode(nameAndVersion[1])] = true
-			err = downloadDependency(true, module, targetRepo, auth)
+			err = downloadDependency(ctx, true, module, targetRepo, auth)
 			dependenciesMap[module] = true
 		} else if resp.StatusCode == 404 {
 			cacheDependenciesMap[goModEncode(nameAndVersion[0])+":"+goModEncode(nameAndVersion[1])] = false
-			err = downloadDependency(false, module, targetRepo, nil)
+			err = downloadDependency(ctx, false, module, targetRepo, nil)
 			dependenciesMap[module] = false
 		}
 
@@ -188,36 +198,34 @@ func goModDecode(name string) string {
 	return str
 }
 
-// Runs the go mod download command. Should set first the environment variable of GoProxy
-func downloadDependency(downloadFromArtifactory bool, fullDependencyName, targetRepo string, auth auth.ArtifactoryDetails) error {
-	var err error
+// Runs the go mod download command, with GOPROXY set to Artifactory or to the VCS.
+func downloadDependency(ctx context.Context, downloadFromArtifactory bool, fullDependencyName, targetRepo string, auth auth.ArtifactoryDetails) error {
 	if downloadFromArtifactory {
-		log.Debug("Downloading dependency from Artifactory:", fullDependencyName)
-		err = utils.SetGoProxyWithApi(targetRepo, auth)
+		cmd.GetLogger(ctx).Debug("Downloading dependency from Artifactory:", fullDependencyName)
+		var err error
+		ctx, err = utils.WithGoProxyApi(ctx, targetRepo, auth)
+		if err != nil {
+			return err
+		}
 	} else {
-		log.Debug("Downloading dependency from VCS:", fullDependencyName)
-		err = os.Unsetenv(utils.GOPROXY)
+		cmd.GetLogger(ctx).Debug("Downloading dependency from VCS:", fullDependencyName)
+		ctx = utils.WithGoProxyDirect(ctx)
 	}
-	if errorutils.CheckError(err) != nil {
-		return err
-	}
-
-	err = cmd.DownloadDependency(fullDependencyName)
-	return err
+	return cmd.DownloadDependency(ctx, fullDependencyName)
 }
 
 // Downloads the mod file from Artifactory to the Go cache
-func downloadModFileFromArtifactoryToLocalCache(cachePath, targetRepo, name, version string, auth auth.ArtifactoryDetails, client *httpclient.HttpClient) string {
+func downloadModFileFromArtifactoryToLocalCache(ctx context.Context, cachePath, targetRepo, name, version string, auth auth.ArtifactoryDetails, client *httpclient.HttpClient) string {
 	pathToModuleCache := filepath.Join(cachePath, name, "@v")
 	dirExists, err := fileutils.IsDirExists(pathToModuleCache, false)
 	if err != nil {
-		log.Error(fmt.Sprintf("Received an error: %s for %s@%s", err, name, version))
+		cmd.GetLogger(ctx).Error(fmt.Sprintf("Received an error: %s for %s@%s", err, name, version))
 		return ""
 	}
 
 	if dirExists {
 		url := auth.GetUrl() + "api/go/" + targetRepo + "/" + name + "/@v/" + version + ".mod"
-		log.Debug("Downloading mod file from Artifactory:", url)
+		cmd.GetLogger(ctx).Debug("Downloading mod file from Artifactory:", url)
 		downloadFileDetails := &httpclient.DownloadFileDetails{
 			FileName: version + ".mod",
 			// Artifactory URL
@@ -227,19 +235,19 @@ func downloadModFileFromArtifactoryToLocalCache(cachePath, targetRepo, name, ver
 		}
 		resp, err := client.DownloadFile(downloadFileDetails, "", auth.CreateHttpClientDetails(), 3, false)
 		if err != nil {
-			log.Error(fmt.Sprintf("Received an error %s downloading a file: %s to the local path: %s", err.Error(), downloadFileDetails.FileName, downloadFileDetails.LocalPath))
+			cmd.GetLogger(ctx).Error(fmt.Sprintf("Received an error %s downloading a file: %s to the local path: %s", err.Error(), downloadFileDetails.FileName, downloadFileDetails.LocalPath))
 			return ""
 		}
 
-		log.Debug(fmt.Sprintf("Received %d from Artifactory %s", resp.StatusCode, url))
+		cmd.GetLogger(ctx).Debug(fmt.Sprintf("Received %d from Artifactory %s", resp.StatusCode, url))
 		return filepath.Join(downloadFileDetails.LocalPath, downloadFileDetails.LocalFileName)
 	}
 	return ""
 }
 
-func downloadAndCreateDependency(cachePath, name, version, fullDependencyName, targetRepo string, downloadedFromArtifactory bool, auth auth.ArtifactoryDetails) (*Package, error) {
+func downloadAndCreateDependency(ctx context.Context, cachePath, name, version, fullDependencyName, targetRepo string, downloadedFromArtifactory bool, auth auth.ArtifactoryDetails) (*Package, error) {
 	// Dependency is missing within the cache. Need to download it...
-	err := downloadDependency(downloadedFromArtifactory, fullDependencyName, targetRepo, auth)
+	err := downloadDependency(ctx, downloadedFromArtifactory, fullDependencyName, targetRepo, auth)
 	if err != nil {
 		return nil, err
 	}
@@ -387,11 +395,7 @@ func mergeReplaceDependenciesWithGraphDependencies(replaceDeps []string, graphDe
 	}
 }
 
-func getReplaceDependencies() ([]string, error) {
-	rootDir, err := cmd.GetProjectRoot()
-	if err != nil {
-		return nil, err
-	}
+func getReplaceDependencies(rootDir string) ([]string, error) {
 	modFilePath := filepath.Join(rootDir, "go.mod")
 	modFileContent, err := ioutil.ReadFile(modFilePath)
 	if err != nil {
@@ -429,22 +433,25 @@ func parseModForReplaceDependencies(modFileContent string) ([]string, error) {
 }
 
 // Runs go mod graph command with fallback.
-func getDependenciesGraphWithFallback(targetRepo string, auth auth.ArtifactoryDetails) (map[string]bool, error) {
+func getDependenciesGraphWithFallback(ctx context.Context, targetRepo string, auth auth.ArtifactoryDetails) (map[string]bool, error) {
 	dependenciesMap := map[string]bool{}
 	modulesWithErrors := map[string]previousTries{}
 	usedProxy := true
 	for {
 		// Configuring each run to use Artifactory/VCS
-		err := setOrUnsetGoProxy(usedProxy, targetRepo, auth)
+		attemptCtx, err := withArtifactoryOrVcsGoProxy(ctx, usedProxy, targetRepo, auth)
 		if err != nil {
 			return nil, err
 		}
 		usedProxy = !usedProxy
-		dependenciesMap, err = cmd.GetDependenciesGraph()
+		dependenciesMap, err = cmd.GetDependencies(attemptCtx)
 		if err == nil {
 			break
 		}
-		moduleAndVersion, err := getModuleAndVersion(usedProxy, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		moduleAndVersion, err := utils.GetModuleAndVersion(usedProxy, err)
 		if err != nil {
 			return nil, err
 		}
@@ -458,35 +465,27 @@ func getDependenciesGraphWithFallback(targetRepo string, auth auth.ArtifactoryDe
 	return dependenciesMap, nil
 }
 
-func setOrUnsetGoProxy(usedProxy bool, targetRepo string, auth auth.ArtifactoryDetails) error {
+// Returns a copy of ctx whose go commands use Artifactory, unless it was used by the previous run, or else the VCS.
+func withArtifactoryOrVcsGoProxy(ctx context.Context, usedProxy bool, targetRepo string, auth auth.ArtifactoryDetails) (context.Context, error) {
 	if !usedProxy {
-		log.Debug("Trying download the dependencies from Artifactory...")
-		return utils.SetGoProxyWithApi(targetRepo, auth)
+		cmd.GetLogger(ctx).Debug("Trying download the dependencies from Artifactory...")
+		return utils.WithGoProxyApi(ctx, targetRepo, auth)
 	} else {
-		log.Debug("Trying download the dependencies from the VCS...")
-		return errorutils.CheckError(os.Unsetenv(utils.GOPROXY))
+		cmd.GetLogger(ctx).Debug("Trying download the dependencies from the VCS...")
+		return utils.WithGoProxyDirect(ctx), nil
 	}
 }
 
-func getModuleAndVersion(usedProxy bool, err error) (string, error) {
-	splittedLine := strings.Split(err.Error(), ":")
-	utils.LogDebug(err, usedProxy)
-	if len(splittedLine) < 2 {
-		return "", errorutils.CheckError(errors.New("Missing module name and version in the error message " + err.Error()))
-	}
-	return strings.TrimSpace(splittedLine[1]), nil
-}
-
-func populateModWithTidy(path string) error {
+func populateModWithTidy(ctx context.Context, path string) error {
 	err := os.Chdir(filepath.Dir(path))
 	if errorutils.CheckError(err) != nil {
 		return err
 	}
-	log.Debug("Preparing to populate mod", filepath.Dir(path))
-	err = removeGoSum(path)
+	cmd.GetLogger(ctx).Debug("Preparing to populate mod", filepath.Dir(path))
+	err = removeGoSum(ctx, path)
 	utils.LogError(err)
 	// Running go mod tidy command
-	err = cmd.RunGoModTidy()
+	_, err = cmd.RunGoModTidy(ctx)
 	if err != nil {
 		return err
 	}
@@ -494,7 +493,7 @@ func populateModWithTidy(path string) error {
 	return nil
 }
 
-func removeGoSum(path string) error {
+func removeGoSum(ctx context.Context, path string) error {
 	// Remove go.sum file to avoid checksum conflicts with the old go.sum
 	goSum := filepath.Join(filepath.Dir(path), "go.sum")
 	exists, err := fileutils.IsFileExists(goSum, false)
@@ -502,6 +501,9 @@ func removeGoSum(path string) error {
 		return err
 	}
 	if exists {
+		if cmd.SkipInDryRunWithContext(ctx, "Removing file: "+goSum) {
+			return nil
+		}
 		err = os.Remove(goSum)
 		if errorutils.CheckError(err) != nil {
 			return err
@@ -510,9 +512,9 @@ func removeGoSum(path string) error {
 	return nil
 }
 
-func runGoModGraph() (output map[string]bool, err error) {
-	// Running go mod graph command
-	return cmd.GetDependenciesGraph()
+func runGoModGraph(ctx context.Context) (output map[string]bool, err error) {
+	// Running go mod graph, or go list -m all, according to the resolution mode
+	return cmd.GetDependencies(ctx)
 }
 
 type previousTries struct {
@@ -529,8 +531,8 @@ func (pt *previousTries) setTriedFrom(usedProxy bool) {
 }
 
 // Download the dependencies from VCS and publish them to Artifactory.
-func getDependencies(dependenciesToPublish map[string]bool) (cachePath string, packageDependencies []Package, err error) {
-	cachePath, err = utils.GetCachePath()
+func getDependencies(ctx context.Context, dependenciesToPublish map[string]bool) (cachePath string, packageDependencies []Package, err error) {
+	cachePath, err = utils.GetCachePath(ctx)
 	if err != nil {
 		return
 	}
@@ -1,12 +1,16 @@
 package executers
 
 import (
+	"context"
 	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/jfrog-client-go/artifactory/auth"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -205,6 +209,54 @@ func TestParseModForReplaceDependencies(t *testing.T) {
 	}
 }
 
+// Fails go mod graph if GOPROXY is direct, and records the GOPROXY of each run.
+type goProxyExecutor struct {
+	mutex     sync.Mutex
+	goProxies []string
+}
+
+func (executor *goProxyExecutor) Run(ctx context.Context, args []string, env map[string]string, dir string) (string, string, int, error) {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+	executor.goProxies = append(executor.goProxies, env["GOPROXY"])
+	if env["GOPROXY"] == "direct" {
+		return "", "go: github.com/test/mod@v1.0.0: 404 Not Found\n", 1, nil
+	}
+	return "github.com/test github.com/test/mod@v1.0.0\n", "", 0, nil
+}
+
+func TestGetDependenciesGraphWithFallback(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "fallbackTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	if err = ioutil.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module github.com/test\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	processGoProxy, processGoProxySet := os.LookupEnv("GOPROXY")
+	executor := &goProxyExecutor{}
+	// The GOPROXY of the options is overridden by each attempt.
+	ctx := cmd.WithOptions(context.Background(), &cmd.Options{Executor: executor, Dir: tempDir, Env: map[string]string{"GOPROXY": "https://proxy.test"}})
+	artDetails := auth.NewArtifactoryDetails()
+	artDetails.SetUrl("https://rt.test/artifactory/")
+
+	dependencies, err := getDependenciesGraphWithFallback(ctx, "go-remote", artDetails)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(map[string]bool{"github.com/test/mod@v1.0.0": true}, dependencies) {
+		t.Errorf("Unexpected dependencies: %v", dependencies)
+	}
+	expected := []string{"direct", "https://rt.test/artifactory/api/go/go-remote"}
+	if !reflect.DeepEqual(expected, executor.goProxies) {
+		t.Errorf("Expected GOPROXY: %v, Got: %v", expected, executor.goProxies)
+	}
+	if goProxy, set := os.LookupEnv("GOPROXY"); goProxy != processGoProxy || set != processGoProxySet {
+		t.Errorf("Expected the GOPROXY of the process to be unchanged, Got: %q", goProxy)
+	}
+}
+
 func getBaseDir() (baseDir string, err error) {
 	pwd, err := os.Getwd()
 	if err != nil {
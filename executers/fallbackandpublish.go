@@ -1,38 +1,34 @@
 package executers
 
 import (
+	"context"
 	"github.com/jfrog/gocmd/cmd"
 	"github.com/jfrog/gocmd/executers/utils"
 	"github.com/jfrog/jfrog-client-go/artifactory"
-	"github.com/jfrog/jfrog-client-go/utils/log"
-	"os"
 )
 
 // Runs Go, with multiple fallbacks if needed and publish missing dependencies to Artifactory
-func RunWithFallbacksAndPublish(goArg []string, targetRepo string, noRegistry bool, serviceManager *artifactory.ArtifactoryServicesManager) error {
+func RunWithFallbacksAndPublish(ctx context.Context, goArg []string, targetRepo string, noRegistry bool, serviceManager *artifactory.ArtifactoryServicesManager) error {
 	if !noRegistry {
 		artDetails := serviceManager.GetConfig().GetArtDetails()
-		err := utils.SetGoProxyWithApi(targetRepo, artDetails)
+		var err error
+		ctx, err = utils.WithGoProxyApi(ctx, targetRepo, artDetails)
 		if err != nil {
 			return err
 		}
 	}
 
-	err := cmd.RunGo(goArg)
+	err := cmd.RunGo(ctx, goArg)
 
 	if err != nil {
 		if utils.DependencyNotFoundInArtifactory(err, noRegistry) {
-			log.Info("Received", err.Error(), "from Artifactory. Trying to download dependencies from VCS...")
-			err := os.Unsetenv(utils.GOPROXY)
+			cmd.GetLogger(ctx).Info("Received", err.Error(), "from Artifactory. Trying to download dependencies from VCS...")
+			ctx = utils.WithGoProxyDirect(ctx)
+			err = collectDependenciesAndPublish(ctx, targetRepo, true, &Package{}, serviceManager)
 			if err != nil {
 				return err
 			}
-
-			err = collectDependenciesAndPublish(targetRepo, true,  &Package{}, serviceManager)
-			if err != nil {
-				return err
-			}
-			return cmd.RunGo(goArg)
+			return cmd.RunGo(ctx, goArg)
 		} else {
 			return err
 		}
@@ -1,55 +1,51 @@
 package executers
 
 import (
+	"context"
 	"github.com/jfrog/gocmd/cmd"
 	"github.com/jfrog/gocmd/executers/utils"
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/auth"
 	clientutils "github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
-	"github.com/jfrog/jfrog-client-go/utils/log"
+	clientlog "github.com/jfrog/jfrog-client-go/utils/log"
 	"net/url"
-	"os"
 )
 
 // Run Go with fallback to VCS without publish
-func RunWithFallback(goArg []string, url string) error {
+func RunWithFallback(ctx context.Context, goArg []string, url string) error {
 	serviceManager, err := createGoCentralServiceManager(url)
 	if err != nil {
 		return err
 	}
 
 	artDetails := serviceManager.GetConfig().GetArtDetails()
-	err = setGoProxyWithoutApi(artDetails)
+	proxyCtx, err := withGoProxyWithoutApi(ctx, artDetails)
 	if err != nil {
 		return err
 	}
-	err = cmd.RunGo(goArg)
+	err = cmd.RunGo(proxyCtx, goArg)
 
 	if err != nil {
-		log.Info("Received", err.Error(), "from proxy. Trying to download dependencies from VCS...")
-		err := os.Unsetenv(utils.GOPROXY)
-		if err != nil {
-			return err
-		}
-		return cmd.RunGo(goArg)
+		cmd.GetLogger(ctx).Info("Received", err.Error(), "from proxy. Trying to download dependencies from VCS...")
+		return cmd.RunGo(utils.WithGoProxyDirect(ctx), goArg)
 	}
 	return nil
 }
 
-func setGoProxyWithoutApi(details auth.ArtifactoryDetails) error {
+// Returns a copy of ctx whose go commands use the URL of the details as GOPROXY.
+func withGoProxyWithoutApi(ctx context.Context, details auth.ArtifactoryDetails) (context.Context, error) {
 	rtUrl, err := url.Parse(details.GetUrl())
 	if err != nil {
-		return errorutils.CheckError(err)
+		return nil, errorutils.CheckError(err)
 	}
-	err = os.Setenv(utils.GOPROXY, rtUrl.String())
-	return errorutils.CheckError(err)
+	return cmd.WithEnv(ctx, utils.GOPROXY, rtUrl.String()), nil
 }
 
 func createGoCentralServiceManager(url string) (*artifactory.ArtifactoryServicesManager, error) {
 	artifactoryDetails := auth.NewArtifactoryDetails()
 	artifactoryDetails.SetUrl(clientutils.AddTrailingSlashIfNeeded(url))
-	serviceConfig, err := artifactory.NewConfigBuilder().SetArtDetails(artifactoryDetails).SetDryRun(false).SetLogger(log.Logger).Build()
+	serviceConfig, err := artifactory.NewConfigBuilder().SetArtDetails(artifactoryDetails).SetDryRun(false).SetLogger(clientlog.Logger).Build()
 	if err != nil {
 		return nil, err
 	}
@@ -1,16 +1,18 @@
 package executers
 
 import (
+	"context"
 	"fmt"
 	"github.com/jfrog/gocmd/cache"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/log"
 	"github.com/jfrog/jfrog-client-go/artifactory"
 	"github.com/jfrog/jfrog-client-go/artifactory/buildinfo"
-	"github.com/jfrog/jfrog-client-go/artifactory/services/go"
-	"github.com/jfrog/jfrog-client-go/utils/log"
+	"strings"
 )
 
 type GoPackage interface {
-	PopulateModAndPublish(targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error
+	PopulateModAndPublish(ctx context.Context, targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error
 	Init() error
 	prepareAndPublish(targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error
 	New(cachePath string, dependency Package) GoPackage
@@ -57,12 +59,12 @@ func (dependencyPackage *Package) Init() error {
 	return nil
 }
 
-func (dependencyPackage *Package) PopulateModAndPublish(targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error {
+func (dependencyPackage *Package) PopulateModAndPublish(ctx context.Context, targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error {
 	published, _ := cache.GetMap()[dependencyPackage.GetId()]
 	if !published {
 		return dependencyPackage.prepareAndPublish(targetRepo, cache, serviceManager)
 	} else {
-		log.Debug(fmt.Sprintf("Dependency %s was published previosly to Artifactory", dependencyPackage.GetId()))
+		cmd.GetLogger(ctx).Debug(fmt.Sprintf("Dependency %s was published previosly to Artifactory", dependencyPackage.GetId()))
 	}
 	return nil
 }
@@ -80,20 +82,28 @@ func (dependencyPackage *Package) prepareAndPublish(targetRepo string, cache *ca
 }
 
 func (dependencyPackage *Package) Publish(summary string, targetRepo string, servicesManager *artifactory.ArtifactoryServicesManager) error {
-	message := fmt.Sprintf("Publishing: %s to %s", dependencyPackage.id, targetRepo)
+	return dependencyPackage.PublishWith(summary, targetRepo, NewArtifactoryPublisher(targetRepo, servicesManager))
+}
+
+// Publishes the dependency using the publisher. The target is used for logging only.
+func (dependencyPackage *Package) PublishWith(summary, target string, publisher Publisher) error {
+	message := fmt.Sprintf("Publishing: %s to %s", dependencyPackage.id, target)
 	if summary != "" {
 		message += ":" + summary
 	}
+	if cmd.SkipInDryRun(message) {
+		return nil
+	}
 	log.Info(message)
-	params := _go.NewGoParams()
-	params.ZipPath = dependencyPackage.zipPath
-	params.ModContent = dependencyPackage.modContent
-	params.Version = dependencyPackage.version
-	params.TargetRepo = targetRepo
-	params.ModuleId = dependencyPackage.id
-	params.ModPath = dependencyPackage.modPath
-
-	return servicesManager.PublishGoProject(params)
+	moduleInfo := strings.Split(dependencyPackage.id, ":")
+	module := ModuleFiles{
+		Path:       goModDecode(moduleInfo[0]),
+		Version:    goModDecode(dependencyPackage.version),
+		ZipPath:    dependencyPackage.zipPath,
+		ModPath:    dependencyPackage.modPath,
+		ModContent: dependencyPackage.modContent,
+	}
+	return publisher.PublishModule(module)
 }
 
 func (dependencyPackage *Package) Dependencies() []buildinfo.Dependency {
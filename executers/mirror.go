@@ -0,0 +1,166 @@
+package executers
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/gocmd/proxy"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Options of MirrorModule and MirrorGoSum.
+type MirrorOptions struct {
+	// Mirror the versions even if they already exist in the target registry.
+	Force bool
+	// Lists the versions which already exist in the target registry, which are skipped.
+	// If nil, all the versions are mirrored.
+	Versions VersionLister
+}
+
+// A module version handled by MirrorModule or MirrorGoSum.
+type MirroredModule struct {
+	Path    string
+	Version string
+	// True if the version already existed in the target registry, and wasn't mirrored.
+	Skipped bool
+}
+
+// Copies the module version from the upstream proxy to the target registry of the publisher.
+// The go.sum entries of the version in sums are verified against the downloaded files before publishing,
+// so a tampered version is never mirrored. The other entries are ignored.
+func MirrorModule(ctx context.Context, upstream *proxy.Client, publisher Publisher, modulePath, version string, sums []gosum.ModuleEntry, options MirrorOptions) (MirroredModule, error) {
+	existing, err := listExistingVersions(ctx, modulePath, options)
+	if err != nil {
+		return MirroredModule{}, err
+	}
+	return mirrorModuleVersion(ctx, upstream, publisher, modulePath, version, sums, existing)
+}
+
+// Mirrors all the module versions of the go.sum file from the upstream proxy to the target registry, see MirrorModule.
+// Versions which have only a "/go.mod" entry are mirrored too, since the go command needs their go.mod files.
+func MirrorGoSum(ctx context.Context, upstream *proxy.Client, publisher Publisher, goSumPath string, options MirrorOptions) ([]MirroredModule, error) {
+	entries, err := gosum.ParseGoSumFileFrom(cmd.GetFileSystem(ctx), goSumPath)
+	if err != nil {
+		return nil, err
+	}
+	var mirrored []MirroredModule
+	versions := map[string]bool{}
+	existing := map[string]map[string]bool{}
+	for _, entry := range entries {
+		if versions[entry.ModuleId()] {
+			continue
+		}
+		versions[entry.ModuleId()] = true
+		if _, listed := existing[entry.Path]; !listed {
+			if existing[entry.Path], err = listExistingVersions(ctx, entry.Path, options); err != nil {
+				return mirrored, err
+			}
+		}
+		module, err := mirrorModuleVersion(ctx, upstream, publisher, entry.Path, entry.Version, entries, existing[entry.Path])
+		if err != nil {
+			return mirrored, err
+		}
+		mirrored = append(mirrored, module)
+	}
+	return mirrored, nil
+}
+
+// Returns the versions of the module to skip, which exist in the target registry.
+func listExistingVersions(ctx context.Context, modulePath string, options MirrorOptions) (map[string]bool, error) {
+	existing := map[string]bool{}
+	if options.Versions == nil || options.Force {
+		return existing, nil
+	}
+	versions, err := options.Versions.ListVersions(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range versions {
+		existing[version] = true
+	}
+	return existing, nil
+}
+
+func mirrorModuleVersion(ctx context.Context, upstream *proxy.Client, publisher Publisher, modulePath, version string, sums []gosum.ModuleEntry, existing map[string]bool) (mirrored MirroredModule, err error) {
+	mirrored = MirroredModule{Path: modulePath, Version: version}
+	if existing[version] {
+		cmd.GetLogger(ctx).Debug("Skipping", modulePath+"@"+version, "which already exists in the target registry")
+		mirrored.Skipped = true
+		return mirrored, nil
+	}
+	if cmd.SkipInDryRunWithContext(ctx, "Mirroring "+modulePath+"@"+version) {
+		return mirrored, nil
+	}
+	tempDirs := cmd.GetTempDirProvider(ctx)
+	tempDir, err := tempDirs.MkdirTemp("gocmd-mirror")
+	if err != nil {
+		return mirrored, err
+	}
+	defer func() {
+		if removeErr := tempDirs.Remove(tempDir, err != nil); err == nil {
+			err = removeErr
+		}
+	}()
+	files, err := downloadModuleFiles(ctx, upstream, modulePath, version, tempDir)
+	if err != nil {
+		return mirrored, err
+	}
+	for _, entry := range sums {
+		if entry.Path != modulePath || entry.Version != version {
+			continue
+		}
+		if entry.IsMod {
+			err = gosum.VerifyModFile(entry, files.ModPath)
+		} else {
+			err = gosum.VerifyModuleZip(entry, files.ZipPath)
+		}
+		if err != nil {
+			return mirrored, err
+		}
+	}
+	cmd.GetLogger(ctx).Info("Mirroring", modulePath+"@"+version)
+	return mirrored, publisher.PublishModule(*files)
+}
+
+// Downloads the zip, go.mod and .info files of the module version from the proxy into dir.
+func downloadModuleFiles(ctx context.Context, upstream *proxy.Client, modulePath, version, dir string) (*ModuleFiles, error) {
+	info, err := upstream.Info(ctx, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	infoContent, err := json.Marshal(info)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	modContent, err := upstream.Mod(ctx, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	files := &ModuleFiles{
+		Path:        modulePath,
+		Version:     version,
+		ZipPath:     filepath.Join(dir, "module.zip"),
+		ModPath:     filepath.Join(dir, "go.mod"),
+		ModContent:  modContent,
+		InfoContent: infoContent,
+	}
+	if err = ioutil.WriteFile(files.ModPath, modContent, 0644); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	zipFile, err := os.Create(files.ZipPath)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	err = upstream.Zip(ctx, modulePath, version, zipFile)
+	if closeErr := zipFile.Close(); err == nil {
+		err = errorutils.CheckError(closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
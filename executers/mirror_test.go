@@ -0,0 +1,116 @@
+package executers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/gocmd/proxy"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func createTestZip(t *testing.T, files map[string]string) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = file.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestMirrorGoSum(t *testing.T) {
+	modContent := []byte("module github.com/test/mod\n")
+	zipContent := createTestZip(t, map[string]string{"github.com/test/mod@v1.0.0/go.mod": string(modContent)})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch filepath.Ext(r.URL.Path) {
+		case ".info":
+			w.Write([]byte(`{"Version":"v1.0.0","Time":"2020-01-02T03:04:05Z"}`))
+		case ".mod":
+			w.Write(modContent)
+		case ".zip":
+			w.Write(zipContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	upstream, err := proxy.NewClient(proxy.Proxy{Url: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tempDir, err := ioutil.TempDir("", "mirrorTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	zipPath := filepath.Join(tempDir, "mod.zip")
+	if err = ioutil.WriteFile(zipPath, zipContent, 0644); err != nil {
+		t.Fatal(err)
+	}
+	zipHash, err := gosum.HashZip(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modHash, err := gosum.HashMod(modContent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goSumPath := filepath.Join(tempDir, "go.sum")
+	goSum := "github.com/test/mod v1.0.0 " + zipHash + "\n" +
+		"github.com/test/mod v1.0.0/go.mod " + modHash + "\n" +
+		"github.com/test/mod v0.9.0/go.mod " + modHash + "\n"
+	if err = ioutil.WriteFile(goSumPath, []byte(goSum), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher := &recordingPublisher{}
+	existing := &staticVersionLister{versions: []string{"v0.9.0"}}
+	mirrored, err := MirrorGoSum(context.Background(), upstream, publisher, goSumPath, MirrorOptions{Versions: existing})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []MirroredModule{
+		{Path: "github.com/test/mod", Version: "v1.0.0"},
+		{Path: "github.com/test/mod", Version: "v0.9.0", Skipped: true},
+	}
+	if !reflect.DeepEqual(mirrored, expected) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, mirrored)
+	}
+	if len(publisher.published) != 1 || string(publisher.published[0].ModContent) != string(modContent) || publisher.published[0].Version != "v1.0.0" {
+		t.Errorf("Unexpected published modules: %+v", publisher.published)
+	}
+
+	// A version which doesn't match its go.sum hash isn't mirrored.
+	tampered := []gosum.ModuleEntry{{Path: "github.com/test/mod", Version: "v1.0.0", Hash: modHash}}
+	publisher = &recordingPublisher{}
+	if _, err = MirrorModule(context.Background(), upstream, publisher, "github.com/test/mod", "v1.0.0", tampered, MirrorOptions{}); err == nil {
+		t.Error("Expected a checksum mismatch error")
+	}
+	if len(publisher.published) != 0 {
+		t.Errorf("Expected no published modules, Got: %+v", publisher.published)
+	}
+}
+
+type staticVersionLister struct {
+	versions []string
+}
+
+func (lister *staticVersionLister) ListVersions(ctx context.Context, modulePath string) ([]string, error) {
+	return lister.versions, nil
+}
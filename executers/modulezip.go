@@ -0,0 +1,81 @@
+package executers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/semver"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/zip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Creates a module zip of the module directory, with the .mod and .info files, in the layout expected by a GOPROXY.
+// The zip is created with golang.org/x/mod/zip, as done by the go command: the files inside the zip are prefixed with
+// "modulePath@version/", version control directories, vendored packages, nested modules and non-regular files are not
+// included, and the file paths and sizes are validated.
+// The zip is created in a new temp directory of the TempDirProvider of ctx, which should be removed by the caller
+// with the provider.
+func CreateModuleZip(ctx context.Context, moduleDir, modulePath, version string) (*ModuleFiles, error) {
+	if err := semver.CheckPathMajor(modulePath, version); err != nil {
+		return nil, err
+	}
+	modPath, modContent, err := getModContent(moduleDir, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	infoContent, err := json.Marshal(map[string]string{"Version": version, "Time": time.Now().UTC().Format(time.RFC3339)})
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	tempDirs := cmd.GetTempDirProvider(ctx)
+	tempDir, err := tempDirs.MkdirTemp("gocmd-zip")
+	if err != nil {
+		return nil, err
+	}
+	zipPath := filepath.Join(tempDir, version+".zip")
+	err = writeModuleZip(zipPath, module.Version{Path: modulePath, Version: version}, moduleDir)
+	if err != nil {
+		tempDirs.Remove(tempDir, true)
+		return nil, err
+	}
+	cmd.GetLogger(ctx).Debug("Created module zip of", modulePath+"@"+version, "at", zipPath)
+	return &ModuleFiles{
+		Path:        modulePath,
+		Version:     version,
+		ZipPath:     zipPath,
+		ModPath:     modPath,
+		ModContent:  modContent,
+		InfoContent: infoContent,
+	}, nil
+}
+
+// Returns the path and content of the go.mod file of the module. If the module has none, returns an empty path and
+// a minimal go.mod content.
+func getModContent(moduleDir, modulePath string) (string, []byte, error) {
+	modPath := filepath.Join(moduleDir, "go.mod")
+	modContent, err := ioutil.ReadFile(modPath)
+	if os.IsNotExist(err) {
+		return "", []byte(fmt.Sprintf("module %s\n", modulePath)), nil
+	}
+	return modPath, modContent, errorutils.CheckError(err)
+}
+
+func writeModuleZip(zipPath string, moduleVersion module.Version, moduleDir string) (err error) {
+	zipFile, err := os.Create(zipPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer func() {
+		closeErr := zipFile.Close()
+		if err == nil {
+			err = errorutils.CheckError(closeErr)
+		}
+	}()
+	return errorutils.CheckError(zip.CreateFromDir(zipFile, moduleVersion, moduleDir))
+}
@@ -0,0 +1,149 @@
+package executers
+
+import (
+	"archive/zip"
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/gosum"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestCreateModuleZip(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "moduleZipTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(moduleDir)
+	files := map[string]string{
+		"test.go":            "package test\n\nfunc a",
+		"sub/sub.go":         "package sub\n",
+		".git/config":        "[core]\n",
+		"vendor/modules.txt": "# rsc.io/quote v1.5.2\n",
+		"nested/go.mod":      "module github.com/test/nested\n",
+		"nested/nested.go":   "package nested\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(moduleDir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tempRoot, err := ioutil.TempDir("", "moduleZipTempRoot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempRoot)
+	ctx := cmd.WithTempDirProvider(context.Background(), &cmd.TempDirs{Root: tempRoot})
+	module, err := CreateModuleZip(ctx, moduleDir, "github.com/test", "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(filepath.Dir(module.ZipPath)) != tempRoot {
+		t.Errorf("Expecting the zip to be created in a temp directory of the provider, got: %s", module.ZipPath)
+	}
+	if string(module.ModContent) != "module github.com/test\n" || module.ModPath != "" {
+		t.Errorf("Expecting a synthesized go.mod without a path, got: %s %s", module.ModPath, module.ModContent)
+	}
+	zipReader, err := zip.OpenReader(module.ZipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zipReader.Close()
+	var names []string
+	for _, file := range zipReader.File {
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	expected := []string{"github.com/test@v1.2.3/sub/sub.go", "github.com/test@v1.2.3/test.go", "github.com/test@v1.2.3/vendor/modules.txt"}
+	if !reflect.DeepEqual(expected, names) {
+		t.Errorf("Expected: %v, Got: %v", expected, names)
+	}
+}
+
+func TestCreateModuleZipHash(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "moduleZipTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(moduleDir)
+	baseDir, err := getBaseDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Recreate the module of the test zip, which should result in the same hash.
+	zipReader, err := zip.OpenReader(filepath.Join(baseDir, "zip", "v1.2.3.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zipReader.Close()
+	reader, err := zipReader.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(moduleDir, "test.go"), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	module, err := CreateModuleZip(context.Background(), moduleDir, "github.com/test", "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(filepath.Dir(module.ZipPath))
+	entry := gosum.ModuleEntry{Path: "github.com/test", Version: "v1.2.3", Hash: "h1:xD90HeW8F8sOt8zOweJdoUEwjoFXTN2nFCipo+wQAKQ="}
+	if err = gosum.VerifyModuleZip(entry, module.ZipPath); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCreateModuleZipInvalidVersion(t *testing.T) {
+	_, err := CreateModuleZip(context.Background(), ".", "github.com/test", "1.2.3")
+	if err == nil {
+		t.Error("Expecting an error for a version without the 'v' prefix")
+	}
+}
+
+func TestCreateModuleZipMajorVersionMismatch(t *testing.T) {
+	_, err := CreateModuleZip(context.Background(), ".", "github.com/test", "v2.0.0")
+	if err == nil {
+		t.Error("Expecting an error for a v2 version of a module path without a major version suffix")
+	}
+}
+
+func TestCreateModuleZipCaseCollision(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "moduleZipTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(moduleDir)
+	for _, name := range []string{"go.mod", "test.go", "TEST.go"} {
+		if err = ioutil.WriteFile(filepath.Join(moduleDir, name), []byte("module github.com/test\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tempRoot, err := ioutil.TempDir("", "moduleZipTempRoot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempRoot)
+	ctx := cmd.WithTempDirProvider(context.Background(), &cmd.TempDirs{Root: tempRoot})
+	if _, err = CreateModuleZip(ctx, moduleDir, "github.com/test", "v1.2.3"); err == nil {
+		t.Error("Expecting an error for files which differ only in case")
+	}
+	if dirs, _ := ioutil.ReadDir(tempRoot); len(dirs) != 0 {
+		t.Error("Expecting the temp directory of the zip to be removed")
+	}
+}
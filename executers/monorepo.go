@@ -0,0 +1,226 @@
+package executers
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/modfile"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A module of a repository with several modules.
+type RepoModule struct {
+	Path string
+	Dir  string
+	// The paths of the other modules of the repository which this module requires.
+	Dependencies []string
+}
+
+// A module published by PublishRepoModules.
+type PublishedModule struct {
+	Path    string
+	Dir     string
+	Version string
+}
+
+// The modules of the repository require each other in a cycle, so there's no order to publish them in.
+type ModuleCycleError struct {
+	Modules []string
+}
+
+func (err *ModuleCycleError) Error() string {
+	return "The modules require each other in a cycle: " + strings.Join(err.Modules, ", ")
+}
+
+// Returns the modules of the go.mod files under root, sorted by their paths.
+// Vendor and testdata dirs, and dirs starting with "." or "_", are skipped, as the go command ignores them.
+// The dependencies of each module are the modules it requires, or replaces with local dirs, among the returned modules.
+// The go.mod files are read from the FileSystem of ctx.
+func DiscoverModules(ctx context.Context, root string) ([]RepoModule, error) {
+	dirs, err := cmd.FindModuleDirs(root)
+	if err != nil {
+		return nil, err
+	}
+	var modFiles []*modfile.ModFile
+	var modules []RepoModule
+	for _, dir := range dirs {
+		path := filepath.Join(dir, "go.mod")
+		modFile, err := modfile.ReadFile(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if modFile.Module() == "" {
+			return nil, errorutils.CheckError(fmt.Errorf("%s has no module directive.", path))
+		}
+		modFiles = append(modFiles, modFile)
+		modules = append(modules, RepoModule{Path: modFile.Module(), Dir: dir})
+	}
+	paths := map[string]bool{}
+	for _, module := range modules {
+		paths[module.Path] = true
+	}
+	for i, modFile := range modFiles {
+		dependencies := map[string]bool{}
+		for _, require := range modFile.Requires() {
+			if paths[require.Path] {
+				dependencies[require.Path] = true
+			}
+		}
+		for _, replace := range modFile.Replaces() {
+			if replace.IsLocal() && paths[replace.OldPath] {
+				dependencies[replace.OldPath] = true
+			}
+		}
+		for dependency := range dependencies {
+			modules[i].Dependencies = append(modules[i].Dependencies, dependency)
+		}
+		sort.Strings(modules[i].Dependencies)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Path < modules[j].Path
+	})
+	return modules, nil
+}
+
+// Returns the module of the go.mod files under root whose path is modulePath, to run the operations in its dir with
+// cmd.WithModuleDir.
+func FindModule(ctx context.Context, root, modulePath string) (RepoModule, error) {
+	modules, err := DiscoverModules(ctx, root)
+	if err != nil {
+		return RepoModule{}, err
+	}
+	for _, module := range modules {
+		if module.Path == modulePath {
+			return module, nil
+		}
+	}
+	return RepoModule{}, errorutils.CheckError(fmt.Errorf("Could not find the module %s under %s.", modulePath, root))
+}
+
+// Returns the modules ordered so that each module comes after the modules it depends on.
+// Modules which don't depend on each other keep their order. Returns a ModuleCycleError if there's no such order.
+func SortModules(modules []RepoModule) ([]RepoModule, error) {
+	remaining := map[string]int{}
+	dependents := map[string][]string{}
+	byPath := map[string]RepoModule{}
+	for _, module := range modules {
+		byPath[module.Path] = module
+	}
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			if _, exists := byPath[dependency]; exists {
+				remaining[module.Path]++
+				dependents[dependency] = append(dependents[dependency], module.Path)
+			}
+		}
+	}
+	var sorted []RepoModule
+	done := map[string]bool{}
+	for len(sorted) < len(modules) {
+		progressed := false
+		for _, module := range modules {
+			if done[module.Path] || remaining[module.Path] > 0 {
+				continue
+			}
+			done[module.Path] = true
+			sorted = append(sorted, module)
+			for _, dependent := range dependents[module.Path] {
+				remaining[dependent]--
+			}
+			progressed = true
+			break
+		}
+		if !progressed {
+			var cycle []string
+			for _, module := range modules {
+				if !done[module.Path] {
+					cycle = append(cycle, module.Path)
+				}
+			}
+			return nil, errorutils.CheckError(&ModuleCycleError{Modules: cycle})
+		}
+	}
+	return sorted, nil
+}
+
+// Publishes all the modules under root, each after the modules it depends on.
+// Each module is published with options.Version, or with the version derived from git, see DeriveVersion.
+// Before a module is published, its requirements of the repository's modules are set to the versions they were just
+// published with, and its replace directives of these modules with local dirs are removed, so the published go.mod
+// files are consistent with each other. The hashes of the required versions are added to go.sum with go mod download.
+// The go.mod and go.sum files are restored after publishing.
+func PublishRepoModules(ctx context.Context, root string, publisher Publisher, options PublishOptions) ([]PublishedModule, error) {
+	modules, err := DiscoverModules(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+	if modules, err = SortModules(modules); err != nil {
+		return nil, err
+	}
+	versions := map[string]string{}
+	var published []PublishedModule
+	for _, module := range modules {
+		version := options.Version
+		if version == "" {
+			if version, err = DeriveVersion(ctx, module.Dir, module.Path); err != nil {
+				return published, err
+			}
+		}
+		if err = publishRepoModule(ctx, module, version, versions, publisher, options); err != nil {
+			return published, err
+		}
+		versions[module.Path] = version
+		published = append(published, PublishedModule{Path: module.Path, Dir: module.Dir, Version: version})
+	}
+	return published, nil
+}
+
+func publishRepoModule(ctx context.Context, module RepoModule, version string, versions map[string]string, publisher Publisher, options PublishOptions) (err error) {
+	modPath := filepath.Join(module.Dir, "go.mod")
+	backups, err := cmd.NewFileBackupManagerWithContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rollbackErr := backups.Rollback(); err == nil {
+			err = rollbackErr
+		}
+	}()
+	for _, path := range []string{modPath, filepath.Join(module.Dir, "go.sum")} {
+		if err = backups.Backup(path); err != nil {
+			return err
+		}
+	}
+	modFile, err := modfile.ReadFile(ctx, modPath)
+	if err != nil {
+		return err
+	}
+	for _, dependency := range module.Dependencies {
+		if err = modFile.SetRequire(dependency, versions[dependency]); err != nil {
+			return err
+		}
+	}
+	for _, replace := range modFile.Replaces() {
+		if _, isRepoModule := versions[replace.OldPath]; isRepoModule && replace.IsLocal() {
+			if err = modFile.RemoveReplace(replace.OldPath, replace.OldVersion); err != nil {
+				return err
+			}
+		}
+	}
+	if err = modFile.WriteFile(ctx, modPath); err != nil {
+		return err
+	}
+	// The go.sum file needs the hashes of the versions which were just published, which go mod download adds.
+	moduleCtx := cmd.WithModuleDir(ctx, module.Dir)
+	for _, dependency := range module.Dependencies {
+		if err = cmd.DownloadDependency(moduleCtx, dependency+"@"+versions[dependency]); err != nil {
+			return err
+		}
+	}
+	options.Version = version
+	_, err = PublishModule(ctx, module.Dir, publisher, options)
+	return err
+}
@@ -0,0 +1,141 @@
+package executers
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiscoverModules(t *testing.T) {
+	repoDir := createTestRepo(t, map[string]string{
+		"go.mod":                 "module github.com/test/repo\n\nrequire github.com/test/repo/lib v0.0.0\n\nreplace github.com/test/repo/lib => ./lib\n",
+		"lib/go.mod":             "module github.com/test/repo/lib\n\nrequire github.com/test/repo/util v1.0.0\n",
+		"util/go.mod":            "module github.com/test/repo/util\n\nrequire github.com/other/mod v1.0.0\n",
+		"vendor/dep/go.mod":      "module github.com/test/vendored\n",
+		"lib/testdata/go.mod":    "module github.com/test/testdata\n",
+		".hidden/go.mod":         "module github.com/test/hidden\n",
+		"_examples/ex/go.mod":    "module github.com/test/example\n",
+		"lib/internal/a/file.go": "package a\n",
+	})
+	defer os.RemoveAll(repoDir)
+
+	modules, err := DiscoverModules(context.Background(), repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []RepoModule{
+		{Path: "github.com/test/repo", Dir: repoDir, Dependencies: []string{"github.com/test/repo/lib"}},
+		{Path: "github.com/test/repo/lib", Dir: filepath.Join(repoDir, "lib"), Dependencies: []string{"github.com/test/repo/util"}},
+		{Path: "github.com/test/repo/util", Dir: filepath.Join(repoDir, "util")},
+	}
+	if !reflect.DeepEqual(modules, expected) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, modules)
+	}
+}
+
+func TestSortModules(t *testing.T) {
+	tests := []struct {
+		name     string
+		modules  []RepoModule
+		expected []string
+		cycle    bool
+	}{
+		{"independent", []RepoModule{{Path: "a"}, {Path: "b"}}, []string{"a", "b"}, false},
+		{"chain", []RepoModule{{Path: "a", Dependencies: []string{"b"}}, {Path: "b", Dependencies: []string{"c"}}, {Path: "c"}}, []string{"c", "b", "a"}, false},
+		{"diamond", []RepoModule{{Path: "a", Dependencies: []string{"b", "c"}}, {Path: "b", Dependencies: []string{"d"}}, {Path: "c", Dependencies: []string{"d"}}, {Path: "d"}}, []string{"d", "b", "c", "a"}, false},
+		{"unknown dependency", []RepoModule{{Path: "a", Dependencies: []string{"x"}}}, []string{"a"}, false},
+		{"cycle", []RepoModule{{Path: "a", Dependencies: []string{"b"}}, {Path: "b", Dependencies: []string{"a"}}, {Path: "c"}}, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sorted, err := SortModules(test.modules)
+			if test.cycle {
+				cycleErr, ok := err.(*ModuleCycleError)
+				if !ok || !reflect.DeepEqual(cycleErr.Modules, []string{"a", "b"}) {
+					t.Errorf("Expected a ModuleCycleError of a and b, Got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			for _, module := range sorted {
+				paths = append(paths, module.Path)
+			}
+			if !reflect.DeepEqual(paths, test.expected) {
+				t.Errorf("Expected: %v, Got: %v", test.expected, paths)
+			}
+		})
+	}
+}
+
+func TestPublishRepoModules(t *testing.T) {
+	rootMod := "module github.com/test/repo\n\nrequire github.com/test/repo/lib v0.0.0\n\nreplace github.com/test/repo/lib => ./lib\n"
+	repoDir := createTestRepo(t, map[string]string{
+		"go.mod":     rootMod,
+		"repo.go":    "package repo\n",
+		"lib/go.mod": "module github.com/test/repo/lib\n",
+		"lib/lib.go": "package lib\n",
+	})
+	defer os.RemoveAll(repoDir)
+	publisher := &recordingPublisher{}
+	executor := cmd.NewFakeExecutor()
+	ctx := cmd.WithOptions(context.Background(), &cmd.Options{Executor: executor})
+
+	published, err := PublishRepoModules(ctx, repoDir, publisher, PublishOptions{Version: "v1.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []PublishedModule{
+		{Path: "github.com/test/repo/lib", Dir: filepath.Join(repoDir, "lib"), Version: "v1.2.0"},
+		{Path: "github.com/test/repo", Dir: repoDir, Version: "v1.2.0"},
+	}
+	if !reflect.DeepEqual(published, expected) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, published)
+	}
+	if len(publisher.published) != 2 {
+		t.Fatalf("Expected 2 published modules, Got: %+v", publisher.published)
+	}
+	modContent := string(publisher.published[1].ModContent)
+	if !strings.Contains(modContent, "github.com/test/repo/lib v1.2.0") || strings.Contains(modContent, "replace") {
+		t.Errorf("Unexpected published go.mod:\n%s", modContent)
+	}
+	// The go.mod file is restored after publishing.
+	content, err := ioutil.ReadFile(filepath.Join(repoDir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != rootMod {
+		t.Errorf("Expected go.mod to be restored, Got:\n%s", content)
+	}
+	// The hashes of the published lib are added to go.sum before publishing the root module.
+	calls := executor.Calls()
+	if len(calls) != 1 || calls[0].Dir != repoDir || strings.Join(calls[0].Cmd[1:], " ") != "mod download -json github.com/test/repo/lib@v1.2.0" {
+		t.Errorf("Expected go mod download of github.com/test/repo/lib@v1.2.0 in %s, Got: %+v", repoDir, calls)
+	}
+}
+
+func TestFindModule(t *testing.T) {
+	repoDir := createTestRepo(t, map[string]string{
+		"src/go.mod":   "module github.com/test/repo\n",
+		"tools/go.mod": "module github.com/test/repo/tools\n",
+	})
+	defer os.RemoveAll(repoDir)
+
+	module, err := FindModule(context.Background(), repoDir, "github.com/test/repo/tools")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if module.Dir != filepath.Join(repoDir, "tools") {
+		t.Errorf("Unexpected module dir: %s", module.Dir)
+	}
+	if _, err = FindModule(context.Background(), repoDir, "github.com/test/other"); err == nil {
+		t.Error("Expecting an error for a module which isn't in the repository")
+	}
+}
@@ -1,6 +1,7 @@
 package executers
 
 import (
+	"context"
 	"fmt"
 	"github.com/jfrog/gocmd/cache"
 	"github.com/jfrog/gocmd/cmd"
@@ -10,7 +11,6 @@ import (
 	"github.com/jfrog/jfrog-client-go/httpclient"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
 	"github.com/jfrog/jfrog-client-go/utils/io/fileutils"
-	"github.com/jfrog/jfrog-client-go/utils/log"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -31,7 +31,7 @@ type PackageWithDeps struct {
 }
 
 // Populates and publish the dependencies.
-func RecursivePublish(targetRepo, goModEditMessage string, serviceManager *artifactory.ArtifactoryServicesManager) error {
+func RecursivePublish(ctx context.Context, targetRepo, goModEditMessage string, serviceManager *artifactory.ArtifactoryServicesManager) error {
 	err := fileutils.CreateTempDirPath()
 	if err != nil {
 		return err
@@ -42,7 +42,7 @@ func RecursivePublish(targetRepo, goModEditMessage string, serviceManager *artif
 	if err != nil {
 		return err
 	}
-	collectDependenciesAndPublish(targetRepo, false, pwd, serviceManager)
+	collectDependenciesAndPublish(ctx, targetRepo, false, pwd, serviceManager)
 	return nil
 }
 
@@ -55,32 +55,33 @@ func (pwd *PackageWithDeps) New(cachePath string, dependency Package) GoPackage
 }
 
 // Populate the mod file and publish the dependency and it's transitive dependencies to Artifactory
-func (pwd *PackageWithDeps) PopulateModAndPublish(targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error {
+func (pwd *PackageWithDeps) PopulateModAndPublish(ctx context.Context, targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error {
 	var path string
-	log.Debug("Starting to work on", pwd.Dependency.GetId())
+	cmd.GetLogger(ctx).Debug("Starting to work on", pwd.Dependency.GetId())
 	serviceManager.GetConfig().GetArtDetails()
 	dependenciesMap := cache.GetMap()
 	published, _ := dependenciesMap[pwd.Dependency.GetId()]
 	if published {
-		log.Debug("Overwriting the mod file in the cache from the one from Artifactory", pwd.Dependency.GetId())
+		cmd.GetLogger(ctx).Debug("Overwriting the mod file in the cache from the one from Artifactory", pwd.Dependency.GetId())
 		moduleAndVersion := strings.Split(pwd.Dependency.GetId(), ":")
 		client, err := httpclient.ClientBuilder().Build()
 		if err != nil {
 			return err
 		}
-		path = downloadModFileFromArtifactoryToLocalCache(pwd.cachePath, targetRepo, moduleAndVersion[0], moduleAndVersion[1], serviceManager.GetConfig().GetArtDetails(), client)
+		path = downloadModFileFromArtifactoryToLocalCache(ctx, pwd.cachePath, targetRepo, moduleAndVersion[0], moduleAndVersion[1], serviceManager.GetConfig().GetArtDetails(), client)
 		err = pwd.updateModContent(path, cache)
 		utils.LogError(err)
 	}
 
 	// Checks if mod is empty, need to run go mod tidy command to populate the mod file.
-	log.Debug(fmt.Sprintf("Dependency %s mod file is empty: %t", pwd.Dependency.GetId(), !pwd.PatternMatched(pwd.regExp.GetNotEmptyModRegex())))
+	cmd.GetLogger(ctx).Debug(fmt.Sprintf("Dependency %s mod file is empty: %t", pwd.Dependency.GetId(), !pwd.PatternMatched(pwd.regExp.GetNotEmptyModRegex())))
 
 	// Creates the dependency in the temp folder and runs go commands: go mod tidy and go mod graph.
 	// Returns the path to the project in the temp and the a map with the project dependencies
-	path, output, err := pwd.createDependencyAndPrepareMod(cache)
+	ctx = utils.WithGoProxyDirect(ctx)
+	path, output, err := pwd.createDependencyAndPrepareMod(ctx, cache)
 	utils.LogError(err)
-	pwd.publishDependencyAndPopulateTransitive(path, targetRepo, output, cache, serviceManager)
+	pwd.publishDependencyAndPopulateTransitive(ctx, path, targetRepo, output, cache, serviceManager)
 	return nil
 }
 
@@ -120,7 +121,7 @@ func (pwd *PackageWithDeps) PatternMatched(regExp *regexp.Regexp) bool {
 
 // Creates the dependency in the temp folder and runs go mod tidy and go mod graph
 // Returns the path to the project in the temp and the a map with the project dependencies
-func (pwd *PackageWithDeps) createDependencyAndPrepareMod(cache *cache.DependenciesCache) (path string, output map[string]bool, err error) {
+func (pwd *PackageWithDeps) createDependencyAndPrepareMod(ctx context.Context, cache *cache.DependenciesCache) (path string, output map[string]bool, err error) {
 	path, err = pwd.getModPathAndUnzipDependency(path)
 	if err != nil {
 		return
@@ -128,56 +129,56 @@ func (pwd *PackageWithDeps) createDependencyAndPrepareMod(cache *cache.Dependenc
 	pwd.shouldRevertToEmptyMod = false
 	// Check the mod in the cache if empty or not
 	if pwd.PatternMatched(pwd.regExp.GetNotEmptyModRegex()) {
-		err = pwd.useCachedMod(path)
+		err = pwd.useCachedMod(ctx, path)
 		if err != nil {
 			return
 		}
 	} else {
 		published, _ := cache.GetMap()[pwd.Dependency.GetId()]
 		if !published {
-			output, err = pwd.prepareUnpublishedDependency(path)
+			output, err = pwd.prepareUnpublishedDependency(ctx, path)
 			return
 		} else {
-			pwd.prepareResolvedDependency(path)
+			pwd.prepareResolvedDependency(ctx, path)
 		}
 	}
-	output, err = runGoModGraph()
+	output, err = runGoModGraph(ctx)
 	return
 }
 
-func (pwd *PackageWithDeps) prepareResolvedDependency(path string) {
+func (pwd *PackageWithDeps) prepareResolvedDependency(ctx context.Context, path string) {
 	// Put the mod file to temp
-	err := writeModContentToModFile(path, pwd.Dependency.GetModContent())
+	err := writeModContentToModFile(ctx, path, pwd.Dependency.GetModContent())
 	utils.LogError(err)
 	// If not empty --> use the mod file and don't run go mod tidy
 	// If empty --> Run go mod tidy. Publish the package with empty mod file.
 	if !pwd.PatternMatched(pwd.regExp.GetNotEmptyModRegex()) {
-		log.Debug("The mod still empty after downloading from Artifactory:", pwd.Dependency.GetId())
+		cmd.GetLogger(ctx).Debug("The mod still empty after downloading from Artifactory:", pwd.Dependency.GetId())
 		originalModContent := pwd.Dependency.GetModContent()
-		pwd.prepareAndRunTidy(path, originalModContent)
+		pwd.prepareAndRunTidy(ctx, path, originalModContent)
 	} else {
-		log.Debug("Project mod file is not empty after downloading from Artifactory", pwd.Dependency.id)
+		cmd.GetLogger(ctx).Debug("Project mod file is not empty after downloading from Artifactory", pwd.Dependency.id)
 	}
 }
 
-func (pwd *PackageWithDeps) prepareAndRunTidy(path string, originalModContent []byte) {
-	err := populateModWithTidy(path)
+func (pwd *PackageWithDeps) prepareAndRunTidy(ctx context.Context, path string, originalModContent []byte) {
+	err := populateModWithTidy(ctx, path)
 	utils.LogError(err)
-	err = pwd.writeModContentToGoCache()
+	err = pwd.writeModContentToGoCache(ctx)
 	utils.LogError(err)
 	pwd.shouldRevertToEmptyMod = true
 	pwd.originalModContent = originalModContent
 }
 
-func (pwd *PackageWithDeps) prepareUnpublishedDependency(pathToModFile string) (output map[string]bool, err error) {
-	err = pwd.prepareAndRunInit(pathToModFile)
+func (pwd *PackageWithDeps) prepareUnpublishedDependency(ctx context.Context, pathToModFile string) (output map[string]bool, err error) {
+	err = pwd.prepareAndRunInit(ctx, pathToModFile)
 	if err != nil {
-		log.Error(err)
+		cmd.GetLogger(ctx).Error(err)
 		exists, err := fileutils.IsFileExists(pathToModFile, false)
 		utils.LogError(err)
 		if !exists {
 			// Create a mod file
-			err = writeModContentToModFile(pathToModFile, pwd.Dependency.GetModContent())
+			err = writeModContentToModFile(ctx, pathToModFile, pwd.Dependency.GetModContent())
 			utils.LogError(err)
 		}
 	}
@@ -189,48 +190,44 @@ func (pwd *PackageWithDeps) prepareUnpublishedDependency(pathToModFile string) (
 	// If not empty --> use the mod file and don't run go mod tidy
 	// If empty --> Run go mod tidy. Publish the package with empty mod file.
 	if !pwd.PatternMatched(pwd.regExp.GetNotEmptyModRegex()) {
-		log.Debug("The mod still empty after running 'go mod init' for:", pwd.Dependency.GetId())
-		pwd.prepareAndRunTidy(pathToModFile, originalModContent)
-		output, err = runGoModGraph()
+		cmd.GetLogger(ctx).Debug("The mod still empty after running 'go mod init' for:", pwd.Dependency.GetId())
+		pwd.prepareAndRunTidy(ctx, pathToModFile, originalModContent)
+		output, err = runGoModGraph(ctx)
 		return
 	} else {
-		log.Debug("Project mod file after init is not empty", pwd.Dependency.id)
-		pwd.signModFile()
-		output, err = runGoModGraph()
+		cmd.GetLogger(ctx).Debug("Project mod file after init is not empty", pwd.Dependency.id)
+		pwd.signModFile(ctx)
+		output, err = runGoModGraph(ctx)
 		if err != nil {
-			log.Debug(fmt.Sprintf("Command go mod graph finished with the following error: %s for dependency %s", err.Error(), pwd.Dependency.GetId()))
+			cmd.GetLogger(ctx).Debug(fmt.Sprintf("Command go mod graph finished with the following error: %s for dependency %s", err.Error(), pwd.Dependency.GetId()))
 			// Graph failed after init. Lets return to empty mod and then run tidy on it and graph again.
 			// First create an empty mod.
-			utils.LogError(writeModContentToModFile(pathToModFile, originalModContent))
+			utils.LogError(writeModContentToModFile(ctx, pathToModFile, originalModContent))
 			pwd.Dependency.SetModContent(originalModContent)
-			pwd.prepareAndRunTidy(pathToModFile, originalModContent)
-			output, err = runGoModGraph()
+			pwd.prepareAndRunTidy(ctx, pathToModFile, originalModContent)
+			output, err = runGoModGraph(ctx)
 		} else {
-			err := pwd.writeModContentToGoCache()
+			err := pwd.writeModContentToGoCache(ctx)
 			utils.LogError(err)
 		}
 	}
 	return
 }
 
-func (pwd *PackageWithDeps) useCachedMod(path string) error {
+func (pwd *PackageWithDeps) useCachedMod(ctx context.Context, path string) error {
 	// Mod not empty in the cache. Use it.
-	log.Debug("Using the mod in the cache since not empty:", pwd.Dependency.GetId())
-	err := writeModContentToModFile(path, pwd.Dependency.GetModContent())
+	cmd.GetLogger(ctx).Debug("Using the mod in the cache since not empty:", pwd.Dependency.GetId())
+	err := writeModContentToModFile(ctx, path, pwd.Dependency.GetModContent())
 	utils.LogError(err)
 	err = os.Chdir(filepath.Dir(path))
 	if errorutils.CheckError(err) != nil {
 		return err
 	}
-	utils.LogError(removeGoSum(path))
+	utils.LogError(removeGoSum(ctx, path))
 	return nil
 }
 
 func (pwd *PackageWithDeps) getModPathAndUnzipDependency(path string) (string, error) {
-	err := os.Unsetenv(utils.GOPROXY)
-	if err != nil {
-		return "", err
-	}
 	// Unzips the zip file into temp
 	tempDir, err := createDependencyInTemp(pwd.Dependency.GetZipPath())
 	if err != nil {
@@ -240,15 +237,15 @@ func (pwd *PackageWithDeps) getModPathAndUnzipDependency(path string) (string, e
 	return path, err
 }
 
-func (pwd *PackageWithDeps) prepareAndRunInit(pathToModFile string) error {
-	log.Debug("Preparing to init", pathToModFile)
+func (pwd *PackageWithDeps) prepareAndRunInit(ctx context.Context, pathToModFile string) error {
+	cmd.GetLogger(ctx).Debug("Preparing to init", pathToModFile)
 	err := os.Chdir(filepath.Dir(pathToModFile))
 	if errorutils.CheckError(err) != nil {
 		return err
 	}
 	exists, err := fileutils.IsFileExists(pathToModFile, false)
 	utils.LogError(err)
-	if exists {
+	if exists && !cmd.SkipInDryRunWithContext(ctx, "Removing file: "+pathToModFile) {
 		err = os.Remove(pathToModFile)
 		utils.LogError(err)
 	}
@@ -256,10 +253,13 @@ func (pwd *PackageWithDeps) prepareAndRunInit(pathToModFile string) error {
 	// If empty, run go mod init
 	moduleId := pwd.Dependency.GetId()
 	moduleInfo := strings.Split(moduleId, ":")
-	return cmd.RunGoModInit(goModDecode(moduleInfo[0]))
+	return cmd.RunGoModInit(ctx, goModDecode(moduleInfo[0]))
 }
 
-func writeModContentToModFile(path string, modContent []byte) error {
+func writeModContentToModFile(ctx context.Context, path string, modContent []byte) error {
+	if cmd.SkipInDryRunWithContext(ctx, "Writing file: "+path) {
+		return nil
+	}
 	return ioutil.WriteFile(path, modContent, 0700)
 }
 
@@ -276,27 +276,27 @@ func (pwd *PackageWithDeps) getModPathInTemp(tempDir string) string {
 	return path
 }
 
-func (pwd *PackageWithDeps) publishDependencyAndPopulateTransitive(pathToModFile, targetRepo string, graphDependencies map[string]bool, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error {
+func (pwd *PackageWithDeps) publishDependencyAndPopulateTransitive(ctx context.Context, pathToModFile, targetRepo string, graphDependencies map[string]bool, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) error {
 	// If the mod is not empty, populate transitive dependencies
 	if len(graphDependencies) > 0 {
-		sumFileContent, sumFileStat, err := cmd.GetSumContentAndRemove(filepath.Dir(pathToModFile))
+		sumFileContent, sumFileStat, err := cmd.GetSumContentAndRemoveWithContext(ctx, filepath.Dir(pathToModFile))
 		utils.LogError(err)
-		pwd.setTransitiveDependencies(targetRepo, graphDependencies, cache, serviceManager.GetConfig().GetArtDetails())
+		pwd.setTransitiveDependencies(ctx, targetRepo, graphDependencies, cache, serviceManager.GetConfig().GetArtDetails())
 		if len(sumFileContent) > 0 && sumFileStat != nil {
-			cmd.RestoreSumFile(filepath.Dir(pathToModFile), sumFileContent, sumFileStat)
+			cmd.RestoreSumFileWithContext(ctx, filepath.Dir(pathToModFile), sumFileContent, sumFileStat)
 		}
 	}
 
 	published, _ := cache.GetMap()[pwd.Dependency.GetId()]
 	// Populate and publish the transitive dependencies.
 	if pwd.transitiveDependencies != nil {
-		pwd.populateTransitive(targetRepo, cache, serviceManager)
+		pwd.populateTransitive(ctx, targetRepo, cache, serviceManager)
 	}
 
 	if !published && pwd.shouldRevertToEmptyMod {
-		log.Debug("Reverting to the original mod of", pwd.Dependency.GetId())
+		cmd.GetLogger(ctx).Debug("Reverting to the original mod of", pwd.Dependency.GetId())
 		pwd.Dependency.SetModContent(pwd.originalModContent)
-		err := pwd.writeModContentToGoCache()
+		err := pwd.writeModContentToGoCache(ctx)
 		utils.LogError(err)
 	}
 	// Publish to Artifactory the dependency if needed.
@@ -308,7 +308,7 @@ func (pwd *PackageWithDeps) publishDependencyAndPopulateTransitive(pathToModFile
 	// Remove from temp folder the dependency.
 	err := os.RemoveAll(filepath.Dir(pathToModFile))
 	if errorutils.CheckError(err) != nil {
-		log.Error(fmt.Sprintf("Removing the following directory %s has encountred an error: %s", err, filepath.Dir(pathToModFile)))
+		cmd.GetLogger(ctx).Error(fmt.Sprintf("Removing the following directory %s has encountred an error: %s", err, filepath.Dir(pathToModFile)))
 	}
 
 	return nil
@@ -322,7 +322,7 @@ func (pwd *PackageWithDeps) prepareAndPublish(targetRepo string, cache *cache.De
 	return err
 }
 
-func (pwd *PackageWithDeps) setTransitiveDependencies(targetRepo string, graphDependencies map[string]bool, cache *cache.DependenciesCache, auth auth.ArtifactoryDetails) {
+func (pwd *PackageWithDeps) setTransitiveDependencies(ctx context.Context, targetRepo string, graphDependencies map[string]bool, cache *cache.DependenciesCache, auth auth.ArtifactoryDetails) {
 	var dependencies []PackageWithDeps
 	for transitiveDependency := range graphDependencies {
 		module := strings.Split(transitiveDependency, "@")
@@ -350,7 +350,7 @@ func (pwd *PackageWithDeps) setTransitiveDependencies(targetRepo string, graphDe
 				}
 				if dep == nil {
 					// Dependency is missing in the local cache. Need to download it...
-					dep, err = downloadAndCreateDependency(pwd.cachePath, name, version, transitiveDependency, targetRepo, downloadedFromArtifactory, auth)
+					dep, err = downloadAndCreateDependency(ctx, pwd.cachePath, name, version, transitiveDependency, targetRepo, downloadedFromArtifactory, auth)
 					utils.LogError(err)
 					if err != nil {
 						continue
@@ -358,7 +358,7 @@ func (pwd *PackageWithDeps) setTransitiveDependencies(targetRepo string, graphDe
 				}
 
 				if dep != nil {
-					log.Debug(fmt.Sprintf("Dependency %s has transitive dependency %s", pwd.Dependency.GetId(), dep.GetId()))
+					cmd.GetLogger(ctx).Debug(fmt.Sprintf("Dependency %s has transitive dependency %s", pwd.Dependency.GetId(), dep.GetId()))
 					depsWithTrans := &PackageWithDeps{Dependency: dep,
 						regExp:           pwd.regExp,
 						cachePath:        pwd.cachePath,
@@ -367,38 +367,41 @@ func (pwd *PackageWithDeps) setTransitiveDependencies(targetRepo string, graphDe
 					dependenciesMap[name+":"+version] = downloadedFromArtifactory
 				}
 			} else {
-				log.Debug("Dependency", transitiveDependency, "has been previously added.")
+				cmd.GetLogger(ctx).Debug("Dependency", transitiveDependency, "has been previously added.")
 			}
 		}
 	}
 	pwd.transitiveDependencies = dependencies
 }
 
-func (pwd *PackageWithDeps) writeModContentToGoCache() error {
+func (pwd *PackageWithDeps) writeModContentToGoCache(ctx context.Context) error {
 	moduleAndVersion := strings.Split(pwd.Dependency.GetId(), ":")
 	pathToModule := strings.Split(moduleAndVersion[0], "/")
 	path := filepath.Join(pwd.cachePath, strings.Join(pathToModule, fileutils.GetFileSeparator()), "@v", moduleAndVersion[1]+".mod")
+	if cmd.SkipInDryRunWithContext(ctx, "Writing file: "+path) {
+		return nil
+	}
 	err := ioutil.WriteFile(path, pwd.Dependency.GetModContent(), 0700)
 	return errorutils.CheckError(err)
 }
 
 // Runs over the transitive dependencies, populate the mod files of those transitive dependencies
-func (pwd *PackageWithDeps) populateTransitive(targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) {
+func (pwd *PackageWithDeps) populateTransitive(ctx context.Context, targetRepo string, cache *cache.DependenciesCache, serviceManager *artifactory.ArtifactoryServicesManager) {
 	cache.IncrementTotal(len(pwd.transitiveDependencies))
 	for _, transitiveDep := range pwd.transitiveDependencies {
 		published, _ := cache.GetMap()[transitiveDep.Dependency.GetId()]
 		if !published {
-			log.Debug("Starting to work on transitive dependency:", transitiveDep.Dependency.GetId())
-			transitiveDep.PopulateModAndPublish(targetRepo, cache, serviceManager)
+			cmd.GetLogger(ctx).Debug("Starting to work on transitive dependency:", transitiveDep.Dependency.GetId())
+			transitiveDep.PopulateModAndPublish(ctx, targetRepo, cache, serviceManager)
 		} else {
 			cache.IncrementSuccess()
-			log.Debug("The dependency", transitiveDep.Dependency.GetId(), "was already handled")
+			cmd.GetLogger(ctx).Debug("The dependency", transitiveDep.Dependency.GetId(), "was already handled")
 		}
 	}
 }
 
-func (pwd *PackageWithDeps) signModFile() {
-	log.Debug("Signing mod file for", pwd.Dependency.GetId())
+func (pwd *PackageWithDeps) signModFile(ctx context.Context) {
+	cmd.GetLogger(ctx).Debug("Signing mod file for", pwd.Dependency.GetId())
 	newContent := append([]byte(pwd.GoModEditMessage+"\n\n"), pwd.Dependency.GetModContent()...)
 	pwd.Dependency.SetModContent(newContent)
 }
@@ -0,0 +1,220 @@
+package executers
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/modfile"
+	"github.com/jfrog/gocmd/proxy"
+	"github.com/jfrog/gocmd/semver"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options of PublishModule.
+type PublishOptions struct {
+	// The version to publish. If empty, the version is derived from the git state of the module, see DeriveVersion.
+	Version string
+	// Publish the version even if it already exists in the registry, overwriting it.
+	Force bool
+	// Lists the versions which already exist in the registry. If nil, the existing versions are not checked.
+	Versions VersionLister
+}
+
+// Lists the versions of a module which exist in a registry.
+type VersionLister interface {
+	ListVersions(ctx context.Context, modulePath string) ([]string, error)
+}
+
+// Lists the versions of modules in a GOPROXY, with the list endpoint of its proxy protocol client.
+type ProxyVersionLister struct {
+	Client *proxy.Client
+}
+
+// Creates a lister of the versions in the GOPROXY at url, such as https://mycorp.jfrog.io/artifactory/api/go/go-local.
+func NewProxyVersionLister(url string) *ProxyVersionLister {
+	return &ProxyVersionLister{Client: &proxy.Client{Proxy: proxy.Proxy{Url: url}}}
+}
+
+// Returns the versions listed by the proxy. A module which doesn't exist in the proxy has no versions.
+func (lister *ProxyVersionLister) ListVersions(ctx context.Context, modulePath string) ([]string, error) {
+	return lister.Client.List(ctx, modulePath)
+}
+
+// The version already exists in the registry, and publishing wasn't forced.
+type VersionExistsError struct {
+	Module  string
+	Version string
+}
+
+func (err *VersionExistsError) Error() string {
+	return fmt.Sprintf("%s@%s already exists in the registry. Publish with force to overwrite it.", err.Module, err.Version)
+}
+
+// Publishes the module in moduleDir with the publisher, and returns the published version.
+func PublishModule(ctx context.Context, moduleDir string, publisher Publisher, options PublishOptions) (version string, err error) {
+	modFile, err := modfile.ReadFile(ctx, filepath.Join(moduleDir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	modulePath := modFile.Module()
+	if modulePath == "" {
+		return "", errorutils.CheckError(fmt.Errorf("The go.mod file in %s has no module directive.", moduleDir))
+	}
+	version = options.Version
+	if version == "" {
+		if version, err = DeriveVersion(ctx, moduleDir, modulePath); err != nil {
+			return "", err
+		}
+		cmd.GetLogger(ctx).Info("Derived version", version, "of", modulePath, "from git")
+	}
+	if !semver.IsValid(version) {
+		return "", errorutils.CheckError(fmt.Errorf("Invalid version: %q. Expecting a semantic version such as v1.2.3.", version))
+	}
+	if err = checkExistingVersions(ctx, modulePath, version, options); err != nil {
+		return "", err
+	}
+
+	if cmd.SkipInDryRunWithContext(ctx, "Publishing "+modulePath+"@"+version) {
+		return version, nil
+	}
+	files, err := CreateModuleZip(ctx, moduleDir, modulePath, version)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if removeErr := cmd.GetTempDirProvider(ctx).Remove(filepath.Dir(files.ZipPath), err != nil); err == nil {
+			err = removeErr
+		}
+	}()
+	cmd.GetLogger(ctx).Info("Publishing", modulePath+"@"+version)
+	start := time.Now()
+	cmd.EmitEvent(ctx, cmd.Event{Type: cmd.PublishStartedEvent, Module: modulePath + "@" + version})
+	err = publisher.PublishModule(*files)
+	cmd.EmitEvent(ctx, cmd.Event{Type: cmd.PublishCompletedEvent, Module: modulePath + "@" + version, Duration: time.Since(start), Err: err})
+	return version, err
+}
+
+// Returns a VersionExistsError if the version exists in the registry and publishing isn't forced.
+// Publishing a version lower than the highest existing one is allowed, as when releasing a patch of an older minor version.
+func checkExistingVersions(ctx context.Context, modulePath, version string, options PublishOptions) error {
+	if options.Versions == nil {
+		return nil
+	}
+	versions, err := options.Versions.ListVersions(ctx, modulePath)
+	if err != nil {
+		return err
+	}
+	highest := ""
+	for _, existing := range versions {
+		if existing == version && !options.Force {
+			return errorutils.CheckError(&VersionExistsError{Module: modulePath, Version: version})
+		}
+		if semver.IsValid(existing) && (highest == "" || semver.Compare(existing, highest) > 0) {
+			highest = existing
+		}
+	}
+	if highest != "" && semver.Compare(version, highest) < 0 {
+		cmd.GetLogger(ctx).Warn("Publishing", modulePath+"@"+version, "which is lower than the highest existing version", highest)
+	}
+	return nil
+}
+
+// Derives the version of the module in moduleDir from the git state of its repository.
+// If HEAD is tagged with a version matching the major version of the module path, that version is returned.
+// Otherwise, the pseudo-version of HEAD is returned, based on the latest such tag merged into HEAD.
+// The tags of a module in a subdirectory of the repository are prefixed by the subdirectory, such as "sub/v1.2.3".
+func DeriveVersion(ctx context.Context, moduleDir, modulePath string) (string, error) {
+	root, err := runGit(ctx, moduleDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	tagPrefix, err := getTagPrefix(root, moduleDir)
+	if err != nil {
+		return "", err
+	}
+	headTags, err := runGit(ctx, moduleDir, "tag", "--points-at", "HEAD", "--list", tagPrefix+"v*")
+	if err != nil {
+		return "", err
+	}
+	if version := highestTaggedVersion(headTags, tagPrefix, modulePath); version != "" {
+		return version, nil
+	}
+
+	mergedTags, err := runGit(ctx, moduleDir, "tag", "--merged", "HEAD", "--list", tagPrefix+"v*")
+	if err != nil {
+		return "", err
+	}
+	commit, err := runGit(ctx, moduleDir, "log", "-1", "--format=%H %ct", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(commit)
+	if len(fields) != 2 {
+		return "", errorutils.CheckError(fmt.Errorf("Unexpected output of git log: %q", commit))
+	}
+	commitTime, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	major := ""
+	if _, pathMajor, ok := semver.SplitPathMajor(modulePath); ok && pathMajor != "" {
+		major = pathMajor[1:]
+	}
+	return semver.NewPseudoVersion(major, highestTaggedVersion(mergedTags, tagPrefix, modulePath), time.Unix(commitTime, 0), fields[0])
+}
+
+// Returns the prefix of the module's tags, which is the module's dir relative to the repository root, followed by a slash.
+func getTagPrefix(root, moduleDir string) (string, error) {
+	absModuleDir, err := filepath.Abs(moduleDir)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	// Resolve symbolic links, such as a temp dir, as git does for the repository root.
+	if resolved, err := filepath.EvalSymlinks(absModuleDir); err == nil {
+		absModuleDir = resolved
+	}
+	relative, err := filepath.Rel(filepath.FromSlash(root), absModuleDir)
+	if err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	if relative == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(relative) + "/", nil
+}
+
+// Returns the highest release or prerelease version among the tags, which matches the major version of the module path.
+func highestTaggedVersion(tags, tagPrefix, modulePath string) string {
+	highest := ""
+	for _, tag := range strings.Fields(tags) {
+		version := strings.TrimPrefix(tag, tagPrefix)
+		kind := semver.Classify(version)
+		if kind != semver.Release && kind != semver.Prerelease {
+			continue
+		}
+		if semver.CheckPathMajor(modulePath, version) != nil {
+			continue
+		}
+		if highest == "" || semver.Compare(version, highest) > 0 {
+			highest = version
+		}
+	}
+	return highest
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	gitCmd := exec.CommandContext(ctx, "git", args...)
+	gitCmd.Dir = dir
+	var stderr strings.Builder
+	gitCmd.Stderr = &stderr
+	output, err := gitCmd.Output()
+	if err != nil {
+		return "", errorutils.CheckError(fmt.Errorf("git %s failed: %s %s", strings.Join(args, " "), err.Error(), strings.TrimSpace(stderr.String())))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,136 @@
+package executers
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+type recordingPublisher struct {
+	published []ModuleFiles
+}
+
+func (publisher *recordingPublisher) PublishModule(module ModuleFiles) error {
+	publisher.published = append(publisher.published, module)
+	return nil
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) {
+	gitCmd := exec.Command("git", args...)
+	gitCmd.Dir = dir
+	gitCmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+	if output, err := gitCmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %s %s", args, err, output)
+	}
+}
+
+func createTestRepo(t *testing.T, files map[string]string) string {
+	repoDir, err := ioutil.TempDir("", "publishTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(repoDir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runTestGit(t, repoDir, "init", "-q")
+	runTestGit(t, repoDir, "add", "-A")
+	runTestGit(t, repoDir, "commit", "-q", "-m", "Initial commit")
+	return repoDir
+}
+
+func TestDeriveVersion(t *testing.T) {
+	repoDir := createTestRepo(t, map[string]string{
+		"go.mod":        "module github.com/test/repo\n",
+		"sub/go.mod":    "module github.com/test/repo/sub/v2\n",
+		"sub/sub.go":    "package sub\n",
+		"repository.go": "package repo\n",
+	})
+	defer os.RemoveAll(repoDir)
+	ctx := context.Background()
+	subDir := filepath.Join(repoDir, "sub")
+
+	// No tags.
+	version, err := DeriveVersion(ctx, subDir, "github.com/test/repo/sub/v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^v2\.0\.0-\d{14}-[0-9a-f]{12}$`).MatchString(version) {
+		t.Errorf("Unexpected pseudo-version: %s", version)
+	}
+
+	// Tagged HEAD. Tags of other modules and of other major versions are ignored.
+	runTestGit(t, repoDir, "tag", "v1.5.0")
+	runTestGit(t, repoDir, "tag", "sub/v1.9.0")
+	runTestGit(t, repoDir, "tag", "sub/v2.1.0")
+	for dir, expected := range map[string]string{repoDir: "v1.5.0", subDir: "v2.1.0"} {
+		modulePath := "github.com/test/repo"
+		if dir == subDir {
+			modulePath += "/sub/v2"
+		}
+		if version, err = DeriveVersion(ctx, dir, modulePath); err != nil {
+			t.Fatal(err)
+		}
+		if version != expected {
+			t.Errorf("Expected: %s, Got: %s", expected, version)
+		}
+	}
+
+	// A commit after the tag.
+	runTestGit(t, repoDir, "commit", "-q", "--allow-empty", "-m", "Second commit")
+	if version, err = DeriveVersion(ctx, subDir, "github.com/test/repo/sub/v2"); err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^v2\.1\.1-0\.\d{14}-[0-9a-f]{12}$`).MatchString(version) {
+		t.Errorf("Unexpected pseudo-version: %s", version)
+	}
+}
+
+func TestPublishModule(t *testing.T) {
+	repoDir := createTestRepo(t, map[string]string{"go.mod": "module github.com/test/repo\n", "repo.go": "package repo\n"})
+	defer os.RemoveAll(repoDir)
+	runTestGit(t, repoDir, "tag", "v1.0.0")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/github.com/test/repo/@v/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("v0.9.0\nv1.0.0\n"))
+	}))
+	defer server.Close()
+	publisher := &recordingPublisher{}
+	options := PublishOptions{Versions: NewProxyVersionLister(server.URL)}
+
+	_, err := PublishModule(context.Background(), repoDir, publisher, options)
+	if _, ok := err.(*VersionExistsError); !ok {
+		t.Errorf("Expected a VersionExistsError, Got: %v", err)
+	}
+	options.Force = true
+	var events []cmd.EventType
+	ctx := cmd.WithEventListener(context.Background(), cmd.EventListenerFunc(func(ctx context.Context, event cmd.Event) {
+		events = append(events, event.Type)
+	}))
+	version, err := PublishModule(ctx, repoDir, publisher, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]cmd.EventType{cmd.PublishStartedEvent, cmd.PublishCompletedEvent}, events) {
+		t.Error("Unexpected events:", events)
+	}
+	if version != "v1.0.0" || len(publisher.published) != 1 || publisher.published[0].Path != "github.com/test/repo" {
+		t.Errorf("Unexpected published modules: %s, %+v", version, publisher.published)
+	}
+}
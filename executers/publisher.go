@@ -0,0 +1,46 @@
+package executers
+
+import (
+	"github.com/jfrog/jfrog-client-go/artifactory"
+	"github.com/jfrog/jfrog-client-go/artifactory/services/go"
+)
+
+// The files of a Go module version, as served by a GOPROXY.
+type ModuleFiles struct {
+	// The module path and version, not encoded.
+	Path    string
+	Version string
+	ZipPath string
+	// The path to the go.mod file, if exists on disk, and its content.
+	ModPath    string
+	ModContent []byte
+	// The content of the .info file, if exists.
+	InfoContent []byte
+}
+
+// Publishes Go modules to a registry.
+type Publisher interface {
+	PublishModule(module ModuleFiles) error
+}
+
+// Publishes Go modules to an Artifactory Go repository.
+type ArtifactoryPublisher struct {
+	targetRepo     string
+	serviceManager *artifactory.ArtifactoryServicesManager
+}
+
+func NewArtifactoryPublisher(targetRepo string, serviceManager *artifactory.ArtifactoryServicesManager) *ArtifactoryPublisher {
+	return &ArtifactoryPublisher{targetRepo: targetRepo, serviceManager: serviceManager}
+}
+
+// Artifactory calculates the .info file by itself, so the info content is not used.
+func (publisher *ArtifactoryPublisher) PublishModule(module ModuleFiles) error {
+	params := _go.NewGoParams()
+	params.ZipPath = module.ZipPath
+	params.ModContent = module.ModContent
+	params.Version = goModEncode(module.Version)
+	params.TargetRepo = publisher.targetRepo
+	params.ModuleId = goModEncode(module.Path) + ":" + goModEncode(module.Version)
+	params.ModPath = module.ModPath
+	return publisher.serviceManager.PublishGoProject(params)
+}
@@ -1,14 +1,15 @@
 package utils
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/jfrog/gocmd/cache"
 	"github.com/jfrog/gocmd/cmd"
-	gofrogio "github.com/jfrog/gofrog/io"
+	"github.com/jfrog/gocmd/log"
 	"github.com/jfrog/jfrog-client-go/artifactory/auth"
 	"github.com/jfrog/jfrog-client-go/utils"
 	"github.com/jfrog/jfrog-client-go/utils/errorutils"
-	"github.com/jfrog/jfrog-client-go/utils/log"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -20,7 +21,23 @@ import (
 const GOPROXY = "GOPROXY"
 
 // Returns true if a dependency was not found Artifactory.
+// The errors wrapped by an ExitError are matched, and a MultiError, of a command which failed for several reasons,
+// is matched if any of its errors is.
 func DependencyNotFoundInArtifactory(err error, noRegistry bool) bool {
+	if exitErr, ok := err.(*cmd.ExitError); ok {
+		return DependencyNotFoundInArtifactory(exitErr.Err, noRegistry)
+	}
+	if multiErr, ok := err.(*cmd.MultiError); ok {
+		for _, e := range multiErr.Errors {
+			if DependencyNotFoundInArtifactory(e, noRegistry) {
+				return true
+			}
+		}
+		return false
+	}
+	if notFoundErr, ok := err.(*cmd.ModuleNotFoundError); ok {
+		return !noRegistry && notFoundErr.StatusCode == 404
+	}
 	regExp, errRegex := utils.GetRegExp(`^404( Not Found)?(\s)?:`)
 	if errRegex != nil {
 		LogError(errRegex)
@@ -32,10 +49,64 @@ func DependencyNotFoundInArtifactory(err error, noRegistry bool) bool {
 	return false
 }
 
+// Returns the module, in the "path@version" notation, which failed to be downloaded.
+// A MultiError, of a command which failed for several modules, returns the first of its modules.
+func GetModuleAndVersion(usedProxy bool, err error) (string, error) {
+	switch goErr := err.(type) {
+	case *cmd.ExitError:
+		return GetModuleAndVersion(usedProxy, goErr.Err)
+	case *cmd.ModuleNotFoundError:
+		LogDebug(err, usedProxy)
+		return goErr.Module, nil
+	case *cmd.UnrecognizedImportError:
+		LogDebug(err, usedProxy)
+		return goErr.Module, nil
+	case *cmd.UnknownRevisionError:
+		LogDebug(err, usedProxy)
+		return goErr.Module, nil
+	case *cmd.MultiError:
+		for _, e := range goErr.Errors {
+			var notFoundErr *cmd.ModuleNotFoundError
+			var unrecognizedErr *cmd.UnrecognizedImportError
+			var unknownRevisionErr *cmd.UnknownRevisionError
+			switch {
+			case errors.As(e, &notFoundErr):
+				return GetModuleAndVersion(usedProxy, notFoundErr)
+			case errors.As(e, &unrecognizedErr):
+				return GetModuleAndVersion(usedProxy, unrecognizedErr)
+			case errors.As(e, &unknownRevisionErr):
+				return GetModuleAndVersion(usedProxy, unknownRevisionErr)
+			}
+		}
+		if len(goErr.Errors) > 0 {
+			return GetModuleAndVersion(usedProxy, goErr.Errors[0])
+		}
+	}
+	splittedLine := strings.Split(err.Error(), ":")
+	LogDebug(err, usedProxy)
+	if len(splittedLine) < 2 {
+		return "", errorutils.CheckError(errors.New("Missing module name and version in the error message " + err.Error()))
+	}
+	return strings.TrimSpace(splittedLine[1]), nil
+}
+
+// Sets GOPROXY in the environment of the process to the Go API of the Artifactory repository.
+//
+// Deprecated: the environment of the process is shared by all the go commands, including those of concurrent builds.
+// Use WithGoProxyApi instead.
 func SetGoProxyWithApi(repoName string, details auth.ArtifactoryDetails) error {
+	goProxy, err := GetGoProxyWithApi(repoName, details)
+	if err != nil {
+		return err
+	}
+	return errorutils.CheckError(os.Setenv(GOPROXY, goProxy))
+}
+
+// Returns the URL of the Go API of the Artifactory repository, with the credentials of the details, to be used as GOPROXY.
+func GetGoProxyWithApi(repoName string, details auth.ArtifactoryDetails) (string, error) {
 	rtUrl, err := url.Parse(details.GetUrl())
 	if err != nil {
-		return errorutils.CheckError(err)
+		return "", errorutils.CheckError(err)
 	}
 	username := details.GetUser()
 	password := details.GetPassword()
@@ -43,29 +114,39 @@ func SetGoProxyWithApi(repoName string, details auth.ArtifactoryDetails) error {
 		rtUrl.User = url.UserPassword(username, password)
 	}
 	rtUrl.Path += "api/go/" + repoName
-	err = os.Setenv(GOPROXY, rtUrl.String())
-	return errorutils.CheckError(err)
+	return rtUrl.String(), nil
+}
+
+// Returns a copy of ctx whose go commands download the modules from the Go API of the Artifactory repository,
+// without changing the environment of the process.
+func WithGoProxyApi(ctx context.Context, repoName string, details auth.ArtifactoryDetails) (context.Context, error) {
+	goProxy, err := GetGoProxyWithApi(repoName, details)
+	if err != nil {
+		return nil, err
+	}
+	return cmd.WithEnv(ctx, GOPROXY, goProxy), nil
 }
 
-func GetCachePath() (string, error) {
-	goPath, err := getGOPATH()
+// Returns a copy of ctx whose go commands download the modules from their VCS, overriding the GOPROXY of the process
+// and of the options carried by ctx.
+func WithGoProxyDirect(ctx context.Context) context.Context {
+	return cmd.WithEnv(ctx, GOPROXY, "direct")
+}
+
+func GetCachePath(ctx context.Context) (string, error) {
+	goPath, err := getGOPATH(ctx)
 	if err != nil {
 		return "", errorutils.CheckError(err)
 	}
 	return filepath.Join(goPath, "pkg", "mod", "cache", "download"), nil
 }
 
-func getGOPATH() (string, error) {
-	goCmd, err := cmd.NewCmd()
+func getGOPATH(ctx context.Context) (string, error) {
+	goEnv, err := cmd.GetGoEnv(ctx)
 	if err != nil {
-		return "", err
-	}
-	goCmd.Command = []string{"env", "GOPATH"}
-	output, err := gofrogio.RunCmdOutput(goCmd)
-	if errorutils.CheckError(err) != nil {
 		return "", fmt.Errorf("Could not find GOPATH env: %s", err.Error())
 	}
-	return strings.TrimSpace(parseGoPath(string(output))), nil
+	return strings.TrimSpace(parseGoPath(goEnv.GOPATH)), nil
 }
 
 func GetRegex() (regExp *RegExp, err error) {
@@ -1,8 +1,11 @@
 package utils
 
 import (
+	"context"
 	"errors"
-	"github.com/jfrog/jfrog-client-go/utils/log"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/log"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -23,6 +26,11 @@ func TestDependencyNotFoundInArtifactory(t *testing.T) {
 		{"withoutResponseMessageWithSpaceWithoutRegistry", true, errors.New("404 : github.com/package@v1.0.0"), false},
 		{"withRegistryNotContainsError", true, errors.New("This error doesn't contain the error message"), false},
 		{"withoutRegistryNotContainsError", false, errors.New("This error doesn't contain the error message"), false},
+		{"moduleNotFound", false, &cmd.ModuleNotFoundError{Module: "github.com/package@v1.0.0", StatusCode: 404}, true},
+		{"multiErrorWithNotFound", false, &cmd.MultiError{Errors: []error{errors.New("go: build failed"), &cmd.ModuleNotFoundError{Module: "github.com/package@v1.0.0", StatusCode: 404}}}, true},
+		{"multiErrorWithNotFoundWithRegistry", true, &cmd.MultiError{Errors: []error{&cmd.ModuleNotFoundError{Module: "github.com/package@v1.0.0", StatusCode: 404}}}, false},
+		{"exitErrorWithNotFound", false, &cmd.ExitError{ExitCode: 1, Err: &cmd.ModuleNotFoundError{Module: "github.com/package@v1.0.0", StatusCode: 404}}, true},
+		{"multiErrorWithoutNotFound", false, &cmd.MultiError{Errors: []error{errors.New("go: build failed"), &cmd.ModuleNotFoundError{Module: "github.com/package@v1.0.0", StatusCode: 403}}}, false},
 	}
 
 	for _, test := range tests {
@@ -35,6 +43,49 @@ func TestDependencyNotFoundInArtifactory(t *testing.T) {
 	}
 }
 
+func TestGetModuleAndVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"moduleNotFound", &cmd.ModuleNotFoundError{Module: "github.com/package@v1.0.0", StatusCode: 404, Status: "404 Not Found"}, "github.com/package@v1.0.0"},
+		{"unknownRevision", &cmd.UnknownRevisionError{Module: "github.com/package@v1.0.0"}, "github.com/package@v1.0.0"},
+		{"untypedError", errors.New("404 Not Found: github.com/package@v1.0.0"), "github.com/package@v1.0.0"},
+		{"multiErrorWithTwoNotFound", &cmd.MultiError{Errors: []error{
+			&cmd.ModuleNotFoundError{Module: "github.com/first@v1.0.0", StatusCode: 404, Status: "404 Not Found"},
+			&cmd.ModuleNotFoundError{Module: "github.com/second@v1.0.0", StatusCode: 404, Status: "404 Not Found"},
+		}}, "github.com/first@v1.0.0"},
+		{"exitErrorWithMultiError", &cmd.ExitError{ExitCode: 1, Err: &cmd.MultiError{Errors: []error{&cmd.UnknownRevisionError{Module: "github.com/package@v1.0.0"}}}}, "github.com/package@v1.0.0"},
+		{"multiErrorWithUntypedFirst", &cmd.MultiError{Errors: []error{errors.New("go: build failed"), &cmd.UnrecognizedImportError{Module: "github.com/package"}}}, "github.com/package"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := GetModuleAndVersion(false, test.err)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.expected != actual {
+				t.Errorf("Test name: %s: Expected: %s, Got: %s", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestGetCachePath(t *testing.T) {
+	executor := cmd.NewFakeExecutor()
+	executor.On(cmd.ExecutorResult{Stdout: `{"GOPATH": "` + filepath.ToSlash(filepath.Join("gopath", "first")) + `"}`}, "env", "-json")
+	ctx := cmd.WithOptions(context.Background(), &cmd.Options{Executor: executor})
+	cachePath, err := GetCachePath(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := filepath.Join("gopath", "first", "pkg", "mod", "cache", "download"); cachePath != expected {
+		t.Errorf("Expected: %s, Got: %s", expected, cachePath)
+	}
+}
+
 func TestParseGoPathWindows(t *testing.T) {
 	if runtime.GOOS != "windows" {
 		log.Debug("Skipping the test since not running on Windows OS")
@@ -0,0 +1,105 @@
+package fsys
+
+import (
+	"errors"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// The file operations on go.mod and go.sum files. Replace it per context with cmd.WithFileSystem, to run fully in memory,
+// or to intercept the writes, for example to keep the source tree read-only.
+type FileSystem interface {
+	Open(path string) (io.ReadCloser, error)
+	// Writes the whole file with the mode.
+	WriteFile(path string, content []byte, mode os.FileMode) error
+	Stat(path string) (os.FileInfo, error)
+	Remove(path string) error
+}
+
+// Returns the content of the file.
+func ReadFile(fileSystem FileSystem, path string) ([]byte, error) {
+	file, err := fileSystem.Open(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer file.Close()
+	content, err := ioutil.ReadAll(file)
+	return content, errorutils.CheckError(err)
+}
+
+// Returns true if the path exists and is not a directory.
+func IsFileExists(fileSystem FileSystem, path string) (bool, error) {
+	info, err := fileSystem.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errorutils.CheckError(err)
+	}
+	return !info.IsDir(), nil
+}
+
+// The files on disk. Files are written atomically.
+type OsFileSystem struct{}
+
+func (OsFileSystem) Open(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+func (OsFileSystem) WriteFile(path string, content []byte, mode os.FileMode) error {
+	return WriteFileAtomically(path, content, mode)
+}
+
+func (OsFileSystem) Stat(path string) (os.FileInfo, error) {
+	return os.Stat(path)
+}
+
+func (OsFileSystem) Remove(path string) error {
+	return os.Remove(path)
+}
+
+// Writes to a temp file in the same directory and renames it, so that the file is never partially written.
+func WriteFileAtomically(path string, content []byte, mode os.FileMode) error {
+	tempFile, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	tempPath := tempFile.Name()
+	_, err = tempFile.Write(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempPath, mode)
+	}
+	if err == nil {
+		err = os.Rename(tempPath, path)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+	}
+	return errorutils.CheckError(err)
+}
+
+// Returned by the writes of a file system returned by ReadOnly.
+var ErrReadOnly = errors.New("the file system is read-only")
+
+// Returns a file system reading from fileSystem, whose writes and removals fail with ErrReadOnly.
+func ReadOnly(fileSystem FileSystem) FileSystem {
+	return readOnlyFileSystem{fileSystem}
+}
+
+type readOnlyFileSystem struct {
+	FileSystem
+}
+
+func (readOnlyFileSystem) WriteFile(path string, content []byte, mode os.FileMode) error {
+	return &os.PathError{Op: "write", Path: path, Err: ErrReadOnly}
+}
+
+func (readOnlyFileSystem) Remove(path string) error {
+	return &os.PathError{Op: "remove", Path: path, Err: ErrReadOnly}
+}
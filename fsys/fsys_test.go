@@ -0,0 +1,90 @@
+package fsys
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMemFileSystem(t *testing.T) {
+	fileSystem := NewMemFileSystem(map[string]string{"/project/go.mod": "module example.com/project\n"})
+	content, err := ReadFile(fileSystem, "/project/go.mod")
+	if err != nil || string(content) != "module example.com/project\n" {
+		t.Error("Unexpected go.mod content:", string(content), err)
+	}
+	if err = fileSystem.WriteFile("/project/go.sum", []byte("sum"), 0600); err != nil {
+		t.Error(err)
+	}
+	info, err := fileSystem.Stat("/project/go.sum")
+	if err != nil || info.Mode() != 0600 || info.Size() != 3 || info.IsDir() {
+		t.Error("Unexpected go.sum info:", info, err)
+	}
+	if err = fileSystem.Remove("/project/go.mod"); err != nil {
+		t.Error(err)
+	}
+	if _, err = fileSystem.Open("/project/go.mod"); !os.IsNotExist(err) {
+		t.Error("Expecting go.mod to be removed, got:", err)
+	}
+	if err = fileSystem.Remove("/project/go.mod"); !os.IsNotExist(err) {
+		t.Error("Expecting removing a missing file to fail, got:", err)
+	}
+	expected := map[string]string{filepath.Clean("/project/go.sum"): "sum"}
+	if files := fileSystem.Files(); !reflect.DeepEqual(files, expected) {
+		t.Error("Expecting", expected, "got", files)
+	}
+}
+
+func TestIsFileExists(t *testing.T) {
+	fileSystem := NewMemFileSystem(map[string]string{"/project/go.mod": ""})
+	tests := []struct {
+		path   string
+		exists bool
+	}{
+		{"/project/go.mod", true},
+		{"/project", false},
+		{"/project/go.sum", false},
+	}
+	for _, test := range tests {
+		exists, err := IsFileExists(fileSystem, test.path)
+		if err != nil || exists != test.exists {
+			t.Error(test.path, "- expecting", test.exists, "got", exists, err)
+		}
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	fileSystem := ReadOnly(NewMemFileSystem(map[string]string{"/project/go.mod": "module example.com/project\n"}))
+	if _, err := ReadFile(fileSystem, "/project/go.mod"); err != nil {
+		t.Error(err)
+	}
+	if err := fileSystem.WriteFile("/project/go.mod", nil, 0644); err == nil || err.(*os.PathError).Err != ErrReadOnly {
+		t.Error("Expecting writing to fail with ErrReadOnly, got:", err)
+	}
+	if err := fileSystem.Remove("/project/go.mod"); err == nil || err.(*os.PathError).Err != ErrReadOnly {
+		t.Error("Expecting removing to fail with ErrReadOnly, got:", err)
+	}
+}
+
+func TestOsFileSystem(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "fsys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "go.mod")
+	fileSystem := OsFileSystem{}
+	if err = fileSystem.WriteFile(path, []byte("module example.com/project\n"), 0600); err != nil {
+		t.Error(err)
+	}
+	content, err := ReadFile(fileSystem, path)
+	if err != nil || string(content) != "module example.com/project\n" {
+		t.Error("Unexpected go.mod content:", string(content), err)
+	}
+	files, err := ioutil.ReadDir(tempDir)
+	if err != nil || len(files) != 1 {
+		t.Error("Expecting only go.mod in the directory, got:", files, err)
+	}
+}
@@ -0,0 +1,137 @@
+package fsys
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// A file system keeping its files in memory, for tests.
+// Directories exist implicitly for the files in them.
+type MemFileSystem struct {
+	mutex sync.Mutex
+	files map[string]*memFile
+}
+
+type memFile struct {
+	content []byte
+	mode    os.FileMode
+	modTime time.Time
+}
+
+// Creates a file system with the files, by their paths.
+func NewMemFileSystem(files map[string]string) *MemFileSystem {
+	fileSystem := &MemFileSystem{files: map[string]*memFile{}}
+	for path, content := range files {
+		fileSystem.files[filepath.Clean(path)] = &memFile{content: []byte(content), mode: 0644, modTime: time.Now()}
+	}
+	return fileSystem
+}
+
+func (fileSystem *MemFileSystem) Open(path string) (io.ReadCloser, error) {
+	fileSystem.mutex.Lock()
+	defer fileSystem.mutex.Unlock()
+	file, exists := fileSystem.files[filepath.Clean(path)]
+	if !exists {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return ioutil.NopCloser(bytes.NewReader(file.content)), nil
+}
+
+func (fileSystem *MemFileSystem) WriteFile(path string, content []byte, mode os.FileMode) error {
+	fileSystem.mutex.Lock()
+	defer fileSystem.mutex.Unlock()
+	fileSystem.files[filepath.Clean(path)] = &memFile{content: append([]byte(nil), content...), mode: mode, modTime: time.Now()}
+	return nil
+}
+
+func (fileSystem *MemFileSystem) Stat(path string) (os.FileInfo, error) {
+	fileSystem.mutex.Lock()
+	defer fileSystem.mutex.Unlock()
+	path = filepath.Clean(path)
+	if file, exists := fileSystem.files[path]; exists {
+		return memFileInfo{name: filepath.Base(path), file: file}, nil
+	}
+	for filePath := range fileSystem.files {
+		if isInDir(filePath, path) {
+			return memFileInfo{name: filepath.Base(path)}, nil
+		}
+	}
+	return nil, &os.PathError{Op: "stat", Path: path, Err: os.ErrNotExist}
+}
+
+func (fileSystem *MemFileSystem) Remove(path string) error {
+	fileSystem.mutex.Lock()
+	defer fileSystem.mutex.Unlock()
+	path = filepath.Clean(path)
+	if _, exists := fileSystem.files[path]; !exists {
+		return &os.PathError{Op: "remove", Path: path, Err: os.ErrNotExist}
+	}
+	delete(fileSystem.files, path)
+	return nil
+}
+
+// Returns the files by their paths.
+func (fileSystem *MemFileSystem) Files() map[string]string {
+	fileSystem.mutex.Lock()
+	defer fileSystem.mutex.Unlock()
+	files := map[string]string{}
+	for path, file := range fileSystem.files {
+		files[path] = string(file.content)
+	}
+	return files
+}
+
+func isInDir(path, dir string) bool {
+	for parent := filepath.Dir(path); ; parent = filepath.Dir(parent) {
+		if parent == dir {
+			return true
+		}
+		if parent == filepath.Dir(parent) {
+			return false
+		}
+	}
+}
+
+// Describes a file, or a directory if the file is nil.
+type memFileInfo struct {
+	name string
+	file *memFile
+}
+
+func (info memFileInfo) Name() string {
+	return info.name
+}
+
+func (info memFileInfo) Size() int64 {
+	if info.file == nil {
+		return 0
+	}
+	return int64(len(info.file.content))
+}
+
+func (info memFileInfo) Mode() os.FileMode {
+	if info.file == nil {
+		return os.ModeDir | 0755
+	}
+	return info.file.mode
+}
+
+func (info memFileInfo) ModTime() time.Time {
+	if info.file == nil {
+		return time.Time{}
+	}
+	return info.file.modTime
+}
+
+func (info memFileInfo) IsDir() bool {
+	return info.file == nil
+}
+
+func (info memFileInfo) Sys() interface{} {
+	return nil
+}
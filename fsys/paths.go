@@ -0,0 +1,74 @@
+package fsys
+
+import (
+	slashpath "path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// The prefix of Windows paths which aren't limited to MAX_PATH characters.
+	longPathPrefix = `\\?\`
+	// The prefix of UNC paths, such as \\server\share, which aren't limited to MAX_PATH characters.
+	longUncPathPrefix = `\\?\UNC\`
+	// Windows directories may have up to MAX_PATH-12 characters, leaving room for an 8.3 file name.
+	maxWindowsDirPath = 248
+)
+
+// Returns the path with the \\?\ prefix if it's an absolute Windows path too long for the Windows APIs,
+// such as a file deep in the module cache. Other paths are returned as is.
+func LongPath(path string) string {
+	return longPath(path, runtime.GOOS)
+}
+
+func longPath(path, goos string) string {
+	if goos != "windows" || len(path) < maxWindowsDirPath || strings.HasPrefix(path, longPathPrefix) {
+		return path
+	}
+	path = strings.Replace(path, "/", `\`, -1)
+	if strings.HasPrefix(path, `\\`) {
+		return longUncPathPrefix + strings.TrimPrefix(path, `\\`)
+	}
+	if len(path) < 3 || path[1] != ':' || path[2] != '\\' {
+		// Relative paths can't have the prefix.
+		return path
+	}
+	return longPathPrefix + path
+}
+
+// Returns the path without the \\?\ prefix added by LongPath, as reported by the go command.
+func TrimLongPathPrefix(path string) string {
+	if strings.HasPrefix(path, longUncPathPrefix) {
+		return `\\` + strings.TrimPrefix(path, longUncPathPrefix)
+	}
+	return strings.TrimPrefix(path, longPathPrefix)
+}
+
+// Returns true if the paths refer to the same file. On Windows, the \\?\ prefix is ignored and the paths are compared
+// case-insensitively, as the file names are.
+func SamePath(path, other string) bool {
+	return samePath(path, other, runtime.GOOS)
+}
+
+func samePath(path, other, goos string) bool {
+	path, other = cleanPath(path, goos), cleanPath(other, goos)
+	if goos == "windows" {
+		return strings.EqualFold(path, other)
+	}
+	return path == other
+}
+
+// Cleans the path by the rules of goos, so that Windows paths can be compared on any OS.
+func cleanPath(path, goos string) string {
+	if goos != "windows" {
+		return filepath.Clean(path)
+	}
+	path = strings.Replace(TrimLongPathPrefix(strings.Replace(path, "/", `\`, -1)), `\`, "/", -1)
+	isUnc := strings.HasPrefix(path, "//")
+	path = slashpath.Clean(path)
+	if isUnc {
+		path = "/" + path
+	}
+	return strings.Replace(path, "/", `\`, -1)
+}
@@ -0,0 +1,66 @@
+package fsys
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLongPath(t *testing.T) {
+	deepDir := strings.Repeat(`\very-long-directory-name`, 12)
+	tests := []struct {
+		name     string
+		path     string
+		goos     string
+		expected string
+	}{
+		{"short", `C:\Users\go\pkg\mod`, "windows", `C:\Users\go\pkg\mod`},
+		{"long", `C:\Users\go\pkg\mod` + deepDir, "windows", `\\?\C:\Users\go\pkg\mod` + deepDir},
+		{"long with slashes", `C:/Users/go/pkg/mod` + deepDir, "windows", `\\?\C:\Users\go\pkg\mod` + deepDir},
+		{"long unc", `\\server\share\mod` + deepDir, "windows", `\\?\UNC\server\share\mod` + deepDir},
+		{"already prefixed", `\\?\C:\mod` + deepDir, "windows", `\\?\C:\mod` + deepDir},
+		{"long relative", `mod` + deepDir, "windows", `mod` + deepDir},
+		{"not windows", "/home/go/pkg/mod" + deepDir, "linux", "/home/go/pkg/mod" + deepDir},
+	}
+	for _, test := range tests {
+		if actual := longPath(test.path, test.goos); actual != test.expected {
+			t.Errorf("Test name: %s: Expected: %s, Got: %s", test.name, test.expected, actual)
+		}
+	}
+}
+
+func TestTrimLongPathPrefix(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{`\\?\C:\Users\go`, `C:\Users\go`},
+		{`\\?\UNC\server\share`, `\\server\share`},
+		{`C:\Users\go`, `C:\Users\go`},
+	}
+	for _, test := range tests {
+		if actual := TrimLongPathPrefix(test.path); actual != test.expected {
+			t.Errorf("Test name: %s: Expected: %s, Got: %s", test.path, test.expected, actual)
+		}
+	}
+}
+
+func TestSamePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		other    string
+		goos     string
+		expected bool
+	}{
+		{`C:\Users\Go\pkg\mod`, `c:\users\go\pkg\mod\`, "windows", true},
+		{`\\?\C:\Users\go`, `C:/Users/go`, "windows", true},
+		{`\\server\share\mod`, `\\?\UNC\server\share\mod`, "windows", true},
+		{`C:\Users\go`, `C:\Users\go2`, "windows", false},
+		{"/home/Go", "/home/go", "linux", false},
+		{"/home/go/", "/home/go", "linux", true},
+	}
+	for _, test := range tests {
+		if actual := samePath(test.path, test.other, test.goos); actual != test.expected {
+			t.Errorf("Test name: %s %s: Expected: %t, Got: %t", test.path, test.other, test.expected, actual)
+		}
+	}
+}
@@ -8,6 +8,7 @@ require (
 	github.com/prometheus/common v0.0.0-20190107103113-2998b132700a // indirect
 	github.com/prometheus/procfs v0.0.0-20190104112138-b1a0a9a36d74 // indirect
 	golang.org/x/lint v0.0.0-20181217174547-8f45f776aaf1 // indirect
+	golang.org/x/mod v0.12.0
 )
 
 replace github.com/jfrog/jfrog-client-go => github.com/jfrog/jfrog-client-go v0.3.1
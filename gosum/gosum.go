@@ -0,0 +1,76 @@
+package gosum
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"strings"
+)
+
+const modSuffix = "/go.mod"
+
+// Represents a line of a go.sum file.
+type ModuleEntry struct {
+	Path    string
+	Version string
+	// The hash of the entry, such as "h1:...".
+	Hash string
+	// True if the hash is of the module's go.mod file, false if it is of the module's content.
+	IsMod bool
+}
+
+// Returns the module in the "path@version" notation.
+func (entry ModuleEntry) ModuleId() string {
+	return entry.Path + "@" + entry.Version
+}
+
+// Parses a go.sum content line by line, without loading the whole content into memory.
+func ParseGoSum(reader io.Reader) ([]ModuleEntry, error) {
+	var entries []ModuleEntry
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		entry, err := parseLine(line)
+		if err != nil {
+			return nil, errorutils.CheckError(fmt.Errorf("go.sum line %d: %s", lineNumber, err.Error()))
+		}
+		entries = append(entries, entry)
+	}
+	return entries, errorutils.CheckError(scanner.Err())
+}
+
+// Parses the go.sum file in the given path.
+func ParseGoSumFile(path string) ([]ModuleEntry, error) {
+	return ParseGoSumFileFrom(fsys.OsFileSystem{}, path)
+}
+
+// Parses the go.sum file at the path of the file system.
+func ParseGoSumFileFrom(fileSystem fsys.FileSystem, path string) ([]ModuleEntry, error) {
+	file, err := fileSystem.Open(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	defer file.Close()
+	return ParseGoSum(file)
+}
+
+// Parses a line such as "rsc.io/quote v1.5.2/go.mod h1:w5Pr...=".
+func parseLine(line string) (ModuleEntry, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return ModuleEntry{}, fmt.Errorf("expecting 3 fields, got %d: %s", len(fields), line)
+	}
+	entry := ModuleEntry{Path: fields[0], Version: fields[1], Hash: fields[2]}
+	if strings.HasSuffix(entry.Version, modSuffix) {
+		entry.Version = strings.TrimSuffix(entry.Version, modSuffix)
+		entry.IsMod = true
+	}
+	return entry, nil
+}
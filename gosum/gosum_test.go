@@ -0,0 +1,46 @@
+package gosum
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGoSum(t *testing.T) {
+	content := `github.com/pkg/errors v0.8.1 h1:iURUrRGxPUNPdy5/HRSm+Yj6okJ6UtLINN0Q9M4+h3I=
+github.com/pkg/errors v0.8.1/go.mod h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=
+
+rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=
+`
+	expected := []ModuleEntry{
+		{"github.com/pkg/errors", "v0.8.1", "h1:iURUrRGxPUNPdy5/HRSm+Yj6okJ6UtLINN0Q9M4+h3I=", false},
+		{"github.com/pkg/errors", "v0.8.1", "h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=", true},
+		{"rsc.io/quote", "v1.5.2", "h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=", true},
+	}
+	actual, err := ParseGoSum(strings.NewReader(content))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expecting: \n%v \nGot: \n%v", expected, actual)
+	}
+}
+
+func TestParseGoSumWindowsLineEndings(t *testing.T) {
+	content := "github.com/pkg/errors v0.8.1/go.mod h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=\r\n\r\n"
+	expected := []ModuleEntry{{"github.com/pkg/errors", "v0.8.1", "h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=", true}}
+	actual, err := ParseGoSum(strings.NewReader(content))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expecting: \n%v \nGot: \n%v", expected, actual)
+	}
+}
+
+func TestParseGoSumInvalidLine(t *testing.T) {
+	_, err := ParseGoSum(strings.NewReader("github.com/pkg/errors v0.8.1\n"))
+	if err == nil {
+		t.Error("Expecting an error for a line with a missing hash")
+	}
+}
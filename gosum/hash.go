@@ -0,0 +1,65 @@
+package gosum
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/mod/sumdb/dirhash"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+const hash1Prefix = "h1:"
+
+// Calculates the "h1:" hash of a module zip, as it appears in go.sum.
+func HashZip(zipPath string) (string, error) {
+	hash, err := dirhash.HashZip(zipPath, dirhash.Hash1)
+	return hash, errorutils.CheckError(err)
+}
+
+// Calculates the "h1:" hash of a go.mod content, as it appears in the "/go.mod" lines of go.sum.
+func HashMod(modContent []byte) (string, error) {
+	hash, err := dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(modContent)), nil
+	})
+	return hash, errorutils.CheckError(err)
+}
+
+// Validates that the module zip matches the hash of the go.sum entry.
+func VerifyModuleZip(entry ModuleEntry, zipPath string) error {
+	if entry.IsMod {
+		return errorutils.CheckError(fmt.Errorf("The go.sum entry of %s is of a go.mod file and not of a module zip.", entry.ModuleId()))
+	}
+	actual, err := HashZip(zipPath)
+	if err != nil {
+		return err
+	}
+	return verifyHash(entry, zipPath, actual)
+}
+
+// Validates that the go.mod file matches the hash of the "/go.mod" go.sum entry.
+func VerifyModFile(entry ModuleEntry, modPath string) error {
+	if !entry.IsMod {
+		return errorutils.CheckError(fmt.Errorf("The go.sum entry of %s is of a module zip and not of a go.mod file.", entry.ModuleId()))
+	}
+	modContent, err := ioutil.ReadFile(modPath)
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	actual, err := HashMod(modContent)
+	if err != nil {
+		return err
+	}
+	return verifyHash(entry, modPath, actual)
+}
+
+func verifyHash(entry ModuleEntry, path, actual string) error {
+	if !strings.HasPrefix(entry.Hash, hash1Prefix) {
+		return errorutils.CheckError(fmt.Errorf("Unsupported hash %s for %s.", entry.Hash, entry.ModuleId()))
+	}
+	if entry.Hash != actual {
+		return errorutils.CheckError(fmt.Errorf("Checksum mismatch for %s at %s: expected %s, got %s.", entry.ModuleId(), path, entry.Hash, actual))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package gosum
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyModuleZip(t *testing.T) {
+	zipPath := filepath.Join("..", "testdata", "zip", "v1.2.3.zip")
+	entry := ModuleEntry{Path: "github.com/test", Version: "v1.2.3", Hash: "h1:xD90HeW8F8sOt8zOweJdoUEwjoFXTN2nFCipo+wQAKQ="}
+	if err := VerifyModuleZip(entry, zipPath); err != nil {
+		t.Error(err)
+	}
+
+	entry.Hash = "h1:4n6JtetFEq2YhEV41xM2K0nLeM63YwPefRNfDF86fhk="
+	if err := VerifyModuleZip(entry, zipPath); err == nil {
+		t.Error("Expecting a checksum mismatch error")
+	}
+
+	entry.IsMod = true
+	if err := VerifyModuleZip(entry, zipPath); err == nil {
+		t.Error("Expecting an error for a go.mod entry")
+	}
+}
+
+func TestHashMod(t *testing.T) {
+	expected := "h1:tKaHevgzwSEAomyR7SKcOuO6kW2uWaVTTPF6Y983y/c="
+	actual, err := HashMod([]byte("module github.com/test"))
+	if err != nil {
+		t.Error(err)
+	}
+	if expected != actual {
+		t.Error("Expecting", expected, "got:", actual)
+	}
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"github.com/jfrog/gocmd/semver"
+	"sort"
+)
+
+// A module whose version changed between two dependency graphs.
+type VersionChange struct {
+	Path   string
+	Before string
+	After  string
+}
+
+// The differences between the dependencies of two states of a project.
+type DependenciesDiff struct {
+	Added   []Module
+	Removed []Module
+	Changed []VersionChange
+}
+
+// Returns true if the dependencies are the same in both states.
+func (diff *DependenciesDiff) IsEmpty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Changed) == 0
+}
+
+// Compares the dependencies of two graphs, such as the graphs before and after a change to go.mod.
+// When a graph includes several versions of the same module, as go mod graph lists, the highest version is used,
+// as it is the version selected for the build. The root modules of the graphs are not compared.
+// The results are sorted by the module path.
+func DiffDependencies(before, after *DependencyGraph) *DependenciesDiff {
+	return diffVersions(before.selectedVersions(), after.selectedVersions())
+}
+
+// Compares the versions of the modules, given by their paths.
+func diffVersions(beforeVersions, afterVersions map[string]string) *DependenciesDiff {
+	diff := &DependenciesDiff{}
+	for _, path := range sortedKeys(beforeVersions, afterVersions) {
+		beforeVersion, inBefore := beforeVersions[path]
+		afterVersion, inAfter := afterVersions[path]
+		switch {
+		case !inBefore:
+			diff.Added = append(diff.Added, Module{Path: path, Version: afterVersion})
+		case !inAfter:
+			diff.Removed = append(diff.Removed, Module{Path: path, Version: beforeVersion})
+		case beforeVersion != afterVersion:
+			diff.Changed = append(diff.Changed, VersionChange{Path: path, Before: beforeVersion, After: afterVersion})
+		}
+	}
+	return diff
+}
+
+// Returns the highest version of each module of the graph, excluding the root.
+func (graph *DependencyGraph) selectedVersions() map[string]string {
+	versions := map[string]string{}
+	for _, module := range graph.nodes {
+		if module == graph.root {
+			continue
+		}
+		if version, exists := versions[module.Path]; !exists || semver.Compare(module.Version, version) > 0 {
+			versions[module.Path] = module.Version
+		}
+	}
+	return versions
+}
+
+func sortedKeys(maps ...map[string]string) []string {
+	keys := map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			keys[key] = true
+		}
+	}
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,28 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffDependencies(t *testing.T) {
+	before := ParseModGraph(modGraphOutput)
+	after := ParseModGraph(`github.com/you/hello rsc.io/quote@v1.5.3
+github.com/you/hello golang.org/x/text@v0.3.1
+github.com/you/hello github.com/pkg/errors@v0.8.1
+rsc.io/quote@v1.5.3 rsc.io/sampler@v1.3.0
+rsc.io/sampler@v1.3.0 golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c
+`)
+	diff := DiffDependencies(before, after)
+	expected := &DependenciesDiff{
+		Added:   []Module{{"github.com/pkg/errors", "v0.8.1"}},
+		Removed: []Module{{"github.com/mholt/archiver", "v2.1.0+incompatible"}},
+		Changed: []VersionChange{{"rsc.io/quote", "v1.5.2", "v1.5.3"}},
+	}
+	if !reflect.DeepEqual(expected, diff) {
+		t.Errorf("Expected: %v, Got: %v", expected, diff)
+	}
+	if diff := DiffDependencies(before, ParseModGraph(modGraphOutput)); !diff.IsEmpty() {
+		t.Error("Expecting no differences between identical graphs, got:", diff)
+	}
+}
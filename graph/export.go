@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Describes how the graph is rendered by WriteDot and WriteMermaid.
+type ExportOptions struct {
+	// Path prefixes, such as "github.com/aws", whose modules are rendered as a single node named by the prefix.
+	// Modules are collapsed by the longest matching prefix.
+	CollapsePrefixes []string
+	// The path of a module whose versions, and the paths leading to them from the root, are highlighted.
+	Highlight string
+}
+
+// The nodes and edges of the graph, as rendered after collapsing.
+type exportedGraph struct {
+	nodes            []string
+	edges            [][2]string
+	highlightedNodes map[string]bool
+	highlightedEdges map[[2]string]bool
+}
+
+// Writes the graph in the Graphviz DOT language.
+func (graph *DependencyGraph) WriteDot(writer io.Writer, options ExportOptions) error {
+	exported := graph.export(options)
+	bufWriter := bufio.NewWriter(writer)
+	fmt.Fprintln(bufWriter, "digraph dependencies {")
+	for _, node := range exported.nodes {
+		attributes := ""
+		if exported.highlightedNodes[node] {
+			attributes = " [color=red, penwidth=2]"
+		}
+		fmt.Fprintf(bufWriter, "\t%s%s;\n", strconv.Quote(node), attributes)
+	}
+	for _, edge := range exported.edges {
+		attributes := ""
+		if exported.highlightedEdges[edge] {
+			attributes = " [color=red, penwidth=2]"
+		}
+		fmt.Fprintf(bufWriter, "\t%s -> %s%s;\n", strconv.Quote(edge[0]), strconv.Quote(edge[1]), attributes)
+	}
+	fmt.Fprintln(bufWriter, "}")
+	return errorutils.CheckError(bufWriter.Flush())
+}
+
+// Writes the graph as a Mermaid flowchart.
+func (graph *DependencyGraph) WriteMermaid(writer io.Writer, options ExportOptions) error {
+	exported := graph.export(options)
+	// Mermaid node ids can't include the characters of module paths, so the nodes are labeled instead.
+	ids := map[string]string{}
+	bufWriter := bufio.NewWriter(writer)
+	fmt.Fprintln(bufWriter, "flowchart LR")
+	var highlightedIds []string
+	for i, node := range exported.nodes {
+		ids[node] = "n" + strconv.Itoa(i)
+		fmt.Fprintf(bufWriter, "    %s[\"%s\"]\n", ids[node], strings.Replace(node, "\"", "#quot;", -1))
+		if exported.highlightedNodes[node] {
+			highlightedIds = append(highlightedIds, ids[node])
+		}
+	}
+	var highlightedEdges []string
+	for i, edge := range exported.edges {
+		fmt.Fprintf(bufWriter, "    %s --> %s\n", ids[edge[0]], ids[edge[1]])
+		if exported.highlightedEdges[edge] {
+			highlightedEdges = append(highlightedEdges, strconv.Itoa(i))
+		}
+	}
+	if len(highlightedIds) > 0 {
+		fmt.Fprintln(bufWriter, "    classDef highlight stroke:red,stroke-width:2px")
+		fmt.Fprintf(bufWriter, "    class %s highlight\n", strings.Join(highlightedIds, ","))
+	}
+	if len(highlightedEdges) > 0 {
+		fmt.Fprintf(bufWriter, "    linkStyle %s stroke:red,stroke-width:2px\n", strings.Join(highlightedEdges, ","))
+	}
+	return errorutils.CheckError(bufWriter.Flush())
+}
+
+// Collapses the modules by the prefixes of the options, and marks the nodes and edges on the paths to the highlighted module.
+func (graph *DependencyGraph) export(options ExportOptions) *exportedGraph {
+	// The highlighted modules and their ancestors. Every edge leading to one of them is on a path to a highlighted module.
+	highlighted := map[string]bool{}
+	if options.Highlight != "" {
+		for _, module := range graph.Nodes() {
+			if module.Path != options.Highlight {
+				continue
+			}
+			highlighted[module.String()] = true
+			for _, ancestor := range graph.Ancestors(module) {
+				highlighted[ancestor.String()] = true
+			}
+		}
+	}
+
+	exported := &exportedGraph{highlightedNodes: map[string]bool{}, highlightedEdges: map[[2]string]bool{}}
+	nodes := map[string]bool{}
+	for _, module := range graph.Nodes() {
+		node := collapse(module, options.CollapsePrefixes)
+		if !nodes[node] {
+			nodes[node] = true
+			exported.nodes = append(exported.nodes, node)
+		}
+		if highlighted[module.String()] {
+			exported.highlightedNodes[node] = true
+		}
+	}
+	edges := map[[2]string]bool{}
+	for _, edge := range graph.Edges() {
+		exportedEdge := [2]string{collapse(edge.From, options.CollapsePrefixes), collapse(edge.To, options.CollapsePrefixes)}
+		if exportedEdge[0] == exportedEdge[1] {
+			continue
+		}
+		if !edges[exportedEdge] {
+			edges[exportedEdge] = true
+			exported.edges = append(exported.edges, exportedEdge)
+		}
+		if highlighted[edge.To.String()] {
+			exported.highlightedEdges[exportedEdge] = true
+		}
+	}
+	sort.Strings(exported.nodes)
+	sort.Slice(exported.edges, func(i, j int) bool {
+		if exported.edges[i][0] != exported.edges[j][0] {
+			return exported.edges[i][0] < exported.edges[j][0]
+		}
+		return exported.edges[i][1] < exported.edges[j][1]
+	})
+	return exported
+}
+
+// Returns the longest prefix matching the module path, or the module in the "path@version" notation if none matches.
+func collapse(module Module, prefixes []string) string {
+	collapsed := ""
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if (module.Path == prefix || strings.HasPrefix(module.Path, prefix+"/")) && len(prefix) > len(collapsed) {
+			collapsed = prefix
+		}
+	}
+	if collapsed == "" {
+		return module.String()
+	}
+	return collapsed
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  ExportOptions
+		expected string
+	}{
+		{"plain", ExportOptions{}, `digraph dependencies {
+	"github.com/mholt/archiver@v2.1.0+incompatible";
+	"github.com/you/hello";
+	"golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c";
+	"golang.org/x/text@v0.3.1";
+	"rsc.io/quote@v1.5.2";
+	"rsc.io/sampler@v1.3.0";
+	"github.com/you/hello" -> "github.com/mholt/archiver@v2.1.0+incompatible";
+	"github.com/you/hello" -> "golang.org/x/text@v0.3.1";
+	"github.com/you/hello" -> "rsc.io/quote@v1.5.2";
+	"rsc.io/quote@v1.5.2" -> "rsc.io/sampler@v1.3.0";
+	"rsc.io/sampler@v1.3.0" -> "golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c";
+}
+`},
+		{"collapsedAndHighlighted", ExportOptions{CollapsePrefixes: []string{"rsc.io/", "github.com/mholt"}, Highlight: "rsc.io/sampler"}, `digraph dependencies {
+	"github.com/mholt";
+	"github.com/you/hello" [color=red, penwidth=2];
+	"golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c";
+	"golang.org/x/text@v0.3.1";
+	"rsc.io" [color=red, penwidth=2];
+	"github.com/you/hello" -> "github.com/mholt";
+	"github.com/you/hello" -> "golang.org/x/text@v0.3.1";
+	"github.com/you/hello" -> "rsc.io" [color=red, penwidth=2];
+	"rsc.io" -> "golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c";
+}
+`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := ParseModGraph(modGraphOutput).WriteDot(&buffer, test.options); err != nil {
+				t.Fatal(err)
+			}
+			if buffer.String() != test.expected {
+				t.Errorf("Test name: %s: Expected:\n%s\nGot:\n%s", test.name, test.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestWriteMermaid(t *testing.T) {
+	expected := `flowchart LR
+    n0["github.com/mholt/archiver@v2.1.0+incompatible"]
+    n1["github.com/you/hello"]
+    n2["golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c"]
+    n3["golang.org/x/text@v0.3.1"]
+    n4["rsc.io"]
+    n1 --> n0
+    n1 --> n3
+    n1 --> n4
+    n4 --> n2
+    classDef highlight stroke:red,stroke-width:2px
+    class n1,n2,n3,n4 highlight
+    linkStyle 1,2,3 stroke:red,stroke-width:2px
+`
+	var buffer bytes.Buffer
+	options := ExportOptions{CollapsePrefixes: []string{"rsc.io"}, Highlight: "golang.org/x/text"}
+	if err := ParseModGraph(modGraphOutput).WriteMermaid(&buffer, options); err != nil {
+		t.Fatal(err)
+	}
+	if buffer.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buffer.String())
+	}
+}
@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// Represents a module in the dependency graph.
+// The main module of the project has no version.
+type Module struct {
+	Path    string
+	Version string
+}
+
+// Creates a module from the "path@version" notation used by go mod graph.
+func NewModule(id string) Module {
+	nameAndVersion := strings.SplitN(id, "@", 2)
+	if len(nameAndVersion) < 2 {
+		return Module{Path: nameAndVersion[0]}
+	}
+	return Module{Path: nameAndVersion[0], Version: nameAndVersion[1]}
+}
+
+// Returns the module in the "path@version" notation.
+func (module Module) String() string {
+	if module.Version == "" {
+		return module.Path
+	}
+	return module.Path + "@" + module.Version
+}
+
+// Represents a parent -> child requirement between two modules.
+type Edge struct {
+	From Module
+	To   Module
+}
+
+// Represents the output of go mod graph, keeping the parent -> child edges between the modules.
+type DependencyGraph struct {
+	root     Module
+	nodes    map[string]Module
+	children map[string][]string
+	parents  map[string][]string
+}
+
+// Creates a graph of the main module. A root without a path creates an empty graph, which has no nodes.
+func NewDependencyGraph(root Module) *DependencyGraph {
+	graph := &DependencyGraph{
+		root:     root,
+		nodes:    map[string]Module{},
+		children: map[string][]string{},
+		parents:  map[string][]string{},
+	}
+	if root.Path != "" {
+		graph.AddModule(root)
+	}
+	return graph
+}
+
+// Parses the output of go mod graph.
+// The root of the graph is the main module, which is the only module listed without a version.
+// An empty output, or one without the main module, has an empty root.
+// The go@version and toolchain@version nodes printed by go 1.21 and higher are skipped, since they aren't modules.
+func ParseModGraph(output string) *DependencyGraph {
+	var edges []Edge
+	root := Module{}
+	for _, line := range strings.Split(output, "\n") {
+		splitLine := strings.Split(strings.TrimSpace(line), " ")
+		if len(splitLine) != 2 {
+			continue
+		}
+		edge := Edge{From: NewModule(splitLine[0]), To: NewModule(splitLine[1])}
+		if isGoVersionModule(edge.From) || isGoVersionModule(edge.To) {
+			continue
+		}
+		if root.Path == "" && edge.From.Version == "" {
+			root = edge.From
+		}
+		edges = append(edges, edge)
+	}
+	graph := NewDependencyGraph(root)
+	for _, edge := range edges {
+		graph.AddEdge(edge.From, edge.To)
+	}
+	return graph
+}
+
+// Returns true for the go and toolchain pseudo-modules, which go mod graph prints for the go and toolchain
+// directives of go.mod files.
+func isGoVersionModule(module Module) bool {
+	return module.Path == "go" || module.Path == "toolchain"
+}
+
+// Returns the main module, or an empty module if the graph has no main module.
+func (graph *DependencyGraph) Root() Module {
+	return graph.root
+}
+
+// Adds a module to the graph, if not already added.
+func (graph *DependencyGraph) AddModule(module Module) {
+	graph.nodes[module.String()] = module
+}
+
+// Adds a requirement of parent on child. Both modules are added to the graph if needed.
+func (graph *DependencyGraph) AddEdge(parent, child Module) {
+	graph.AddModule(parent)
+	graph.AddModule(child)
+	parentId, childId := parent.String(), child.String()
+	for _, id := range graph.children[parentId] {
+		if id == childId {
+			return
+		}
+	}
+	graph.children[parentId] = append(graph.children[parentId], childId)
+	graph.parents[childId] = append(graph.parents[childId], parentId)
+}
+
+// Returns true if the module is part of the graph.
+func (graph *DependencyGraph) Contains(module Module) bool {
+	_, exists := graph.nodes[module.String()]
+	return exists
+}
+
+// Returns all the modules of the graph, including the root, sorted by their "path@version" notation.
+func (graph *DependencyGraph) Nodes() []Module {
+	var ids []string
+	for id := range graph.nodes {
+		ids = append(ids, id)
+	}
+	return graph.toModules(ids)
+}
+
+// Returns all the edges of the graph, sorted by parent and then by child.
+func (graph *DependencyGraph) Edges() []Edge {
+	var edges []Edge
+	for _, parent := range graph.Nodes() {
+		for _, child := range graph.Children(parent) {
+			edges = append(edges, Edge{From: parent, To: child})
+		}
+	}
+	return edges
+}
+
+// Returns the modules directly required by the module.
+func (graph *DependencyGraph) Children(module Module) []Module {
+	return graph.toModules(graph.children[module.String()])
+}
+
+// Returns the modules directly requiring the module.
+func (graph *DependencyGraph) Parents(module Module) []Module {
+	return graph.toModules(graph.parents[module.String()])
+}
+
+// Returns all the modules from which the module can be reached.
+func (graph *DependencyGraph) Ancestors(module Module) []Module {
+	return graph.toModules(graph.collect(module.String(), graph.parents))
+}
+
+// Returns all the modules that can be reached from the module.
+func (graph *DependencyGraph) Descendants(module Module) []Module {
+	return graph.toModules(graph.collect(module.String(), graph.children))
+}
+
+// Returns the shortest chain of requirements from the root to the module, including both.
+// Answers the question "why is this module here?". Returns nil if the module can't be reached from the root.
+func (graph *DependencyGraph) PathTo(module Module) []Module {
+	target := module.String()
+	rootId := graph.root.String()
+	previous := map[string]string{rootId: ""}
+	queue := []string{rootId}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == target {
+			var path []Module
+			for id := current; id != ""; id = previous[id] {
+				path = append([]Module{graph.nodes[id]}, path...)
+			}
+			return path
+		}
+		for _, child := range graph.children[current] {
+			if _, visited := previous[child]; !visited {
+				previous[child] = current
+				queue = append(queue, child)
+			}
+		}
+	}
+	return nil
+}
+
+// Walks the graph from the given module using the provided adjacency map.
+// Returns the ids of all the visited modules, excluding the starting one.
+func (graph *DependencyGraph) collect(id string, adjacency map[string][]string) []string {
+	visited := map[string]bool{id: true}
+	var result []string
+	queue := []string{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range adjacency[current] {
+			if !visited[next] {
+				visited[next] = true
+				result = append(result, next)
+				queue = append(queue, next)
+			}
+		}
+	}
+	return result
+}
+
+func (graph *DependencyGraph) toModules(ids []string) []Module {
+	sorted := append([]string(nil), ids...)
+	sort.Strings(sorted)
+	modules := make([]Module, 0, len(sorted))
+	for _, id := range sorted {
+		modules = append(modules, graph.nodes[id])
+	}
+	return modules
+}
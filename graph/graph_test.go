@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+const modGraphOutput = `github.com/you/hello github.com/mholt/archiver@v2.1.0+incompatible
+github.com/you/hello rsc.io/quote@v1.5.2
+github.com/you/hello golang.org/x/text@v0.3.1
+rsc.io/quote@v1.5.2 rsc.io/sampler@v1.3.0
+rsc.io/sampler@v1.3.0 golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c
+	`
+
+func TestParseModGraph(t *testing.T) {
+	graph := ParseModGraph(modGraphOutput)
+	if graph.Root() != (Module{Path: "github.com/you/hello"}) {
+		t.Error("Expecting root github.com/you/hello, got:", graph.Root())
+	}
+	if len(graph.Nodes()) != 6 {
+		t.Error("Expecting 6 nodes, got:", len(graph.Nodes()))
+	}
+	if len(graph.Edges()) != 5 {
+		t.Error("Expecting 5 edges, got:", len(graph.Edges()))
+	}
+	expected := []Module{{"rsc.io/sampler", "v1.3.0"}}
+	if actual := graph.Children(NewModule("rsc.io/quote@v1.5.2")); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expecting: %v, Got: %v", expected, actual)
+	}
+}
+
+// The output of go mod graph of go 1.22, with the go and toolchain directives of the modules.
+const goVersionsModGraphOutput = `example.com/hello example.com/dep@v1.0.0
+example.com/hello go@1.22
+example.com/hello toolchain@go1.22
+example.com/dep@v1.0.0 go@1.22
+go@1.22 toolchain@go1.22
+`
+
+func TestParseModGraphGoVersions(t *testing.T) {
+	graph := ParseModGraph(goVersionsModGraphOutput)
+	if graph.Root() != (Module{Path: "example.com/hello"}) {
+		t.Error("Expecting root example.com/hello, got:", graph.Root())
+	}
+	expected := []Module{{Path: "example.com/hello"}, {"example.com/dep", "v1.0.0"}}
+	if actual := graph.BuildList(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expecting: %v, Got: %v", expected, actual)
+	}
+	if len(graph.Nodes()) != 2 || len(graph.Edges()) != 1 {
+		t.Errorf("Expecting 2 nodes and 1 edge, got: %v, %v", graph.Nodes(), graph.Edges())
+	}
+}
+
+func TestParseEmptyModGraph(t *testing.T) {
+	for _, output := range []string{"", "\n  \n"} {
+		graph := ParseModGraph(output)
+		if graph.Root() != (Module{}) {
+			t.Error("Expecting an empty root, got:", graph.Root())
+		}
+		if len(graph.Nodes()) != 0 || len(graph.Edges()) != 0 {
+			t.Errorf("Expecting no nodes and edges, got: %v, %v", graph.Nodes(), graph.Edges())
+		}
+		if graph.Contains(Module{}) {
+			t.Error("Expecting the empty root not to be a node of the graph")
+		}
+	}
+}
+
+func TestTraversal(t *testing.T) {
+	graph := ParseModGraph(modGraphOutput)
+	oldText := NewModule("golang.org/x/text@v0.0.0-20170915032832-14c0d48ead0c")
+	tests := []struct {
+		name     string
+		actual   []Module
+		expected []Module
+	}{
+		{"ancestors", graph.Ancestors(oldText), []Module{{"github.com/you/hello", ""}, {"rsc.io/quote", "v1.5.2"}, {"rsc.io/sampler", "v1.3.0"}}},
+		{"descendants", graph.Descendants(NewModule("rsc.io/quote@v1.5.2")), []Module{oldText, {"rsc.io/sampler", "v1.3.0"}}},
+		{"pathTo", graph.PathTo(oldText), []Module{{"github.com/you/hello", ""}, {"rsc.io/quote", "v1.5.2"}, {"rsc.io/sampler", "v1.3.0"}, oldText}},
+		{"pathToMissing", graph.PathTo(NewModule("rsc.io/missing@v1.0.0")), nil},
+		{"descendantsOfLeaf", graph.Descendants(oldText), []Module{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if !reflect.DeepEqual(test.expected, test.actual) {
+				t.Errorf("Test name: %s: Expected: %v, Got: %v", test.name, test.expected, test.actual)
+			}
+		})
+	}
+}
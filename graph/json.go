@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+)
+
+// The version of the JSON schema of GraphDocument. It is incremented only for incompatible changes of the schema.
+const GraphSchemaVersion = 1
+
+// The JSON serialization of a dependency graph, which can be consumed without this package.
+type GraphDocument struct {
+	SchemaVersion int `json:"schemaVersion"`
+	// The "path@version" notation of the main module, which is one of the nodes.
+	Main string `json:"main"`
+	// The go version the graph was resolved with, such as "1.21.3", if known.
+	GoVersion string         `json:"goVersion,omitempty"`
+	Nodes     []DocumentNode `json:"nodes"`
+	Edges     []DocumentEdge `json:"edges"`
+}
+
+type DocumentNode struct {
+	// The "path@version" notation of the module, referred to by the edges.
+	Id      string `json:"id"`
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+}
+
+// A requirement of one node on another, by their ids.
+type DocumentEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Creates the JSON document of the graph, with its nodes and edges sorted.
+func NewGraphDocument(graph *DependencyGraph, goVersion string) *GraphDocument {
+	document := &GraphDocument{
+		SchemaVersion: GraphSchemaVersion,
+		Main:          graph.Root().String(),
+		GoVersion:     goVersion,
+		Nodes:         []DocumentNode{},
+		Edges:         []DocumentEdge{},
+	}
+	for _, module := range graph.Nodes() {
+		document.Nodes = append(document.Nodes, DocumentNode{Id: module.String(), Path: module.Path, Version: module.Version})
+	}
+	for _, edge := range graph.Edges() {
+		document.Edges = append(document.Edges, DocumentEdge{From: edge.From.String(), To: edge.To.String()})
+	}
+	return document
+}
+
+// Reads a document written by Write. Documents of newer schema versions, or with edges of unknown nodes, are rejected.
+// The main module is required to be one of the nodes, unless the graph is empty.
+func ReadGraphDocument(reader io.Reader) (*GraphDocument, error) {
+	document := &GraphDocument{}
+	if err := json.NewDecoder(reader).Decode(document); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the dependency graph: %s", err.Error()))
+	}
+	if document.SchemaVersion < 1 || document.SchemaVersion > GraphSchemaVersion {
+		return nil, errorutils.CheckError(fmt.Errorf("Unsupported dependency graph schema version %d. Expecting up to %d.", document.SchemaVersion, GraphSchemaVersion))
+	}
+	ids := map[string]bool{}
+	for _, node := range document.Nodes {
+		ids[node.Id] = true
+	}
+	// An empty graph, such as the graph of ParseModGraph with no output, has neither nodes nor a main module.
+	if !ids[document.Main] && (document.Main != "" || len(document.Nodes) > 0) {
+		return nil, errorutils.CheckError(fmt.Errorf("The main module %q of the dependency graph is not one of its nodes.", document.Main))
+	}
+	for _, edge := range document.Edges {
+		if !ids[edge.From] || !ids[edge.To] {
+			return nil, errorutils.CheckError(fmt.Errorf("The edge %s -> %s of the dependency graph refers to an unknown node.", edge.From, edge.To))
+		}
+	}
+	return document, nil
+}
+
+// Writes the document as JSON.
+func (document *GraphDocument) Write(writer io.Writer) error {
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = writer.Write(append(content, '\n'))
+	return errorutils.CheckError(err)
+}
+
+// Returns the dependency graph of the document.
+func (document *GraphDocument) Graph() *DependencyGraph {
+	modules := map[string]Module{}
+	for _, node := range document.Nodes {
+		modules[node.Id] = Module{Path: node.Path, Version: node.Version}
+	}
+	graph := NewDependencyGraph(modules[document.Main])
+	for _, module := range modules {
+		graph.AddModule(module)
+	}
+	for _, edge := range document.Edges {
+		graph.AddEdge(modules[edge.From], modules[edge.To])
+	}
+	return graph
+}
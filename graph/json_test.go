@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGraphDocument(t *testing.T) {
+	graph := ParseModGraph(modGraphOutput)
+	document := NewGraphDocument(graph, "1.21.3")
+	var buffer bytes.Buffer
+	if err := document.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{`"schemaVersion": 1`, `"main": "github.com/you/hello"`, `"goVersion": "1.21.3"`,
+		`"id": "rsc.io/quote@v1.5.2"`, `"from": "rsc.io/quote@v1.5.2"`} {
+		if !strings.Contains(buffer.String(), expected) {
+			t.Errorf("Expected the document to contain %s, Got:\n%s", expected, buffer.String())
+		}
+	}
+
+	read, err := ReadGraphDocument(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(document, read) {
+		t.Errorf("Expected: %+v, Got: %+v", document, read)
+	}
+	readGraph := read.Graph()
+	if readGraph.Root() != graph.Root() || !reflect.DeepEqual(graph.Nodes(), readGraph.Nodes()) || !reflect.DeepEqual(graph.Edges(), readGraph.Edges()) {
+		t.Errorf("Expected the graph read to be equal to the graph written, Got: %+v", readGraph.Edges())
+	}
+}
+
+func TestEmptyGraphDocument(t *testing.T) {
+	document := NewGraphDocument(ParseModGraph(""), "")
+	var buffer bytes.Buffer
+	if err := document.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadGraphDocument(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(document, read) {
+		t.Errorf("Expected: %+v, Got: %+v", document, read)
+	}
+	if graph := read.Graph(); graph.Root() != (Module{}) || len(graph.Nodes()) != 0 || len(graph.Edges()) != 0 {
+		t.Errorf("Expected an empty graph, Got: %+v", graph.Nodes())
+	}
+}
+
+func TestReadGraphDocumentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalidJson", `{"schemaVersion": "1"}`},
+		{"newerSchema", `{"schemaVersion": 2, "main": "a", "nodes": [{"id": "a", "path": "a"}]}`},
+		{"unknownMain", `{"schemaVersion": 1, "main": "b", "nodes": [{"id": "a", "path": "a"}]}`},
+		{"noMain", `{"schemaVersion": 1, "main": "", "nodes": [{"id": "a", "path": "a"}]}`},
+		{"unknownNode", `{"schemaVersion": 1, "main": "a", "nodes": [{"id": "a", "path": "a"}], "edges": [{"from": "a", "to": "b@v1.0.0"}]}`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ReadGraphDocument(strings.NewReader(test.content)); err == nil {
+				t.Errorf("Test name: %s: Expected an error", test.name)
+			}
+		})
+	}
+}
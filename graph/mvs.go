@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"github.com/jfrog/gocmd/semver"
+	"sort"
+)
+
+// Returns the modules directly required by the module.
+type RequirementsFunc func(module Module) ([]Module, error)
+
+// Computes the build list of the root by minimal version selection: walks all the modules reachable from the root
+// through the requirements, and selects the highest version of each module path.
+// Returns the root followed by the selected versions of the other modules, sorted by their paths.
+// Unlike the go command, the replace and exclude directives of the root are not applied, and the graph is not pruned
+// for modules at go 1.17 or higher, so the build list may differ from the one of go list -m all. The requirements
+// should already reflect the directives, as the output of go mod graph does.
+func SelectVersions(root Module, requirements RequirementsFunc) ([]Module, error) {
+	selected := map[string]string{}
+	visited := map[string]bool{root.String(): true}
+	queue := []Module{root}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		required, err := requirements(current)
+		if err != nil {
+			return nil, err
+		}
+		for _, module := range required {
+			if module.Path == root.Path {
+				continue
+			}
+			if version, exists := selected[module.Path]; !exists || semver.Compare(module.Version, version) > 0 {
+				selected[module.Path] = module.Version
+			}
+			if !visited[module.String()] {
+				visited[module.String()] = true
+				queue = append(queue, module)
+			}
+		}
+	}
+
+	var paths []string
+	for path := range selected {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	buildList := []Module{root}
+	for _, path := range paths {
+		buildList = append(buildList, Module{Path: path, Version: selected[path]})
+	}
+	return buildList, nil
+}
+
+// Returns the build list of the graph's root, computed by SelectVersions over the requirements of the graph,
+// such as the output of go mod graph, without running the go command.
+func (graph *DependencyGraph) BuildList() []Module {
+	// The requirements of the graph never fail.
+	buildList, _ := SelectVersions(graph.root, graph.requirements)
+	return buildList
+}
+
+func (graph *DependencyGraph) requirements(module Module) ([]Module, error) {
+	return graph.Children(module), nil
+}
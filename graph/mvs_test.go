@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuildList(t *testing.T) {
+	tests := []struct {
+		name     string
+		modGraph string
+		expected []Module
+	}{
+		{"modGraph", modGraphOutput, []Module{{"github.com/you/hello", ""}, {"github.com/mholt/archiver", "v2.1.0+incompatible"},
+			{"golang.org/x/text", "v0.3.1"}, {"rsc.io/quote", "v1.5.2"}, {"rsc.io/sampler", "v1.3.0"}}},
+		// The example of https://research.swtch.com/vgo-mvs. The requirements of D v1.3 count, although it isn't selected.
+		{"diamond", `a b@v1.2.0
+a c@v1.2.0
+b@v1.2.0 d@v1.3.0
+c@v1.2.0 d@v1.4.0
+d@v1.3.0 e@v1.2.0
+d@v1.4.0 e@v1.1.0
+f@v1.1.0 g@v1.1.0
+`, []Module{{"a", ""}, {"b", "v1.2.0"}, {"c", "v1.2.0"}, {"d", "v1.4.0"}, {"e", "v1.2.0"}}},
+		{"cycle", "a b@v1.0.0\nb@v1.0.0 c@v1.1.0\nc@v1.1.0 b@v1.1.0\nb@v1.1.0 a@v0.1.0\n",
+			[]Module{{"a", ""}, {"b", "v1.1.0"}, {"c", "v1.1.0"}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := ParseModGraph(test.modGraph).BuildList(); !reflect.DeepEqual(test.expected, actual) {
+				t.Errorf("Test name: %s: Expected: %v, Got: %v", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSelectVersionsError(t *testing.T) {
+	expectedErr := errors.New("go.mod not found")
+	_, err := SelectVersions(Module{Path: "a"}, func(module Module) ([]Module, error) {
+		if module.Path == "a" {
+			return []Module{{"b", "v1.0.0"}}, nil
+		}
+		return nil, expectedErr
+	})
+	if err != expectedErr {
+		t.Errorf("Expected: %v, Got: %v", expectedErr, err)
+	}
+}
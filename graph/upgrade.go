@@ -0,0 +1,139 @@
+package graph
+
+import (
+	"fmt"
+)
+
+// The go.mod directives and requirements SimulateUpgrade needs beyond the graph.
+type UpgradeOptions struct {
+	// Loads the requirements of modules which aren't part of the graph, such as the upgraded version.
+	// If nil, such modules are assumed to have no requirements.
+	Requirements RequirementsFunc
+	// True if Requirements returns the requirements of the replacement of a replaced module, as the go command does.
+	RequirementsReplaced bool
+	// The replace directives of the main module, from the replaced module to its replacement.
+	// A replaced module without a version replaces all its versions.
+	Replaces []Edge
+	// The exclude directives of the main module.
+	Excludes []Module
+}
+
+// The predicted effect of upgrading a requirement of the main module.
+type UpgradeImpact struct {
+	Upgraded Module
+	// The other modules whose selected versions would change, and the modules added to or removed from the build list.
+	DependenciesDiff
+	// The replace and exclude directives conflicting with the selected versions after the upgrade.
+	Conflicts []string
+	// The replaced modules whose requirements were loaded by a Requirements function not applying the replacements,
+	// and may thus differ from the ones the go command reads from the replacement.
+	Limitations []string
+}
+
+// Predicts the effect of requiring the version of the module by the root of the graph, such as the output of
+// go mod graph, using minimal version selection. Neither go.mod nor the graph are changed.
+// The replace and exclude directives aren't applied to the modules whose requirements are loaded, nor is the graph
+// pruned, as explained by SelectVersions. Instead, the directives affected by the upgrade are reported as Conflicts,
+// and the replaced modules whose requirements were loaded without applying their replacements as Limitations.
+func (graph *DependencyGraph) SimulateUpgrade(path, version string, options UpgradeOptions) (*UpgradeImpact, error) {
+	upgraded := Module{Path: path, Version: version}
+	requirements := func(module Module) ([]Module, error) {
+		if module == graph.root {
+			return withRequirement(graph.Children(module), upgraded), nil
+		}
+		if graph.Contains(module) || options.Requirements == nil {
+			return graph.Children(module), nil
+		}
+		return options.Requirements(module)
+	}
+	after, err := SelectVersions(graph.root, requirements)
+	if err != nil {
+		return nil, err
+	}
+	beforeVersions, afterVersions := buildListVersions(graph.BuildList()), buildListVersions(after)
+	diff := diffVersions(beforeVersions, afterVersions)
+
+	impact := &UpgradeImpact{Upgraded: upgraded, DependenciesDiff: DependenciesDiff{Added: diff.Added, Removed: diff.Removed}}
+	for _, change := range diff.Changed {
+		if change.Path != path {
+			impact.Changed = append(impact.Changed, change)
+		}
+	}
+	for _, modulePath := range sortedKeys(afterVersions) {
+		if beforeVersions[modulePath] != afterVersions[modulePath] {
+			impact.Conflicts = append(impact.Conflicts, findConflicts(modulePath, beforeVersions[modulePath], afterVersions[modulePath], options)...)
+			selectedModule := Module{Path: modulePath, Version: afterVersions[modulePath]}
+			if options.Requirements == nil || options.RequirementsReplaced || graph.Contains(selectedModule) {
+				continue
+			}
+			if replacement, replaced := findReplacement(selectedModule, options.Replaces); replaced {
+				impact.Limitations = append(impact.Limitations, fmt.Sprintf("The requirements of %s were read from the module rather than from its replacement %s.", selectedModule, replacement))
+			}
+		}
+	}
+	return impact, nil
+}
+
+// Returns the replacement of the module by the replace directives, preferring a directive of its version over one
+// replacing all the versions, as the go command does.
+func findReplacement(module Module, replaces []Edge) (Module, bool) {
+	var replacement Module
+	found := false
+	for _, replace := range replaces {
+		if replace.From == module {
+			return replace.To, true
+		}
+		if replace.From.Path == module.Path && replace.From.Version == "" {
+			replacement, found = replace.To, true
+		}
+	}
+	return replacement, found
+}
+
+// Returns the requirements with the version of the required module set, or added if not required.
+func withRequirement(requirements []Module, required Module) []Module {
+	var result []Module
+	found := false
+	for _, requirement := range requirements {
+		if requirement.Path == required.Path {
+			requirement, found = required, true
+		}
+		result = append(result, requirement)
+	}
+	if !found {
+		result = append(result, required)
+	}
+	return result
+}
+
+// Returns the versions of the build list by their module paths, excluding the root.
+func buildListVersions(buildList []Module) map[string]string {
+	versions := map[string]string{}
+	for _, module := range buildList[1:] {
+		versions[module.Path] = module.Version
+	}
+	return versions
+}
+
+// Returns the conflicts of the directives with changing the selected version of the module.
+// before is empty for modules added to the build list.
+func findConflicts(path, before, after string, options UpgradeOptions) []string {
+	var conflicts []string
+	for _, replace := range options.Replaces {
+		if replace.From.Path != path {
+			continue
+		}
+		switch replace.From.Version {
+		case "":
+			conflicts = append(conflicts, fmt.Sprintf("All the versions of %s are replaced by %s, so %s would not be used.", path, replace.To, after))
+		case before:
+			conflicts = append(conflicts, fmt.Sprintf("The replacement of %s by %s would no longer apply to the selected version %s.", replace.From, replace.To, after))
+		}
+	}
+	for _, exclude := range options.Excludes {
+		if exclude.Path == path && exclude.Version == after {
+			conflicts = append(conflicts, fmt.Sprintf("The selected version %s@%s is excluded.", path, after))
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimulateUpgrade(t *testing.T) {
+	var loaded []Module
+	options := UpgradeOptions{
+		Requirements: func(module Module) ([]Module, error) {
+			loaded = append(loaded, module)
+			if module == (Module{"rsc.io/quote", "v1.5.3"}) {
+				return []Module{{"rsc.io/sampler", "v1.99.0"}, {"golang.org/x/net", "v0.1.0"}}, nil
+			}
+			return nil, nil
+		},
+		Replaces: []Edge{{From: Module{"rsc.io/sampler", "v1.3.0"}, To: Module{Path: "../sampler"}}, {From: Module{Path: "rsc.io/quote"}, To: Module{"example.com/quote", "v1.0.0"}}},
+		Excludes: []Module{{"golang.org/x/net", "v0.1.0"}, {"rsc.io/sampler", "v1.3.0"}},
+	}
+	impact, err := ParseModGraph(modGraphOutput).SimulateUpgrade("rsc.io/quote", "v1.5.3", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &UpgradeImpact{
+		Upgraded: Module{"rsc.io/quote", "v1.5.3"},
+		DependenciesDiff: DependenciesDiff{
+			Added:   []Module{{"golang.org/x/net", "v0.1.0"}},
+			Changed: []VersionChange{{"rsc.io/sampler", "v1.3.0", "v1.99.0"}},
+		},
+		Conflicts: []string{
+			"The selected version golang.org/x/net@v0.1.0 is excluded.",
+			"All the versions of rsc.io/quote are replaced by example.com/quote@v1.0.0, so v1.5.3 would not be used.",
+			"The replacement of rsc.io/sampler@v1.3.0 by ../sampler would no longer apply to the selected version v1.99.0.",
+		},
+		Limitations: []string{"The requirements of rsc.io/quote@v1.5.3 were read from the module rather than from its replacement example.com/quote@v1.0.0."},
+	}
+	if !reflect.DeepEqual(expected, impact) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, impact)
+	}
+	expectedLoaded := []Module{{"rsc.io/quote", "v1.5.3"}, {"rsc.io/sampler", "v1.99.0"}, {"golang.org/x/net", "v0.1.0"}}
+	if !reflect.DeepEqual(expectedLoaded, loaded) {
+		t.Errorf("Expected loading the requirements of: %v, Got: %v", expectedLoaded, loaded)
+	}
+
+	// Requiring a new module, without loading requirements.
+	impact, err = ParseModGraph(modGraphOutput).SimulateUpgrade("golang.org/x/text", "v0.3.2", UpgradeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(impact.Added) != 0 || len(impact.Removed) != 0 || len(impact.Changed) != 0 || len(impact.Conflicts) != 0 {
+		t.Errorf("Expected no impact on other modules, Got: %+v", impact)
+	}
+}
@@ -0,0 +1,153 @@
+package license
+
+import (
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/log"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The identifier of licenses which couldn't be classified.
+const Unknown = "NOASSERTION"
+
+// The largest license file that is classified. Larger files are reported as unknown.
+const maxLicenseFileSize = 1 << 20
+
+var (
+	licenseFileName       = regexp.MustCompile(`(?i)^(un)?licen[cs]e|^copying`)
+	spdxIdentifierPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+\-]+)`)
+	whitespacePattern     = regexp.MustCompile(`\s+`)
+)
+
+// A license file found in a module.
+type License struct {
+	// The path of the file, relative to the module directory.
+	File string
+	// The SPDX identifier of the license, such as "MIT", or Unknown.
+	SpdxId string
+}
+
+// The licenses found in a module.
+type ModuleLicenses struct {
+	// The module in the "path@version" notation.
+	Module   string
+	Dir      string
+	Licenses []License
+	// The error reading the module directory, if any.
+	Err error
+}
+
+// Returns true if no license file was found, or if none of the license files could be classified.
+func (moduleLicenses *ModuleLicenses) IsUnknown() bool {
+	for _, license := range moduleLicenses.Licenses {
+		if license.SpdxId != Unknown {
+			return false
+		}
+	}
+	return true
+}
+
+// The text fragments identifying a license. All the fragments should appear in the normalized license text.
+// Licenses whose text includes the text of other licenses, such as the LGPL, appear first.
+var signatures = []struct {
+	spdxId    string
+	fragments []string
+}{
+	{"AGPL-3.0", []string{"gnu affero general public license", "version 3"}},
+	{"LGPL-3.0", []string{"gnu lesser general public license", "version 3"}},
+	{"LGPL-2.1", []string{"gnu lesser general public license", "version 2.1"}},
+	{"GPL-3.0", []string{"gnu general public license", "version 3"}},
+	{"GPL-2.0", []string{"gnu general public license", "version 2"}},
+	{"Apache-2.0", []string{"apache license", "version 2.0"}},
+	{"MPL-2.0", []string{"mozilla public license", "2.0"}},
+	{"EPL-2.0", []string{"eclipse public license", "2.0"}},
+	{"BSL-1.0", []string{"boost software license"}},
+	{"CC0-1.0", []string{"cc0 1.0 universal"}},
+	{"Unlicense", []string{"this is free and unencumbered software released into the public domain"}},
+	{"ISC", []string{"permission to use, copy, modify, and/or distribute this software for any purpose with or without fee is hereby granted"}},
+	{"BSD-3-Clause", []string{"redistribution and use in source and binary forms", "neither the name"}},
+	{"BSD-2-Clause", []string{"redistribution and use in source and binary forms"}},
+	{"MIT", []string{"permission is hereby granted, free of charge"}},
+}
+
+// Detects and classifies the license files in the root directory of a module.
+func DetectLicenses(moduleDir string) ([]License, error) {
+	files, err := ioutil.ReadDir(moduleDir)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var licenses []License
+	for _, file := range files {
+		if file.IsDir() || !licenseFileName.MatchString(file.Name()) {
+			continue
+		}
+		spdxId := Unknown
+		if file.Size() <= maxLicenseFileSize {
+			content, err := ioutil.ReadFile(filepath.Join(moduleDir, file.Name()))
+			if err != nil {
+				return nil, errorutils.CheckError(err)
+			}
+			spdxId = Classify(string(content))
+		}
+		log.Debug("Found license file", file.Name(), "in", moduleDir, "classified as", spdxId)
+		licenses = append(licenses, License{File: file.Name(), SpdxId: spdxId})
+	}
+	return licenses, nil
+}
+
+// Returns the SPDX identifier of the license text, or Unknown.
+// An explicit "SPDX-License-Identifier" tag in the text takes precedence.
+func Classify(text string) string {
+	if match := spdxIdentifierPattern.FindStringSubmatch(text); match != nil {
+		return match[1]
+	}
+	normalized := whitespacePattern.ReplaceAllString(strings.ToLower(text), " ")
+	for _, signature := range signatures {
+		if containsAll(normalized, signature.fragments) {
+			return signature.spdxId
+		}
+	}
+	return Unknown
+}
+
+// Detects the licenses of the modules, given the directory of each module by its "path@version" notation.
+// Failing to read a module directory doesn't stop the detection. The error is reported in the module's result.
+// The results are sorted by the modules.
+func DetectModulesLicenses(moduleDirs map[string]string) []ModuleLicenses {
+	var modules []string
+	for module := range moduleDirs {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	var report []ModuleLicenses
+	for _, module := range modules {
+		licenses, err := DetectLicenses(moduleDirs[module])
+		report = append(report, ModuleLicenses{Module: module, Dir: moduleDirs[module], Licenses: licenses, Err: err})
+	}
+	return report
+}
+
+// Returns the directories in the module cache of the downloaded modules, by their "path@version" notation.
+// Modules which failed to download are not included.
+func GetModuleDirs(downloads []cmd.ModuleDownload) map[string]string {
+	moduleDirs := map[string]string{}
+	for _, download := range downloads {
+		if download.Error == "" && download.Dir != "" {
+			moduleDirs[download.Path+"@"+download.Version] = download.Dir
+		}
+	}
+	return moduleDirs
+}
+
+func containsAll(text string, fragments []string) bool {
+	for _, fragment := range fragments {
+		if !strings.Contains(text, fragment) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package license
+
+import (
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const mitText = `MIT License
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction`
+
+const bsdText = `Redistribution and use in source and binary forms, with or without
+modification, are permitted provided that the following conditions are met:
+   * Neither the name of Google Inc. nor the names of its
+contributors may be used to endorse or promote products derived from`
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"mit", mitText, "MIT"},
+		{"bsd3", bsdText, "BSD-3-Clause"},
+		{"apache", "Apache License\n   Version 2.0, January 2004", "Apache-2.0"},
+		{"lgpl", "GNU LESSER GENERAL PUBLIC LICENSE\nVersion 3, 29 June 2007\n... the GNU General Public License", "LGPL-3.0"},
+		{"spdxTag", "// SPDX-License-Identifier: MPL-2.0", "MPL-2.0"},
+		{"unknown", "All rights reserved.", Unknown},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := Classify(test.text); actual != test.expected {
+				t.Errorf("Test name: %s: Expected: %s, Got: %s", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDetectModulesLicenses(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "licenseTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	files := map[string]string{
+		"licensed/LICENSE":       mitText,
+		"licensed/COPYING.md":    "Proprietary",
+		"licensed/license/a.txt": bsdText,
+		"unlicensed/main.go":     "package main",
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, filepath.FromSlash(name))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	downloads := []cmd.ModuleDownload{
+		{Path: "github.com/licensed", Version: "v1.0.0", Dir: filepath.Join(tempDir, "licensed")},
+		{Path: "github.com/unlicensed", Version: "v1.0.0", Dir: filepath.Join(tempDir, "unlicensed")},
+		{Path: "github.com/missing", Version: "v1.0.0", Dir: filepath.Join(tempDir, "missing")},
+		{Path: "github.com/failed", Version: "v1.0.0", Error: "not found"},
+	}
+	report := DetectModulesLicenses(GetModuleDirs(downloads))
+	if len(report) != 3 {
+		t.Fatal("Expecting 3 modules, got:", len(report))
+	}
+	expected := []License{{"COPYING.md", Unknown}, {"LICENSE", "MIT"}}
+	if report[0].Module != "github.com/licensed@v1.0.0" || !reflect.DeepEqual(expected, report[0].Licenses) || report[0].IsUnknown() {
+		t.Errorf("Expected: %v, Got: %v", expected, report[0])
+	}
+	if report[1].Module != "github.com/missing@v1.0.0" || report[1].Err == nil {
+		t.Error("Expecting an error for a missing module directory, got:", report[1])
+	}
+	if report[2].Module != "github.com/unlicensed@v1.0.0" || len(report[2].Licenses) != 0 || !report[2].IsUnknown() {
+		t.Error("Expecting no licenses, got:", report[2])
+	}
+}
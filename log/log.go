@@ -0,0 +1,136 @@
+package log
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// The severity of a log entry.
+type Level int
+
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+func (level Level) String() string {
+	switch level {
+	case DebugLevel:
+		return "debug"
+	case InfoLevel:
+		return "info"
+	case WarnLevel:
+		return "warn"
+	case ErrorLevel:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(level))
+}
+
+// Structured data attached to a log entry, such as the command or the module it refers to.
+type Fields map[string]interface{}
+
+// Receives the log entries of this module.
+type Logger interface {
+	Log(level Level, message string, fields Fields)
+}
+
+var logger Logger = NewClientLogger()
+var loggerMutex sync.Mutex
+
+// Sets the logger receiving the log entries which aren't sent to a logger of their own, such as the logger of a cmd.Runner.
+// By default, the entries are logged by the jfrog-client-go logger.
+func SetLogger(newLogger Logger) {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	logger = newLogger
+}
+
+// Returns the logger set by SetLogger.
+func GetLogger() Logger {
+	loggerMutex.Lock()
+	defer loggerMutex.Unlock()
+	return logger
+}
+
+// Logs entries with fields to a logger.
+type Entry struct {
+	// If nil, the logger set by SetLogger is used.
+	Logger Logger
+	Fields Fields
+}
+
+// Returns an entry logging to logger. If logger is nil, the entry logs to the logger set by SetLogger.
+func NewEntry(logger Logger) *Entry {
+	return &Entry{Logger: logger}
+}
+
+// Returns an entry with the fields of entry and the given fields.
+func (entry *Entry) WithFields(fields Fields) *Entry {
+	merged := Fields{}
+	for key, value := range entry.Fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return &Entry{Logger: entry.Logger, Fields: merged}
+}
+
+// Returns an entry with the fields of entry and the given field.
+func (entry *Entry) WithField(key string, value interface{}) *Entry {
+	return entry.WithFields(Fields{key: value})
+}
+
+func (entry *Entry) Debug(a ...interface{}) {
+	entry.log(DebugLevel, a)
+}
+
+func (entry *Entry) Info(a ...interface{}) {
+	entry.log(InfoLevel, a)
+}
+
+func (entry *Entry) Warn(a ...interface{}) {
+	entry.log(WarnLevel, a)
+}
+
+func (entry *Entry) Error(a ...interface{}) {
+	entry.log(ErrorLevel, a)
+}
+
+func (entry *Entry) log(level Level, a []interface{}) {
+	logger := entry.Logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+	logger.Log(level, formatMessage(a), entry.Fields)
+}
+
+// Returns an entry with the fields, logging to the logger set by SetLogger.
+func WithFields(fields Fields) *Entry {
+	return NewEntry(nil).WithFields(fields)
+}
+
+func Debug(a ...interface{}) {
+	NewEntry(nil).log(DebugLevel, a)
+}
+
+func Info(a ...interface{}) {
+	NewEntry(nil).log(InfoLevel, a)
+}
+
+func Warn(a ...interface{}) {
+	NewEntry(nil).log(WarnLevel, a)
+}
+
+func Error(a ...interface{}) {
+	NewEntry(nil).log(ErrorLevel, a)
+}
+
+// Joins the values with spaces, like the jfrog-client-go logger does.
+func formatMessage(a []interface{}) string {
+	return strings.TrimSuffix(fmt.Sprintln(a...), "\n")
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+type recordedEntry struct {
+	level   Level
+	message string
+	fields  Fields
+}
+
+type recordingLogger struct {
+	entries []recordedEntry
+}
+
+func (logger *recordingLogger) Log(level Level, message string, fields Fields) {
+	logger.entries = append(logger.entries, recordedEntry{level, message, fields})
+}
+
+func TestEntry(t *testing.T) {
+	logger := &recordingLogger{}
+	entry := NewEntry(logger).WithField("command", "go mod graph")
+	entry.WithFields(Fields{"dir": "/project"}).Info("Running 'go mod graph' in", "/project")
+	entry.Debug("Done")
+
+	expected := []recordedEntry{
+		{InfoLevel, "Running 'go mod graph' in /project", Fields{"command": "go mod graph", "dir": "/project"}},
+		{DebugLevel, "Done", Fields{"command": "go mod graph"}},
+	}
+	if !reflect.DeepEqual(logger.entries, expected) {
+		t.Errorf("Expected entries: %v, Got: %v", expected, logger.entries)
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(GetLogger())
+	logger := &recordingLogger{}
+	SetLogger(logger)
+	Warn("Attempt", 1, "failed")
+	expected := []recordedEntry{{WarnLevel, "Attempt 1 failed", nil}}
+	if !reflect.DeepEqual(logger.entries, expected) {
+		t.Errorf("Expected entries: %v, Got: %v", expected, logger.entries)
+	}
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	clientlog "github.com/jfrog/jfrog-client-go/utils/log"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Logs the entries with the jfrog-client-go logger, appending the fields to the message as key=value pairs.
+type ClientLogger struct{}
+
+func NewClientLogger() *ClientLogger {
+	return &ClientLogger{}
+}
+
+func (logger *ClientLogger) Log(level Level, message string, fields Fields) {
+	if len(fields) > 0 {
+		message += " " + formatFields(fields)
+	}
+	switch level {
+	case DebugLevel:
+		clientlog.Debug(message)
+	case InfoLevel:
+		clientlog.Info(message)
+	case WarnLevel:
+		clientlog.Warn(message)
+	default:
+		clientlog.Error(message)
+	}
+}
+
+// Writes the entries of the level and above as lines of text, such as:
+// 2019-06-30T10:00:00Z [info] Running 'go mod graph' dir=/project
+type TextLogger struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	level  Level
+}
+
+func NewTextLogger(writer io.Writer, level Level) *TextLogger {
+	return &TextLogger{writer: writer, level: level}
+}
+
+func (logger *TextLogger) Log(level Level, message string, fields Fields) {
+	if level < logger.level {
+		return
+	}
+	line := fmt.Sprintf("%s [%s] %s", time.Now().UTC().Format(time.RFC3339), level, message)
+	if len(fields) > 0 {
+		line += " " + formatFields(fields)
+	}
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	fmt.Fprintln(logger.writer, line)
+}
+
+// Writes the entries of the level and above as JSON lines, to be ingested by log aggregators, such as:
+// {"dir":"/project","level":"info","msg":"Running 'go mod graph'","time":"2019-06-30T10:00:00Z"}
+// The fields are written as keys of the JSON object, next to the time, level and msg keys.
+type JSONLogger struct {
+	mutex  sync.Mutex
+	writer io.Writer
+	level  Level
+}
+
+func NewJSONLogger(writer io.Writer, level Level) *JSONLogger {
+	return &JSONLogger{writer: writer, level: level}
+}
+
+func (logger *JSONLogger) Log(level Level, message string, fields Fields) {
+	if level < logger.level {
+		return
+	}
+	entry := map[string]interface{}{}
+	for key, value := range fields {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		entry[key] = value
+	}
+	entry["time"] = time.Now().UTC().Format(time.RFC3339)
+	entry["level"] = level.String()
+	entry["msg"] = message
+	content, err := json.Marshal(entry)
+	if err != nil {
+		content, _ = json.Marshal(map[string]interface{}{"time": entry["time"], "level": entry["level"], "msg": message, "logError": err.Error()})
+	}
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+	logger.writer.Write(append(content, '\n'))
+}
+
+// Formats the fields as key=value pairs sorted by key. Values with spaces are quoted.
+func formatFields(fields Fields) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		value := fmt.Sprint(fields[key])
+		if strings.ContainsAny(value, " \t\n\"") {
+			value = fmt.Sprintf("%q", value)
+		}
+		pairs[i] = key + "=" + value
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewJSONLogger(&buffer, InfoLevel)
+	logger.Log(DebugLevel, "Filtered", nil)
+	logger.Log(WarnLevel, "Retrying", Fields{"attempt": 1, "error": errors.New("i/o timeout")})
+
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected a single line, Got: %q", buffer.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "warn" || entry["msg"] != "Retrying" || entry["attempt"] != float64(1) || entry["error"] != "i/o timeout" || entry["time"] == nil {
+		t.Errorf("Unexpected entry: %v", entry)
+	}
+}
+
+func TestTextLogger(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := NewTextLogger(&buffer, DebugLevel)
+	logger.Log(InfoLevel, "Running 'go mod graph'", Fields{"dir": "/project", "command": "go mod graph"})
+	expectedSuffix := " [info] Running 'go mod graph' command=\"go mod graph\" dir=/project\n"
+	if !strings.HasSuffix(buffer.String(), expectedSuffix) {
+		t.Errorf("Expected a line ending with: %q, Got: %q", expectedSuffix, buffer.String())
+	}
+}
@@ -0,0 +1,48 @@
+package modfile
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	gomodfile "golang.org/x/mod/modfile"
+)
+
+// An exclude directive.
+type Exclude struct {
+	Path    string
+	Version string
+}
+
+func (exclude Exclude) String() string {
+	return exclude.Path + " " + exclude.Version
+}
+
+// Returns the exclude directives, in the order they appear.
+func (modFile *ModFile) Excludes() []Exclude {
+	var excludes []Exclude
+	for _, exclude := range modFile.mod.Exclude {
+		excludes = append(excludes, Exclude{Path: exclude.Mod.Path, Version: exclude.Mod.Version})
+	}
+	return excludes
+}
+
+// Excludes the version of the module. Does nothing if the version is already excluded.
+func (modFile *ModFile) AddExclude(path, version string) error {
+	if path == "" || !isVersion(version) {
+		return errorutils.CheckError(fmt.Errorf("Invalid exclude of %q at version %q.", path, version))
+	}
+	return modFile.edit(func(mod *gomodfile.File) error {
+		return mod.AddExclude(path, version)
+	}, nil)
+}
+
+// Removes the exclude directive of the version of the module. Returns an error if no such directive exists.
+func (modFile *ModFile) RemoveExclude(path, version string) error {
+	for _, exclude := range modFile.mod.Exclude {
+		if exclude.Mod.Path == path && exclude.Mod.Version == version {
+			return modFile.edit(func(mod *gomodfile.File) error {
+				return mod.DropExclude(path, version)
+			}, nil)
+		}
+	}
+	return errorutils.CheckError(fmt.Errorf("No exclude directive of %s %s.", path, version))
+}
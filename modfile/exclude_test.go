@@ -0,0 +1,46 @@
+package modfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExcludes(t *testing.T) {
+	modFile, err := Parse([]byte("module a\n\nexclude b v1.0.0\n\nexclude (\n\tc v1.1.0\n\td v0.1.0 // Broken.\n)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Exclude{{"b", "v1.0.0"}, {"c", "v1.1.0"}, {"d", "v0.1.0"}}
+	if actual := modFile.Excludes(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
+
+func TestAddRemoveExclude(t *testing.T) {
+	modFile, err := Parse([]byte("module a\n\nexclude (\n\tb v1.0.0\n)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, exclude := range []Exclude{{"c", "v1.1.0"}, {"b", "v1.0.0"}} {
+		if err = modFile.AddExclude(exclude.Path, exclude.Version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := "module a\n\nexclude b v1.0.0\n\nexclude c v1.1.0\n"
+	if actual := string(modFile.Format()); actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+	if err = modFile.RemoveExclude("b", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err = modFile.RemoveExclude("b", "v1.0.0"); err == nil {
+		t.Error("Expecting an error for a missing exclude directive")
+	}
+	if err = modFile.AddExclude("b", "latest"); err == nil {
+		t.Error("Expecting an error for an invalid version")
+	}
+	expected = "module a\n\nexclude c v1.1.0\n"
+	if actual := string(modFile.Format()); actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+}
@@ -0,0 +1,212 @@
+package modfile
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/gocmd/semver"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"go/parser"
+	"go/token"
+	gomodfile "golang.org/x/mod/modfile"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The changes made by UpgradeMajorVersion.
+type MajorUpgrade struct {
+	OldPath string
+	NewPath string
+	// The go.mod file and the go files whose imports were rewritten.
+	ChangedFiles []string
+}
+
+// Upgrades the module in moduleDir to a new major version, such as 2 for v2: sets the major version suffix of the
+// module path in go.mod, rewrites the imports of the module's packages in the module's go files to the new path, and
+// verifies that the module builds. Vendor directories, testdata directories and nested modules are not changed.
+// The files are read from and written to the FileSystem of ctx. If writing them or the build fails, the changed files
+// are restored and an error is returned, along with the changes which were rolled back.
+func UpgradeMajorVersion(ctx context.Context, moduleDir string, major int) (*MajorUpgrade, error) {
+	modPath := filepath.Join(moduleDir, "go.mod")
+	modFile, err := ReadFile(ctx, modPath)
+	if err != nil {
+		return nil, err
+	}
+	upgrade := &MajorUpgrade{OldPath: modFile.Module()}
+	if upgrade.NewPath, err = majorVersionPath(upgrade.OldPath, major); err != nil {
+		return nil, err
+	}
+	cmd.GetLogger(ctx).Info("Upgrading", upgrade.OldPath, "to", upgrade.NewPath)
+
+	goFiles, nestedModules, err := getModuleGoFiles(ctx, moduleDir)
+	if err != nil {
+		return nil, err
+	}
+	files := cmd.GetFileSystem(ctx)
+	changedContents := map[string][]byte{}
+	for _, goFile := range goFiles {
+		content, err := fsys.ReadFile(files, goFile)
+		if err != nil {
+			return nil, err
+		}
+		rewritten, err := rewriteImports(goFile, content, upgrade.OldPath, upgrade.NewPath, nestedModules)
+		if err != nil {
+			return nil, err
+		}
+		if rewritten != nil {
+			changedContents[goFile] = rewritten
+		}
+	}
+
+	backups, err := cmd.NewFileBackupManagerWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer backups.RecoverAndRollback()
+	if err = writeMajorUpgrade(ctx, backups, upgrade, modFile, modPath, goFiles, changedContents); err == nil {
+		err = cmd.RunGo(cmd.WithModuleDir(ctx, moduleDir), []string{"build", "./..."})
+	}
+	if err != nil {
+		cmd.GetLogger(ctx).Info("Rolling back the upgrade of", upgrade.OldPath+":", err.Error())
+		if rollbackErr := backups.Rollback(); rollbackErr != nil {
+			return upgrade, rollbackErr
+		}
+		return upgrade, err
+	}
+	return upgrade, backups.Commit()
+}
+
+// Backs up and writes the go.mod file and the changed go files, adding them to the changed files of the upgrade.
+func writeMajorUpgrade(ctx context.Context, backups *cmd.FileBackupManager, upgrade *MajorUpgrade, modFile *ModFile, modPath string, goFiles []string, changedContents map[string][]byte) error {
+	if err := backups.Backup(modPath); err != nil {
+		return err
+	}
+	err := modFile.edit(func(mod *gomodfile.File) error {
+		return mod.AddModuleStmt(upgrade.NewPath)
+	}, nil)
+	if err != nil {
+		return err
+	}
+	if err = modFile.WriteFile(ctx, modPath); err != nil {
+		return err
+	}
+	upgrade.ChangedFiles = append(upgrade.ChangedFiles, modPath)
+	files := cmd.GetFileSystem(ctx)
+	for _, goFile := range goFiles {
+		content, changed := changedContents[goFile]
+		if !changed {
+			continue
+		}
+		cmd.GetLogger(ctx).Debug("Rewriting the imports of", goFile)
+		if !cmd.SkipInDryRunWithContext(ctx, "Writing "+goFile) {
+			info, err := files.Stat(goFile)
+			if err != nil {
+				return errorutils.CheckError(err)
+			}
+			if err = backups.Backup(goFile); err != nil {
+				return err
+			}
+			if err = files.WriteFile(goFile, content, info.Mode()); err != nil {
+				return errorutils.CheckError(err)
+			}
+		}
+		upgrade.ChangedFiles = append(upgrade.ChangedFiles, goFile)
+	}
+	return nil
+}
+
+// Returns the path of the module at the major version.
+func majorVersionPath(modulePath string, major int) (string, error) {
+	prefix, pathMajor, ok := semver.SplitPathMajor(modulePath)
+	if modulePath == "" || !ok {
+		return "", errorutils.CheckError(fmt.Errorf("Invalid module path: %q.", modulePath))
+	}
+	current := 1
+	if pathMajor != "" {
+		current, _ = strconv.Atoi(strings.TrimSuffix(pathMajor[2:], "-unstable"))
+	}
+	if major <= current {
+		return "", errorutils.CheckError(fmt.Errorf("Invalid major version %d of %s: expecting a version higher than %d.", major, modulePath, current))
+	}
+	if strings.HasPrefix(modulePath, "gopkg.in/") {
+		return fmt.Sprintf("%s.v%d", prefix, major), nil
+	}
+	return fmt.Sprintf("%s/v%d", prefix, major), nil
+}
+
+// Returns the go files of the module, excluding directories the go command ignores and nested modules, and the paths
+// of the nested modules.
+func getModuleGoFiles(ctx context.Context, moduleDir string) (goFiles, nestedModules []string, err error) {
+	err = filepath.Walk(moduleDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path == moduleDir {
+				return nil
+			}
+			name := info.Name()
+			if name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				nestedModFile, err := ReadFile(ctx, filepath.Join(path, "go.mod"))
+				if err != nil {
+					return err
+				}
+				nestedModules = append(nestedModules, nestedModFile.Module())
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Mode().IsRegular() && strings.HasSuffix(info.Name(), ".go") {
+			goFiles = append(goFiles, path)
+		}
+		return nil
+	})
+	return goFiles, nestedModules, errorutils.CheckError(err)
+}
+
+// Rewrites the imports of oldPath and its packages to newPath, keeping the rest of the content unchanged.
+// Packages of the nested modules are not rewritten. Returns nil if the content has no imports to rewrite.
+func rewriteImports(fileName string, content []byte, oldPath, newPath string, nestedModules []string) ([]byte, error) {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, fileName, content, parser.ImportsOnly)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	var rewritten []byte
+	last := 0
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || !hasPathPrefix(importPath, oldPath) || hasAnyPathPrefix(importPath, nestedModules) {
+			continue
+		}
+		start := fileSet.Position(spec.Path.Pos()).Offset
+		end := fileSet.Position(spec.Path.End()).Offset
+		rewritten = append(rewritten, content[last:start]...)
+		rewritten = append(rewritten, strconv.Quote(newPath+importPath[len(oldPath):])...)
+		last = end
+	}
+	if rewritten == nil {
+		return nil, nil
+	}
+	return append(rewritten, content[last:]...), nil
+}
+
+// Returns true if the path is the prefix or a package under it.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+func hasAnyPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix != "" && hasPathPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMajorVersionPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		major    int
+		expected string
+	}{
+		{"github.com/user/repo", 2, "github.com/user/repo/v2"},
+		{"github.com/user/repo/v2", 3, "github.com/user/repo/v3"},
+		{"gopkg.in/yaml.v2", 3, "gopkg.in/yaml.v3"},
+		{"github.com/user/repo/v3", 2, ""},
+		{"github.com/user/repo", 1, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			actual, err := majorVersionPath(test.path, test.major)
+			if test.expected == "" {
+				if err == nil {
+					t.Errorf("Test name: %s: Expecting an error for the major version %d", test.path, test.major)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != test.expected {
+				t.Errorf("Test name: %s: Expected: %s, Got: %s", test.path, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUpgradeMajorVersion(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "majorTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(moduleDir)
+	files := map[string]string{
+		"go.mod":           "module github.com/test/repo // Test.\n\ngo 1.12\n",
+		"main.go":          "package main\n\nimport (\n\t\"fmt\"\n\n\tlib \"github.com/test/repo/lib\"\n)\n\nfunc main() {\n\tfmt.Println(lib.Name)\n}\n",
+		"lib/lib.go":       "package lib\n\n// The \"github.com/test/repo/lib\" package.\nconst Name = \"lib\"\n",
+		"nested/go.mod":    "module github.com/test/repo/nested\n",
+		"nested/nested.go": "package nested\n\nimport _ \"github.com/test/repo/nested/sub\"\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(moduleDir, filepath.FromSlash(path))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	upgrade, err := UpgradeMajorVersion(context.Background(), moduleDir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if upgrade.NewPath != "github.com/test/repo/v2" || len(upgrade.ChangedFiles) != 2 {
+		t.Errorf("Expected the new path github.com/test/repo/v2 and 2 changed files, Got: %s, %v", upgrade.NewPath, upgrade.ChangedFiles)
+	}
+	expected := map[string]string{
+		"go.mod":           "module github.com/test/repo/v2 // Test.\n\ngo 1.12\n",
+		"main.go":          "package main\n\nimport (\n\t\"fmt\"\n\n\tlib \"github.com/test/repo/v2/lib\"\n)\n\nfunc main() {\n\tfmt.Println(lib.Name)\n}\n",
+		"lib/lib.go":       files["lib/lib.go"],
+		"nested/nested.go": files["nested/nested.go"],
+	}
+	for path, content := range expected {
+		actual, err := ioutil.ReadFile(filepath.Join(moduleDir, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != content {
+			t.Errorf("Test name: %s: Expected:\n%s\nGot:\n%s", path, content, actual)
+		}
+	}
+}
+
+func TestUpgradeMajorVersionRollback(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "majorTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(moduleDir)
+	files := map[string]string{
+		"go.mod":     "module github.com/test/repo\n\ngo 1.12\n",
+		"main.go":    "package main\n\nimport _ \"github.com/test/repo/lib\"\n\nfunc main() {}\n",
+		"lib/lib.go": "package lib\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(moduleDir, filepath.FromSlash(path))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	executor := cmd.NewFakeExecutor()
+	executor.On(cmd.ExecutorResult{Stderr: "main.go:3:8: undefined: lib\n", ExitCode: 1}, "build", "./...")
+
+	upgrade, err := UpgradeMajorVersion(cmd.WithExecutor(context.Background(), executor), moduleDir, 2)
+	if err == nil {
+		t.Fatal("Expecting the build failure")
+	}
+	if upgrade == nil || len(upgrade.ChangedFiles) != 2 {
+		t.Errorf("Expecting the 2 rolled back files, got: %+v", upgrade)
+	}
+	for path, content := range files {
+		actual, err := ioutil.ReadFile(filepath.Join(moduleDir, filepath.FromSlash(path)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(actual) != content {
+			t.Errorf("Expecting %s to be restored, got:\n%s", path, actual)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/gocmd/graph"
+	"path/filepath"
+	"sort"
+)
+
+// A go.sum entry needed for building the module, which go.sum doesn't have.
+type MissingSum struct {
+	// The module in the "path@version" notation. Replaced modules are reported by their replacements.
+	Module string
+	// True if the hash of the module's go.mod file is missing, false if the hash of the module's content is missing.
+	IsMod bool
+}
+
+// Finds the go.sum entries the go commands need for building the module in moduleDir, and are missing from its go.sum.
+// The requirements of go.mod need the hashes of their go.mod files, and the modules providing packages to the builds of
+// the module, its tests and its tools need also the hashes of their contents, whether they are direct or indirect
+// requirements. The modules providing packages are listed with go list -deps, as cmd.GetDependenciesScopes does.
+// If the dependency graph isn't nil, such as the output of go mod graph, all its modules need the hashes of their go.mod
+// files too. Modules replaced by local directories need no hashes.
+// The results are sorted by the modules.
+func FindMissingSums(ctx context.Context, moduleDir string, dependencyGraph *graph.DependencyGraph) ([]MissingSum, error) {
+	modFile, err := ReadFile(ctx, filepath.Join(moduleDir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []gosum.ModuleEntry
+	sumPath := filepath.Join(moduleDir, "go.sum")
+	if exists, err := fsys.IsFileExists(cmd.GetFileSystem(ctx), sumPath); err != nil {
+		return nil, err
+	} else if exists {
+		if entries, err = gosum.ParseGoSumFileFrom(cmd.GetFileSystem(ctx), sumPath); err != nil {
+			return nil, err
+		}
+	}
+	existing := map[MissingSum]bool{}
+	for _, entry := range entries {
+		existing[MissingSum{Module: entry.ModuleId(), IsMod: entry.IsMod}] = true
+	}
+
+	var needed []MissingSum
+	replaces := modFile.Replaces()
+	need := func(module graph.Module, isMod bool) {
+		if module = replacementOf(module, replaces); module.Version != "" {
+			needed = append(needed, MissingSum{Module: module.String(), IsMod: isMod})
+		}
+	}
+	for _, require := range modFile.Requires() {
+		need(graph.Module{Path: require.Path, Version: require.Version}, true)
+	}
+	scopes, err := cmd.GetDependenciesScopes(cmd.WithModuleDir(ctx, moduleDir))
+	if err != nil {
+		return nil, err
+	}
+	for module := range scopes {
+		need(graph.NewModule(module), false)
+	}
+	if dependencyGraph != nil {
+		for _, module := range dependencyGraph.Nodes() {
+			if module != dependencyGraph.Root() {
+				need(module, true)
+			}
+		}
+	}
+
+	var missing []MissingSum
+	for _, sum := range needed {
+		if !existing[sum] {
+			// Found missing sums are marked as existing, so they are reported once.
+			existing[sum] = true
+			missing = append(missing, sum)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].Module != missing[j].Module {
+			return missing[i].Module < missing[j].Module
+		}
+		return missing[i].IsMod && !missing[j].IsMod
+	})
+	return missing, nil
+}
+
+// Returns the go mod download commands adding the missing entries to go.sum, one per module.
+func DownloadCommands(missing []MissingSum) []string {
+	var commands []string
+	added := map[string]bool{}
+	for _, sum := range missing {
+		if !added[sum.Module] {
+			added[sum.Module] = true
+			commands = append(commands, "go mod download "+sum.Module)
+		}
+	}
+	return commands
+}
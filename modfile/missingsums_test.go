@@ -0,0 +1,87 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/graph"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFindMissingSums(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "missingSumsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(moduleDir)
+	files := map[string]string{
+		"go.mod": `module github.com/you/hello
+
+require (
+	github.com/pkg/errors v0.9.1
+	golang.org/x/text v0.3.0 // indirect
+	rsc.io/quote v1.5.2
+	rsc.io/local v1.0.0
+)
+
+replace golang.org/x/text => golang.org/x/text v0.3.2
+
+replace rsc.io/local => ../local
+`,
+		"go.sum": `github.com/pkg/errors v0.9.1 h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=
+github.com/pkg/errors v0.9.1/go.mod h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=
+rsc.io/quote v1.5.2/go.mod h1:LzX7hefJvL54yjefDEDHNONDjII0t9xZLPXsUe+TKr0=
+`,
+	}
+	for name, content := range files {
+		if err = ioutil.WriteFile(filepath.Join(moduleDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The indirect golang.org/x/text provides packages, unlike the direct github.com/pkg/errors.
+	executor := cmd.NewFakeExecutor()
+	executor.On(cmd.ExecutorResult{Stdout: "rsc.io/quote@v1.5.2\ngolang.org/x/text@v0.3.0\n"}, "list", "-deps", "-f", "{{with .Module}}{{if not .Main}}{{.Path}}@{{.Version}}{{end}}{{end}}", "./...")
+	ctx := cmd.WithExecutor(context.Background(), executor)
+	dependencyGraph := graph.ParseModGraph("github.com/you/hello rsc.io/quote@v1.5.2\nrsc.io/quote@v1.5.2 rsc.io/sampler@v1.3.0\n")
+
+	tests := []struct {
+		name     string
+		graph    *graph.DependencyGraph
+		expected []MissingSum
+		commands []string
+	}{
+		{"goMod", nil, []MissingSum{{"golang.org/x/text@v0.3.2", true}, {"golang.org/x/text@v0.3.2", false}, {"rsc.io/quote@v1.5.2", false}},
+			[]string{"go mod download golang.org/x/text@v0.3.2", "go mod download rsc.io/quote@v1.5.2"}},
+		{"graph", dependencyGraph, []MissingSum{{"golang.org/x/text@v0.3.2", true}, {"golang.org/x/text@v0.3.2", false}, {"rsc.io/quote@v1.5.2", false}, {"rsc.io/sampler@v1.3.0", true}},
+			[]string{"go mod download golang.org/x/text@v0.3.2", "go mod download rsc.io/quote@v1.5.2", "go mod download rsc.io/sampler@v1.3.0"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			missing, err := FindMissingSums(ctx, moduleDir, test.graph)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(test.expected, missing) {
+				t.Errorf("Test name: %s: Expected: %v, Got: %v", test.name, test.expected, missing)
+			}
+			if commands := DownloadCommands(missing); !reflect.DeepEqual(test.commands, commands) {
+				t.Errorf("Test name: %s: Expected: %v, Got: %v", test.name, test.commands, commands)
+			}
+		})
+	}
+
+	// Without go.sum, all the needed hashes are missing.
+	if err = os.Remove(filepath.Join(moduleDir, "go.sum")); err != nil {
+		t.Fatal(err)
+	}
+	missing, err := FindMissingSums(ctx, moduleDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 5 || !reflect.DeepEqual(missing[0], MissingSum{"github.com/pkg/errors@v0.9.1", true}) {
+		t.Errorf("Expected the hashes of all the requirements to be missing, Got: %v", missing)
+	}
+}
@@ -0,0 +1,148 @@
+package modfile
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	gomodfile "golang.org/x/mod/modfile"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A go.mod or go.work file, which can be edited while keeping its comments.
+// The file is parsed, edited and formatted with golang.org/x/mod/modfile, as 'go mod edit' does.
+type ModFile struct {
+	// The parsed go.mod file, which is empty for a go.work file.
+	mod *gomodfile.File
+	// The parsed go.work file, or nil for a go.mod file.
+	work *gomodfile.WorkFile
+}
+
+// Parses a go.mod content.
+func Parse(content []byte) (*ModFile, error) {
+	mod, err := gomodfile.Parse("go.mod", content, nil)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return &ModFile{mod: mod}, nil
+}
+
+// Parses a go.work content.
+func ParseWork(content []byte) (*ModFile, error) {
+	work, err := gomodfile.ParseWork("go.work", content, nil)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return &ModFile{mod: &gomodfile.File{}, work: work}, nil
+}
+
+// Reads and parses the go.mod or go.work file in path, from the FileSystem of ctx.
+func ReadFile(ctx context.Context, path string) (*ModFile, error) {
+	content, err := fsys.ReadFile(cmd.GetFileSystem(ctx), path)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Base(path) == "go.work" {
+		return ParseWork(content)
+	}
+	return Parse(content)
+}
+
+// Returns the content of the file.
+func (modFile *ModFile) Format() []byte {
+	if modFile.work != nil {
+		return gomodfile.Format(modFile.work.Syntax)
+	}
+	return gomodfile.Format(modFile.mod.Syntax)
+}
+
+// Writes the file to path, through the FileSystem of ctx.
+func (modFile *ModFile) WriteFile(ctx context.Context, path string) error {
+	if cmd.SkipInDryRunWithContext(ctx, "Writing "+path) {
+		return nil
+	}
+	fileSystem := cmd.GetFileSystem(ctx)
+	mode := os.FileMode(0644)
+	if info, err := fileSystem.Stat(path); err == nil {
+		mode = info.Mode()
+	}
+	return errorutils.CheckError(fileSystem.WriteFile(path, modFile.Format(), mode))
+}
+
+// Returns the module path, or an empty string if the file has no module directive.
+func (modFile *ModFile) Module() string {
+	if modFile.mod.Module != nil {
+		return modFile.mod.Module.Mod.Path
+	}
+	return ""
+}
+
+// Returns the version of the go directive, such as "1.21", or an empty string if the file has no go directive.
+func (modFile *ModFile) Go() string {
+	if modFile.work != nil && modFile.work.Go != nil {
+		return modFile.work.Go.Version
+	}
+	if modFile.mod.Go != nil {
+		return modFile.mod.Go.Version
+	}
+	return ""
+}
+
+// Returns the toolchain directive, such as "go1.21.3", or an empty string if the file has no toolchain directive.
+func (modFile *ModFile) Toolchain() string {
+	if modFile.work != nil && modFile.work.Toolchain != nil {
+		return modFile.work.Toolchain.Name
+	}
+	if modFile.mod.Toolchain != nil {
+		return modFile.mod.Toolchain.Name
+	}
+	return ""
+}
+
+// Rewrites all the lines in the canonical format, as gofmt does for go code: single spaces between the tokens and
+// tab indentation in blocks. Comments and empty lines are kept.
+//
+// Deprecated: Format always writes the canonical format, so Normalize does nothing.
+func (modFile *ModFile) Normalize() {
+}
+
+// Applies editMod to a go.mod file, or editWork to a go.work file, and parses the edited content again, so the
+// accessors reflect the edit. A nil editWork fails on go.work files. If the edit fails, or leaves the content invalid,
+// the file is left unchanged and the error is returned.
+func (modFile *ModFile) edit(editMod func(mod *gomodfile.File) error, editWork func(work *gomodfile.WorkFile) error) error {
+	content := modFile.Format()
+	var err error
+	if modFile.work != nil {
+		if editWork == nil {
+			return errorutils.CheckError(fmt.Errorf("The edit applies only to go.mod files."))
+		}
+		if err = editWork(modFile.work); err == nil {
+			modFile.work.Cleanup()
+			var edited *gomodfile.WorkFile
+			if edited, err = gomodfile.ParseWork("go.work", modFile.Format(), nil); err == nil {
+				modFile.work = edited
+				return nil
+			}
+		}
+		modFile.work, _ = gomodfile.ParseWork("go.work", content, nil)
+		return errorutils.CheckError(err)
+	}
+	if err = editMod(modFile.mod); err == nil {
+		modFile.mod.Cleanup()
+		var edited *gomodfile.File
+		if edited, err = gomodfile.Parse("go.mod", modFile.Format(), nil); err == nil {
+			modFile.mod = edited
+			return nil
+		}
+	}
+	modFile.mod, _ = gomodfile.Parse("go.mod", content, nil)
+	return errorutils.CheckError(err)
+}
+
+// Returns true if the version looks like a module version, such as v1.2.3.
+func isVersion(version string) bool {
+	return len(version) > 1 && version[0] == 'v' && version[1] >= '0' && version[1] <= '9' && !strings.ContainsAny(version, " \t,[]")
+}
@@ -0,0 +1,159 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/fsys"
+	"strings"
+	"testing"
+)
+
+const testModFile = `// A test module.
+module github.com/jfrog/test // The module path.
+
+go 1.12
+
+require (
+	github.com/jfrog/gofrog v1.0.5 // indirect
+	"github.com/pkg/errors" v0.8.1
+
+	// Testing.
+	github.com/stretchr/testify v1.3.0
+)
+
+replace github.com/pkg/errors => github.com/pkg/errors v0.9.1
+`
+
+// The content of testModFile as formatted, with the quotes of the paths which don't require them removed.
+var formattedTestModFile = strings.Replace(testModFile, `"github.com/pkg/errors"`, "github.com/pkg/errors", 1)
+
+func TestParseFormat(t *testing.T) {
+	modFile, err := Parse([]byte(testModFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := string(modFile.Format()); actual != formattedTestModFile {
+		t.Errorf("Expected:\n%s\nGot:\n%s", formattedTestModFile, actual)
+	}
+	requires := modFile.Requires()
+	if len(requires) != 3 {
+		t.Fatalf("Expected 3 require directives, Got: %d", len(requires))
+	}
+	if requires[1].Path != "github.com/pkg/errors" {
+		t.Errorf("Expected the unquoted path github.com/pkg/errors, Got: %s", requires[1].Path)
+	}
+	if !requires[0].Indirect {
+		t.Errorf("Expected the requirement of %s to be indirect", requires[0].Path)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unclosedBlock", "module a\nrequire (\n\tb v1.0.0\n"},
+		{"unterminatedQuote", "module \"a\n"},
+		{"nestedBlock", "require (\n\tb (\n)\n"},
+		{"unknownDirective", "module a\n\nuse ./b\n"},
+		{"nonCanonicalVersion", "module a\n\nrequire b 1.0\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := Parse([]byte(test.content)); err == nil {
+				t.Errorf("Test name: %s: Expecting an error", test.name)
+			}
+		})
+	}
+}
+
+func TestParseWork(t *testing.T) {
+	content := "go 1.21\n\ntoolchain go1.21.3\n\nuse (\n\t./a\n\t./b // The b module.\n)\n"
+	workFile, err := ParseWork([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workFile.Go() != "1.21" || workFile.Toolchain() != "go1.21.3" {
+		t.Errorf("Expected go 1.21 and toolchain go1.21.3, Got: %s and %s", workFile.Go(), workFile.Toolchain())
+	}
+	if actual := string(workFile.Format()); actual != content {
+		t.Errorf("Expected the unchanged content, Got:\n%s", actual)
+	}
+	if _, err = Parse([]byte(content)); err == nil {
+		t.Error("Expected an error parsing a go.work content as a go.mod file")
+	}
+}
+
+func TestEditWorkFile(t *testing.T) {
+	content := "go 1.21\n\nuse ./a\n"
+	workFile, err := ParseWork([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = workFile.SetRequire("b", "v1.0.0"); err == nil {
+		t.Error("Expecting an error requiring a module in a go.work file")
+	}
+	if err = workFile.SetGo("1.22"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "go 1.22\n\nuse ./a\n"
+	if actual := string(workFile.Format()); actual != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, actual)
+	}
+	if workFile.Go() != "1.22" {
+		t.Errorf("Expected go 1.22, Got: %s", workFile.Go())
+	}
+}
+
+func TestDirectivesAccessors(t *testing.T) {
+	modFile, err := Parse([]byte(testModFile + "\ntoolchain go1.21.3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+	}{
+		{"module", "github.com/jfrog/test", modFile.Module()},
+		{"go", "1.12", modFile.Go()},
+		{"toolchain", "go1.21.3", modFile.Toolchain()},
+	}
+	for _, test := range tests {
+		if test.actual != test.expected {
+			t.Errorf("Test name: %s: Expected: %s, Got: %s", test.name, test.expected, test.actual)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	modFile, err := Parse([]byte("module   a // The module.\n\nrequire (\n    b  v1.0.0\n\n\t\t// Testing.\n  c v1.1.0 // indirect\n  )\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	modFile.Normalize()
+	expected := "module a // The module.\n\nrequire (\n\tb v1.0.0\n\n\t// Testing.\n\tc v1.1.0 // indirect\n)\n"
+	if actual := string(modFile.Format()); actual != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, actual)
+	}
+}
+
+func TestReadWriteFileWithFileSystem(t *testing.T) {
+	fileSystem := fsys.NewMemFileSystem(map[string]string{"go.mod": testModFile})
+	ctx := cmd.WithFileSystem(context.Background(), fileSystem)
+
+	modFile, err := ReadFile(ctx, "go.mod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = modFile.WriteFile(ctx, "go.copy.mod"); err != nil {
+		t.Error(err)
+	}
+	if copied := fileSystem.Files()["go.copy.mod"]; copied != formattedTestModFile {
+		t.Errorf("Expected:\n%s\nGot:\n%s", formattedTestModFile, copied)
+	}
+
+	if err = modFile.WriteFile(cmd.WithFileSystem(ctx, fsys.ReadOnly(fileSystem)), "go.mod"); err == nil {
+		t.Error("Expecting writing to a read-only file system to fail")
+	}
+}
@@ -0,0 +1,107 @@
+package modfile
+
+import (
+	"fmt"
+	"github.com/jfrog/gocmd/graph"
+	"github.com/jfrog/gocmd/semver"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	gomodfile "golang.org/x/mod/modfile"
+	"path/filepath"
+	"strings"
+)
+
+// A replace directive. An empty OldVersion replaces all the versions of the module.
+// A replacement by a local directory has a NewPath and no NewVersion.
+type Replace struct {
+	OldPath    string
+	OldVersion string
+	NewPath    string
+	NewVersion string
+}
+
+// Returns true if the module is replaced by a local directory.
+func (replace *Replace) IsLocal() bool {
+	return isLocalPath(replace.NewPath)
+}
+
+func (replace Replace) String() string {
+	old := replace.OldPath
+	if replace.OldVersion != "" {
+		old += " " + replace.OldVersion
+	}
+	replacement := replace.NewPath
+	if replace.NewVersion != "" {
+		replacement += " " + replace.NewVersion
+	}
+	return old + " => " + replacement
+}
+
+// Returns the replace directives, in the order they appear.
+func (modFile *ModFile) Replaces() []Replace {
+	var replaces []Replace
+	for _, replace := range modFile.mod.Replace {
+		replaces = append(replaces, Replace{OldPath: replace.Old.Path, OldVersion: replace.Old.Version, NewPath: replace.New.Path, NewVersion: replace.New.Version})
+	}
+	return replaces
+}
+
+// Replaces oldPath, at oldVersion or at all its versions if oldVersion is empty, with newPath at newVersion.
+// newPath may be a local directory, starting with "./", "../" or "/", in which case newVersion should be empty.
+// Otherwise, newVersion is required to be a semantic version, as is oldVersion if not empty.
+// An existing replace directive of oldPath and oldVersion is updated. As with 'go mod edit -replace', an empty
+// oldVersion also updates the replace directives of specific versions of oldPath.
+func (modFile *ModFile) AddReplace(oldPath, oldVersion, newPath, newVersion string) error {
+	if oldPath == "" || newPath == "" {
+		return errorutils.CheckError(fmt.Errorf("Invalid replace of %q with %q: both paths are required.", oldPath, newPath))
+	}
+	if isLocalPath(newPath) && newVersion != "" {
+		return errorutils.CheckError(fmt.Errorf("Invalid replace of %s with the local directory %s: local replacements have no version.", oldPath, newPath))
+	}
+	if !isLocalPath(newPath) && newVersion == "" {
+		return errorutils.CheckError(fmt.Errorf("Invalid replace of %s with the module %s: a version is required.", oldPath, newPath))
+	}
+	if !isLocalPath(newPath) && !semver.IsValid(newVersion) {
+		return errorutils.CheckError(fmt.Errorf("Invalid replace of %s with the module %s at version %q: expecting a version such as v1.2.3.", oldPath, newPath, newVersion))
+	}
+	if oldVersion != "" && !semver.IsValid(oldVersion) {
+		return errorutils.CheckError(fmt.Errorf("Invalid replace of %s at version %q: expecting a version such as v1.2.3.", oldPath, oldVersion))
+	}
+	return modFile.edit(func(mod *gomodfile.File) error {
+		return mod.AddReplace(oldPath, oldVersion, newPath, newVersion)
+	}, nil)
+}
+
+// Removes the replace directive of oldPath and oldVersion. Returns an error if no such directive exists.
+func (modFile *ModFile) RemoveReplace(oldPath, oldVersion string) error {
+	for _, replace := range modFile.mod.Replace {
+		if replace.Old.Path == oldPath && replace.Old.Version == oldVersion {
+			return modFile.edit(func(mod *gomodfile.File) error {
+				return mod.DropReplace(oldPath, oldVersion)
+			}, nil)
+		}
+	}
+	return errorutils.CheckError(fmt.Errorf("No replace directive of %s %s.", oldPath, oldVersion))
+}
+
+// Returns the replacement of the module, or the module if not replaced. A replacement by a local directory has no version.
+// A version specific replacement takes precedence over the replacement of all the versions.
+func replacementOf(module graph.Module, replaces []Replace) graph.Module {
+	replacement := module
+	for _, replace := range replaces {
+		if replace.OldPath != module.Path {
+			continue
+		}
+		if replace.OldVersion == module.Version {
+			return graph.Module{Path: replace.NewPath, Version: replace.NewVersion}
+		}
+		if replace.OldVersion == "" {
+			replacement = graph.Module{Path: replace.NewPath, Version: replace.NewVersion}
+		}
+	}
+	return replacement
+}
+
+func isLocalPath(path string) bool {
+	return strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../") || path == "." || path == ".." ||
+		strings.HasPrefix(path, `.\`) || strings.HasPrefix(path, `..\`) || filepath.IsAbs(path) || strings.HasPrefix(path, "/")
+}
@@ -0,0 +1,106 @@
+package modfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplaces(t *testing.T) {
+	modFile, err := Parse([]byte("module a\n\nreplace (\n\tb v1.0.0 => c v1.1.0\n\td => ../d // Local.\n)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Replace{{"b", "v1.0.0", "c", "v1.1.0"}, {"d", "", "../d", ""}}
+	if actual := modFile.Replaces(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+	if !expected[1].IsLocal() || expected[0].IsLocal() {
+		t.Error("Expected only the second replacement to be local")
+	}
+}
+
+func TestAddReplace(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		oldPath    string
+		oldVersion string
+		newPath    string
+		newVersion string
+		expected   string
+	}{
+		{"noReplaces", "module a\n", "b", "", "../b", "", "module a\n\nreplace b => ../b\n"},
+		{"afterLine", "module a\n\nreplace b => ../b\n\ngo 1.12\n", "c", "v1.0.0", "d", "v1.1.0", "module a\n\nreplace b => ../b\n\ngo 1.12\n\nreplace c v1.0.0 => d v1.1.0\n"},
+		{"toBlock", "module a\n\nreplace (\n\tb => ../b\n)\n", "c", "", "/tmp/my c", "", "module a\n\nreplace b => ../b\n\nreplace c => \"/tmp/my c\"\n"},
+		{"update", "module a\n\nreplace b => ../b // Keep.\n", "b", "", "c", "v1.0.0", "module a\n\nreplace b => c v1.0.0 // Keep.\n"},
+		{"otherVersion", "module a\n\nreplace b => ../b\n", "b", "v1.0.0", "../b1", "", "module a\n\nreplace (\n\tb => ../b\n\tb v1.0.0 => ../b1\n)\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modFile, err := Parse([]byte(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = modFile.AddReplace(test.oldPath, test.oldVersion, test.newPath, test.newVersion); err != nil {
+				t.Fatal(err)
+			}
+			if actual := string(modFile.Format()); actual != test.expected {
+				t.Errorf("Test name: %s: Expected:\n%s\nGot:\n%s", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAddReplaceErrors(t *testing.T) {
+	modFile, err := Parse([]byte("module a\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = modFile.AddReplace("b", "", "../b", "v1.0.0"); err == nil {
+		t.Error("Expecting an error for a local replacement with a version")
+	}
+	if err = modFile.AddReplace("b", "", "c", ""); err == nil {
+		t.Error("Expecting an error for a module replacement without a version")
+	}
+	if err = modFile.AddReplace("b", "", "c", "master"); err == nil {
+		t.Error("Expecting an error for a module replacement with an invalid version")
+	}
+	if err = modFile.AddReplace("b", "latest", "../b", ""); err == nil {
+		t.Error("Expecting an error for an invalid replaced version")
+	}
+	if actual := string(modFile.Format()); actual != "module a\n" {
+		t.Errorf("Expected the unchanged content, Got:\n%s", actual)
+	}
+}
+
+func TestRemoveReplace(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		oldPath    string
+		oldVersion string
+		expected   string
+	}{
+		{"line", "module a\n\nreplace b => ../b\n", "b", "", "module a\n"},
+		{"fromBlock", "module a\n\nreplace (\n\tb => ../b\n\tc => ../c\n)\n", "b", "", "module a\n\nreplace c => ../c\n"},
+		{"lastInBlock", "module a\n\nreplace (\n\tb v1.0.0 => ../b\n)\n", "b", "v1.0.0", "module a\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modFile, err := Parse([]byte(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = modFile.RemoveReplace(test.oldPath, test.oldVersion); err != nil {
+				t.Fatal(err)
+			}
+			if actual := string(modFile.Format()); actual != test.expected {
+				t.Errorf("Test name: %s: Expected:\n%q\nGot:\n%q", test.name, test.expected, actual)
+			}
+		})
+	}
+	modFile, _ := Parse([]byte("module a\n"))
+	if err := modFile.RemoveReplace("b", ""); err == nil {
+		t.Error("Expecting an error for a missing replace directive")
+	}
+}
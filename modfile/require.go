@@ -0,0 +1,37 @@
+package modfile
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	gomodfile "golang.org/x/mod/modfile"
+)
+
+// A require directive. Indirect is true for requirements marked with an "// indirect" comment.
+type Require struct {
+	Path     string
+	Version  string
+	Indirect bool
+}
+
+func (require Require) String() string {
+	return require.Path + "@" + require.Version
+}
+
+// Returns the require directives, in the order they appear.
+func (modFile *ModFile) Requires() []Require {
+	var requires []Require
+	for _, require := range modFile.mod.Require {
+		requires = append(requires, Require{Path: require.Mod.Path, Version: require.Mod.Version, Indirect: require.Indirect})
+	}
+	return requires
+}
+
+// Requires the version of the module. An existing require directive of the module is updated, keeping its comment.
+func (modFile *ModFile) SetRequire(path, version string) error {
+	if path == "" || !isVersion(version) {
+		return errorutils.CheckError(fmt.Errorf("Invalid require of %q at version %q.", path, version))
+	}
+	return modFile.edit(func(mod *gomodfile.File) error {
+		return mod.AddRequire(path, version)
+	}, nil)
+}
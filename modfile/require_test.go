@@ -0,0 +1,41 @@
+package modfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRequires(t *testing.T) {
+	modFile, err := Parse([]byte(testModFile + "\nrequire github.com/jfrog/jfrog-client-go v0.5.0 // indirect; for tests\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Require{
+		{"github.com/jfrog/gofrog", "v1.0.5", true},
+		{"github.com/pkg/errors", "v0.8.1", false},
+		{"github.com/stretchr/testify", "v1.3.0", false},
+		{"github.com/jfrog/jfrog-client-go", "v0.5.0", true},
+	}
+	if actual := modFile.Requires(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
+
+func TestSetRequire(t *testing.T) {
+	modFile, err := Parse([]byte("module a\n\nrequire (\n\tb v1.0.0 // indirect\n)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, require := range []Require{{Path: "b", Version: "v1.1.0"}, {Path: "c", Version: "v0.2.0"}} {
+		if err = modFile.SetRequire(require.Path, require.Version); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected := "module a\n\nrequire (\n\tb v1.1.0 // indirect\n\tc v0.2.0\n)\n"
+	if actual := string(modFile.Format()); actual != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, actual)
+	}
+	if err = modFile.SetRequire("b", "latest"); err == nil {
+		t.Error("Expecting an error for an invalid version")
+	}
+}
@@ -0,0 +1,90 @@
+package modfile
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	gomodfile "golang.org/x/mod/modfile"
+	"strings"
+)
+
+// A retract directive of a single version, in which case Low and High are equal, or of a closed interval of versions.
+// The rationale is taken from the comment lines preceding the directive, or from the comment at the end of its line.
+type Retract struct {
+	Low       string
+	High      string
+	Rationale string
+}
+
+func (retract Retract) String() string {
+	if retract.Low == retract.High {
+		return retract.Low
+	}
+	return "[" + retract.Low + ", " + retract.High + "]"
+}
+
+// Returns the retract directives, in the order they appear.
+func (modFile *ModFile) Retracts() []Retract {
+	var retracts []Retract
+	for _, retract := range modFile.mod.Retract {
+		retracts = append(retracts, Retract{Low: retract.Low, High: retract.High, Rationale: retract.Rationale})
+	}
+	return retracts
+}
+
+// Retracts the versions from low to high, or only low if high is empty.
+// A single line rationale is written at the end of the directive's line, and a longer one in the lines preceding it.
+// An existing retract directive of the same versions is replaced.
+func (modFile *ModFile) AddRetract(low, high, rationale string) error {
+	if high == "" {
+		high = low
+	}
+	if !isVersion(low) || !isVersion(high) {
+		return errorutils.CheckError(fmt.Errorf("Invalid retract of the versions %q to %q.", low, high))
+	}
+	versions := gomodfile.VersionInterval{Low: low, High: high}
+	var lines []string
+	if rationale = strings.TrimSpace(rationale); rationale != "" {
+		for _, line := range strings.Split(rationale, "\n") {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	multilineRationale := ""
+	if len(lines) > 1 {
+		multilineRationale = strings.Join(lines, "\n")
+	}
+	err := modFile.edit(func(mod *gomodfile.File) error {
+		if err := mod.DropRetract(versions); err != nil {
+			return err
+		}
+		return mod.AddRetract(versions, multilineRationale)
+	}, nil)
+	if err != nil || len(lines) != 1 {
+		return err
+	}
+	// AddRetract writes the rationale in the lines preceding the directive, so a single line is added to its end instead.
+	return modFile.edit(func(mod *gomodfile.File) error {
+		for _, retract := range mod.Retract {
+			if retract.VersionInterval == versions {
+				retract.Syntax.Comment().Suffix = []gomodfile.Comment{{Token: "// " + lines[0], Suffix: true}}
+			}
+		}
+		return nil
+	}, nil)
+}
+
+// Removes the retract directive of the versions from low to high, or only low if high is empty, with its rationale.
+// Returns an error if no such directive exists.
+func (modFile *ModFile) RemoveRetract(low, high string) error {
+	if high == "" {
+		high = low
+	}
+	versions := gomodfile.VersionInterval{Low: low, High: high}
+	for _, retract := range modFile.mod.Retract {
+		if retract.VersionInterval == versions {
+			return modFile.edit(func(mod *gomodfile.File) error {
+				return mod.DropRetract(versions)
+			}, nil)
+		}
+	}
+	return errorutils.CheckError(fmt.Errorf("No retract directive of %s.", Retract{Low: low, High: high}))
+}
@@ -0,0 +1,80 @@
+package modfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRetracts(t *testing.T) {
+	content := `module a
+
+// Published accidentally.
+retract v1.0.0
+
+retract (
+	[v1.1.0, v1.1.5] // Data race in the client.
+	// Broken build.
+	// Use v1.3.1.
+	v1.3.0
+)
+`
+	modFile, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Retract{
+		{"v1.0.0", "v1.0.0", "Published accidentally."},
+		{"v1.1.0", "v1.1.5", "Data race in the client."},
+		{"v1.3.0", "v1.3.0", "Broken build.\nUse v1.3.1."},
+	}
+	if actual := modFile.Retracts(); !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Expected: %v, Got: %v", expected, actual)
+	}
+}
+
+func TestAddRetract(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		low       string
+		high      string
+		rationale string
+		expected  string
+	}{
+		{"version", "module a\n", "v1.0.0", "", "Published accidentally.", "module a\n\nretract v1.0.0 // Published accidentally.\n"},
+		{"interval", "module a\n\nretract v1.0.0\n", "v1.1.0", "v1.1.5", "", "module a\n\nretract (\n\tv1.0.0\n\t[v1.1.0, v1.1.5]\n)\n"},
+		{"multilineRationale", "module a\n\nretract (\n\tv1.0.0\n)\n", "v1.2.0", "", "Broken build.\nUse v1.2.1.", "module a\n\nretract (\n\tv1.0.0\n\t// Broken build.\n\t// Use v1.2.1.\n\tv1.2.0\n)\n"},
+		{"replaceRationale", "module a\n\n// Old.\nretract v1.0.0\n", "v1.0.0", "v1.0.0", "New.", "module a\n\nretract v1.0.0 // New.\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modFile, err := Parse([]byte(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = modFile.AddRetract(test.low, test.high, test.rationale); err != nil {
+				t.Fatal(err)
+			}
+			if actual := string(modFile.Format()); actual != test.expected {
+				t.Errorf("Test name: %s: Expected:\n%q\nGot:\n%q", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRemoveRetract(t *testing.T) {
+	modFile, err := Parse([]byte("module a\n\nretract (\n\t// Broken.\n\t[v1.0.0, v1.0.2]\n\tv1.1.0\n)\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = modFile.RemoveRetract("v1.0.0", "v1.0.2"); err != nil {
+		t.Fatal(err)
+	}
+	expected := "module a\n\nretract v1.1.0\n"
+	if actual := string(modFile.Format()); actual != expected {
+		t.Errorf("Expected:\n%q\nGot:\n%q", expected, actual)
+	}
+	if err = modFile.RemoveRetract("v1.0.0", ""); err == nil {
+		t.Error("Expecting an error for a missing retract directive")
+	}
+}
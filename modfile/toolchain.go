@@ -0,0 +1,169 @@
+package modfile
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	gomodfile "golang.org/x/mod/modfile"
+	"path/filepath"
+	"regexp"
+)
+
+// Removes the toolchain directives when set as the Toolchain of GoDirectivesUpdate.
+const NoToolchain = "none"
+
+// Toolchain names, such as "go1.21.3" and "go1.21.3-custom".
+var toolchainRegexp = regexp.MustCompile(`^go1(\.(0|[1-9][0-9]*)){1,2}((rc|beta)[1-9][0-9]*)?(-[A-Za-z0-9._-]+)?$`)
+
+// The go and toolchain directives of a go.mod or go.work file.
+type GoDirectives struct {
+	// The path of the go.mod or go.work file.
+	File      string
+	Go        string
+	Toolchain string
+}
+
+// Describes how UpdateGoDirectives updates the go and toolchain directives.
+type GoDirectivesUpdate struct {
+	// The version of the go directive, such as "1.21". Empty to keep the go directives.
+	Go string
+	// The toolchain directive, such as "go1.21.3". Empty to keep the toolchain directives, NoToolchain to remove them.
+	Toolchain string
+	// Runs 'go mod tidy' in each module after the update, with -go set to Go if not empty.
+	Tidy bool
+}
+
+// Sets the version of the go directive, such as "1.21", adding the directive if missing.
+func (modFile *ModFile) SetGo(version string) error {
+	if !cmd.IsGoVersion(version) {
+		return errorutils.CheckError(fmt.Errorf("Invalid go version: %q. Expecting a version such as 1.21 or 1.21.3.", version))
+	}
+	return modFile.edit(func(mod *gomodfile.File) error {
+		return mod.AddGoStmt(version)
+	}, func(work *gomodfile.WorkFile) error {
+		return work.AddGoStmt(version)
+	})
+}
+
+// Sets the toolchain directive, such as "go1.21.3", adding the directive if missing.
+// An empty toolchain removes the directive.
+func (modFile *ModFile) SetToolchain(toolchain string) error {
+	if toolchain == "" {
+		return modFile.edit(func(mod *gomodfile.File) error {
+			mod.DropToolchainStmt()
+			return nil
+		}, func(work *gomodfile.WorkFile) error {
+			work.DropToolchainStmt()
+			return nil
+		})
+	}
+	if !toolchainRegexp.MatchString(toolchain) {
+		return errorutils.CheckError(fmt.Errorf("Invalid toolchain: %q. Expecting a toolchain such as go1.21.3.", toolchain))
+	}
+	return modFile.edit(func(mod *gomodfile.File) error {
+		return mod.AddToolchainStmt(toolchain)
+	}, func(work *gomodfile.WorkFile) error {
+		return work.AddToolchainStmt(toolchain)
+	})
+}
+
+// Returns the go and toolchain directives of the module in dir, or of the workspace in dir and all its modules if dir
+// has a go.work file.
+func ReadGoDirectives(ctx context.Context, dir string) ([]GoDirectives, error) {
+	files, err := getGoDirectivesFiles(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	var directives []GoDirectives
+	for _, file := range files {
+		modFile, err := ReadFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, GoDirectives{File: file, Go: modFile.Go(), Toolchain: modFile.Toolchain()})
+	}
+	return directives, nil
+}
+
+// Updates the go and toolchain directives of the module in dir, or of the workspace in dir and all its modules if dir
+// has a go.work file. Returns the files which were changed.
+func UpdateGoDirectives(ctx context.Context, dir string, update GoDirectivesUpdate) ([]string, error) {
+	if update.Go != "" && !cmd.IsGoVersion(update.Go) {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid go version: %q. Expecting a version such as 1.21 or 1.21.3.", update.Go))
+	}
+	if update.Toolchain != "" && update.Toolchain != NoToolchain && !toolchainRegexp.MatchString(update.Toolchain) {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid toolchain: %q. Expecting a toolchain such as go1.21.3.", update.Toolchain))
+	}
+	files, err := getGoDirectivesFiles(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, file := range files {
+		modFile, err := ReadFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		before := string(modFile.Format())
+		if update.Go != "" {
+			if err = modFile.SetGo(update.Go); err != nil {
+				return nil, err
+			}
+		}
+		if update.Toolchain == NoToolchain {
+			err = modFile.SetToolchain("")
+		} else if update.Toolchain != "" {
+			err = modFile.SetToolchain(update.Toolchain)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if string(modFile.Format()) == before {
+			continue
+		}
+		cmd.GetLogger(ctx).Info("Updating the go directives of", file)
+		if err = modFile.WriteFile(ctx, file); err != nil {
+			return nil, err
+		}
+		changed = append(changed, file)
+	}
+	if update.Tidy {
+		for _, file := range files {
+			if filepath.Base(file) == "go.mod" {
+				args := []string{"mod", "tidy"}
+				if update.Go != "" {
+					args = append(args, "-go="+update.Go)
+				}
+				if err = cmd.RunGo(cmd.WithModuleDir(ctx, filepath.Dir(file)), args); err != nil {
+					return changed, err
+				}
+			}
+		}
+	}
+	return changed, nil
+}
+
+// Returns the go.mod file in dir, or the go.work file in dir followed by the go.mod files of the workspace modules.
+func getGoDirectivesFiles(ctx context.Context, dir string) ([]string, error) {
+	workPath := filepath.Join(dir, "go.work")
+	if exists, err := fsys.IsFileExists(cmd.GetFileSystem(ctx), workPath); err != nil {
+		return nil, err
+	} else if !exists {
+		return []string{filepath.Join(dir, "go.mod")}, nil
+	}
+	workFile, err := ReadFile(ctx, workPath)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{workPath}
+	for _, use := range workFile.work.Use {
+		moduleDir := filepath.FromSlash(use.Path)
+		if !filepath.IsAbs(moduleDir) {
+			moduleDir = filepath.Join(dir, moduleDir)
+		}
+		files = append(files, filepath.Join(moduleDir, "go.mod"))
+	}
+	return files, nil
+}
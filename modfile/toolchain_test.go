@@ -0,0 +1,125 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSetGoAndToolchain(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		goVersion string
+		toolchain string
+		expected  string
+	}{
+		{"update", "module a\n\ngo 1.12\n\ntoolchain go1.20.1 // Pinned.\n", "1.21", "go1.21.3", "module a\n\ngo 1.21\n\ntoolchain go1.21.3 // Pinned.\n"},
+		{"add", "module a\n\nrequire b v1.0.0\n", "1.21.0", "go1.21.3", "module a\n\ngo 1.21.0\n\ntoolchain go1.21.3\n\nrequire b v1.0.0\n"},
+		{"removeToolchain", "module a\n\ngo 1.21\n\ntoolchain go1.21.3\n", "1.22rc1", "", "module a\n\ngo 1.22rc1\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modFile, err := Parse([]byte(test.content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = modFile.SetGo(test.goVersion); err != nil {
+				t.Fatal(err)
+			}
+			if err = modFile.SetToolchain(test.toolchain); err != nil {
+				t.Fatal(err)
+			}
+			if actual := string(modFile.Format()); actual != test.expected {
+				t.Errorf("Test name: %s: Expected:\n%q\nGot:\n%q", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSetGoAndToolchainErrors(t *testing.T) {
+	modFile, err := Parse([]byte("module a\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"1", "go1.21", "1.21.x", "2.0"} {
+		if err = modFile.SetGo(version); err == nil {
+			t.Errorf("Expecting an error for the go version %s", version)
+		}
+	}
+	for _, toolchain := range []string{"1.21.3", "go1", "go1.21 custom"} {
+		if err = modFile.SetToolchain(toolchain); err == nil {
+			t.Errorf("Expecting an error for the toolchain %s", toolchain)
+		}
+	}
+}
+
+func TestUpdateGoDirectives(t *testing.T) {
+	workspaceDir, err := ioutil.TempDir("", "workspaceTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(workspaceDir)
+	files := map[string]string{
+		"go.work":   "go 1.20\n\nuse (\n\t./a\n\t./b\n)\n",
+		"a/go.mod":  "module a\n\ngo 1.20\n",
+		"b/go.mod":  "module b\n\ngo 1.21\n\ntoolchain go1.21.3\n",
+		"c/go.mod":  "module c\n\ngo 1.12\n",
+		"b/main.go": "package main\n",
+	}
+	for path, content := range files {
+		path = filepath.Join(workspaceDir, filepath.FromSlash(path))
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changed, err := UpdateGoDirectives(context.Background(), workspaceDir, GoDirectivesUpdate{Go: "1.21", Toolchain: NoToolchain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedChanged := []string{filepath.Join(workspaceDir, "go.work"), filepath.Join(workspaceDir, "a", "go.mod"), filepath.Join(workspaceDir, "b", "go.mod")}
+	if !reflect.DeepEqual(expectedChanged, changed) {
+		t.Errorf("Expected: %v, Got: %v", expectedChanged, changed)
+	}
+	directives, err := ReadGoDirectives(context.Background(), workspaceDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []GoDirectives{{expectedChanged[0], "1.21", ""}, {expectedChanged[1], "1.21", ""}, {expectedChanged[2], "1.21", ""}}
+	if !reflect.DeepEqual(expected, directives) {
+		t.Errorf("Expected: %v, Got: %v", expected, directives)
+	}
+	if _, err = UpdateGoDirectives(context.Background(), workspaceDir, GoDirectivesUpdate{Go: "latest"}); err == nil {
+		t.Error("Expecting an error for an invalid go version")
+	}
+
+	// Tidy runs in the directory of each changed module, without changing the working directory of the process.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	executor := cmd.NewFakeExecutor()
+	ctx := cmd.WithOptions(context.Background(), &cmd.Options{Executor: executor})
+	if _, err = UpdateGoDirectives(ctx, workspaceDir, GoDirectivesUpdate{Go: "1.22", Tidy: true}); err != nil {
+		t.Fatal(err)
+	}
+	var dirs []string
+	for _, call := range executor.Calls() {
+		dirs = append(dirs, call.Dir)
+	}
+	expectedDirs := []string{filepath.Join(workspaceDir, "a"), filepath.Join(workspaceDir, "b")}
+	if !reflect.DeepEqual(expectedDirs, dirs) {
+		t.Errorf("Expected go mod tidy in: %v, Got: %v", expectedDirs, executor.Calls())
+	}
+	if actualWd, err := os.Getwd(); err != nil || actualWd != wd {
+		t.Errorf("Expected the working directory %s, Got: %s", wd, actualWd)
+	}
+}
@@ -0,0 +1,144 @@
+package modfile
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"strings"
+)
+
+// The first go release which can download and switch to the toolchain required by the go.mod file.
+const toolchainSwitchGoVersion = "1.21"
+
+// Controls whether the toolchain required by the go and toolchain directives may be downloaded, as GOTOOLCHAIN does.
+type ToolchainMode string
+
+const (
+	// Only the local go is used. Fails with a ToolchainRequiredError if it's older than the required toolchain.
+	LocalToolchain ToolchainMode = "local"
+	// The required toolchain is downloaded by the local go if the local go is older than it.
+	AutoToolchain ToolchainMode = "auto"
+)
+
+// Describes how WithToolchain selects the toolchain.
+type ToolchainOptions struct {
+	// Defaults to LocalToolchain.
+	Mode ToolchainMode
+	// Pins the toolchain, such as "go1.21.3", instead of the one required by the go and toolchain directives.
+	// Downloaded even if the local go is newer, unless it's of the same version.
+	Toolchain string
+}
+
+// The toolchain selected by WithToolchain.
+type ToolchainSelection struct {
+	// The go version required by the go and toolchain directives, such as "1.21.3". Empty if the directives require none.
+	Required string
+	// The local go binary.
+	Local *cmd.GoExecutable
+	// The toolchain the go commands run with, such as "go1.21.3", or "local" if they run with the local go.
+	Toolchain string
+	// The go version which actually executed, as reported by go version, such as "1.21.3".
+	Executed string
+}
+
+// Returns true if the go commands run with a toolchain other than the local go.
+func (selection *ToolchainSelection) Switched() bool {
+	return selection.Toolchain != string(LocalToolchain)
+}
+
+// The local go is older than the toolchain required by the go.mod file, and the toolchain may not be downloaded,
+// or the local go is too old to download it.
+type ToolchainRequiredError struct {
+	LocalVersion    string
+	RequiredVersion string
+	// The reason the required toolchain isn't used.
+	Reason string
+}
+
+func (err *ToolchainRequiredError) Error() string {
+	return fmt.Sprintf("Go %s is required, but the local go is of version %s. %s", err.RequiredVersion, err.LocalVersion, err.Reason)
+}
+
+// Returns the highest go version required by the go and toolchain directives of the module in dir,
+// or of the workspace in dir and all its modules, such as "1.21.3". Returns an empty string if none is required.
+func RequiredGoVersion(ctx context.Context, dir string) (string, error) {
+	directives, err := ReadGoDirectives(ctx, dir)
+	if err != nil {
+		return "", err
+	}
+	required := ""
+	for _, directive := range directives {
+		for _, version := range []string{directive.Go, toolchainVersion(directive.Toolchain)} {
+			if version != "" && (required == "" || cmd.CompareGoVersions(version, required) > 0) {
+				required = version
+			}
+		}
+	}
+	return required, nil
+}
+
+// Returns a copy of ctx carrying the options of ctx, whose go commands run with the toolchain required by the go and
+// toolchain directives of the module in dir, or with the toolchain pinned by the options.
+// When the local go is older than the toolchain, the toolchain is downloaded by the local go, if the mode allows it,
+// and GOTOOLCHAIN is set to it, so all the go commands run with it. Otherwise, GOTOOLCHAIN is set to local.
+func WithToolchain(ctx context.Context, dir string, options ToolchainOptions) (context.Context, *ToolchainSelection, error) {
+	if options.Toolchain != "" && !toolchainRegexp.MatchString(options.Toolchain) {
+		return nil, nil, errorutils.CheckError(fmt.Errorf("Invalid toolchain: %q. Expecting a toolchain such as go1.21.3.", options.Toolchain))
+	}
+	required, err := RequiredGoVersion(ctx, dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	local := cmd.GetOptions(ctx).Executable
+	if local == nil {
+		if local, err = cmd.FindGoExecutable(ctx, ""); err != nil {
+			return nil, nil, err
+		}
+	}
+	selection := &ToolchainSelection{Required: required, Local: local, Toolchain: string(LocalToolchain), Executed: local.Version}
+
+	toolchain := options.Toolchain
+	if toolchain == "" && required != "" && local.RequireVersion(required) != nil {
+		toolchain = toolchainName(required)
+	}
+	if toolchain == "" || toolchainVersion(toolchain) == local.Version {
+		return cmd.WithEnv(ctx, "GOTOOLCHAIN", string(LocalToolchain)), selection, nil
+	}
+	version := toolchainVersion(toolchain)
+	if options.Mode != AutoToolchain {
+		return nil, nil, errorutils.CheckError(&ToolchainRequiredError{LocalVersion: local.Version, RequiredVersion: version, Reason: "Allow downloading it with AutoToolchain."})
+	}
+	if local.RequireVersion(toolchainSwitchGoVersion) != nil {
+		return nil, nil, errorutils.CheckError(&ToolchainRequiredError{LocalVersion: local.Version, RequiredVersion: version, Reason: "Downloading toolchains requires go " + toolchainSwitchGoVersion + " or above."})
+	}
+
+	// Running go version with the toolchain downloads it, and reports the version which actually executes the commands.
+	cmd.GetLogger(ctx).Info("Switching to the", toolchain, "toolchain")
+	toolchainCtx := cmd.WithEnv(ctx, "GOTOOLCHAIN", toolchain)
+	executable, err := cmd.FindGoExecutable(toolchainCtx, local.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	selection.Toolchain = toolchain
+	selection.Executed = executable.Version
+	// The patterns of the go commands are selected by the version which executes them.
+	return cmd.WithExecutable(toolchainCtx, executable), selection, nil
+}
+
+// Returns the version of a toolchain, such as "1.21.3" for "go1.21.3" and "go1.21.3-custom".
+func toolchainVersion(toolchain string) string {
+	version := strings.TrimPrefix(toolchain, "go")
+	if index := strings.Index(version, "-"); index >= 0 {
+		version = version[:index]
+	}
+	return version
+}
+
+// Returns the toolchain of a go version. Since go 1.21, the go directive "1.21" refers to the "go1.21.0" release.
+func toolchainName(version string) string {
+	if strings.Count(version, ".") == 1 && strings.Trim(version, "0123456789.") == "" && cmd.CompareGoVersions(version, toolchainSwitchGoVersion) >= 0 {
+		version += ".0"
+	}
+	return "go" + version
+}
@@ -0,0 +1,105 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createToolchainTestModule(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "toolchainTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestRequiredGoVersion(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected string
+	}{
+		{"module a\n", ""},
+		{"module a\n\ngo 1.20\n", "1.20"},
+		{"module a\n\ngo 1.21\n\ntoolchain go1.22.1\n", "1.22.1"},
+		{"module a\n\ngo 1.22.3\n\ntoolchain go1.22.1-custom\n", "1.22.3"},
+	}
+	for _, test := range tests {
+		dir := createToolchainTestModule(t, test.content)
+		actual, err := RequiredGoVersion(context.Background(), dir)
+		os.RemoveAll(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != test.expected {
+			t.Errorf("Expecting %q for %q, got: %q", test.expected, test.content, actual)
+		}
+	}
+}
+
+func TestToolchainName(t *testing.T) {
+	tests := map[string]string{"1.20": "go1.20", "1.21": "go1.21.0", "1.22.1": "go1.22.1", "1.22rc1": "go1.22rc1"}
+	for version, expected := range tests {
+		if actual := toolchainName(version); actual != expected {
+			t.Errorf("Expecting %s for %s, got: %s", expected, version, actual)
+		}
+	}
+}
+
+func TestWithToolchain(t *testing.T) {
+	dir := createToolchainTestModule(t, "module a\n\ngo 1.22.1\n")
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name              string
+		localVersion      string
+		options           ToolchainOptions
+		expectedToolchain string
+		expectedExecuted  string
+		expectedErr       bool
+	}{
+		{"localIsNewer", "1.23.0", ToolchainOptions{}, "local", "1.23.0", false},
+		{"localIsOlder", "1.21.5", ToolchainOptions{}, "", "", true},
+		{"download", "1.21.5", ToolchainOptions{Mode: AutoToolchain}, "go1.22.1", "1.22.1", false},
+		{"localCannotDownload", "1.20.5", ToolchainOptions{Mode: AutoToolchain}, "", "", true},
+		{"pinned", "1.23.0", ToolchainOptions{Mode: AutoToolchain, Toolchain: "go1.22.1"}, "go1.22.1", "1.22.1", false},
+		{"invalidPin", "1.23.0", ToolchainOptions{Mode: AutoToolchain, Toolchain: "latest"}, "", "", true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			executor := cmd.NewFakeExecutor()
+			executor.On(cmd.ExecutorResult{Stdout: "go version go1.22.1 linux/amd64\n"}, "version")
+			ctx := cmd.WithExecutable(cmd.WithExecutor(context.Background(), executor), &cmd.GoExecutable{Path: "go", Version: test.localVersion})
+
+			toolchainCtx, selection, err := WithToolchain(ctx, dir, test.options)
+			if test.expectedErr {
+				if err == nil {
+					t.Error("Expecting an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if selection.Toolchain != test.expectedToolchain || selection.Executed != test.expectedExecuted || selection.Required != "1.22.1" {
+				t.Errorf("Unexpected selection: %+v", selection)
+			}
+			options := cmd.GetOptions(toolchainCtx)
+			if expected := test.expectedToolchain; options.Env["GOTOOLCHAIN"] != expected {
+				t.Errorf("Expecting GOTOOLCHAIN=%s, got: %s", expected, options.Env["GOTOOLCHAIN"])
+			}
+			if options.Executable.Version != test.expectedExecuted {
+				t.Errorf("Expecting the go commands to run with go %s, got: %s", test.expectedExecuted, options.Executable.Version)
+			}
+			if selection.Switched() && len(executor.Calls()) != 1 {
+				t.Errorf("Expecting the toolchain to be downloaded by go version, got: %v", executor.Calls())
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"path/filepath"
+	"strings"
+)
+
+// Returns the direct requirements of the go.mod file in moduleDir whose modules have no packages imported by the module,
+// its tests or its tools. Such requirements are left behind when the imports of the module's packages are removed.
+func FindUnusedRequirements(ctx context.Context, moduleDir string) ([]Require, error) {
+	modFile, err := ReadFile(ctx, filepath.Join(moduleDir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := cmd.GetDependenciesScopes(cmd.WithModuleDir(ctx, moduleDir))
+	if err != nil {
+		return nil, err
+	}
+	// The selected version of a module may be newer than its required version, so the modules are matched by their path.
+	usedPaths := map[string]bool{}
+	for module := range scopes {
+		usedPaths[module[:strings.LastIndex(module, "@")]] = true
+	}
+	var unused []Require
+	for _, require := range modFile.Requires() {
+		if !require.Indirect && !usedPaths[require.Path] {
+			unused = append(unused, require)
+		}
+	}
+	return unused, nil
+}
+
+// Finds the unused direct requirements of the go.mod file in moduleDir, and removes them by running go mod tidy if
+// confirm returns true for them. Returns the unused requirements, and the changes of go mod tidy if it ran.
+func RemoveUnusedRequirements(ctx context.Context, moduleDir string, confirm func(unused []Require) bool) ([]Require, *cmd.TidyResult, error) {
+	unused, err := FindUnusedRequirements(ctx, moduleDir)
+	if err != nil || len(unused) == 0 {
+		return unused, nil, err
+	}
+	if !confirm(unused) {
+		cmd.GetLogger(ctx).Info("Keeping", len(unused), "unused requirements of", filepath.Join(moduleDir, "go.mod"))
+		return unused, nil, nil
+	}
+	result, err := cmd.RunGoModTidy(cmd.WithModuleDir(ctx, moduleDir))
+	return unused, result, err
+}
@@ -0,0 +1,85 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const unusedModContent = `module example.com/m
+
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.4.0
+	golang.org/x/text v0.3.0 // indirect
+	rsc.io/quote v1.5.2
+)
+`
+
+func TestRemoveUnusedRequirements(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "unusedTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(moduleDir)
+	if err = ioutil.WriteFile(filepath.Join(moduleDir, "go.mod"), []byte(unusedModContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	executor := cmd.NewFakeExecutor()
+	executor.On(cmd.ExecutorResult{Stdout: "github.com/pkg/errors@v0.9.2\n"}, "list", "-deps", "-f", "{{with .Module}}{{if not .Main}}{{.Path}}@{{.Version}}{{end}}{{end}}", "./...")
+	executor.On(cmd.ExecutorResult{Stdout: "github.com/stretchr/testify@v1.4.0\n"}, "list", "-deps", "-test", "-f", "{{with .Module}}{{if not .Main}}{{.Path}}@{{.Version}}{{end}}{{end}}", "./...")
+	ctx := cmd.WithExecutor(context.Background(), executor)
+
+	expected := []Require{{Path: "rsc.io/quote", Version: "v1.5.2"}}
+	unused, result, err := RemoveUnusedRequirements(ctx, moduleDir, func(unused []Require) bool { return false })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, unused) || result != nil {
+		t.Errorf("Expected: %v without tidying, Got: %v, %v", expected, unused, result)
+	}
+	for _, call := range executor.Calls() {
+		if call.Dir != moduleDir || call.Cmd[1] == "mod" {
+			t.Errorf("Unexpected call: %+v", call)
+		}
+	}
+
+	var confirmed []Require
+	unused, result, err = RemoveUnusedRequirements(ctx, moduleDir, func(unused []Require) bool {
+		confirmed = unused
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, unused) || !reflect.DeepEqual(expected, confirmed) || result == nil {
+		t.Errorf("Expected: %v with tidying, Got: %v, %v", expected, unused, result)
+	}
+	calls := executor.Calls()
+	if lastCall := calls[len(calls)-1]; lastCall.Dir != moduleDir || !reflect.DeepEqual(lastCall.Cmd[1:], []string{"mod", "tidy"}) {
+		t.Errorf("Expected go mod tidy in %s, Got: %+v", moduleDir, lastCall)
+	}
+}
+
+// The tools.go file of the project imports the main package of a tool, which go list reports as an error.
+func TestFindUnusedRequirementsWithToolsFile(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("The test runs go list")
+	}
+	moduleDir, err := filepath.Abs(filepath.Join("testdata", "tools"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unused, err := FindUnusedRequirements(cmd.WithEnv(context.Background(), "GOTOOLCHAIN", "local"), moduleDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unused) != 0 {
+		t.Error("Expecting no unused requirements, got:", unused)
+	}
+}
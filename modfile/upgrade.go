@@ -0,0 +1,97 @@
+package modfile
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/fsys"
+	"github.com/jfrog/gocmd/graph"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"path/filepath"
+)
+
+// Predicts the effect of upgrading the requirement of the module in moduleDir on path to the version, by minimal version
+// selection over the output of go mod graph, without changing go.mod. The requirements of versions which aren't part of
+// the graph are read from their go.mod files, or from their replacements. The go.mod files are downloaded with
+// go mod download in a scratch dir outside the module, so the go.sum of the module isn't changed either.
+func SimulateUpgrade(ctx context.Context, moduleDir, path, version string) (impact *graph.UpgradeImpact, err error) {
+	modFile, err := ReadFile(ctx, filepath.Join(moduleDir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	ctx = cmd.WithModuleDir(ctx, moduleDir)
+	dependencyGraph, err := cmd.GetDependencyGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tempDirs := cmd.GetTempDirProvider(ctx)
+	scratchDir, err := tempDirs.MkdirTemp("gocmd-upgrade")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if removeErr := tempDirs.Remove(scratchDir, err != nil); err == nil {
+			err = removeErr
+		}
+	}()
+	downloadCtx := cmd.WithModuleDir(ctx, scratchDir)
+
+	replaces := modFile.Replaces()
+	upgradeOptions := graph.UpgradeOptions{RequirementsReplaced: true, Requirements: func(module graph.Module) ([]graph.Module, error) {
+		return readRequirements(downloadCtx, moduleDir, module, replaces)
+	}}
+	for _, replace := range replaces {
+		upgradeOptions.Replaces = append(upgradeOptions.Replaces, graph.Edge{
+			From: graph.Module{Path: replace.OldPath, Version: replace.OldVersion},
+			To:   graph.Module{Path: replace.NewPath, Version: replace.NewVersion},
+		})
+	}
+	for _, exclude := range modFile.Excludes() {
+		upgradeOptions.Excludes = append(upgradeOptions.Excludes, graph.Module{Path: exclude.Path, Version: exclude.Version})
+	}
+	return dependencyGraph.SimulateUpgrade(path, version, upgradeOptions)
+}
+
+// Returns the requirements of the module's go.mod file, or of its replacement's go.mod file if replaced.
+// The go.mod file is downloaded in the dir of ctx, which should be outside the module.
+func readRequirements(ctx context.Context, moduleDir string, module graph.Module, replaces []Replace) ([]graph.Module, error) {
+	source := replacementOf(module, replaces)
+	goModPath := ""
+	// The go.mod files of the replacements are read from the FileSystem of ctx, and the downloaded ones from the module cache.
+	fileSystem := cmd.GetFileSystem(ctx)
+	if source.Version == "" {
+		replacementDir := filepath.FromSlash(source.Path)
+		if !filepath.IsAbs(replacementDir) {
+			replacementDir = filepath.Join(moduleDir, replacementDir)
+		}
+		goModPath = filepath.Join(replacementDir, "go.mod")
+	} else {
+		downloads, err := cmd.DownloadModules(ctx, source.String())
+		if err != nil {
+			return nil, err
+		}
+		if len(downloads) == 0 {
+			// Nothing is downloaded in dry run.
+			return nil, nil
+		}
+		if downloads[0].Error != "" {
+			return nil, errorutils.CheckError(fmt.Errorf("Failed downloading the go.mod file of %s: %s", source, downloads[0].Error))
+		}
+		goModPath = downloads[0].GoMod
+		fileSystem = fsys.OsFileSystem{}
+	}
+	content, err := fsys.ReadFile(fileSystem, goModPath)
+	if err != nil {
+		return nil, err
+	}
+	goMod, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	var requirements []graph.Module
+	for _, require := range goMod.Requires() {
+		requirements = append(requirements, graph.Module{Path: require.Path, Version: require.Version})
+	}
+	return requirements, nil
+}
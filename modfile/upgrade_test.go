@@ -0,0 +1,62 @@
+package modfile
+
+import (
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/graph"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSimulateUpgrade(t *testing.T) {
+	moduleDir, err := ioutil.TempDir("", "upgradeTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(moduleDir)
+	files := map[string]string{
+		"go.mod":         "module github.com/you/hello\n\nrequire rsc.io/quote v1.5.2\n\nreplace rsc.io/sampler v1.99.0 => ./sampler\n\nexclude golang.org/x/net v0.1.0\n",
+		"sampler/go.mod": "module rsc.io/sampler\n\nrequire golang.org/x/text v0.3.5\n",
+		"quote.mod":      "module rsc.io/quote\n\nrequire (\n\trsc.io/sampler v1.99.0\n\tgolang.org/x/net v0.1.0\n)\n",
+		"empty.mod":      "module empty\n",
+	}
+	for name, content := range files {
+		if err = os.MkdirAll(filepath.Join(moduleDir, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(filepath.Join(moduleDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	executor := cmd.NewFakeExecutor()
+	executor.On(cmd.ExecutorResult{Stdout: "github.com/you/hello rsc.io/quote@v1.5.2\ngithub.com/you/hello golang.org/x/text@v0.3.1\nrsc.io/quote@v1.5.2 rsc.io/sampler@v1.3.0\n"}, "mod", "graph")
+	executor.On(cmd.ExecutorResult{Stdout: `{"Path": "rsc.io/quote", "Version": "v1.5.3", "GoMod": "` + filepath.ToSlash(filepath.Join(moduleDir, "quote.mod")) + `"}`}, "mod", "download", "-json", "rsc.io/quote@v1.5.3")
+	emptyDownload := cmd.ExecutorResult{Stdout: `{"GoMod": "` + filepath.ToSlash(filepath.Join(moduleDir, "empty.mod")) + `"}`}
+	executor.On(emptyDownload, "mod", "download", "-json", "golang.org/x/net@v0.1.0")
+	executor.On(emptyDownload, "mod", "download", "-json", "golang.org/x/text@v0.3.5")
+
+	impact, err := SimulateUpgrade(cmd.WithExecutor(context.Background(), executor), moduleDir, "rsc.io/quote", "v1.5.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &graph.UpgradeImpact{
+		Upgraded: graph.Module{Path: "rsc.io/quote", Version: "v1.5.3"},
+		DependenciesDiff: graph.DependenciesDiff{
+			Added:   []graph.Module{{Path: "golang.org/x/net", Version: "v0.1.0"}},
+			Changed: []graph.VersionChange{{Path: "golang.org/x/text", Before: "v0.3.1", After: "v0.3.5"}, {Path: "rsc.io/sampler", Before: "v1.3.0", After: "v1.99.0"}},
+		},
+		Conflicts: []string{"The selected version golang.org/x/net@v0.1.0 is excluded."},
+	}
+	if !reflect.DeepEqual(expected, impact) {
+		t.Errorf("Expected: %+v, Got: %+v", expected, impact)
+	}
+	// go mod download runs outside the module, so it doesn't add the downloaded modules to go.sum.
+	for _, call := range executor.Calls() {
+		if isDownload := call.Cmd[1] == "mod" && call.Cmd[2] == "download"; isDownload == (call.Dir == moduleDir) {
+			t.Errorf("Expected only go mod graph to run in %s, Got: %+v", moduleDir, call)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/license"
+	"github.com/jfrog/gocmd/semver"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+// The rules a violation may break.
+const (
+	DeniedModuleRule    = "denied-module"
+	MaxDependenciesRule = "max-dependencies"
+	BannedLicenseRule   = "banned-license"
+	MinVersionRule      = "min-version"
+)
+
+// The rules the resolved dependencies of a module must follow. Empty rules are not evaluated.
+type Policy struct {
+	// Globs of the denied modules. Like GOPRIVATE, each glob matches a prefix of the module paths, such as
+	// "github.com/untrusted" or "*.example.com".
+	DeniedModules []string `json:"deniedModules,omitempty"`
+	// Globs of modules which are allowed even though matched by DeniedModules.
+	AllowedModules []string `json:"allowedModules,omitempty"`
+	// The maximum number of dependencies, unlimited if not positive.
+	MaxDependencies int `json:"maxDependencies,omitempty"`
+	// The SPDX identifiers of the banned licenses, such as "AGPL-3.0".
+	BannedLicenses []string `json:"bannedLicenses,omitempty"`
+	// The minimum versions of modules, by their paths.
+	MinVersions map[string]string `json:"minVersions,omitempty"`
+}
+
+// A rule broken by the dependencies.
+type Violation struct {
+	// One of the rule constants, such as DeniedModuleRule.
+	Rule string `json:"rule"`
+	// The module breaking the rule in the "path@version" notation, empty for MaxDependenciesRule.
+	Module  string `json:"module,omitempty"`
+	Message string `json:"message"`
+}
+
+func (violation Violation) String() string {
+	if violation.Module == "" {
+		return violation.Rule + ": " + violation.Message
+	}
+	return violation.Rule + ": " + violation.Module + ": " + violation.Message
+}
+
+// Reads a policy from a JSON file.
+func ReadPolicy(path string) (*Policy, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	policy := &Policy{}
+	if err = json.Unmarshal(content, policy); err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Failed parsing the policy %s: %s", path, err.Error()))
+	}
+	return policy, policy.Validate()
+}
+
+// Validates the globs and versions of the policy.
+func (policy *Policy) Validate() error {
+	for _, pattern := range append(append([]string{}, policy.DeniedModules...), policy.AllowedModules...) {
+		if _, err := path.Match(pattern, ""); pattern == "" || err != nil {
+			return errorutils.CheckError(fmt.Errorf("Invalid module pattern %q.", pattern))
+		}
+	}
+	for modulePath, version := range policy.MinVersions {
+		if !semver.IsValid(version) {
+			return errorutils.CheckError(fmt.Errorf("Invalid minimum version %q of %s.", version, modulePath))
+		}
+	}
+	return nil
+}
+
+// Evaluates the policy over the resolved modules, as returned by cmd.ListModules, and their licenses, if the licenses
+// should be checked. Replaced modules are evaluated by their replacements. Modules replaced by local directories are
+// evaluated by their paths, but not by their versions.
+// Returns the violations ordered by the rules and then by the order of the modules.
+func (policy *Policy) Evaluate(modules []cmd.ListedModule, licenses []license.ModuleLicenses) ([]Violation, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	var dependencies []cmd.ListedModule
+	for _, module := range modules {
+		if module.Main {
+			continue
+		}
+		// The replacement, if not a local directory, has no replacement itself.
+		if module.Replace != nil && module.Replace.Version != "" {
+			module = *module.Replace
+		}
+		dependencies = append(dependencies, module)
+	}
+
+	var violations []Violation
+	for _, module := range dependencies {
+		if cmd.MatchPrefixPatterns(policy.DeniedModules, module.Path) && !cmd.MatchPrefixPatterns(policy.AllowedModules, module.Path) {
+			violations = append(violations, Violation{Rule: DeniedModuleRule, Module: moduleId(module), Message: "The module is denied."})
+		}
+	}
+	if policy.MaxDependencies > 0 && len(dependencies) > policy.MaxDependencies {
+		violations = append(violations, Violation{Rule: MaxDependenciesRule,
+			Message: fmt.Sprintf("%d dependencies exceed the maximum of %d.", len(dependencies), policy.MaxDependencies)})
+	}
+	violations = append(violations, policy.evaluateLicenses(licenses)...)
+	for _, module := range dependencies {
+		minVersion, ok := policy.MinVersions[module.Path]
+		if ok && module.Replace == nil && semver.Compare(module.Version, minVersion) < 0 {
+			violations = append(violations, Violation{Rule: MinVersionRule, Module: moduleId(module),
+				Message: "The version is lower than the minimum version " + minVersion + "."})
+		}
+	}
+	return violations, nil
+}
+
+func (policy *Policy) evaluateLicenses(licenses []license.ModuleLicenses) []Violation {
+	banned := map[string]bool{}
+	for _, spdxId := range policy.BannedLicenses {
+		banned[strings.ToLower(spdxId)] = true
+	}
+	var violations []Violation
+	for _, moduleLicenses := range licenses {
+		var bannedIds []string
+		for _, moduleLicense := range moduleLicenses.Licenses {
+			if banned[strings.ToLower(moduleLicense.SpdxId)] {
+				bannedIds = append(bannedIds, moduleLicense.SpdxId)
+			}
+		}
+		if len(bannedIds) > 0 {
+			sort.Strings(bannedIds)
+			violations = append(violations, Violation{Rule: BannedLicenseRule, Module: moduleLicenses.Module,
+				Message: "The module is licensed under the banned " + strings.Join(bannedIds, ", ") + "."})
+		}
+	}
+	return violations
+}
+
+func moduleId(module cmd.ListedModule) string {
+	if module.Version == "" {
+		return module.Path
+	}
+	return module.Path + "@" + module.Version
+}
@@ -0,0 +1,94 @@
+package policy
+
+import (
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/license"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var modules = []cmd.ListedModule{
+	{Path: "github.com/you/hello", Main: true},
+	{Path: "github.com/untrusted/lib", Version: "v1.0.0"},
+	{Path: "github.com/untrusted/approved", Version: "v1.2.0"},
+	{Path: "golang.org/x/text", Version: "v0.3.0", Replace: &cmd.ListedModule{Path: "golang.org/x/text", Version: "v0.3.2"}},
+	{Path: "rsc.io/quote", Version: "v1.5.2", Replace: &cmd.ListedModule{Path: "../quote"}},
+}
+
+var licenses = []license.ModuleLicenses{
+	{Module: "github.com/untrusted/lib@v1.0.0", Licenses: []license.License{{File: "LICENSE", SpdxId: "MIT"}}},
+	{Module: "rsc.io/quote@v1.5.2", Licenses: []license.License{{File: "LICENSE", SpdxId: "GPL-3.0"}, {File: "COPYING", SpdxId: "AGPL-3.0"}}},
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		expected []Violation
+	}{
+		{"empty", Policy{}, nil},
+		{"denied", Policy{DeniedModules: []string{"github.com/untrusted"}, AllowedModules: []string{"github.com/*/approved"}},
+			[]Violation{{DeniedModuleRule, "github.com/untrusted/lib@v1.0.0", "The module is denied."}}},
+		{"maxDependencies", Policy{MaxDependencies: 3}, []Violation{{MaxDependenciesRule, "", "4 dependencies exceed the maximum of 3."}}},
+		{"bannedLicenses", Policy{BannedLicenses: []string{"agpl-3.0", "GPL-3.0"}},
+			[]Violation{{BannedLicenseRule, "rsc.io/quote@v1.5.2", "The module is licensed under the banned AGPL-3.0, GPL-3.0."}}},
+		{"minVersions", Policy{MinVersions: map[string]string{"golang.org/x/text": "v0.3.3", "github.com/untrusted/lib": "v1.0.0", "rsc.io/quote": "v1.6.0"}},
+			[]Violation{{MinVersionRule, "golang.org/x/text@v0.3.2", "The version is lower than the minimum version v0.3.3."}}},
+		{"ordered", Policy{MaxDependencies: 4, DeniedModules: []string{"golang.org"}, MinVersions: map[string]string{"github.com/untrusted/approved": "v1.3"}},
+			[]Violation{{DeniedModuleRule, "golang.org/x/text@v0.3.2", "The module is denied."}, {MinVersionRule, "github.com/untrusted/approved@v1.2.0", "The version is lower than the minimum version v1.3."}}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations, err := test.policy.Evaluate(modules, licenses)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(test.expected, violations) {
+				t.Errorf("Test name: %s: Expected: %v, Got: %v", test.name, test.expected, violations)
+			}
+		})
+	}
+}
+
+func TestReadPolicy(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "policyTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	tests := []struct {
+		name     string
+		content  string
+		expected *Policy
+	}{
+		{"valid", `{"deniedModules": ["github.com/untrusted"], "maxDependencies": 100, "minVersions": {"golang.org/x/text": "v0.3.3"}}`,
+			&Policy{DeniedModules: []string{"github.com/untrusted"}, MaxDependencies: 100, MinVersions: map[string]string{"golang.org/x/text": "v0.3.3"}}},
+		{"invalidPattern", `{"allowedModules": ["github.com/["]}`, nil},
+		{"invalidVersion", `{"minVersions": {"golang.org/x/text": "0.3.3"}}`, nil},
+		{"invalidJson", `{"maxDependencies": "100"}`, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policyPath := filepath.Join(tempDir, test.name+".json")
+			if err := ioutil.WriteFile(policyPath, []byte(test.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			policy, err := ReadPolicy(policyPath)
+			if test.expected == nil {
+				if err == nil {
+					t.Error("Expected an error for", test.content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(test.expected, policy) {
+				t.Errorf("Expected: %+v, Got: %+v", test.expected, policy)
+			}
+		})
+	}
+}
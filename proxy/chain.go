@@ -0,0 +1,234 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The special GOPROXY values.
+const (
+	Direct = "direct"
+	Off    = "off"
+)
+
+// The module probed for checking the availability of the proxies.
+const probeModule = "golang.org/x/text"
+
+// A proxy in a GOPROXY list.
+type Proxy struct {
+	// The URL of the proxy, or Direct, or Off.
+	Url      string
+	Username string
+	Password string
+	// If true, the go command falls back to the next proxy on any error, and not only on 404 and 410 responses.
+	// Separated from the next proxy by "|" instead of ",".
+	FallbackOnAnyError bool
+}
+
+// Returns true for Direct and Off.
+func (proxy *Proxy) IsKeyword() bool {
+	return proxy.Url == Direct || proxy.Url == Off
+}
+
+// Models a GOPROXY value, which is an ordered list of proxies, tried one after the other.
+type ProxyChain struct {
+	proxies  []Proxy
+	provider CredentialProvider
+}
+
+func NewProxyChain() *ProxyChain {
+	return &ProxyChain{}
+}
+
+// Parses a comma or pipe separated GOPROXY value. Credentials included in the URLs are kept.
+func ParseProxyChain(goproxy string) (*ProxyChain, error) {
+	chain := NewProxyChain()
+	for goproxy != "" {
+		var element string
+		fallbackOnAnyError := false
+		if index := strings.IndexAny(goproxy, ",|"); index >= 0 {
+			element, fallbackOnAnyError, goproxy = goproxy[:index], goproxy[index] == '|', goproxy[index+1:]
+		} else {
+			element, goproxy = goproxy, ""
+		}
+		element = strings.TrimSpace(element)
+		if element == "" {
+			continue
+		}
+		proxy := Proxy{Url: element, FallbackOnAnyError: fallbackOnAnyError}
+		if !proxy.IsKeyword() {
+			parsedUrl, err := url.Parse(element)
+			if err != nil {
+				return nil, errorutils.CheckError(err)
+			}
+			if parsedUrl.User != nil {
+				proxy.Username = parsedUrl.User.Username()
+				proxy.Password, _ = parsedUrl.User.Password()
+				parsedUrl.User = nil
+				proxy.Url = parsedUrl.String()
+			}
+		}
+		if err := chain.Append(proxy); err != nil {
+			return nil, err
+		}
+	}
+	return chain, nil
+}
+
+// Validates the proxy and appends it to the end of the chain.
+// Proxies can't be appended after Off, since the go command never reaches them.
+func (chain *ProxyChain) Append(proxy Proxy) error {
+	if length := len(chain.proxies); length > 0 && chain.proxies[length-1].Url == Off {
+		return errorutils.CheckError(fmt.Errorf("Can't append %s to a GOPROXY ending with %s.", proxy.Url, Off))
+	}
+	if !proxy.IsKeyword() {
+		parsedUrl, err := url.Parse(proxy.Url)
+		if err != nil {
+			return errorutils.CheckError(err)
+		}
+		if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" && parsedUrl.Scheme != "file" {
+			return errorutils.CheckError(fmt.Errorf("Invalid proxy URL %s: the scheme must be http, https or file.", proxy.Url))
+		}
+		if parsedUrl.Scheme != "file" && parsedUrl.Host == "" {
+			return errorutils.CheckError(fmt.Errorf("Invalid proxy URL %s: missing host.", proxy.Url))
+		}
+	}
+	chain.proxies = append(chain.proxies, proxy)
+	return nil
+}
+
+// Appends Direct, so modules not found in the proxies are downloaded from their VCS.
+func (chain *ProxyChain) AppendDirect() error {
+	return chain.Append(Proxy{Url: Direct})
+}
+
+// Appends Off, so modules not found in the proxies fail to download.
+func (chain *ProxyChain) AppendOff() error {
+	return chain.Append(Proxy{Url: Off})
+}
+
+// Sets the credentials of all the proxies with the given URL.
+func (chain *ProxyChain) SetCredentials(proxyUrl, username, password string) error {
+	found := false
+	for i := range chain.proxies {
+		if strings.TrimSuffix(chain.proxies[i].Url, "/") == strings.TrimSuffix(proxyUrl, "/") {
+			chain.proxies[i].Username = username
+			chain.proxies[i].Password = password
+			found = true
+		}
+	}
+	if !found {
+		return errorutils.CheckError(fmt.Errorf("The proxy %s is not part of the GOPROXY.", proxyUrl))
+	}
+	return nil
+}
+
+// Returns a copy of the proxies of the chain.
+func (chain *ProxyChain) Proxies() []Proxy {
+	return append([]Proxy(nil), chain.proxies...)
+}
+
+// Returns the GOPROXY value, with the credentials of the proxies included in their URLs.
+func (chain *ProxyChain) String() string {
+	return chain.format(true)
+}
+
+// Returns the GOPROXY value without credentials, for logging.
+func (chain *ProxyChain) MaskedString() string {
+	return chain.format(false)
+}
+
+func (chain *ProxyChain) format(withCredentials bool) string {
+	var goproxy strings.Builder
+	for i, proxy := range chain.proxies {
+		if withCredentials {
+			goproxy.WriteString(proxy.urlWithCredentials())
+		} else {
+			goproxy.WriteString(proxy.Url)
+		}
+		if i < len(chain.proxies)-1 {
+			if proxy.FallbackOnAnyError {
+				goproxy.WriteString("|")
+			} else {
+				goproxy.WriteString(",")
+			}
+		}
+	}
+	return goproxy.String()
+}
+
+func (proxy *Proxy) urlWithCredentials() string {
+	if proxy.IsKeyword() || proxy.Username == "" {
+		return proxy.Url
+	}
+	parsedUrl, err := url.Parse(proxy.Url)
+	if err != nil {
+		return proxy.Url
+	}
+	if proxy.Password == "" {
+		parsedUrl.User = url.User(proxy.Username)
+	} else {
+		parsedUrl.User = url.UserPassword(proxy.Username, proxy.Password)
+	}
+	return parsedUrl.String()
+}
+
+// Returns a copy of ctx, whose go commands use the chain as their GOPROXY.
+func WithProxyChain(ctx context.Context, chain *ProxyChain) context.Context {
+	return cmd.WithEnv(ctx, "GOPROXY", chain.String())
+}
+
+// The result of probing a proxy.
+type ProbeResult struct {
+	Proxy      Proxy
+	Available  bool
+	StatusCode int
+	Err        error
+}
+
+// Checks the availability of the http and https proxies of the chain, by requesting the versions list of a module.
+// A proxy is available if it responds with a status other than a server error or an authentication failure.
+// If client is nil, the transport set by SetTransport is used.
+func (chain *ProxyChain) Probe(ctx context.Context, client *http.Client) []ProbeResult {
+	client = getHttpClient(client)
+	var results []ProbeResult
+	for _, proxy := range chain.proxies {
+		if proxy.IsKeyword() || strings.HasPrefix(proxy.Url, "file:") {
+			continue
+		}
+		result := ProbeResult{Proxy: proxy}
+		result.StatusCode, result.Err = chain.probe(ctx, client, proxy)
+		result.Available = result.Err == nil && result.StatusCode < 500 && result.StatusCode != http.StatusUnauthorized && result.StatusCode != http.StatusForbidden
+		cmd.GetLogger(ctx).Debug("Probed proxy", proxy.Url, "available:", result.Available)
+		results = append(results, result)
+	}
+	return results
+}
+
+func (chain *ProxyChain) probe(ctx context.Context, client *http.Client, proxy Proxy) (int, error) {
+	request, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(proxy.Url, "/")+"/"+probeModule+"/@v/list", nil)
+	if err != nil {
+		return 0, errorutils.CheckError(err)
+	}
+	credentials, err := chain.getCredentials(ctx, proxy)
+	if err != nil {
+		return 0, err
+	}
+	if credentials != nil {
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	if err = cmd.GetOptions(ctx).RateLimiter.Wait(ctx); err != nil {
+		return 0, errorutils.CheckError(err)
+	}
+	response, err := client.Do(request.WithContext(ctx))
+	if err != nil {
+		return 0, errorutils.CheckError(err)
+	}
+	response.Body.Close()
+	return response.StatusCode, nil
+}
@@ -0,0 +1,162 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The metadata of a module version, returned by the .info and @latest endpoints.
+type VersionInfo struct {
+	Version string
+	Time    time.Time
+}
+
+// A client of the module proxy protocol, for fetching modules from a GOPROXY without the go command.
+// See https://golang.org/ref/mod#goproxy-protocol.
+type Client struct {
+	// An http or https proxy. Its credentials, if any, are sent with basic authentication.
+	Proxy Proxy
+	// Supplies the credentials if the proxy has none. Optional.
+	CredentialProvider CredentialProvider
+	// If nil, a client with Transport is used.
+	HttpClient *http.Client
+	// Sends the requests if HttpClient is nil. Defaults to the transport set by SetTransport.
+	Transport http.RoundTripper
+}
+
+func NewClient(proxy Proxy) (*Client, error) {
+	if proxy.IsKeyword() || strings.HasPrefix(proxy.Url, "file:") {
+		return nil, errorutils.CheckError(fmt.Errorf("The proxy protocol client doesn't support %s.", proxy.Url))
+	}
+	return &Client{Proxy: proxy}, nil
+}
+
+// Returns the versions listed by the proxy, not including pseudo-versions. A module which doesn't exist has no versions.
+func (client *Client) List(ctx context.Context, modulePath string) ([]string, error) {
+	body, err := client.get(ctx, modulePath, "", "/@v/list")
+	if _, ok := err.(*ModuleNotFoundError); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	var versions []string
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		if version := strings.TrimSpace(scanner.Text()); version != "" {
+			versions = append(versions, version)
+		}
+	}
+	return versions, errorutils.CheckError(scanner.Err())
+}
+
+// Returns the metadata of the module version. The version may also be a query the proxy resolves, such as a branch name.
+func (client *Client) Info(ctx context.Context, modulePath, version string) (*VersionInfo, error) {
+	body, err := client.get(ctx, modulePath, version, "/@v/"+cmd.EscapeModulePath(version)+".info")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return readVersionInfo(body)
+}
+
+// Returns the metadata of the latest version of the module, as chosen by the proxy.
+func (client *Client) Latest(ctx context.Context, modulePath string) (*VersionInfo, error) {
+	body, err := client.get(ctx, modulePath, "", "/@latest")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return readVersionInfo(body)
+}
+
+// Returns the content of the go.mod file of the module version.
+func (client *Client) Mod(ctx context.Context, modulePath, version string) ([]byte, error) {
+	body, err := client.get(ctx, modulePath, version, "/@v/"+cmd.EscapeModulePath(version)+".mod")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	content, err := ioutil.ReadAll(body)
+	return content, errorutils.CheckError(err)
+}
+
+// Writes the zip of the module version to writer.
+func (client *Client) Zip(ctx context.Context, modulePath, version string, writer io.Writer) error {
+	body, err := client.get(ctx, modulePath, version, "/@v/"+cmd.EscapeModulePath(version)+".zip")
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(writer, body)
+	return errorutils.CheckError(err)
+}
+
+// Requests the endpoint of the module, and returns the body of a successful response.
+// Returns a ModuleNotFoundError for 404 and 410 responses, which the proxy returns for modules and versions it doesn't have.
+// The request waits for the RateLimiter of ctx, if any.
+func (client *Client) get(ctx context.Context, modulePath, version, endpoint string) (io.ReadCloser, error) {
+	requestUrl := strings.TrimSuffix(client.Proxy.Url, "/") + "/" + cmd.EscapeModulePath(modulePath) + endpoint
+	request, err := http.NewRequest(http.MethodGet, requestUrl, nil)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	credentials, err := client.getCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if credentials != nil {
+		request.SetBasicAuth(credentials.Username, credentials.Password)
+	}
+	httpClient := client.HttpClient
+	if httpClient == nil && client.Transport != nil {
+		httpClient = &http.Client{Transport: client.Transport}
+	}
+	httpClient = getHttpClient(httpClient)
+	if err = cmd.GetOptions(ctx).RateLimiter.Wait(ctx); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	cmd.GetLogger(ctx).Debug("Requesting", requestUrl)
+	response, err := httpClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	switch response.StatusCode {
+	case http.StatusOK:
+		return response.Body, nil
+	case http.StatusNotFound, http.StatusGone:
+		response.Body.Close()
+		return nil, errorutils.CheckError(&ModuleNotFoundError{Registry: client.Proxy.Url, Module: modulePath, Version: version})
+	default:
+		response.Body.Close()
+		return nil, errorutils.CheckError(fmt.Errorf("Failed requesting %s from %s: %s", modulePath+endpoint, client.Proxy.Url, response.Status))
+	}
+}
+
+func (client *Client) getCredentials(ctx context.Context) (*Credentials, error) {
+	if client.Proxy.Username != "" {
+		return &Credentials{Username: client.Proxy.Username, Password: client.Proxy.Password}, nil
+	}
+	if client.CredentialProvider == nil {
+		return nil, nil
+	}
+	return client.CredentialProvider.GetCredentials(ctx, client.Proxy.Url)
+}
+
+func readVersionInfo(body io.Reader) (*VersionInfo, error) {
+	info := &VersionInfo{}
+	if err := json.NewDecoder(body).Decode(info); err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	return info, nil
+}
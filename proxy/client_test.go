@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"github.com/jfrog/gocmd/cmd"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestProxyServer() *httptest.Server {
+	return httptest.NewServer(testProxyHandler)
+}
+
+var testProxyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if username, password, _ := r.BasicAuth(); username != "user" || password != "pass" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch r.URL.Path {
+	case "/github.com/!test/mod/@v/list":
+		w.Write([]byte("v1.0.0\nv1.1.0\n"))
+	case "/github.com/!test/mod/@v/v1.1.0.info", "/github.com/!test/mod/@latest":
+		w.Write([]byte(`{"Version":"v1.1.0","Time":"2020-01-02T03:04:05Z"}`))
+	case "/github.com/!test/mod/@v/v1.1.0.mod":
+		w.Write([]byte("module github.com/Test/mod\n"))
+	case "/github.com/!test/mod/@v/v1.1.0.zip":
+		w.Write([]byte("zip content"))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+})
+
+func TestClient(t *testing.T) {
+	server := newTestProxyServer()
+	defer server.Close()
+	client, err := NewClient(Proxy{Url: server.URL + "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.CredentialProvider = NewStaticCredentialProvider("user", "pass")
+	ctx := context.Background()
+	module := "github.com/Test/mod"
+
+	versions, err := client.List(ctx, module)
+	if err != nil || !reflect.DeepEqual(versions, []string{"v1.0.0", "v1.1.0"}) {
+		t.Errorf("Unexpected versions: %v, %v", versions, err)
+	}
+	versions, err = client.List(ctx, "github.com/test/missing")
+	if err != nil || versions != nil {
+		t.Errorf("Expected no versions for a missing module, Got: %v, %v", versions, err)
+	}
+	expectedInfo := &VersionInfo{Version: "v1.1.0", Time: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+	info, err := client.Info(ctx, module, "v1.1.0")
+	if err != nil || !reflect.DeepEqual(info, expectedInfo) {
+		t.Errorf("Unexpected info: %+v, %v", info, err)
+	}
+	info, err = client.Latest(ctx, module)
+	if err != nil || !reflect.DeepEqual(info, expectedInfo) {
+		t.Errorf("Unexpected latest: %+v, %v", info, err)
+	}
+	mod, err := client.Mod(ctx, module, "v1.1.0")
+	if err != nil || string(mod) != "module github.com/Test/mod\n" {
+		t.Errorf("Unexpected go.mod: %s, %v", mod, err)
+	}
+	var zip bytes.Buffer
+	if err = client.Zip(ctx, module, "v1.1.0", &zip); err != nil || zip.String() != "zip content" {
+		t.Errorf("Unexpected zip: %s, %v", zip.String(), err)
+	}
+	if _, err = client.Mod(ctx, module, "v2.0.0"); err == nil {
+		t.Error("Expected an error for a missing version")
+	} else if notFound, ok := err.(*ModuleNotFoundError); !ok || notFound.Version != "v2.0.0" {
+		t.Errorf("Expected a ModuleNotFoundError, Got: %v", err)
+	}
+
+	client.CredentialProvider = nil
+	if _, err = client.Info(ctx, module, "v1.1.0"); err == nil {
+		t.Error("Expected an error without credentials")
+	}
+	if _, err = NewClient(Proxy{Url: Direct}); err == nil {
+		t.Error("Expected an error for direct")
+	}
+}
+
+func TestClientRateLimited(t *testing.T) {
+	server := newTestProxyServer()
+	defer server.Close()
+	client, err := NewClient(Proxy{Url: server.URL, Username: "user", Password: "pass"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := cmd.WithRateLimiter(context.Background(), cmd.NewRateLimiter(20, 1))
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err = client.Latest(ctx, "github.com/Test/mod"); err != nil {
+			t.Error(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Error("Expecting the requests to be rate limited, took:", elapsed)
+	}
+}
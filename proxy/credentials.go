@@ -0,0 +1,241 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// Supplies the credentials of a proxy when they are needed, instead of keeping them in the GOPROXY URLs.
+type CredentialProvider interface {
+	// Returns the credentials for the proxy, or nil if the provider has none.
+	GetCredentials(ctx context.Context, proxyUrl string) (*Credentials, error)
+}
+
+// Provides the same credentials for all the proxies.
+type StaticCredentialProvider struct {
+	credentials Credentials
+}
+
+func NewStaticCredentialProvider(username, password string) *StaticCredentialProvider {
+	return &StaticCredentialProvider{credentials: Credentials{Username: username, Password: password}}
+}
+
+func (provider *StaticCredentialProvider) GetCredentials(ctx context.Context, proxyUrl string) (*Credentials, error) {
+	credentials := provider.credentials
+	return &credentials, nil
+}
+
+// Provides the credentials from environment variables.
+type EnvCredentialProvider struct {
+	usernameVar string
+	passwordVar string
+}
+
+func NewEnvCredentialProvider(usernameVar, passwordVar string) *EnvCredentialProvider {
+	return &EnvCredentialProvider{usernameVar: usernameVar, passwordVar: passwordVar}
+}
+
+// Returns nil if the username variable is not set.
+func (provider *EnvCredentialProvider) GetCredentials(ctx context.Context, proxyUrl string) (*Credentials, error) {
+	username := os.Getenv(provider.usernameVar)
+	if username == "" {
+		return nil, nil
+	}
+	return &Credentials{Username: username, Password: os.Getenv(provider.passwordVar)}, nil
+}
+
+// Provides the credentials of the proxy's host from a netrc file.
+type NetrcCredentialProvider struct {
+	path string
+}
+
+// Creates a provider reading the netrc file in path. If path is empty, the file used by the go command is read,
+// which is $NETRC, or .netrc (_netrc on Windows) in the home directory.
+func NewNetrcCredentialProvider(path string) *NetrcCredentialProvider {
+	return &NetrcCredentialProvider{path: path}
+}
+
+func (provider *NetrcCredentialProvider) GetCredentials(ctx context.Context, proxyUrl string) (*Credentials, error) {
+	path := provider.path
+	if path == "" {
+		path = defaultNetrcPath()
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	parsedUrl, err := url.Parse(proxyUrl)
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	machines := parseNetrc(string(content))
+	if credentials, ok := machines[parsedUrl.Hostname()]; ok {
+		return credentials, nil
+	}
+	// The default machine matches any host without a machine of its own.
+	return machines[""], nil
+}
+
+// Provides the credentials by calling a function.
+type CallbackCredentialProvider func(ctx context.Context, proxyUrl string) (*Credentials, error)
+
+func (callback CallbackCredentialProvider) GetCredentials(ctx context.Context, proxyUrl string) (*Credentials, error) {
+	return callback(ctx, proxyUrl)
+}
+
+// Sets the provider supplying the credentials of the proxies which have no credentials of their own.
+func (chain *ProxyChain) SetCredentialProvider(provider CredentialProvider) {
+	chain.provider = provider
+}
+
+// Returns the credentials of the proxy, if it has any, or the credentials supplied by the chain's provider.
+func (chain *ProxyChain) getCredentials(ctx context.Context, proxy Proxy) (*Credentials, error) {
+	if proxy.Username != "" {
+		return &Credentials{Username: proxy.Username, Password: proxy.Password}, nil
+	}
+	if chain.provider == nil {
+		return nil, nil
+	}
+	return chain.provider.GetCredentials(ctx, proxy.Url)
+}
+
+// Returns a copy of ctx, whose go commands use the chain as their GOPROXY without credentials in the URLs.
+// The credentials are written to a netrc file in a new directory of the TempDirProvider of ctx, which the go commands
+// read through the NETRC and GOAUTH environment variables. The machines of the netrc file the go commands would
+// otherwise read, from the NETRC of ctx or the default netrc file, are kept in the file after those of the chain.
+// The returned function removes the file, and should be called when done.
+func WithProxyChainCredentials(ctx context.Context, chain *ProxyChain) (context.Context, func(), error) {
+	var netrc strings.Builder
+	for _, proxy := range chain.proxies {
+		if proxy.IsKeyword() || strings.HasPrefix(proxy.Url, "file:") {
+			continue
+		}
+		credentials, err := chain.getCredentials(ctx, proxy)
+		if err != nil {
+			return nil, nil, err
+		}
+		if credentials == nil {
+			continue
+		}
+		parsedUrl, err := url.Parse(proxy.Url)
+		if err != nil {
+			return nil, nil, errorutils.CheckError(err)
+		}
+		if strings.ContainsAny(credentials.Username+credentials.Password, " \t\n") {
+			return nil, nil, errorutils.CheckError(fmt.Errorf("The credentials of %s contain whitespaces, which netrc doesn't support.", proxy.Url))
+		}
+		fmt.Fprintf(&netrc, "machine %s login %s password %s\n", parsedUrl.Hostname(), credentials.Username, credentials.Password)
+	}
+	// The go command uses the first machine matching a host, so the machines of the chain take precedence.
+	userNetrc, err := readUserNetrc(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	netrc.Write(userNetrc)
+
+	tempDirs := cmd.GetTempDirProvider(ctx)
+	netrcDir, err := tempDirs.MkdirTemp("gocmd-netrc")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		if err := tempDirs.Remove(netrcDir, false); err != nil {
+			cmd.GetLogger(ctx).Debug("Failed removing the netrc file", netrcDir, err.Error())
+		}
+	}
+	netrcPath := filepath.Join(netrcDir, "netrc")
+	if err = ioutil.WriteFile(netrcPath, []byte(netrc.String()), 0600); err != nil {
+		tempDirs.Remove(netrcDir, true)
+		return nil, nil, errorutils.CheckError(err)
+	}
+	ctx = cmd.WithEnv(ctx, "GOPROXY", chain.MaskedString())
+	ctx = cmd.WithEnv(ctx, "NETRC", netrcPath)
+	ctx = cmd.WithEnv(ctx, "GOAUTH", "netrc")
+	return ctx, cleanup, nil
+}
+
+// Returns the content of the netrc file the go commands of ctx read, ending with a newline, or nil if there's none.
+func readUserNetrc(ctx context.Context) ([]byte, error) {
+	path := cmd.GetOptions(ctx).Env["NETRC"]
+	if path == "" {
+		path = defaultNetrcPath()
+	}
+	if path == "" {
+		return nil, nil
+	}
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errorutils.CheckError(err)
+	}
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		content = append(content, '\n')
+	}
+	return content, nil
+}
+
+// Parses the machines of a netrc content. The default machine is returned under an empty key.
+func parseNetrc(content string) map[string]*Credentials {
+	machines := map[string]*Credentials{}
+	var current *Credentials
+	tokens := strings.Fields(content)
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			if i+1 < len(tokens) {
+				i++
+				current = &Credentials{}
+				machines[tokens[i]] = current
+			}
+		case "default":
+			current = &Credentials{}
+			machines[""] = current
+		case "login":
+			if i+1 < len(tokens) && current != nil {
+				i++
+				current.Username = tokens[i]
+			}
+		case "password":
+			if i+1 < len(tokens) && current != nil {
+				i++
+				current.Password = tokens[i]
+			}
+		case "macdef":
+			// Macros end with an empty line, which is lost when splitting to tokens. Stop parsing, as done by the go command.
+			return machines
+		}
+	}
+	return machines
+}
+
+func defaultNetrcPath() string {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "_netrc")
+	}
+	return filepath.Join(home, ".netrc")
+}
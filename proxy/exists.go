@@ -0,0 +1,57 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"net/http"
+)
+
+// The module version doesn't exist in the registry.
+type ModuleNotFoundError struct {
+	Registry string
+	Module   string
+	// Empty if no version of the module exists.
+	Version string
+}
+
+func (err *ModuleNotFoundError) Error() string {
+	if err.Version == "" {
+		return fmt.Sprintf("No version of %s exists in %s.", err.Module, err.Registry)
+	}
+	return fmt.Sprintf("%s@%s doesn't exist in %s.", err.Module, err.Version, err.Registry)
+}
+
+// Checks whether the module version exists in the registry, which is an http or https GOPROXY.
+// The version is checked with its .info endpoint, so pseudo-versions which aren't listed are found too.
+// If version is empty, checks whether the registry lists any version of the module.
+// Responses other than 200, 404 and 410 are returned as errors. If httpClient is nil, the transport set by SetTransport is used.
+func ModuleExists(ctx context.Context, httpClient *http.Client, registry Proxy, modulePath, version string) (bool, error) {
+	client, err := NewClient(registry)
+	if err != nil {
+		return false, err
+	}
+	client.HttpClient = httpClient
+	if version == "" {
+		versions, err := client.List(ctx, modulePath)
+		return len(versions) > 0, err
+	}
+	_, err = client.Info(ctx, modulePath, version)
+	if _, ok := err.(*ModuleNotFoundError); ok {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Returns a ModuleNotFoundError if the module version doesn't exist in the registry, see ModuleExists.
+// Checking before running the go command gives a precise error, instead of the 404 reported by the go command.
+func CheckModuleExists(ctx context.Context, client *http.Client, registry Proxy, modulePath, version string) error {
+	exists, err := ModuleExists(ctx, client, registry, modulePath, version)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errorutils.CheckError(&ModuleNotFoundError{Registry: registry.Url, Module: modulePath, Version: version})
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestModuleExists(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username, password, _ := r.BasicAuth(); username != "user" || password != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/github.com/!test/mod/@v/list":
+			w.Write([]byte("v1.0.0\n"))
+		case "/github.com/test/empty/@v/list":
+			w.Write([]byte("\n"))
+		case "/github.com/!test/mod/@v/v1.0.0.info":
+			w.Write([]byte(`{"Version":"v1.0.0"}`))
+		case "/github.com/test/error/@v/v1.0.0.info":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/github.com/test/gone/@v/v1.0.0.info":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	registry := Proxy{Url: server.URL + "/", Username: "user", Password: "pass"}
+	tests := []struct {
+		name       string
+		module     string
+		version    string
+		exists     bool
+		expectsErr bool
+	}{
+		{"version", "github.com/Test/mod", "v1.0.0", true, false},
+		{"missingVersion", "github.com/Test/mod", "v2.0.0", false, false},
+		{"anyVersion", "github.com/Test/mod", "", true, false},
+		{"emptyList", "github.com/test/empty", "", false, false},
+		{"missingModule", "github.com/test/missing", "", false, false},
+		{"gone", "github.com/test/gone", "v1.0.0", false, false},
+		{"serverError", "github.com/test/error", "v1.0.0", false, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			exists, err := ModuleExists(context.Background(), nil, registry, test.module, test.version)
+			if (err != nil) != test.expectsErr {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if exists != test.exists {
+				t.Errorf("Expected exists: %t, Got: %t", test.exists, exists)
+			}
+		})
+	}
+
+	if _, err := ModuleExists(context.Background(), nil, Proxy{Url: server.URL}, "github.com/Test/mod", "v1.0.0"); err == nil {
+		t.Error("Expected an error for a registry rejecting the credentials")
+	}
+	if _, err := ModuleExists(context.Background(), nil, Proxy{Url: Direct}, "github.com/Test/mod", ""); err == nil {
+		t.Error("Expected an error for direct")
+	}
+	err := CheckModuleExists(context.Background(), nil, registry, "github.com/Test/mod", "v2.0.0")
+	if notFound, ok := err.(*ModuleNotFoundError); !ok || notFound.Version != "v2.0.0" {
+		t.Errorf("Expected a ModuleNotFoundError, Got: %v", err)
+	}
+	if err = CheckModuleExists(context.Background(), nil, registry, "github.com/Test/mod", "v1.0.0"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,200 @@
+package proxy
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/log"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The TLS settings of proxies served with certificates of a custom CA, or requiring client certificates (mTLS).
+type TlsOptions struct {
+	// A PEM bundle of the CA certificates trusted in addition to the system roots.
+	CaBundlePath string
+	// The PEM client certificate and its key, presented to the proxies requiring mTLS.
+	ClientCertPath string
+	ClientKeyPath  string
+}
+
+// Returns a TLS configuration trusting the system roots and the CA bundle, and presenting the client certificate.
+func (options *TlsOptions) TlsConfig() (*tls.Config, error) {
+	if (options.ClientCertPath == "") != (options.ClientKeyPath == "") {
+		return nil, errorutils.CheckError(errors.New("The client certificate and its key must be set together."))
+	}
+	config := &tls.Config{}
+	if options.CaBundlePath != "" {
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			log.Debug("Failed loading the system root certificates, trusting only", options.CaBundlePath+":", err.Error())
+			roots = x509.NewCertPool()
+		}
+		bundle, err := ioutil.ReadFile(options.CaBundlePath)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		if !roots.AppendCertsFromPEM(bundle) {
+			return nil, errorutils.CheckError(fmt.Errorf("No PEM certificates found in the CA bundle %s.", options.CaBundlePath))
+		}
+		config.RootCAs = roots
+	}
+	if options.ClientCertPath != "" {
+		certificate, err := tls.LoadX509KeyPair(options.ClientCertPath, options.ClientKeyPath)
+		if err != nil {
+			return nil, errorutils.CheckError(err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+// Returns a transport with the settings of http.DefaultTransport and the TLS configuration,
+// to be set with SetTransport or as the Transport of a Client.
+func (options *TlsOptions) NewTransport() (*http.Transport, error) {
+	config, err := options.TlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		TLSClientConfig:       config,
+	}, nil
+}
+
+// Returns a copy of ctx, whose go commands use the chain as their GOPROXY with the TLS options.
+// The CA bundle is set as SSL_CERT_FILE, which replaces the default CA file of the OS on Unix, so it should include
+// the public CAs the go commands need, such as the CA of the checksum database. The CA bundle and the client certificate
+// are also set for the git commands fetching modules directly, as GIT_SSL_CAINFO, GIT_SSL_CERT and GIT_SSL_KEY.
+// Since the go command can't present client certificates, the go commands send the requests of the http and https
+// proxies to a local forwarding server, which presents the client certificate and adds the credentials of the proxies.
+// The server only forwards the requests whose path starts with a random token, which is known only to the go commands,
+// so other local processes can't use it to reach the proxies with the credentials.
+// The returned function stops the server, and should be called when done.
+func WithProxyTls(ctx context.Context, chain *ProxyChain, options TlsOptions) (context.Context, func(), error) {
+	transport, err := options.NewTransport()
+	if err != nil {
+		return nil, nil, err
+	}
+	if options.CaBundlePath != "" {
+		ctx = cmd.WithEnv(ctx, "SSL_CERT_FILE", options.CaBundlePath)
+		ctx = cmd.WithEnv(ctx, "GIT_SSL_CAINFO", options.CaBundlePath)
+	}
+	if options.ClientCertPath == "" {
+		return WithProxyChain(ctx, chain), func() {}, nil
+	}
+	ctx = cmd.WithEnv(ctx, "GIT_SSL_CERT", options.ClientCertPath)
+	ctx = cmd.WithEnv(ctx, "GIT_SSL_KEY", options.ClientKeyPath)
+
+	token, err := newForwarderToken()
+	if err != nil {
+		return nil, nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, errorutils.CheckError(err)
+	}
+	forwarder := &forwarder{transport: transport, token: token}
+	localChain := NewProxyChain()
+	for _, proxy := range chain.proxies {
+		if !proxy.IsKeyword() && !strings.HasPrefix(proxy.Url, "file:") {
+			credentials, err := chain.getCredentials(ctx, proxy)
+			if err != nil {
+				listener.Close()
+				return nil, nil, err
+			}
+			target, err := url.Parse(proxy.Url)
+			if err != nil {
+				listener.Close()
+				return nil, nil, errorutils.CheckError(err)
+			}
+			forwarder.targets = append(forwarder.targets, forwardTarget{url: target, credentials: credentials})
+			proxy = Proxy{Url: "http://" + listener.Addr().String() + "/" + token + "/" + strconv.Itoa(len(forwarder.targets)-1), FallbackOnAnyError: proxy.FallbackOnAnyError}
+		}
+		localChain.proxies = append(localChain.proxies, proxy)
+	}
+	server := &http.Server{Handler: forwarder}
+	go server.Serve(listener)
+	cmd.GetLogger(ctx).Debug("Forwarding the requests of the go commands to", chain.MaskedString(), "through", listener.Addr().String())
+	cleanup := func() {
+		if err := server.Close(); err != nil {
+			cmd.GetLogger(ctx).Debug("Failed stopping the proxy forwarding server:", err.Error())
+		}
+	}
+	return WithProxyChain(ctx, localChain), cleanup, nil
+}
+
+// Forwards the requests of "/<token>/<index>/<path>" to the path of the proxy at the index, through the transport.
+// Requests without the token are rejected.
+type forwarder struct {
+	transport http.RoundTripper
+	token     string
+	targets   []forwardTarget
+}
+
+// Returns a random token of 32 hex digits.
+func newForwarderToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", errorutils.CheckError(err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+type forwardTarget struct {
+	url         *url.URL
+	credentials *Credentials
+}
+
+func (forwarder *forwarder) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	tokenAndPath := strings.SplitN(strings.TrimPrefix(request.URL.EscapedPath(), "/"), "/", 2)
+	if len(tokenAndPath) != 2 || subtle.ConstantTimeCompare([]byte(tokenAndPath[0]), []byte(forwarder.token)) != 1 {
+		http.Error(writer, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+	indexAndPath := strings.SplitN(tokenAndPath[1], "/", 2)
+	index, err := strconv.Atoi(indexAndPath[0])
+	if err != nil || index < 0 || index >= len(forwarder.targets) || len(indexAndPath) != 2 {
+		http.NotFound(writer, request)
+		return
+	}
+	path, err := url.PathUnescape(indexAndPath[1])
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+	target := forwarder.targets[index]
+	reverseProxy := &httputil.ReverseProxy{
+		Transport: forwarder.transport,
+		Director: func(forwarded *http.Request) {
+			forwarded.URL.Scheme = target.url.Scheme
+			forwarded.URL.Host = target.url.Host
+			forwarded.URL.Path = strings.TrimSuffix(target.url.Path, "/") + "/" + path
+			forwarded.URL.RawPath = ""
+			forwarded.Host = target.url.Host
+			forwarded.Header.Del("Authorization")
+			if target.credentials != nil {
+				forwarded.SetBasicAuth(target.credentials.Username, target.credentials.Password)
+			}
+		},
+	}
+	reverseProxy.ServeHTTP(writer, request)
+}
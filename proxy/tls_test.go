@@ -0,0 +1,181 @@
+package proxy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/jfrog/gocmd/cmd"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Starts the test proxy server with TLS, requiring a client certificate of the returned pool.
+func newMtlsTestProxyServer(clientCas *x509.CertPool) *httptest.Server {
+	server := httptest.NewUnstartedServer(testProxyHandler)
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCas}
+	server.StartTLS()
+	return server
+}
+
+// Writes a self-signed client certificate and its key to the dir, and returns their paths and the certificate.
+func writeClientCertificate(t *testing.T, dir string) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "gocmd-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPath, keyPath := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	writePem(t, certPath, "CERTIFICATE", der)
+	writePem(t, keyPath, "EC PRIVATE KEY", keyDer)
+	return certPath, keyPath, certificate
+}
+
+func writePem(t *testing.T, path, blockType string, der []byte) {
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTlsOptions(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "tlsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	certPath, keyPath, clientCertificate := writeClientCertificate(t, tempDir)
+	clientCas := x509.NewCertPool()
+	clientCas.AddCert(clientCertificate)
+	server := newMtlsTestProxyServer(clientCas)
+	defer server.Close()
+	caPath := filepath.Join(tempDir, "ca.pem")
+	writePem(t, caPath, "CERTIFICATE", server.Certificate().Raw)
+
+	tests := []struct {
+		name    string
+		options TlsOptions
+		success bool
+	}{
+		{"mtls", TlsOptions{CaBundlePath: caPath, ClientCertPath: certPath, ClientKeyPath: keyPath}, true},
+		{"noClientCertificate", TlsOptions{CaBundlePath: caPath}, false},
+		{"untrustedServer", TlsOptions{ClientCertPath: certPath, ClientKeyPath: keyPath}, false},
+	}
+	for _, test := range tests {
+		client, err := NewClient(Proxy{Url: server.URL, Username: "user", Password: "pass"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if client.Transport, err = test.options.NewTransport(); err != nil {
+			t.Error(err)
+			continue
+		}
+		_, err = client.Info(context.Background(), "github.com/Test/mod", "v1.1.0")
+		if (err == nil) != test.success {
+			t.Errorf("Test name: %s: Expected success: %t, Got: %v", test.name, test.success, err)
+		}
+	}
+
+	for _, options := range []TlsOptions{{ClientCertPath: certPath}, {CaBundlePath: keyPath}, {CaBundlePath: filepath.Join(tempDir, "missing.pem")}} {
+		if _, err = options.TlsConfig(); err == nil {
+			t.Error("Expecting an error for the invalid TLS options:", options)
+		}
+	}
+}
+
+func TestWithProxyTls(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "tlsTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	certPath, keyPath, clientCertificate := writeClientCertificate(t, tempDir)
+	clientCas := x509.NewCertPool()
+	clientCas.AddCert(clientCertificate)
+	server := newMtlsTestProxyServer(clientCas)
+	defer server.Close()
+	caPath := filepath.Join(tempDir, "ca.pem")
+	writePem(t, caPath, "CERTIFICATE", server.Certificate().Raw)
+
+	chain, err := ParseProxyChain("https://user:pass@" + server.Listener.Addr().String() + ",direct")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cleanup, err := WithProxyTls(context.Background(), chain, TlsOptions{CaBundlePath: caPath, ClientCertPath: certPath, ClientKeyPath: keyPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	env := cmd.GetOptions(ctx).Env
+	for key, expected := range map[string]string{"SSL_CERT_FILE": caPath, "GIT_SSL_CAINFO": caPath, "GIT_SSL_CERT": certPath, "GIT_SSL_KEY": keyPath} {
+		if env[key] != expected {
+			t.Errorf("Expecting %s to be %s, got: %s", key, expected, env[key])
+		}
+	}
+
+	// The go commands send their requests to the local forwarding server, without TLS and credentials.
+	localChain, err := ParseProxyChain(env["GOPROXY"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxies := localChain.Proxies()
+	if len(proxies) != 2 || proxies[1].Url != Direct || proxies[0].Username != "" {
+		t.Fatal("Unexpected GOPROXY:", env["GOPROXY"])
+	}
+	client, err := NewClient(proxies[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HttpClient = &http.Client{}
+	content, err := client.Mod(context.Background(), "github.com/Test/mod", "v1.1.0")
+	if err != nil || string(content) != "module github.com/Test/mod\n" {
+		t.Error("Unexpected go.mod content:", string(content), err)
+	}
+
+	// Requests without the token of the forwarding server are rejected.
+	localUrl, err := url.Parse(proxies[0].Url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"/0/github.com/!test/mod/@v/v1.1.0.mod", "/wrongtoken/0/github.com/!test/mod/@v/v1.1.0.mod"} {
+		response, err := http.Get("http://" + localUrl.Host + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		response.Body.Close()
+		if response.StatusCode != http.StatusForbidden {
+			t.Errorf("Expecting %s to be forbidden, got: %d", path, response.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package proxy
+
+import (
+	"github.com/jfrog/gocmd/cmd"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var transport http.RoundTripper
+var transportMutex sync.Mutex
+
+// Sets the transport of the requests sent to the proxies without an http.Client of their own, such as by Client,
+// ModuleExists and ProxyChain.Probe. Use it to add corporate proxy settings, mTLS client certificates or request logging.
+// By default, http.DefaultTransport is used. The go commands send their requests with their own transport.
+func SetTransport(newTransport http.RoundTripper) {
+	transportMutex.Lock()
+	defer transportMutex.Unlock()
+	transport = newTransport
+}
+
+// Returns the transport set by SetTransport, or http.DefaultTransport if none.
+func GetTransport() http.RoundTripper {
+	transportMutex.Lock()
+	defer transportMutex.Unlock()
+	if transport == nil {
+		return http.DefaultTransport
+	}
+	return transport
+}
+
+// Returns the client, or a client with the transport set by SetTransport if nil.
+func getHttpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Transport: GetTransport()}
+}
+
+// Logs the requests sent through its base transport, with their statuses and durations.
+type LoggingTransport struct {
+	// Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+func (loggingTransport *LoggingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := loggingTransport.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	requestUrl := cmd.MaskSecrets(request.URL.String())
+	start := time.Now()
+	response, err := base.RoundTrip(request)
+	if err != nil {
+		cmd.GetLogger(request.Context()).Debug(request.Method, requestUrl, "failed after", time.Since(start).String()+":", err.Error())
+		return nil, err
+	}
+	cmd.GetLogger(request.Context()).Debug(request.Method, requestUrl, response.Status, "in", time.Since(start).String())
+	return response, nil
+}
@@ -0,0 +1,73 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+// Adds the basic authentication of the test proxy server to the requests, and records their paths.
+type recordingTransport struct {
+	mutex sync.Mutex
+	paths []string
+}
+
+func (transport *recordingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport.mutex.Lock()
+	transport.paths = append(transport.paths, request.URL.Path)
+	transport.mutex.Unlock()
+	authenticated := *request
+	authenticated.Header = http.Header{}
+	for key, values := range request.Header {
+		authenticated.Header[key] = values
+	}
+	authenticated.SetBasicAuth("user", "pass")
+	return http.DefaultTransport.RoundTrip(&authenticated)
+}
+
+func TestClientTransport(t *testing.T) {
+	server := newTestProxyServer()
+	defer server.Close()
+	client, err := NewClient(Proxy{Url: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := &recordingTransport{}
+	client.Transport = &LoggingTransport{Base: transport}
+
+	info, err := client.Info(context.Background(), "github.com/Test/mod", "v1.1.0")
+	if err != nil || info.Version != "v1.1.0" {
+		t.Error("Unexpected info:", info, err)
+	}
+	if len(transport.paths) != 1 || transport.paths[0] != "/github.com/!test/mod/@v/v1.1.0.info" {
+		t.Error("Expecting the request to be sent through the transport, got:", transport.paths)
+	}
+}
+
+func TestSetTransport(t *testing.T) {
+	server := newTestProxyServer()
+	defer server.Close()
+	transport := &recordingTransport{}
+	SetTransport(transport)
+	defer SetTransport(nil)
+
+	exists, err := ModuleExists(context.Background(), nil, Proxy{Url: server.URL}, "github.com/Test/mod", "v1.1.0")
+	if err != nil || !exists {
+		t.Error("Expecting the module to exist, got:", exists, err)
+	}
+	chain, err := ParseProxyChain(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := chain.Probe(context.Background(), nil)
+	if len(results) != 1 || !results[0].Available {
+		t.Error("Expecting the proxy to be available, got:", results)
+	}
+	if len(transport.paths) != 2 {
+		t.Error("Expecting the requests to be sent through the transport, got:", transport.paths)
+	}
+	if GetTransport() != transport {
+		t.Error("Expecting the transport set by SetTransport")
+	}
+}
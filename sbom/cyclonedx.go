@@ -0,0 +1,129 @@
+package sbom
+
+import (
+	"encoding/json"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/gocmd/graph"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	cycloneDXSpecVersion = "1.5"
+	// The name of the property holding the go.sum h1 hash of a component.
+	goDirHashProperty = "golang:h1"
+)
+
+// A CycloneDX JSON BOM.
+type CycloneDXBom struct {
+	BomFormat    string                `json:"bomFormat"`
+	SpecVersion  string                `json:"specVersion"`
+	SerialNumber string                `json:"serialNumber,omitempty"`
+	Version      int                   `json:"version"`
+	Metadata     CycloneDXMetadata     `json:"metadata"`
+	Components   []CycloneDXComponent  `json:"components"`
+	Dependencies []CycloneDXDependency `json:"dependencies"`
+}
+
+type CycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Component CycloneDXComponent `json:"component"`
+}
+
+type CycloneDXComponent struct {
+	Type    string `json:"type"`
+	BomRef  string `json:"bom-ref"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Purl    string `json:"purl"`
+	// The go.sum hash of the module zip, labeled as a Go h1 dirhash. The h1 hash is a SHA-256 of the list of the
+	// files of the module with their hashes, rather than of the zip itself, so it isn't published as a SHA-256 hash.
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+	// "required" for components of the built application, and "excluded" for components needed only by its tests or tools.
+	Scope string `json:"scope,omitempty"`
+}
+
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CycloneDXDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// Creates a CycloneDX BOM of the modules of the dependency graph.
+// The root of the graph is the BOM's main component. The modules are identified by their package URLs,
+// and have the h1 hashes of their go.sum entries as the "golang:h1" property, if found in sums.
+func NewCycloneDXBom(dependencyGraph *graph.DependencyGraph, sums []gosum.ModuleEntry) *CycloneDXBom {
+	hashes := moduleHashes(sums)
+	root := dependencyGraph.Root()
+	bom := &CycloneDXBom{
+		BomFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Component: newCycloneDXComponent("application", root, ""),
+		},
+		Components:   []CycloneDXComponent{},
+		Dependencies: []CycloneDXDependency{},
+	}
+	bom.Dependencies = append(bom.Dependencies, newCycloneDXDependency(dependencyGraph, root))
+	for _, module := range dependencyGraph.Nodes() {
+		if module != root {
+			bom.Components = append(bom.Components, newCycloneDXComponent("library", module, hashes[module.String()]))
+			bom.Dependencies = append(bom.Dependencies, newCycloneDXDependency(dependencyGraph, module))
+		}
+	}
+	return bom
+}
+
+// Writes the BOM as JSON.
+func (bom *CycloneDXBom) Write(writer io.Writer) error {
+	content, err := json.MarshalIndent(bom, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = writer.Write(append(content, '\n'))
+	return errorutils.CheckError(err)
+}
+
+// Sets the scopes of the components by the scopes of their modules, as returned by cmd.GetDependenciesScopes.
+func (bom *CycloneDXBom) SetScopes(scopes map[string]cmd.DependencyScope) {
+	for i, component := range bom.Components {
+		switch scopes[component.Name+"@"+component.Version] {
+		case cmd.MainScope:
+			bom.Components[i].Scope = "required"
+		case cmd.TestScope, cmd.ToolScope:
+			bom.Components[i].Scope = "excluded"
+		}
+	}
+}
+
+func newCycloneDXComponent(componentType string, module graph.Module, hash string) CycloneDXComponent {
+	component := CycloneDXComponent{
+		Type:    componentType,
+		BomRef:  Purl(module),
+		Name:    module.Path,
+		Version: module.Version,
+		Purl:    Purl(module),
+	}
+	if strings.HasPrefix(hash, "h1:") {
+		component.Properties = []CycloneDXProperty{{Name: goDirHashProperty, Value: hash}}
+	}
+	return component
+}
+
+func newCycloneDXDependency(dependencyGraph *graph.DependencyGraph, module graph.Module) CycloneDXDependency {
+	dependency := CycloneDXDependency{Ref: Purl(module), DependsOn: []string{}}
+	for _, child := range dependencyGraph.Children(module) {
+		dependency.DependsOn = append(dependency.DependsOn, Purl(child))
+	}
+	return dependency
+}
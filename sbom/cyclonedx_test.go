@@ -0,0 +1,79 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/gocmd/graph"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+const modGraphOutput = `github.com/you/hello github.com/mholt/archiver@v2.1.0+incompatible
+github.com/you/hello rsc.io/quote@v1.5.2
+rsc.io/quote@v1.5.2 rsc.io/sampler@v1.3.0
+`
+
+var sums = []gosum.ModuleEntry{
+	{Path: "rsc.io/quote", Version: "v1.5.2", Hash: "h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y="},
+	{Path: "rsc.io/quote", Version: "v1.5.2", Hash: "h1:3fEykkD9k7lYzXqCYrwGAf7iNhbk4yCjHmKBN9td4L0=", IsMod: true},
+}
+
+func TestPurl(t *testing.T) {
+	tests := []struct {
+		module   graph.Module
+		expected string
+	}{
+		{graph.Module{Path: "rsc.io/quote", Version: "v1.5.2"}, "pkg:golang/rsc.io/quote@v1.5.2"},
+		{graph.Module{Path: "github.com/mholt/archiver", Version: "v2.1.0+incompatible"}, "pkg:golang/github.com/mholt/archiver@v2.1.0%2Bincompatible"},
+		{graph.Module{Path: "github.com/you/hello"}, "pkg:golang/github.com/you/hello"},
+	}
+	for _, test := range tests {
+		t.Run(test.module.String(), func(t *testing.T) {
+			if actual := Purl(test.module); actual != test.expected {
+				t.Errorf("Test name: %s: Expected: %s, Got: %s", test.module, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewCycloneDXBom(t *testing.T) {
+	bom := NewCycloneDXBom(graph.ParseModGraph(modGraphOutput), sums)
+	if bom.Metadata.Component.Purl != "pkg:golang/github.com/you/hello" || bom.Metadata.Component.Type != "application" {
+		t.Error("Unexpected main component:", bom.Metadata.Component)
+	}
+	if !regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(bom.SerialNumber) {
+		t.Error("Unexpected serial number:", bom.SerialNumber)
+	}
+	if len(bom.Components) != 3 {
+		t.Fatal("Expecting 3 components, got:", len(bom.Components))
+	}
+	quote := bom.Components[1]
+	expectedProperties := []CycloneDXProperty{{"golang:h1", "h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y="}}
+	if quote.Name != "rsc.io/quote" || !reflect.DeepEqual(expectedProperties, quote.Properties) {
+		t.Errorf("Unexpected component: %v", quote)
+	}
+	expectedDependency := CycloneDXDependency{Ref: "pkg:golang/github.com/you/hello", DependsOn: []string{"pkg:golang/github.com/mholt/archiver@v2.1.0%2Bincompatible", "pkg:golang/rsc.io/quote@v1.5.2"}}
+	if !reflect.DeepEqual(expectedDependency, bom.Dependencies[0]) {
+		t.Errorf("Expected: %v, Got: %v", expectedDependency, bom.Dependencies[0])
+	}
+
+	bom.SetScopes(map[string]cmd.DependencyScope{"rsc.io/quote@v1.5.2": cmd.MainScope, "github.com/mholt/archiver@v2.1.0+incompatible": cmd.TestScope})
+	if bom.Components[0].Scope != "excluded" || bom.Components[1].Scope != "required" || bom.Components[2].Scope != "" {
+		t.Errorf("Unexpected scopes: %v", bom.Components)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := bom.Write(buffer); err != nil {
+		t.Fatal(err)
+	}
+	var written map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &written); err != nil {
+		t.Fatal(err)
+	}
+	if written["bomFormat"] != "CycloneDX" || written["specVersion"] != "1.5" {
+		t.Error("Unexpected BOM header:", written["bomFormat"], written["specVersion"])
+	}
+}
@@ -0,0 +1,46 @@
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/gocmd/graph"
+	"net/url"
+	"strings"
+)
+
+// Returns the package URL of the module, such as "pkg:golang/github.com/jfrog/gocmd@v0.1.0".
+func Purl(module graph.Module) string {
+	var segments []string
+	for _, segment := range strings.Split(module.Path, "/") {
+		segments = append(segments, url.PathEscape(segment))
+	}
+	purl := "pkg:golang/" + strings.Join(segments, "/")
+	if module.Version != "" {
+		purl += "@" + strings.Replace(url.PathEscape(module.Version), "+", "%2B", -1)
+	}
+	return purl
+}
+
+// Returns the "h1:" hashes of the module zips by their "path@version" notation.
+// The hashes of the go.mod files are not included.
+func moduleHashes(sums []gosum.ModuleEntry) map[string]string {
+	hashes := map[string]string{}
+	for _, entry := range sums {
+		if !entry.IsMod {
+			hashes[entry.ModuleId()] = entry.Hash
+		}
+	}
+	return hashes
+}
+
+// Returns a random version 4 UUID.
+func newUUID() string {
+	uuid := make([]byte, 16)
+	if _, err := rand.Read(uuid); err != nil {
+		return ""
+	}
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+}
@@ -0,0 +1,193 @@
+package sbom
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/jfrog/gocmd/cmd"
+	"github.com/jfrog/gocmd/gosum"
+	"github.com/jfrog/gocmd/graph"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	spdxVersion     = "SPDX-2.3"
+	spdxNoAssertion = "NOASSERTION"
+	// The proxy used for the download locations of the packages, if none is provided.
+	DefaultProxyUrl = "https://proxy.golang.org"
+	// The name of a root module without a path, as of a project whose go.mod has no module directive.
+	spdxUnnamedRoot = "root"
+)
+
+var invalidSpdxIdChars = regexp.MustCompile(`[^A-Za-z0-9.\-]`)
+
+// An SPDX document.
+type SpdxDocument struct {
+	SpdxVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SpdxId            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SpdxCreationInfo   `json:"creationInfo"`
+	Packages          []SpdxPackage      `json:"packages"`
+	Relationships     []SpdxRelationship `json:"relationships"`
+}
+
+type SpdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SpdxPackage struct {
+	Name             string            `json:"name"`
+	SpdxId           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	ExternalRefs     []SpdxExternalRef `json:"externalRefs"`
+	// The go.sum h1 hash of the module zip. The h1 hash is a SHA-256 of the list of the files of the module with their
+	// hashes, rather than of the zip itself, so it isn't published as a SHA256 checksum.
+	Comment string `json:"comment,omitempty"`
+}
+
+type SpdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type SpdxRelationship struct {
+	SpdxElementId      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSpdxElement string `json:"relatedSpdxElement"`
+}
+
+// Creates an SPDX document of the modules of the dependency graph, describing the root of the graph.
+// The download location of each module is its zip in the proxy, or in DefaultProxyUrl if proxyUrl is empty.
+// The h1 hashes of the go.sum entries of the modules, if found in sums, are added to the comments of the packages.
+// The files of the modules are not analyzed, so the packages have no verification codes.
+func NewSpdxDocument(dependencyGraph *graph.DependencyGraph, sums []gosum.ModuleEntry, proxyUrl string) *SpdxDocument {
+	if proxyUrl == "" {
+		proxyUrl = DefaultProxyUrl
+	}
+	proxyUrl = strings.TrimSuffix(proxyUrl, "/")
+	hashes := moduleHashes(sums)
+	root := dependencyGraph.Root()
+	document := &SpdxDocument{
+		SpdxVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SpdxId:            "SPDXRef-DOCUMENT",
+		Name:              spdxName(root),
+		DocumentNamespace: "https://spdx.org/spdxdocs/" + invalidSpdxIdChars.ReplaceAllString(spdxName(root), "-") + "-" + newUUID(),
+		CreationInfo: SpdxCreationInfo{
+			Created:  time.Now().UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: gocmd"},
+		},
+		Packages:      []SpdxPackage{},
+		Relationships: []SpdxRelationship{},
+	}
+	document.Packages = append(document.Packages, newSpdxPackage(root, "", spdxNoAssertion))
+	document.Relationships = append(document.Relationships, SpdxRelationship{document.SpdxId, "DESCRIBES", spdxId(root)})
+	for _, module := range dependencyGraph.Nodes() {
+		if module != root {
+			downloadLocation := proxyUrl + "/" + cmd.EscapeModulePath(module.Path) + "/@v/" + cmd.EscapeModulePath(module.Version) + ".zip"
+			document.Packages = append(document.Packages, newSpdxPackage(module, hashes[module.String()], downloadLocation))
+		}
+	}
+	for _, edge := range dependencyGraph.Edges() {
+		document.Relationships = append(document.Relationships, SpdxRelationship{spdxId(edge.From), "DEPENDS_ON", spdxId(edge.To)})
+	}
+	return document
+}
+
+// Writes the document in the SPDX JSON format.
+func (document *SpdxDocument) WriteJSON(writer io.Writer) error {
+	content, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	_, err = writer.Write(append(content, '\n'))
+	return errorutils.CheckError(err)
+}
+
+// Writes the document in the SPDX tag/value format.
+func (document *SpdxDocument) WriteTagValue(writer io.Writer) error {
+	buffered := bufio.NewWriter(writer)
+	fmt.Fprintf(buffered, "SPDXVersion: %s\n", document.SpdxVersion)
+	fmt.Fprintf(buffered, "DataLicense: %s\n", document.DataLicense)
+	fmt.Fprintf(buffered, "SPDXID: %s\n", document.SpdxId)
+	fmt.Fprintf(buffered, "DocumentName: %s\n", document.Name)
+	fmt.Fprintf(buffered, "DocumentNamespace: %s\n", document.DocumentNamespace)
+	for _, creator := range document.CreationInfo.Creators {
+		fmt.Fprintf(buffered, "Creator: %s\n", creator)
+	}
+	fmt.Fprintf(buffered, "Created: %s\n", document.CreationInfo.Created)
+	for _, spdxPackage := range document.Packages {
+		fmt.Fprintf(buffered, "\nPackageName: %s\n", spdxPackage.Name)
+		fmt.Fprintf(buffered, "SPDXID: %s\n", spdxPackage.SpdxId)
+		if spdxPackage.VersionInfo != "" {
+			fmt.Fprintf(buffered, "PackageVersion: %s\n", spdxPackage.VersionInfo)
+		}
+		fmt.Fprintf(buffered, "PackageDownloadLocation: %s\n", spdxPackage.DownloadLocation)
+		fmt.Fprintf(buffered, "FilesAnalyzed: %t\n", spdxPackage.FilesAnalyzed)
+		fmt.Fprintf(buffered, "PackageLicenseConcluded: %s\n", spdxPackage.LicenseConcluded)
+		fmt.Fprintf(buffered, "PackageLicenseDeclared: %s\n", spdxPackage.LicenseDeclared)
+		fmt.Fprintf(buffered, "PackageCopyrightText: %s\n", spdxPackage.CopyrightText)
+		for _, ref := range spdxPackage.ExternalRefs {
+			fmt.Fprintf(buffered, "ExternalRef: %s %s %s\n", ref.ReferenceCategory, ref.ReferenceType, ref.ReferenceLocator)
+		}
+		if spdxPackage.Comment != "" {
+			fmt.Fprintf(buffered, "PackageComment: <text>%s</text>\n", spdxPackage.Comment)
+		}
+	}
+	fmt.Fprintln(buffered)
+	for _, relationship := range document.Relationships {
+		fmt.Fprintf(buffered, "Relationship: %s %s %s\n", relationship.SpdxElementId, relationship.RelationshipType, relationship.RelatedSpdxElement)
+	}
+	return errorutils.CheckError(buffered.Flush())
+}
+
+func newSpdxPackage(module graph.Module, hash, downloadLocation string) SpdxPackage {
+	spdxPackage := SpdxPackage{
+		Name:             spdxName(module),
+		SpdxId:           spdxId(module),
+		VersionInfo:      module.Version,
+		DownloadLocation: downloadLocation,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  spdxNoAssertion,
+		CopyrightText:    spdxNoAssertion,
+		ExternalRefs:     []SpdxExternalRef{{"PACKAGE-MANAGER", "purl", Purl(module)}},
+	}
+	if strings.HasPrefix(hash, "h1:") {
+		spdxPackage.Comment = "Go h1 dirhash: " + hash
+	}
+	return spdxPackage
+}
+
+// SPDX identifiers may contain only letters, numbers, "." and "-". Since replacing the other characters may map
+// different modules, such as a/b@v1 and a-b@v1, to the same identifier, a short hash of the module is appended.
+func spdxId(module graph.Module) string {
+	name := spdxName(module)
+	if module.Version != "" {
+		name += "@" + module.Version
+	}
+	hash := sha256.Sum256([]byte(module.String()))
+	return "SPDXRef-Package-" + invalidSpdxIdChars.ReplaceAllString(name, "-") + "-" + hex.EncodeToString(hash[:4])
+}
+
+// Returns the path of the module, or a fallback name if it has none.
+func spdxName(module graph.Module) string {
+	if module.Path == "" {
+		return spdxUnnamedRoot
+	}
+	return module.Path
+}
@@ -0,0 +1,99 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/jfrog/gocmd/graph"
+	"strings"
+	"testing"
+)
+
+func TestNewSpdxDocument(t *testing.T) {
+	document := NewSpdxDocument(graph.ParseModGraph(modGraphOutput+"github.com/you/hello github.com/Azure/go-autorest@v10.15.0+incompatible\n"), sums, "https://goproxy.example.com/")
+	if len(document.Packages) != 5 {
+		t.Fatal("Expecting 5 packages, got:", len(document.Packages))
+	}
+	if document.Packages[0].SpdxId != "SPDXRef-Package-github.com-you-hello-13413c4f" || document.Packages[0].DownloadLocation != "NOASSERTION" {
+		t.Error("Expecting the first package to be the described root, got:", document.Packages[0])
+	}
+	expectedLocations := map[string]string{
+		"github.com/Azure/go-autorest": "https://goproxy.example.com/github.com/!azure/go-autorest/@v/v10.15.0+incompatible.zip",
+		"rsc.io/quote":                 "https://goproxy.example.com/rsc.io/quote/@v/v1.5.2.zip",
+	}
+	for _, spdxPackage := range document.Packages {
+		if expected, exists := expectedLocations[spdxPackage.Name]; exists && spdxPackage.DownloadLocation != expected {
+			t.Errorf("Expected: %s, Got: %s", expected, spdxPackage.DownloadLocation)
+		}
+		if spdxPackage.Name == "rsc.io/quote" {
+			if expected := "Go h1 dirhash: h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y="; spdxPackage.Comment != expected {
+				t.Errorf("Expected: %s, Got: %s", expected, spdxPackage.Comment)
+			}
+		}
+	}
+	expectedRelationship := SpdxRelationship{"SPDXRef-Package-rsc.io-quote-v1.5.2-45149b34", "DEPENDS_ON", "SPDXRef-Package-rsc.io-sampler-v1.3.0-97a7beb1"}
+	if !containsRelationship(document.Relationships, expectedRelationship) {
+		t.Error("Expecting the relationships to include:", expectedRelationship)
+	}
+	if document.Relationships[0].RelationshipType != "DESCRIBES" {
+		t.Error("Expecting the document to describe the root, got:", document.Relationships[0])
+	}
+}
+
+func TestWriteSpdxDocument(t *testing.T) {
+	document := NewSpdxDocument(graph.ParseModGraph(modGraphOutput), sums, "")
+	buffer := &bytes.Buffer{}
+	if err := document.WriteJSON(buffer); err != nil {
+		t.Fatal(err)
+	}
+	var written map[string]interface{}
+	if err := json.Unmarshal(buffer.Bytes(), &written); err != nil {
+		t.Fatal(err)
+	}
+	if written["spdxVersion"] != "SPDX-2.3" || written["SPDXID"] != "SPDXRef-DOCUMENT" {
+		t.Error("Unexpected document header:", written["spdxVersion"], written["SPDXID"])
+	}
+
+	buffer.Reset()
+	if err := document.WriteTagValue(buffer); err != nil {
+		t.Fatal(err)
+	}
+	tagValue := buffer.String()
+	for _, expected := range []string{
+		"SPDXVersion: SPDX-2.3\n",
+		"PackageDownloadLocation: https://proxy.golang.org/rsc.io/quote/@v/v1.5.2.zip\n",
+		"PackageComment: <text>Go h1 dirhash: h1:w5fcysjrx7yqtD/aO+QwRjYZOKnaM9Uh2b40tElTs3Y=</text>\n",
+		"ExternalRef: PACKAGE-MANAGER purl pkg:golang/rsc.io/quote@v1.5.2\n",
+		"Relationship: SPDXRef-DOCUMENT DESCRIBES SPDXRef-Package-github.com-you-hello-13413c4f\n",
+	} {
+		if !strings.Contains(tagValue, expected) {
+			t.Errorf("Expecting the tag/value document to include: %q", expected)
+		}
+	}
+}
+
+func TestSpdxIdsAreUnique(t *testing.T) {
+	first := spdxId(graph.Module{Path: "example.com/a/b", Version: "v1.0.0"})
+	second := spdxId(graph.Module{Path: "example.com/a-b", Version: "v1.0.0"})
+	if first == second {
+		t.Error("Expecting different ids for modules whose paths differ only in invalid characters, got:", first)
+	}
+	if invalidSpdxIdChars.MatchString(strings.TrimPrefix(first, "SPDXRef-")) {
+		t.Error("Expecting a valid SPDX id, got:", first)
+	}
+}
+
+func TestNewSpdxDocumentUnnamedRoot(t *testing.T) {
+	document := NewSpdxDocument(graph.ParseModGraph(" rsc.io/quote@v1.5.2\n"), nil, "")
+	if document.Name != "root" || document.Packages[0].Name != "root" || !strings.HasPrefix(document.Packages[0].SpdxId, "SPDXRef-Package-root-") {
+		t.Error("Expecting the fallback name of the root, got:", document.Name, document.Packages[0])
+	}
+}
+
+func containsRelationship(relationships []SpdxRelationship, relationship SpdxRelationship) bool {
+	for _, existing := range relationships {
+		if existing == relationship {
+			return true
+		}
+	}
+	return false
+}
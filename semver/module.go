@@ -0,0 +1,105 @@
+package semver
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/mod/module"
+	gosemver "golang.org/x/mod/semver"
+	"strings"
+)
+
+const incompatibleSuffix = "+incompatible"
+
+// Returns the major version of the version, such as "v2" for v2.1.0, or an empty string if the version is invalid.
+func Major(version string) string {
+	return gosemver.Major(version)
+}
+
+// Returns the major and minor versions of the version, such as "v2.1" for v2.1.3, or an empty string if the version is
+// invalid.
+func MajorMinor(version string) string {
+	return gosemver.MajorMinor(version)
+}
+
+// Returns true if the version is of a v2+ module without a go.mod file, such as v2.1.0+incompatible.
+func IsIncompatible(version string) bool {
+	return strings.HasSuffix(version, incompatibleSuffix)
+}
+
+// Splits the module path to the path prefix and the major version suffix, such as "/v2" for github.com/user/repo/v2
+// or ".v2" for gopkg.in/yaml.v2. The suffix is empty for modules whose path has no major version.
+// Returns false if the major version suffix is invalid, such as "/v1" or "/v02", in which case the prefix is the path.
+func SplitPathMajor(modulePath string) (prefix, pathMajor string, ok bool) {
+	return module.SplitPathVersion(modulePath)
+}
+
+// Checks that the version is valid for the module path: versions of modules whose path has no major version suffix
+// must be v0 or v1, or v2+ with "+incompatible". Versions of modules with a suffix, such as github.com/user/repo/v2,
+// must be of the suffix's major version, without "+incompatible".
+func CheckPathMajor(modulePath, version string) error {
+	if !IsValid(version) {
+		return errorutils.CheckError(fmt.Errorf("Invalid version %s of %s: expecting a semantic version such as v1.2.3.", version, modulePath))
+	}
+	_, pathMajor, ok := SplitPathMajor(modulePath)
+	if !ok {
+		return errorutils.CheckError(fmt.Errorf("Invalid major version suffix in the module path %s.", modulePath))
+	}
+	// module.CheckPathMajor allows +incompatible for any major version, which the go command rejects when fetching.
+	if IsIncompatible(version) {
+		if pathMajor != "" {
+			return errorutils.CheckError(fmt.Errorf("Invalid version %s of %s: +incompatible is not allowed for a module path with a major version suffix.", version, modulePath))
+		}
+		if major := Major(version); major == "v0" || major == "v1" {
+			return errorutils.CheckError(fmt.Errorf("Invalid version %s of %s: +incompatible is allowed only for v2 or later.", version, modulePath))
+		}
+	}
+	if err := module.CheckPathMajor(version, pathMajor); err != nil {
+		reason := err.Error()
+		if invalidErr, ok := err.(*module.InvalidVersionError); ok {
+			// Such as "should be v0 or v1, not v2".
+			reason = invalidErr.Err.Error()
+		}
+		if pathMajor == "" {
+			major := Major(version)
+			return errorutils.CheckError(fmt.Errorf("Invalid version %s of %s: %s. Versions %s or later require the module path to end with /%s.", version, modulePath, reason, major, major))
+		}
+		return errorutils.CheckError(fmt.Errorf("Invalid version %s of %s: %s.", version, modulePath, reason))
+	}
+	return nil
+}
+
+// Returns the highest of the versions which are valid for the module path, according to CheckPathMajor.
+// As the go command does for the "latest" version, releases are preferred over prereleases, which are preferred over
+// pseudo-versions. Returns an empty string if none of the versions is valid for the module path.
+func HighestCompatible(modulePath string, versions []string) string {
+	highest := ""
+	highestKind := Invalid
+	for _, version := range versions {
+		if CheckPathMajor(modulePath, version) != nil {
+			continue
+		}
+		kind := Classify(version)
+		if highest == "" || kindPreference(kind) > kindPreference(highestKind) || (kind == highestKind && Compare(version, highest) > 0) {
+			highest = version
+			highestKind = kind
+		}
+	}
+	return highest
+}
+
+// Sorts the versions in ascending order. Invalid versions are first.
+func Sort(versions []string) {
+	gosemver.Sort(versions)
+}
+
+func kindPreference(kind Kind) int {
+	switch kind {
+	case Release:
+		return 3
+	case Prerelease:
+		return 2
+	case Pseudo:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,101 @@
+package semver
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitPathMajor(t *testing.T) {
+	tests := []struct {
+		path      string
+		prefix    string
+		pathMajor string
+		ok        bool
+	}{
+		{"github.com/user/repo", "github.com/user/repo", "", true},
+		{"github.com/user/repo/v2", "github.com/user/repo", "/v2", true},
+		{"github.com/user/repo/v1", "github.com/user/repo/v1", "", false},
+		{"github.com/user/repo/v02", "github.com/user/repo/v02", "", false},
+		{"github.com/user/repo/vendor", "github.com/user/repo/vendor", "", true},
+		{"gopkg.in/yaml.v2", "gopkg.in/yaml", ".v2", true},
+		{"gopkg.in/check.v1-unstable", "gopkg.in/check", ".v1-unstable", true},
+		{"gopkg.in/yaml", "gopkg.in/yaml", "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			prefix, pathMajor, ok := SplitPathMajor(test.path)
+			if prefix != test.prefix || pathMajor != test.pathMajor || ok != test.ok {
+				t.Errorf("Test name: %s: Expected: %s, %s, %t, Got: %s, %s, %t", test.path, test.prefix, test.pathMajor, test.ok, prefix, pathMajor, ok)
+			}
+		})
+	}
+}
+
+func TestCheckPathMajor(t *testing.T) {
+	tests := []struct {
+		path    string
+		version string
+		valid   bool
+	}{
+		{"github.com/user/repo", "v1.2.3", true},
+		{"github.com/user/repo", "v0.0.0-20170915032832-14c0d48ead0c", true},
+		{"github.com/user/repo", "v2.1.0+incompatible", true},
+		{"github.com/user/repo", "v2.1.0", false},
+		{"github.com/user/repo", "v1.2.3+incompatible", false},
+		{"github.com/user/repo", "1.2.3", false},
+		{"github.com/user/repo/v2", "v2.1.0", true},
+		{"github.com/user/repo/v2", "v3.0.0", false},
+		{"github.com/user/repo/v2", "v2.1.0+incompatible", false},
+		{"github.com/user/repo/v1", "v1.0.0", false},
+		{"gopkg.in/yaml.v2", "v2.4.0", true},
+		{"gopkg.in/yaml.v2", "v1.0.0", false},
+		{"gopkg.in/check.v1", "v0.0.0-20161208181325-20d25e280405", true},
+	}
+	for _, test := range tests {
+		t.Run(test.path+"@"+test.version, func(t *testing.T) {
+			if err := CheckPathMajor(test.path, test.version); (err == nil) != test.valid {
+				t.Errorf("Test name: %s@%s: Expected valid: %t, Got: %v", test.path, test.version, test.valid, err)
+			}
+		})
+	}
+}
+
+func TestHighestCompatible(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		versions []string
+		expected string
+	}{
+		{"release", "github.com/user/repo", []string{"v1.2.0", "v1.10.0", "v1.11.0-beta.1", "v2.0.0"}, "v1.10.0"},
+		{"incompatible", "github.com/user/repo", []string{"v1.2.0", "v2.0.0+incompatible"}, "v2.0.0+incompatible"},
+		{"prerelease", "github.com/user/repo", []string{"v0.0.0-20170915032832-14c0d48ead0c", "v0.1.0-rc.1"}, "v0.1.0-rc.1"},
+		{"pseudo", "github.com/user/repo/v2", []string{"v1.0.0", "v2.0.0-20170915032832-14c0d48ead0c"}, "v2.0.0-20170915032832-14c0d48ead0c"},
+		{"none", "github.com/user/repo/v3", []string{"v1.0.0", "v2.0.0"}, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := HighestCompatible(test.path, test.versions); actual != test.expected {
+				t.Errorf("Test name: %s: Expected: %s, Got: %s", test.name, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"v1.10.0", "v1.2.0", "invalid", "v1.2.0-rc.1", "v0.0.0-20170915032832-14c0d48ead0c"}
+	Sort(versions)
+	expected := []string{"invalid", "v0.0.0-20170915032832-14c0d48ead0c", "v1.2.0-rc.1", "v1.2.0", "v1.10.0"}
+	if !reflect.DeepEqual(expected, versions) {
+		t.Errorf("Expected: %v, Got: %v", expected, versions)
+	}
+}
+
+func TestMajorMinor(t *testing.T) {
+	tests := map[string]string{"v2.1.3": "v2.1", "v0.0.0-20170915032832-14c0d48ead0c": "v0.0", "v1": "v1.0", "latest": ""}
+	for version, expected := range tests {
+		if actual := MajorMinor(version); actual != expected {
+			t.Errorf("Test name: %s: Expected: %s, Got: %s", version, expected, actual)
+		}
+	}
+}
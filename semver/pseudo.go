@@ -0,0 +1,110 @@
+package semver
+
+import (
+	"fmt"
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"golang.org/x/mod/module"
+	gosemver "golang.org/x/mod/semver"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The length of the commit hash prefix in pseudo-versions.
+const pseudoVersionRevisionLength = 12
+
+var revisionRegexp = regexp.MustCompile(`^[0-9a-f]{12,}$`)
+
+// The kind of a module version.
+type Kind int
+
+const (
+	Invalid Kind = iota
+	// A version such as v1.2.3.
+	Release
+	// A version such as v1.2.3-beta.1.
+	Prerelease
+	// A version of an untagged commit, such as v0.0.0-20170915032832-14c0d48ead0c.
+	Pseudo
+)
+
+func (kind Kind) String() string {
+	switch kind {
+	case Release:
+		return "release"
+	case Prerelease:
+		return "prerelease"
+	case Pseudo:
+		return "pseudo"
+	}
+	return "invalid"
+}
+
+// The parts of a pseudo-version.
+type PseudoVersion struct {
+	// The tagged version the pseudo-version follows, or an empty string if it follows no tag.
+	Base string
+	// The UTC time of the commit.
+	Time time.Time
+	// The prefix of the commit hash.
+	Revision string
+}
+
+// Returns the kind of the version. Build metadata, such as "+incompatible", is ignored.
+func Classify(version string) Kind {
+	switch {
+	case !IsValid(version):
+		return Invalid
+	case IsPseudoVersion(version):
+		return Pseudo
+	case gosemver.Prerelease(version) != "":
+		return Prerelease
+	}
+	return Release
+}
+
+// Returns true if the version is a pseudo-version.
+func IsPseudoVersion(version string) bool {
+	return module.IsPseudoVersion(version)
+}
+
+// Creates the pseudo-version of a commit, from the commit's time and hash.
+// base is the latest tag before the commit, such as v1.2.3, or an empty string if there is no such tag, in which case
+// major sets the major version of the pseudo-version, such as v2, or v0 if empty.
+// Build metadata of base, such as "+incompatible", is kept.
+func NewPseudoVersion(major, base string, commitTime time.Time, revision string) (string, error) {
+	if !revisionRegexp.MatchString(revision) {
+		return "", errorutils.CheckError(fmt.Errorf("Invalid commit hash: %q. Expecting at least %d lowercase hexadecimal characters.", revision, pseudoVersionRevisionLength))
+	}
+	if base == "" && major != "" {
+		if _, err := strconv.Atoi(strings.TrimPrefix(major, "v")); err != nil || !strings.HasPrefix(major, "v") {
+			return "", errorutils.CheckError(fmt.Errorf("Invalid major version: %q. Expecting a version such as v2.", major))
+		}
+	}
+	// module.PseudoVersion completes shorthands such as v1.2, which aren't tags.
+	if base != "" && (!IsValid(base) || gosemver.Canonical(base) != strings.TrimSuffix(base, gosemver.Build(base)) || IsPseudoVersion(base)) {
+		return "", errorutils.CheckError(fmt.Errorf("Invalid base version: %q. Expecting a tagged version such as v1.2.3.", base))
+	}
+	return module.PseudoVersion(major, base, commitTime, revision[:pseudoVersionRevisionLength]), nil
+}
+
+// Parses a pseudo-version.
+func ParsePseudoVersion(version string) (*PseudoVersion, error) {
+	if !IsPseudoVersion(version) {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid pseudo-version: %q.", version))
+	}
+	base, err := module.PseudoVersionBase(version)
+	if err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid pseudo-version: %q: %s", version, err.Error()))
+	}
+	commitTime, err := module.PseudoVersionTime(version)
+	if err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid pseudo-version: %q: %s", version, err.Error()))
+	}
+	revision, err := module.PseudoVersionRev(version)
+	if err != nil {
+		return nil, errorutils.CheckError(fmt.Errorf("Invalid pseudo-version: %q: %s", version, err.Error()))
+	}
+	return &PseudoVersion{Base: strings.TrimSuffix(base, gosemver.Build(base)), Time: commitTime, Revision: revision}, nil
+}
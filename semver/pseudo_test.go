@@ -0,0 +1,99 @@
+package semver
+
+import (
+	"testing"
+	"time"
+)
+
+var commitTime = time.Date(2017, 9, 15, 3, 28, 32, 0, time.UTC)
+
+const revision = "14c0d48ead0cd47e3104ada247d91be04afc7a5a"
+
+func TestNewPseudoVersion(t *testing.T) {
+	tests := []struct {
+		name         string
+		major        string
+		base         string
+		expected     string
+		expectedBase string
+	}{
+		{"noTag", "", "", "v0.0.0-20170915032832-14c0d48ead0c", ""},
+		{"noTagMajor", "v2", "", "v2.0.0-20170915032832-14c0d48ead0c", ""},
+		{"release", "", "v1.2.3", "v1.2.4-0.20170915032832-14c0d48ead0c", "v1.2.3"},
+		{"prerelease", "", "v1.2.3-beta.1", "v1.2.3-beta.1.0.20170915032832-14c0d48ead0c", "v1.2.3-beta.1"},
+		{"incompatible", "", "v2.1.0+incompatible", "v2.1.1-0.20170915032832-14c0d48ead0c+incompatible", "v2.1.0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := NewPseudoVersion(test.major, test.base, commitTime, revision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != test.expected {
+				t.Errorf("Test name: %s: Expected: %s, Got: %s", test.name, test.expected, actual)
+			}
+			parsed, err := ParsePseudoVersion(actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.Base != test.expectedBase || !parsed.Time.Equal(commitTime) || parsed.Revision != revision[:12] {
+				t.Errorf("Test name: %s: Expected: %s, %s, %s, Got: %v", test.name, test.expectedBase, commitTime, revision[:12], parsed)
+			}
+		})
+	}
+}
+
+func TestNewPseudoVersionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		major    string
+		base     string
+		revision string
+	}{
+		{"shortRevision", "", "", "14c0d48"},
+		{"uppercaseRevision", "", "", "14C0D48EAD0CD47E"},
+		{"invalidMajor", "2", "", revision},
+		{"invalidBase", "", "1.2.3", revision},
+		{"shorthandBase", "", "v1.2", revision},
+		{"pseudoBase", "", "v0.0.0-20170915032832-14c0d48ead0c", revision},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := NewPseudoVersion(test.major, test.base, commitTime, test.revision); err == nil {
+				t.Errorf("Test name: %s: Expecting an error", test.name)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected Kind
+	}{
+		{"v1.2.3", Release},
+		{"v2.1.0+incompatible", Release},
+		{"v1.2.3-beta.1", Prerelease},
+		{"v1.2.3-2017-x", Prerelease},
+		{"v0.0.0-20170915032832-14c0d48ead0c", Pseudo},
+		{"v1.2.4-0.20170915032832-14c0d48ead0c", Pseudo},
+		{"v1.2.3-beta.1.0.20170915032832-14c0d48ead0c", Pseudo},
+		{"1.2.3", Invalid},
+		{"latest", Invalid},
+	}
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			if actual := Classify(test.version); actual != test.expected {
+				t.Errorf("Test name: %s: Expected: %s, Got: %s", test.version, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestParsePseudoVersionErrors(t *testing.T) {
+	for _, version := range []string{"v1.2.3", "v1.0.0-0.20170915032832-14c0d48ead0c", "v0.0.0-20171315032832-14c0d48ead0c"} {
+		if _, err := ParsePseudoVersion(version); err == nil {
+			t.Errorf("Expecting an error for %s", version)
+		}
+	}
+}
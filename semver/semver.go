@@ -0,0 +1,22 @@
+package semver
+
+import (
+	gosemver "golang.org/x/mod/semver"
+	"strings"
+)
+
+// Compares two semantic versions, such as "v1.2.3" or "v0.0.0-20170915032832-14c0d48ead0c".
+// Returns a negative number if v1 < v2, a positive number if v1 > v2 and 0 if they are equal.
+// Build metadata is ignored. Invalid versions are lower than valid ones, and compared lexically between themselves.
+func Compare(v1, v2 string) int {
+	if !gosemver.IsValid(v1) && !gosemver.IsValid(v2) {
+		return strings.Compare(v1, v2)
+	}
+	return gosemver.Compare(v1, v2)
+}
+
+// Returns true if the version is a valid semantic version, such as "v1.2.3", "v1.2.3-pre" or the "v1.2" shorthand,
+// according to the rules of the go command.
+func IsValid(version string) bool {
+	return gosemver.IsValid(version)
+}
@@ -0,0 +1,61 @@
+package semver
+
+import (
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		v1       string
+		v2       string
+		expected int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.10.0", -1},
+		{"v2.0.0", "v1.9.9", 1},
+		{"v1.0.0-alpha", "v1.0.0", -1},
+		{"v1.0.0-alpha.1", "v1.0.0-alpha.beta", -1},
+		{"v1.0.0-beta.11", "v1.0.0-beta.2", 1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v0.3.1", "v0.0.0-20170915032832-14c0d48ead0c", 1},
+		{"v2.1.0+incompatible", "v2.1.0", 0},
+		{"invalid", "v0.0.1", -1},
+	}
+	for _, test := range tests {
+		t.Run(test.v1+":"+test.v2, func(t *testing.T) {
+			actual := Compare(test.v1, test.v2)
+			if sign(actual) != test.expected {
+				t.Errorf("Test name: %s:%s: Expected: %d, Got: %d", test.v1, test.v2, test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	tests := map[string]bool{
+		"v1.2.3":        true,
+		"v1.2":          true,
+		"v1.2.3-beta.1": true,
+		"v1.2.3+meta":   true,
+		"1.2.3":         false,
+		"v01.2.3":       false,
+		"v1.2.3-01":     false,
+		"v1.2.3-a..b":   false,
+		"v1.2.3.4":      false,
+	}
+	for version, expected := range tests {
+		if actual := IsValid(version); actual != expected {
+			t.Errorf("Test name: %s: Expected: %t, Got: %t", version, expected, actual)
+		}
+	}
+}
+
+func sign(number int) int {
+	switch {
+	case number < 0:
+		return -1
+	case number > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"github.com/jfrog/jfrog-client-go/utils/errorutils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+// Keys are used as file names and in the commands of key-value stores, so they are limited to safe characters.
+var keyRegExp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Stores values, such as resolved dependency graphs, by their keys. Implementations must be safe for concurrent use.
+//
+// To share the values across machines, such as by a fleet of CI agents, implement Store over the client of a
+// key-value store, which then handles the connections, TLS and authentication. For example, with go-redis:
+//
+//	type RedisStore struct {
+//		Client *redis.Client
+//		TTL    time.Duration
+//	}
+//
+//	func (store *RedisStore) Get(key string) ([]byte, bool, error) {
+//		value, err := store.Client.Get(context.Background(), key).Bytes()
+//		if err == redis.Nil {
+//			return nil, false, nil
+//		}
+//		return value, err == nil, err
+//	}
+//
+//	func (store *RedisStore) Put(key string, value []byte) error {
+//		return store.Client.Set(context.Background(), key, value, store.TTL).Err()
+//	}
+type Store interface {
+	// Returns the value of the key, and false if there's none.
+	Get(key string) ([]byte, bool, error)
+	// Stores the value of the key, replacing the existing value, if any.
+	Put(key string, value []byte) error
+}
+
+// Stores each value in a file named after its key. Several processes, such as CI agents sharing a network file system,
+// may use the same dir. Values are written atomically, so a value is never read partially written.
+type FileStore struct {
+	Dir string
+}
+
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+func (store *FileStore) Get(key string) ([]byte, bool, error) {
+	if err := validateKey(key); err != nil {
+		return nil, false, err
+	}
+	value, err := ioutil.ReadFile(store.path(key))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, errorutils.CheckError(err)
+	}
+	return value, true, nil
+}
+
+func (store *FileStore) Put(key string, value []byte) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(store.Dir, 0755); err != nil {
+		return errorutils.CheckError(err)
+	}
+	tempFile, err := ioutil.TempFile(store.Dir, "."+key+".tmp")
+	if err != nil {
+		return errorutils.CheckError(err)
+	}
+	defer os.Remove(tempFile.Name())
+	_, err = tempFile.Write(value)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), store.path(key))
+	}
+	return errorutils.CheckError(err)
+}
+
+func (store *FileStore) path(key string) string {
+	return filepath.Join(store.Dir, key)
+}
+
+func validateKey(key string) error {
+	if !keyRegExp.MatchString(key) {
+		return errorutils.CheckError(&InvalidKeyError{Key: key})
+	}
+	return nil
+}
+
+// A key includes characters other than letters, digits, '.', '_' and '-'.
+type InvalidKeyError struct {
+	Key string
+}
+
+func (err *InvalidKeyError) Error() string {
+	return "Invalid store key: '" + err.Key + "'. Keys may only include letters, digits, '.', '_' and '-'."
+}
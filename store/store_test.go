@@ -0,0 +1,36 @@
+package store
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "storeTest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+	store := NewFileStore(filepath.Join(tempDir, "store"))
+	testStore(t, store)
+}
+
+func testStore(t *testing.T, store Store) {
+	if _, found, err := store.Get("missing"); err != nil || found {
+		t.Errorf("Expected a missing key, Got: %v, %v", found, err)
+	}
+	for _, value := range []string{"first", "second"} {
+		if err := store.Put("key", []byte(value)); err != nil {
+			t.Fatal(err)
+		}
+		actual, found, err := store.Get("key")
+		if err != nil || !found || string(actual) != value {
+			t.Errorf("Expected: %s, Got: %s, %v, %v", value, actual, found, err)
+		}
+	}
+	if err := store.Put("../key", []byte("value")); err == nil {
+		t.Error("Expecting an error for an invalid key")
+	}
+}